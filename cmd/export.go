@@ -0,0 +1,125 @@
+package cmd
+
+import (
+	"bufio"
+	"compress/gzip"
+	"flag"
+	"fmt"
+	"io"
+	"medidhaka/directory"
+	"medidhaka/infra/db"
+	"medidhaka/repo"
+	"medidhaka/util"
+	"net/url"
+	"os"
+	"slices"
+	"strings"
+)
+
+// Export writes every hospital or doctor matching a listing filter to a
+// file, e.g.
+//
+//	medidhaka export -kind hospitals -filter "type=private&min_beds=50" -gzip -out hospitals.csv.gz
+//
+// The filter takes the query parameters of GET /hospitals or GET /doctors.
+// Unlike the public doctor listing, doctors of every verification status are
+// exported unless -filter sets status.
+func Export(args []string) {
+	fs := flag.NewFlagSet("export", flag.ExitOnError)
+	kind := fs.String("kind", "", "what to export: hospitals or doctors")
+	format := fs.String("format", util.ExportCSV, "file format: "+strings.Join(util.ExportFormats, ", "))
+	filterQuery := fs.String("filter", "", "listing filters as a query string, e.g. search=dhaka&sort=rating")
+	path := fs.String("out", "-", "file to write, - for standard output")
+	compress := fs.Bool("gzip", false, "gzip-compress the output")
+	fs.Parse(args)
+
+	if !slices.Contains(util.ExportFormats, *format) {
+		fmt.Println("Unknown export format: ", *format)
+		os.Exit(1)
+	}
+	query, err := url.ParseQuery(*filterQuery)
+	if err != nil {
+		fmt.Println("Invalid filter: ", err)
+		os.Exit(1)
+	}
+
+	var out io.Writer = os.Stdout
+	if *path != "-" {
+		f, err := os.Create(*path)
+		if err != nil {
+			fmt.Println("Failed to create export file: ", err)
+			os.Exit(1)
+		}
+		defer f.Close()
+		out = f
+	}
+	buf := bufio.NewWriter(out)
+	out = buf
+	var gz *gzip.Writer
+	if *compress {
+		gz = gzip.NewWriter(buf)
+		out = gz
+	}
+
+	dbCon, err := db.NewConnection()
+	if err != nil {
+		fmt.Println("Database connection failed: ", err)
+		os.Exit(1)
+	}
+	defer dbCon.Close()
+
+	switch *kind {
+	case "hospitals":
+		filter, msg := directory.ParseHospitalFilter(query)
+		if msg != "" {
+			fmt.Println("Invalid filter: ", msg)
+			os.Exit(1)
+		}
+		rw, err := util.NewRecordWriter(out, *format, repo.HospitalExportColumns)
+		if err == nil {
+			err = repo.NewHospitalRepo(dbCon).Export(filter, func(h *repo.Hospital) error {
+				return rw.Write(h, h.ExportRecord())
+			})
+		}
+		if err == nil {
+			err = rw.Flush()
+		}
+		exitOnExportError(err)
+	case "doctors":
+		status := query.Get("status")
+		if status != "" && !slices.Contains(repo.VerificationStatuses, status) {
+			fmt.Println("Invalid filter: status must be unverified, pending, verified or rejected")
+			os.Exit(1)
+		}
+		filter, msg := directory.ParseDoctorFilter(query, status)
+		if msg != "" {
+			fmt.Println("Invalid filter: ", msg)
+			os.Exit(1)
+		}
+		rw, err := util.NewRecordWriter(out, *format, repo.DoctorExportColumns)
+		if err == nil {
+			err = repo.NewDoctorRepo(dbCon).Export(filter, func(d *repo.Doctor) error {
+				return rw.Write(d, d.ExportRecord())
+			})
+		}
+		if err == nil {
+			err = rw.Flush()
+		}
+		exitOnExportError(err)
+	default:
+		fmt.Println("kind must be hospitals or doctors")
+		os.Exit(1)
+	}
+
+	if gz != nil {
+		exitOnExportError(gz.Close())
+	}
+	exitOnExportError(buf.Flush())
+}
+
+func exitOnExportError(err error) {
+	if err != nil {
+		fmt.Println("Export failed: ", err)
+		os.Exit(1)
+	}
+}
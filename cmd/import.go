@@ -0,0 +1,69 @@
+package cmd
+
+import (
+	"encoding/json"
+	"flag"
+	"fmt"
+	"io"
+	"medidhaka/infra/db"
+	"medidhaka/repo"
+	"os"
+	"strings"
+)
+
+// Import loads hospitals, doctors or affiliations from a CSV file, e.g.
+//
+//	medidhaka import -kind doctors -file doctors.csv -dry-run
+//
+// It prints the import report as JSON and exits with status 1 when any row
+// failed.
+func Import(args []string) {
+	fs := flag.NewFlagSet("import", flag.ExitOnError)
+	kind := fs.String("kind", "", "what the file contains: "+strings.Join(repo.ImportKinds, ", "))
+	path := fs.String("file", "-", "CSV file to import, - for standard input")
+	dryRun := fs.Bool("dry-run", false, "validate and report without saving anything")
+	atomic := fs.Bool("atomic", false, "save nothing if any row fails")
+	chunk := fs.Int("chunk", repo.DefaultImportChunkSize, "rows per transaction when not atomic")
+	fs.Parse(args)
+
+	var in io.Reader = os.Stdin
+	if *path != "-" {
+		f, err := os.Open(*path)
+		if err != nil {
+			fmt.Println("Failed to open import file: ", err)
+			os.Exit(1)
+		}
+		defer f.Close()
+		in = f
+	}
+	file, err := repo.ReadImportCSV(in)
+	if err != nil {
+		fmt.Println("Failed to read import file: ", err)
+		os.Exit(1)
+	}
+
+	dbCon, err := db.NewConnection()
+	if err != nil {
+		fmt.Println("Database connection failed: ", err)
+		os.Exit(1)
+	}
+	defer dbCon.Close()
+
+	report, err := repo.NewImportRepo(dbCon).Import(*kind, file, repo.ImportOptions{
+		DryRun:    *dryRun,
+		Atomic:    *atomic,
+		ChunkSize: *chunk,
+	})
+	if err != nil {
+		fmt.Println("Import failed: ", err)
+		os.Exit(1)
+	}
+
+	out := json.NewEncoder(os.Stdout)
+	out.SetIndent("", "  ")
+	out.Encode(report)
+	if report.Failed > 0 {
+		dbCon.Close()
+		os.Exit(1)
+	}
+}
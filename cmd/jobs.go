@@ -0,0 +1,115 @@
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"log"
+	"medidhaka/infra/webhook"
+	"medidhaka/repo"
+	"sync"
+	"time"
+)
+
+// Webhook deliveries claimed per run, and sent at most this many at a time.
+const (
+	webhookBatchSize = 50
+	webhookWorkers   = 8
+)
+
+// runEvery calls job immediately and then on every tick of interval.
+func runEvery(interval time.Duration, job func()) {
+	go func() {
+		job()
+		for range time.Tick(interval) {
+			job()
+		}
+	}()
+}
+
+// startWaitlistExpiry periodically expires unclaimed waitlist offers so the
+// freed slots move on to the next patient in line.
+func startWaitlistExpiry(waitlistRepo repo.WaitlistRepo) {
+	runEvery(time.Minute, func() {
+		n, err := waitlistRepo.ExpireOffers()
+		if err != nil {
+			log.Printf("Failed to expire waitlist offers: %v", err)
+			return
+		}
+		if n > 0 {
+			log.Printf("Expired %d waitlist offer(s)", n)
+		}
+	})
+}
+
+// startLicenceCheck periodically flags hospitals whose DGHS licence has
+// expired, so listings can filter them without comparing dates.
+func startLicenceCheck(hospitalRepo repo.HospitalRepo) {
+	runEvery(time.Hour, func() {
+		n, err := hospitalRepo.FlagExpiredLicences()
+		if err != nil {
+			log.Printf("Failed to check hospital licences: %v", err)
+			return
+		}
+		if n > 0 {
+			log.Printf("Updated the licence status of %d hospital(s)", n)
+		}
+	})
+}
+
+// startWebhookDelivery sends the webhook deliveries that are due and records
+// each outcome, so that failures are retried with backoff and endpoints that
+// keep failing get disabled.
+func startWebhookDelivery(webhookRepo repo.WebhookRepo) {
+	sender := webhook.NewSender()
+	runEvery(10*time.Second, func() {
+		jobs, err := webhookRepo.ClaimDue(webhookBatchSize)
+		if err != nil {
+			log.Printf("Failed to claim webhook deliveries: %v", err)
+			return
+		}
+
+		var wg sync.WaitGroup
+		slots := make(chan struct{}, webhookWorkers)
+		for _, job := range jobs {
+			wg.Add(1)
+			slots <- struct{}{}
+			go func() {
+				defer func() { <-slots; wg.Done() }()
+				deliverWebhook(webhookRepo, sender, job)
+			}()
+		}
+		wg.Wait()
+	})
+}
+
+// deliverWebhook makes one attempt at a delivery.
+func deliverWebhook(webhookRepo repo.WebhookRepo, sender *webhook.Sender, job repo.WebhookJob) {
+	var status *int
+	var attemptErr string
+
+	body, err := json.Marshal(webhook.Event{
+		ID:        fmt.Sprintf("evt_%d", job.EventID),
+		Type:      job.EventType,
+		CreatedAt: job.EventCreatedAt,
+		Data:      job.Payload,
+	})
+	if err == nil {
+		var code int
+		code, err = sender.Send(job.URL, job.Secret, job.EventType, job.DeliveryID, body)
+		if code != 0 {
+			status = &code
+		}
+	}
+	if err != nil {
+		attemptErr = err.Error()
+	}
+
+	disabled, err := webhookRepo.RecordAttempt(job, status, attemptErr)
+	if err != nil {
+		log.Printf("Failed to record webhook delivery %d: %v", job.DeliveryID, err)
+		return
+	}
+	if disabled {
+		log.Printf("Disabled webhook subscription %d: every delivery failed for %s", job.SubscriptionID, repo.WebhookDisableAfter)
+	}
+}
@@ -4,8 +4,11 @@ import (
 	"fmt"
 	"medidhaka/config"
 	"medidhaka/infra/db"
+	"medidhaka/infra/sms"
+	"medidhaka/infra/storage"
 	"medidhaka/repo"
 	"medidhaka/rest"
+	"medidhaka/rpc"
 	"os"
 )
 
@@ -22,6 +25,83 @@ func Serve() {
 	hospitalRepo := repo.NewHospitalRepo(dbCon)
 	doctorRepo := repo.NewDoctorRepo(dbCon)
 	hospitalDoctorRepo := repo.NewHospitalDoctorRepo(dbCon)
+	specialtyRepo := repo.NewSpecialtyRepo(dbCon)
+	serviceRepo := repo.NewServiceRepo(dbCon)
+	scheduleRepo := repo.NewScheduleRepo(dbCon)
+	appointmentRepo := repo.NewAppointmentRepo(dbCon)
+	patientRepo := repo.NewPatientRepo(dbCon)
+	authRepo := repo.NewAuthRepo(dbCon)
+	waitlistRepo := repo.NewWaitlistRepo(dbCon)
+	queueRepo := repo.NewQueueRepo(dbCon)
+	reviewRepo := repo.NewReviewRepo(dbCon)
+	staffKeyRepo := repo.NewStaffKeyRepo(dbCon)
+	bedRepo := repo.NewBedRepo(dbCon)
+	hoursRepo := repo.NewHoursRepo(dbCon)
+	bloodRepo := repo.NewBloodRepo(dbCon)
+	insuranceRepo := repo.NewInsuranceRepo(dbCon)
+	credentialRepo := repo.NewCredentialRepo(dbCon)
+	importRepo := repo.NewImportRepo(dbCon)
+	webhookRepo := repo.NewWebhookRepo(dbCon)
 
-	rest.Start(conf, hospitalRepo, doctorRepo, hospitalDoctorRepo)
+	// Patient login is the only user of SMS, so without it there is no
+	// sender and the auth routes answer 503.
+	var smsSender sms.Sender
+	if conf.JwtSecret != "" {
+		smsSender, err = sms.NewSender(sms.Config{
+			Driver:           conf.SmsDriver,
+			Development:      conf.Development,
+			TwilioAccountSID: conf.TwilioAccountSID,
+			TwilioAuthToken:  conf.TwilioAuthToken,
+			TwilioFrom:       conf.TwilioFrom,
+		})
+		if err != nil {
+			fmt.Println("SMS sender setup failed: ", err)
+			os.Exit(1)
+		}
+	}
+
+	store, err := storage.New(conf.StorageDriver, conf.MediaDir, conf.MediaBaseURL)
+	if err != nil {
+		fmt.Println("Media storage setup failed: ", err)
+		os.Exit(1)
+	}
+
+	startWaitlistExpiry(waitlistRepo)
+	startLicenceCheck(hospitalRepo)
+	startWebhookDelivery(webhookRepo)
+
+	if conf.GrpcPort != 0 {
+		go rpc.Start(conf, rpc.Deps{
+			HospitalRepo:       hospitalRepo,
+			DoctorRepo:         doctorRepo,
+			HospitalDoctorRepo: hospitalDoctorRepo,
+			WebhookRepo:        webhookRepo,
+			Store:              store,
+		})
+	}
+
+	rest.Start(conf, rest.Deps{
+		HospitalRepo:       hospitalRepo,
+		DoctorRepo:         doctorRepo,
+		HospitalDoctorRepo: hospitalDoctorRepo,
+		SpecialtyRepo:      specialtyRepo,
+		ServiceRepo:        serviceRepo,
+		ScheduleRepo:       scheduleRepo,
+		AppointmentRepo:    appointmentRepo,
+		PatientRepo:        patientRepo,
+		AuthRepo:           authRepo,
+		WaitlistRepo:       waitlistRepo,
+		QueueRepo:          queueRepo,
+		ReviewRepo:         reviewRepo,
+		StaffKeyRepo:       staffKeyRepo,
+		BedRepo:            bedRepo,
+		HoursRepo:          hoursRepo,
+		BloodRepo:          bloodRepo,
+		InsuranceRepo:      insuranceRepo,
+		CredentialRepo:     credentialRepo,
+		ImportRepo:         importRepo,
+		WebhookRepo:        webhookRepo,
+		SmsSender:          smsSender,
+		Store:              store,
+	})
 }
@@ -13,6 +13,19 @@ type Config struct {
 	Version     string
 	ServiceName string
 	HttpPort    int
+	Development bool // APP_ENV is "development"
+	GrpcPort    int  // 0 when the gRPC server is off
+	JwtSecret   string
+	SmsDriver   string
+	AdminApiKey string
+
+	TwilioAccountSID string
+	TwilioAuthToken  string
+	TwilioFrom       string
+
+	StorageDriver string
+	MediaDir      string
+	MediaBaseURL  string
 }
 
 var (
@@ -26,11 +39,30 @@ func loadConfig() {
 	version := os.Getenv("VERSION")
 	serviceName := os.Getenv("SERVICE_NAME")
 	httpPort := os.Getenv("HTTP_PORT")
+	grpcPort := os.Getenv("GRPC_PORT")           // the gRPC server is off when empty
+	jwtSecret := os.Getenv("JWT_SECRET")         // patient login is off when empty
+	appEnv := os.Getenv("APP_ENV")               // "development" allows the log SMS driver
+	smsDriver := os.Getenv("SMS_DRIVER")         // required with JWT_SECRET
+	adminApiKey := os.Getenv("ADMIN_API_KEY")    // admin routes are closed when empty
+	storageDriver := os.Getenv("STORAGE_DRIVER") // defaults to the local filesystem
+	mediaDir := os.Getenv("MEDIA_DIR")
+	mediaBaseURL := os.Getenv("MEDIA_BASE_URL")
 
 	if version == "" || serviceName == "" || httpPort == "" {
 		fmt.Println("Missing required environment variables")
 		os.Exit(1)
 	}
+	if jwtSecret != "" && smsDriver == "" {
+		fmt.Println("SMS_DRIVER is required when patient login is on (JWT_SECRET is set)")
+		os.Exit(1)
+	}
+
+	if mediaDir == "" {
+		mediaDir = "media"
+	}
+	if mediaBaseURL == "" {
+		mediaBaseURL = "/media"
+	}
 
 	port, err := strconv.Atoi(httpPort)
 	if err != nil {
@@ -38,10 +70,32 @@ func loadConfig() {
 		os.Exit(1)
 	}
 
+	var gport int
+	if grpcPort != "" {
+		gport, err = strconv.Atoi(grpcPort)
+		if err != nil {
+			fmt.Println("GRPC_PORT must be a number")
+			os.Exit(1)
+		}
+	}
+
 	config = Config{
 		Version:     version,
 		ServiceName: serviceName,
 		HttpPort:    port,
+		Development: appEnv == "development",
+		GrpcPort:    gport,
+		JwtSecret:   jwtSecret,
+		SmsDriver:   smsDriver,
+		AdminApiKey: adminApiKey,
+
+		TwilioAccountSID: os.Getenv("TWILIO_ACCOUNT_SID"),
+		TwilioAuthToken:  os.Getenv("TWILIO_AUTH_TOKEN"),
+		TwilioFrom:       os.Getenv("TWILIO_FROM"),
+
+		StorageDriver: storageDriver,
+		MediaDir:      mediaDir,
+		MediaBaseURL:  mediaBaseURL,
 	}
 }
 
@@ -0,0 +1,5 @@
+ALTER TABLE hospitals
+    ADD COLUMN latitude DOUBLE PRECISION CHECK (latitude BETWEEN -90 AND 90),
+    ADD COLUMN longitude DOUBLE PRECISION CHECK (longitude BETWEEN -180 AND 180);
+
+CREATE INDEX idx_hospitals_location ON hospitals (latitude, longitude);
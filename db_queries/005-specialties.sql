@@ -0,0 +1,60 @@
+CREATE TABLE specialties (
+    specialty_id SERIAL PRIMARY KEY,
+    code VARCHAR(50) NOT NULL UNIQUE,
+    name_en VARCHAR(100) NOT NULL,
+    name_bn VARCHAR(100) NOT NULL DEFAULT '',
+    parent_id INT REFERENCES specialties(specialty_id) ON DELETE SET NULL,
+    synonyms TEXT[] NOT NULL DEFAULT '{}',
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE TABLE doctor_specialty (
+    doctor_id INT NOT NULL,
+    specialty_id INT NOT NULL,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    PRIMARY KEY (doctor_id, specialty_id),
+    FOREIGN KEY (doctor_id) REFERENCES doctors(doctor_id) ON DELETE CASCADE,
+    FOREIGN KEY (specialty_id) REFERENCES specialties(specialty_id) ON DELETE CASCADE
+);
+
+-- Seed the most common specialties together with the free-text variants
+-- already present in doctors.specialty.
+INSERT INTO specialties (code, name_en, name_bn, synonyms) VALUES
+    ('medicine', 'Medicine', 'মেডিসিন', ARRAY['general medicine', 'internal medicine', 'medicine specialist']),
+    ('cardiology', 'Cardiology', 'হৃদরোগ', ARRAY['cardiologist', 'heart specialist', 'heart']),
+    ('neurology', 'Neurology', 'স্নায়ুরোগ', ARRAY['neurologist', 'brain specialist', 'nerve specialist']),
+    ('orthopedics', 'Orthopedics', 'অর্থোপেডিক্স', ARRAY['orthopedic', 'orthopaedics', 'bone specialist']),
+    ('pediatrics', 'Pediatrics', 'শিশুরোগ', ARRAY['pediatrician', 'paediatrics', 'child specialist']),
+    ('gynecology', 'Gynecology & Obstetrics', 'স্ত্রীরোগ ও প্রসূতি', ARRAY['gynecologist', 'gynaecology', 'obstetrics']),
+    ('dermatology', 'Dermatology', 'চর্মরোগ', ARRAY['dermatologist', 'skin specialist', 'skin']),
+    ('ent', 'ENT', 'নাক কান গলা', ARRAY['otolaryngology', 'ent specialist', 'ear nose throat']),
+    ('ophthalmology', 'Ophthalmology', 'চক্ষুরোগ', ARRAY['ophthalmologist', 'eye specialist', 'eye']),
+    ('psychiatry', 'Psychiatry', 'মনোরোগ', ARRAY['psychiatrist', 'mental health']),
+    ('nephrology', 'Nephrology', 'কিডনি রোগ', ARRAY['nephrologist', 'kidney specialist']),
+    ('gastroenterology', 'Gastroenterology', 'পরিপাকতন্ত্র', ARRAY['gastroenterologist', 'liver specialist']),
+    ('oncology', 'Oncology', 'ক্যান্সার', ARRAY['oncologist', 'cancer specialist']),
+    ('surgery', 'General Surgery', 'সার্জারি', ARRAY['surgeon', 'general surgeon']),
+    ('dentistry', 'Dentistry', 'দন্তরোগ', ARRAY['dentist', 'dental']);
+
+UPDATE specialties child
+SET parent_id = parent.specialty_id
+FROM specialties parent
+WHERE parent.code = 'medicine'
+  AND child.code IN ('cardiology', 'neurology', 'nephrology', 'gastroenterology', 'psychiatry', 'dermatology');
+
+UPDATE specialties child
+SET parent_id = parent.specialty_id
+FROM specialties parent
+WHERE parent.code = 'surgery'
+  AND child.code IN ('orthopedics');
+
+-- Map existing free-text values onto the taxonomy.
+INSERT INTO doctor_specialty (doctor_id, specialty_id)
+SELECT DISTINCT d.doctor_id, s.specialty_id
+FROM doctors d
+JOIN specialties s
+  ON LOWER(TRIM(d.specialty)) = LOWER(s.code)
+  OR LOWER(TRIM(d.specialty)) = LOWER(s.name_en)
+  OR LOWER(TRIM(d.specialty)) = ANY (s.synonyms)
+ON CONFLICT DO NOTHING;
@@ -0,0 +1,36 @@
+CREATE TABLE services (
+    service_id SERIAL PRIMARY KEY,
+    code VARCHAR(50) NOT NULL UNIQUE,
+    name VARCHAR(150) NOT NULL,
+    department VARCHAR(100) NOT NULL DEFAULT '',
+    description TEXT NOT NULL DEFAULT '',
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE TABLE hospital_service (
+    hospital_id INT NOT NULL,
+    service_id INT NOT NULL,
+    notes VARCHAR(255) NOT NULL DEFAULT '',
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    PRIMARY KEY (hospital_id, service_id),
+    FOREIGN KEY (hospital_id) REFERENCES hospitals(hospital_id) ON DELETE CASCADE,
+    FOREIGN KEY (service_id) REFERENCES services(service_id) ON DELETE CASCADE
+);
+
+CREATE INDEX idx_hospital_service_service ON hospital_service (service_id);
+
+INSERT INTO services (code, name, department) VALUES
+    ('emergency', 'Emergency Department', 'Emergency'),
+    ('icu', 'Intensive Care Unit', 'Critical Care'),
+    ('ccu', 'Coronary Care Unit', 'Cardiology'),
+    ('nicu', 'Neonatal ICU', 'Pediatrics'),
+    ('cath_lab', 'Cardiac Cath Lab', 'Cardiology'),
+    ('dialysis', 'Dialysis', 'Nephrology'),
+    ('ct_scan', 'CT Scan', 'Radiology'),
+    ('mri', 'MRI', 'Radiology'),
+    ('xray', 'X-Ray', 'Radiology'),
+    ('pathology', 'Pathology Lab', 'Laboratory'),
+    ('blood_bank', 'Blood Bank', 'Laboratory'),
+    ('ot', 'Operation Theatre', 'Surgery'),
+    ('pharmacy', 'Pharmacy', 'Support');
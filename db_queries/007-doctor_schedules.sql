@@ -0,0 +1,37 @@
+-- Weekly chamber schedule of a doctor at a hospital. Times are wall-clock
+-- times in Asia/Dhaka; day_of_week follows PostgreSQL DOW (0 = Sunday).
+CREATE TABLE doctor_schedules (
+    schedule_id SERIAL PRIMARY KEY,
+    hospital_id INT NOT NULL,
+    doctor_id INT NOT NULL,
+    day_of_week SMALLINT NOT NULL CHECK (day_of_week BETWEEN 0 AND 6),
+    start_time TIME NOT NULL,
+    end_time TIME NOT NULL,
+    room VARCHAR(50) NOT NULL DEFAULT '',
+    max_patients INT NOT NULL DEFAULT 0 CHECK (max_patients >= 0),
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    CHECK (start_time < end_time),
+    FOREIGN KEY (hospital_id, doctor_id) REFERENCES hospital_doctor(hospital_id, doctor_id) ON DELETE CASCADE
+);
+
+CREATE INDEX idx_doctor_schedules_hospital_day ON doctor_schedules (hospital_id, day_of_week);
+CREATE INDEX idx_doctor_schedules_doctor ON doctor_schedules (doctor_id);
+
+-- Date-specific overrides: either the doctor is off (is_available = false)
+-- or sits at different hours than usual.
+CREATE TABLE schedule_exceptions (
+    exception_id SERIAL PRIMARY KEY,
+    hospital_id INT NOT NULL,
+    doctor_id INT NOT NULL,
+    exception_date DATE NOT NULL,
+    is_available BOOLEAN NOT NULL DEFAULT FALSE,
+    start_time TIME,
+    end_time TIME,
+    room VARCHAR(50) NOT NULL DEFAULT '',
+    reason VARCHAR(255) NOT NULL DEFAULT '',
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    UNIQUE (hospital_id, doctor_id, exception_date),
+    CHECK (NOT is_available OR (start_time IS NOT NULL AND end_time IS NOT NULL AND start_time < end_time)),
+    FOREIGN KEY (hospital_id, doctor_id) REFERENCES hospital_doctor(hospital_id, doctor_id) ON DELETE CASCADE
+);
@@ -0,0 +1,24 @@
+CREATE TABLE appointments (
+    appointment_id SERIAL PRIMARY KEY,
+    hospital_id INT NOT NULL,
+    doctor_id INT NOT NULL,
+    appointment_date DATE NOT NULL,
+    start_time TIME NOT NULL,
+    end_time TIME NOT NULL,
+    patient_name VARCHAR(255) NOT NULL,
+    patient_phone VARCHAR(50) NOT NULL,
+    notes TEXT NOT NULL DEFAULT '',
+    status VARCHAR(20) NOT NULL DEFAULT 'booked'
+        CHECK (status IN ('booked', 'checked_in', 'completed', 'no_show', 'cancelled')),
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    FOREIGN KEY (hospital_id, doctor_id) REFERENCES hospital_doctor(hospital_id, doctor_id) ON DELETE CASCADE
+);
+
+-- A slot can only be held by one live appointment; cancelling frees it.
+CREATE UNIQUE INDEX uq_appointments_slot
+    ON appointments (hospital_id, doctor_id, appointment_date, start_time)
+    WHERE status <> 'cancelled';
+
+CREATE INDEX idx_appointments_doctor_date ON appointments (doctor_id, appointment_date);
+CREATE INDEX idx_appointments_hospital_date ON appointments (hospital_id, appointment_date);
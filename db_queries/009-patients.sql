@@ -0,0 +1,38 @@
+CREATE TABLE patients (
+    patient_id SERIAL PRIMARY KEY,
+    phone_number VARCHAR(20) NOT NULL UNIQUE,
+    name VARCHAR(255) NOT NULL DEFAULT '',
+    email VARCHAR(100) NOT NULL DEFAULT '',
+    gender VARCHAR(20) NOT NULL DEFAULT '',
+    date_of_birth DATE,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP
+);
+
+-- One-time login codes. Only a SHA-256 hash of the code is stored.
+CREATE TABLE otp_codes (
+    otp_id SERIAL PRIMARY KEY,
+    phone_number VARCHAR(20) NOT NULL,
+    code_hash CHAR(64) NOT NULL,
+    attempts INT NOT NULL DEFAULT 0,
+    expires_at TIMESTAMP NOT NULL,
+    consumed_at TIMESTAMP,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX idx_otp_codes_phone_created ON otp_codes (phone_number, created_at DESC);
+
+-- Long-lived refresh tokens, stored hashed and rotated on every use.
+CREATE TABLE refresh_tokens (
+    token_id SERIAL PRIMARY KEY,
+    patient_id INT NOT NULL REFERENCES patients(patient_id) ON DELETE CASCADE,
+    token_hash CHAR(64) NOT NULL UNIQUE,
+    expires_at TIMESTAMP NOT NULL,
+    revoked_at TIMESTAMP,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP
+);
+
+ALTER TABLE appointments
+    ADD COLUMN patient_id INT REFERENCES patients(patient_id) ON DELETE SET NULL;
+
+CREATE INDEX idx_appointments_patient ON appointments (patient_id);
@@ -0,0 +1,32 @@
+-- Patients waiting for a slot with a doctor at a hospital on a date. When an
+-- appointment is cancelled, the first waiting entry is offered the freed
+-- slot; the slot is held for it until offer_expires_at.
+CREATE TABLE waitlist_entries (
+    entry_id SERIAL PRIMARY KEY,
+    hospital_id INT NOT NULL,
+    doctor_id INT NOT NULL,
+    waitlist_date DATE NOT NULL,
+    patient_id INT NOT NULL REFERENCES patients(patient_id) ON DELETE CASCADE,
+    status VARCHAR(20) NOT NULL DEFAULT 'waiting'
+        CHECK (status IN ('waiting', 'offered', 'booked', 'expired', 'left')),
+    offered_start_time TIME,
+    offered_end_time TIME,
+    offer_expires_at TIMESTAMP,
+    appointment_id INT REFERENCES appointments(appointment_id) ON DELETE SET NULL,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    FOREIGN KEY (hospital_id, doctor_id) REFERENCES hospital_doctor(hospital_id, doctor_id) ON DELETE CASCADE
+);
+
+-- A patient can only be in one live entry per doctor, hospital and date.
+CREATE UNIQUE INDEX uq_waitlist_live_entry
+    ON waitlist_entries (hospital_id, doctor_id, waitlist_date, patient_id)
+    WHERE status IN ('waiting', 'offered');
+
+CREATE INDEX idx_waitlist_queue
+    ON waitlist_entries (hospital_id, doctor_id, waitlist_date, created_at)
+    WHERE status = 'waiting';
+
+CREATE INDEX idx_waitlist_offer_expiry
+    ON waitlist_entries (offer_expires_at)
+    WHERE status = 'offered';
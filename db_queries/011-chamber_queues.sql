@@ -0,0 +1,26 @@
+-- Daily serial-number queue of a walk-in chamber.
+CREATE TABLE chamber_queues (
+    queue_id SERIAL PRIMARY KEY,
+    hospital_id INT NOT NULL,
+    doctor_id INT NOT NULL,
+    queue_date DATE NOT NULL,
+    current_serial INT NOT NULL DEFAULT 0,
+    last_serial INT NOT NULL DEFAULT 0,
+    updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    UNIQUE (hospital_id, doctor_id, queue_date),
+    FOREIGN KEY (hospital_id, doctor_id) REFERENCES hospital_doctor(hospital_id, doctor_id) ON DELETE CASCADE
+);
+
+CREATE TABLE queue_serials (
+    serial_id SERIAL PRIMARY KEY,
+    queue_id INT NOT NULL REFERENCES chamber_queues(queue_id) ON DELETE CASCADE,
+    serial_number INT NOT NULL,
+    patient_name VARCHAR(255) NOT NULL DEFAULT '',
+    patient_phone VARCHAR(50) NOT NULL DEFAULT '',
+    patient_id INT REFERENCES patients(patient_id) ON DELETE SET NULL,
+    status VARCHAR(20) NOT NULL DEFAULT 'waiting'
+        CHECK (status IN ('waiting', 'serving', 'done', 'skipped')),
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    UNIQUE (queue_id, serial_number)
+);
@@ -0,0 +1,30 @@
+-- Patient reviews of a completed visit. A review rates the doctor and the
+-- hospital of its appointment and is only published once approved.
+CREATE TABLE reviews (
+    review_id SERIAL PRIMARY KEY,
+    appointment_id INT NOT NULL UNIQUE REFERENCES appointments(appointment_id) ON DELETE CASCADE,
+    patient_id INT NOT NULL REFERENCES patients(patient_id) ON DELETE CASCADE,
+    hospital_id INT NOT NULL REFERENCES hospitals(hospital_id) ON DELETE CASCADE,
+    doctor_id INT NOT NULL REFERENCES doctors(doctor_id) ON DELETE CASCADE,
+    rating SMALLINT NOT NULL CHECK (rating BETWEEN 1 AND 5),
+    review_text TEXT NOT NULL DEFAULT '',
+    status VARCHAR(20) NOT NULL DEFAULT 'pending'
+        CHECK (status IN ('pending', 'approved', 'rejected')),
+    moderation_note TEXT NOT NULL DEFAULT '',
+    moderated_at TIMESTAMP,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX idx_reviews_doctor ON reviews (doctor_id, created_at DESC) WHERE status = 'approved';
+CREATE INDEX idx_reviews_hospital ON reviews (hospital_id, created_at DESC) WHERE status = 'approved';
+CREATE INDEX idx_reviews_pending ON reviews (created_at) WHERE status = 'pending';
+
+-- Aggregates of approved reviews, kept up to date on moderation.
+ALTER TABLE doctors
+    ADD COLUMN rating_avg NUMERIC(3, 2) NOT NULL DEFAULT 0,
+    ADD COLUMN rating_count INT NOT NULL DEFAULT 0;
+
+ALTER TABLE hospitals
+    ADD COLUMN rating_avg NUMERIC(3, 2) NOT NULL DEFAULT 0,
+    ADD COLUMN rating_count INT NOT NULL DEFAULT 0;
@@ -0,0 +1,29 @@
+-- API keys that let a hospital's staff update its live data. Only a SHA-256
+-- hash of the key is stored; the key itself is shown once when issued.
+CREATE TABLE hospital_staff_keys (
+    key_id SERIAL PRIMARY KEY,
+    hospital_id INT NOT NULL REFERENCES hospitals(hospital_id) ON DELETE CASCADE,
+    label VARCHAR(100) NOT NULL DEFAULT '',
+    key_hash CHAR(64) NOT NULL UNIQUE,
+    last_used_at TIMESTAMP,
+    revoked_at TIMESTAMP,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX idx_staff_keys_hospital ON hospital_staff_keys (hospital_id);
+
+-- Bed inventory per hospital and ward type.
+CREATE TABLE hospital_beds (
+    hospital_id INT NOT NULL REFERENCES hospitals(hospital_id) ON DELETE CASCADE,
+    ward_type VARCHAR(20) NOT NULL
+        CHECK (ward_type IN ('general', 'cabin', 'icu', 'ccu', 'nicu')),
+    total_beds INT NOT NULL CHECK (total_beds >= 0),
+    occupied_beds INT NOT NULL CHECK (occupied_beds >= 0),
+    updated_by_key_id INT REFERENCES hospital_staff_keys(key_id) ON DELETE SET NULL,
+    updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    PRIMARY KEY (hospital_id, ward_type),
+    CHECK (occupied_beds <= total_beds)
+);
+
+CREATE INDEX idx_hospital_beds_ward_free
+    ON hospital_beds (ward_type, (total_beds - occupied_beds) DESC);
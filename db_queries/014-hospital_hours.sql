@@ -0,0 +1,38 @@
+-- Emergency department and ambulance details of a hospital.
+ALTER TABLE hospitals
+    ADD COLUMN open_24_7 BOOLEAN NOT NULL DEFAULT FALSE,
+    ADD COLUMN has_emergency BOOLEAN NOT NULL DEFAULT FALSE,
+    ADD COLUMN emergency_phone VARCHAR(50) NOT NULL DEFAULT '',
+    ADD COLUMN ambulance_numbers TEXT[] NOT NULL DEFAULT '{}';
+
+-- Weekly opening hours. Times are wall-clock times in Asia/Dhaka;
+-- day_of_week follows PostgreSQL DOW (0 = Sunday). A day can have several
+-- ranges; a day without rows is closed unless the hospital is open 24/7.
+-- A range cannot pass midnight, so overnight hours are stored as one range
+-- closing at 24:00 and one opening at 00:00 on the next day.
+CREATE TABLE hospital_hours (
+    hours_id SERIAL PRIMARY KEY,
+    hospital_id INT NOT NULL REFERENCES hospitals(hospital_id) ON DELETE CASCADE,
+    day_of_week SMALLINT NOT NULL CHECK (day_of_week BETWEEN 0 AND 6),
+    open_time TIME NOT NULL,
+    close_time TIME NOT NULL,
+    CHECK (open_time < close_time)
+);
+
+CREATE INDEX idx_hospital_hours_day ON hospital_hours (hospital_id, day_of_week);
+
+-- Holiday and other date-specific overrides of the weekly hours, including
+-- for 24/7 hospitals. An open override without times means open all day.
+CREATE TABLE hospital_hour_overrides (
+    override_id SERIAL PRIMARY KEY,
+    hospital_id INT NOT NULL REFERENCES hospitals(hospital_id) ON DELETE CASCADE,
+    override_date DATE NOT NULL,
+    is_open BOOLEAN NOT NULL DEFAULT FALSE,
+    open_time TIME,
+    close_time TIME,
+    reason VARCHAR(255) NOT NULL DEFAULT '',
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    UNIQUE (hospital_id, override_date),
+    CHECK ((open_time IS NULL AND close_time IS NULL)
+        OR (is_open AND open_time IS NOT NULL AND close_time IS NOT NULL AND open_time < close_time))
+);
@@ -0,0 +1,17 @@
+-- Blood stock per hospital, blood group and component, kept up to date by
+-- the hospital's staff.
+CREATE TABLE hospital_blood_inventory (
+    hospital_id INT NOT NULL REFERENCES hospitals(hospital_id) ON DELETE CASCADE,
+    blood_group VARCHAR(3) NOT NULL
+        CHECK (blood_group IN ('A+', 'A-', 'B+', 'B-', 'AB+', 'AB-', 'O+', 'O-')),
+    component VARCHAR(20) NOT NULL
+        CHECK (component IN ('whole_blood', 'red_cells', 'platelets', 'plasma', 'cryo')),
+    units_available INT NOT NULL CHECK (units_available >= 0),
+    updated_by_key_id INT REFERENCES hospital_staff_keys(key_id) ON DELETE SET NULL,
+    updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    PRIMARY KEY (hospital_id, blood_group, component)
+);
+
+CREATE INDEX idx_blood_inventory_stock
+    ON hospital_blood_inventory (blood_group, component)
+    WHERE units_available > 0;
@@ -0,0 +1,30 @@
+-- Consultation fees of a doctor at a hospital. NULL means the fee is not
+-- known.
+ALTER TABLE hospital_doctor
+    ADD COLUMN fee_new_patient NUMERIC(10, 2) CHECK (fee_new_patient >= 0),
+    ADD COLUMN fee_follow_up NUMERIC(10, 2) CHECK (fee_follow_up >= 0),
+    ADD COLUMN fee_report_review NUMERIC(10, 2) CHECK (fee_report_review >= 0),
+    ADD COLUMN fee_currency CHAR(3) NOT NULL DEFAULT 'BDT';
+
+CREATE INDEX idx_hospital_doctor_fee ON hospital_doctor (doctor_id, fee_new_patient);
+
+-- Insurance companies and corporate panels that hospitals accept.
+CREATE TABLE insurance_panels (
+    panel_id SERIAL PRIMARY KEY,
+    code VARCHAR(50) NOT NULL UNIQUE,
+    name VARCHAR(150) NOT NULL,
+    panel_type VARCHAR(20) NOT NULL DEFAULT 'insurance'
+        CHECK (panel_type IN ('insurance', 'corporate')),
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE TABLE hospital_insurance (
+    hospital_id INT NOT NULL REFERENCES hospitals(hospital_id) ON DELETE CASCADE,
+    panel_id INT NOT NULL REFERENCES insurance_panels(panel_id) ON DELETE CASCADE,
+    notes VARCHAR(255) NOT NULL DEFAULT '',
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    PRIMARY KEY (hospital_id, panel_id)
+);
+
+CREATE INDEX idx_hospital_insurance_panel ON hospital_insurance (panel_id);
@@ -0,0 +1,26 @@
+-- BMDC (Bangladesh Medical & Dental Council) registration and profile
+-- details. Existing doctors start unverified and are hidden from public
+-- search until an admin verifies them.
+ALTER TABLE doctors
+    ADD COLUMN bmdc_reg_no VARCHAR(20) UNIQUE,
+    ADD COLUMN gender VARCHAR(10) NOT NULL DEFAULT ''
+        CHECK (gender IN ('', 'male', 'female', 'other')),
+    ADD COLUMN languages TEXT[] NOT NULL DEFAULT '{}',
+    ADD COLUMN verification_status VARCHAR(20) NOT NULL DEFAULT 'unverified'
+        CHECK (verification_status IN ('unverified', 'pending', 'verified', 'rejected')),
+    ADD COLUMN verification_note TEXT NOT NULL DEFAULT '',
+    ADD COLUMN verification_requested_at TIMESTAMP,
+    ADD COLUMN verified_at TIMESTAMP;
+
+CREATE INDEX idx_doctors_verification ON doctors (verification_status);
+
+CREATE TABLE doctor_qualifications (
+    qualification_id SERIAL PRIMARY KEY,
+    doctor_id INT NOT NULL REFERENCES doctors(doctor_id) ON DELETE CASCADE,
+    degree VARCHAR(100) NOT NULL,
+    institution VARCHAR(255) NOT NULL DEFAULT '',
+    year_awarded INT CHECK (year_awarded BETWEEN 1900 AND 2100),
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX idx_doctor_qualifications_doctor ON doctor_qualifications (doctor_id);
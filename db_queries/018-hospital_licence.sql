@@ -0,0 +1,16 @@
+-- Ownership, DGHS (Directorate General of Health Services) licence and
+-- accreditation details of a hospital. licence_expired is maintained by the
+-- scheduled licence check and on every update.
+ALTER TABLE hospitals
+    ADD COLUMN hospital_type VARCHAR(20) NOT NULL DEFAULT ''
+        CHECK (hospital_type IN ('', 'government', 'private', 'ngo')),
+    ADD COLUMN dghs_licence_no VARCHAR(50) NOT NULL DEFAULT '',
+    ADD COLUMN licence_expiry DATE,
+    ADD COLUMN licence_expired BOOLEAN NOT NULL DEFAULT FALSE,
+    ADD COLUMN bed_capacity INT CHECK (bed_capacity >= 0),
+    ADD COLUMN accreditations TEXT[] NOT NULL DEFAULT '{}',
+    ADD COLUMN website VARCHAR(255) NOT NULL DEFAULT '';
+
+CREATE UNIQUE INDEX idx_hospitals_dghs_licence ON hospitals (dghs_licence_no) WHERE dghs_licence_no <> '';
+CREATE INDEX idx_hospitals_type ON hospitals (hospital_type);
+CREATE INDEX idx_hospitals_accreditations ON hospitals USING GIN (accreditations);
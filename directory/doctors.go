@@ -0,0 +1,68 @@
+package directory
+
+import (
+	"math"
+	"medidhaka/repo"
+	"net/url"
+	"slices"
+	"strconv"
+	"strings"
+)
+
+var genders = []string{"", "male", "female", "other"}
+
+// NormalizeCredentials cleans up the BMDC number and gender of doc and
+// reports the first invalid one, if any.
+func NormalizeCredentials(doc *repo.Doctor) string {
+	if doc.BMDCRegNo != nil {
+		reg, ok := repo.NormalizeBMDC(*doc.BMDCRegNo)
+		if reg == "" {
+			doc.BMDCRegNo = nil
+		} else if !ok {
+			return "bmdc_reg_no must look like A-12345"
+		} else {
+			doc.BMDCRegNo = &reg
+		}
+	}
+	doc.Gender = strings.ToLower(strings.TrimSpace(doc.Gender))
+	if !slices.Contains(genders, doc.Gender) {
+		return "gender must be male, female or other"
+	}
+	return ""
+}
+
+// ParseDoctorFilter reads the filters shared by the doctor listings and
+// export and reports the first invalid one, if any. Only doctors with the
+// given verification status match; an empty status matches every doctor.
+func ParseDoctorFilter(query url.Values, status string) (repo.DoctorFilter, string) {
+	sort, ok := ParseListSort(query.Get("sort"))
+	if !ok {
+		return repo.DoctorFilter{}, "sort must be newest or rating"
+	}
+	filter := repo.DoctorFilter{
+		Search:    query.Get("search"),
+		Status:    status,
+		Sort:      sort,
+		Insurance: strings.ToLower(strings.TrimSpace(query.Get("insurance"))),
+	}
+	if v := query.Get("max_fee"); v != "" {
+		maxFee, err := strconv.ParseFloat(v, 64)
+		if err != nil || maxFee < 0 || math.IsNaN(maxFee) || math.IsInf(maxFee, 0) {
+			return filter, "max_fee must be a non-negative number"
+		}
+		filter.MaxFee = &maxFee
+	}
+	return filter, ""
+}
+
+// ParseListSort validates the sort query parameter of doctor and hospital
+// listings.
+func ParseListSort(value string) (string, bool) {
+	switch value {
+	case "", "newest":
+		return repo.SortNewest, true
+	case "rating":
+		return repo.SortRating, true
+	}
+	return "", false
+}
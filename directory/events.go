@@ -0,0 +1,14 @@
+package directory
+
+import (
+	"log"
+	"medidhaka/repo"
+)
+
+// PublishEvent queues a webhook event about a change that has been saved.
+// Failing to queue it does not undo the change, so it is only logged.
+func PublishEvent(webhooks repo.WebhookRepo, eventType string, data interface{}) {
+	if err := webhooks.Publish(eventType, data); err != nil {
+		log.Printf("Failed to publish %s event: %v", eventType, err)
+	}
+}
@@ -0,0 +1,21 @@
+package directory
+
+import (
+	"medidhaka/repo"
+	"strings"
+)
+
+// ValidateFees normalizes the currency of rel and reports the first invalid
+// fee field, if any.
+func ValidateFees(rel *repo.HospitalDoctor) string {
+	for _, fee := range []*float64{rel.FeeNewPatient, rel.FeeFollowUp, rel.FeeReportReview} {
+		if fee != nil && *fee < 0 {
+			return "fees cannot be negative"
+		}
+	}
+	rel.FeeCurrency = strings.ToUpper(strings.TrimSpace(rel.FeeCurrency))
+	if rel.FeeCurrency != "" && len(rel.FeeCurrency) != 3 {
+		return "fee_currency must be a 3-letter ISO 4217 code"
+	}
+	return ""
+}
@@ -0,0 +1,80 @@
+// Package directory holds the rules for hospitals, doctors and their
+// affiliations that the REST handlers and the gRPC server share: input
+// validation, list filters, photo cleanup and webhook events.
+package directory
+
+import (
+	"medidhaka/repo"
+	"net/url"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// ValidateHospitalDetails normalizes the ownership, licence and website
+// fields of h and reports the first invalid one, if any.
+func ValidateHospitalDetails(h *repo.Hospital) string {
+	h.HospitalType = strings.ToLower(strings.TrimSpace(h.HospitalType))
+	if h.HospitalType != "" && !slices.Contains(repo.HospitalTypes, h.HospitalType) {
+		return "hospital_type must be government, private or ngo"
+	}
+	h.LicenceNo = strings.ToUpper(strings.TrimSpace(h.LicenceNo))
+	if h.LicenceExpiry != nil {
+		if *h.LicenceExpiry == "" {
+			h.LicenceExpiry = nil
+		} else if _, err := time.Parse("2006-01-02", *h.LicenceExpiry); err != nil {
+			return "licence_expiry must be a YYYY-MM-DD date"
+		}
+	}
+	if h.BedCapacity != nil && *h.BedCapacity < 0 {
+		return "bed_capacity cannot be negative"
+	}
+	h.Website = strings.TrimSpace(h.Website)
+	if h.Website != "" {
+		u, err := url.Parse(h.Website)
+		if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+			return "website must be an http or https URL"
+		}
+	}
+	return ""
+}
+
+// ParseHospitalFilter reads the filters shared by the hospital listing and
+// export and reports the first invalid one, if any.
+func ParseHospitalFilter(query url.Values) (repo.HospitalFilter, string) {
+	sort, ok := ParseListSort(query.Get("sort"))
+	if !ok {
+		return repo.HospitalFilter{}, "sort must be newest or rating"
+	}
+	filter := repo.HospitalFilter{
+		Search:     query.Get("search"),
+		OpenNow:    query.Get("open_now") == "true",
+		Emergency:  query.Get("emergency") == "true",
+		Type:       strings.ToLower(query.Get("type")),
+		Licence:    query.Get("licence"),
+		Accredited: strings.TrimSpace(query.Get("accreditation")),
+		Sort:       sort,
+	}
+	if filter.Type != "" && !slices.Contains(repo.HospitalTypes, filter.Type) {
+		return filter, "type must be government, private or ngo"
+	}
+	if filter.Licence != "" && filter.Licence != repo.LicenceValid && filter.Licence != repo.LicenceExpired {
+		return filter, "licence must be valid or expired"
+	}
+	if v := query.Get("min_beds"); v != "" {
+		minBeds, err := strconv.Atoi(v)
+		if err != nil || minBeds < 0 {
+			return filter, "min_beds must be a non-negative integer"
+		}
+		filter.MinBeds = minBeds
+	}
+	if svc := query.Get("service"); svc != "" {
+		for _, code := range strings.Split(svc, ",") {
+			if code = strings.ToLower(strings.TrimSpace(code)); code != "" {
+				filter.Services = append(filter.Services, code)
+			}
+		}
+	}
+	return filter, ""
+}
@@ -0,0 +1,29 @@
+package directory
+
+import (
+	"fmt"
+	"log"
+	"medidhaka/infra/storage"
+	"medidhaka/util"
+	"strings"
+)
+
+// RemoveStoredImage deletes the file behind url and its variants if it lives
+// in our storage under the owning record's prefix/id/ folder. A URL pointing
+// anywhere else, such as another record's photo, is left alone. Failures are
+// only logged; a leftover file is harmless.
+func RemoveStoredImage(store storage.Storage, url, prefix string, id int) {
+	key, ok := store.KeyFromURL(url)
+	if !ok || !strings.HasPrefix(key, fmt.Sprintf("%s/%d/", prefix, id)) {
+		return
+	}
+	keys := []string{key}
+	for _, v := range util.ImageVariants {
+		keys = append(keys, util.VariantKey(key, v))
+	}
+	for _, k := range keys {
+		if err := store.Delete(k); err != nil {
+			log.Printf("Failed to delete stored image %s: %v", k, err)
+		}
+	}
+}
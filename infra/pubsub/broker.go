@@ -0,0 +1,49 @@
+package pubsub
+
+import "sync"
+
+// Broker is an in-process publish/subscribe hub. Subscribers that fall behind
+// miss messages instead of blocking publishers.
+type Broker struct {
+	mu     sync.Mutex
+	topics map[string]map[chan []byte]struct{}
+}
+
+func NewBroker() *Broker {
+	return &Broker{topics: make(map[string]map[chan []byte]struct{})}
+}
+
+// Subscribe registers a listener on topic. The returned function must be
+// called to unsubscribe.
+func (b *Broker) Subscribe(topic string) (<-chan []byte, func()) {
+	ch := make(chan []byte, 8)
+
+	b.mu.Lock()
+	if b.topics[topic] == nil {
+		b.topics[topic] = make(map[chan []byte]struct{})
+	}
+	b.topics[topic][ch] = struct{}{}
+	b.mu.Unlock()
+
+	return ch, func() {
+		b.mu.Lock()
+		delete(b.topics[topic], ch)
+		if len(b.topics[topic]) == 0 {
+			delete(b.topics, topic)
+		}
+		b.mu.Unlock()
+	}
+}
+
+// Publish sends msg to every current subscriber of topic.
+func (b *Broker) Publish(topic string, msg []byte) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	for ch := range b.topics[topic] {
+		select {
+		case ch <- msg:
+		default:
+		}
+	}
+}
@@ -0,0 +1,72 @@
+// Package ratelimit limits how often each client may call the API. The HTTP
+// and gRPC servers share it so that both sides apply the same limits.
+package ratelimit
+
+import (
+	"sync"
+	"time"
+)
+
+// Limiter is a token bucket per client key: a client may make burst calls at
+// once and then rate calls per second.
+type Limiter struct {
+	rate  float64
+	burst float64
+
+	mu      sync.Mutex
+	buckets map[string]*bucket
+	swept   time.Time
+}
+
+type bucket struct {
+	tokens float64
+	last   time.Time
+}
+
+func New(rate, burst float64) *Limiter {
+	return &Limiter{rate: rate, burst: burst, buckets: map[string]*bucket{}}
+}
+
+// Allow takes a token from the client's bucket if there is one. Buckets that
+// have refilled completely are dropped once a minute, as they are the same
+// as new ones.
+func (l *Limiter) Allow(key string, now time.Time) bool {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	if now.Sub(l.swept) > time.Minute {
+		for k, b := range l.buckets {
+			if b.tokens+now.Sub(b.last).Seconds()*l.rate >= l.burst {
+				delete(l.buckets, k)
+			}
+		}
+		l.swept = now
+	}
+
+	b, ok := l.buckets[key]
+	if !ok {
+		b = &bucket{tokens: l.burst, last: now}
+		l.buckets[key] = b
+	}
+	b.tokens = min(l.burst, b.tokens+now.Sub(b.last).Seconds()*l.rate)
+	b.last = now
+	if b.tokens < 1 {
+		return false
+	}
+	b.tokens--
+	return true
+}
+
+// Each client may make Rate calls per second to the gRPC API and to the HTTP
+// routes that change data, with bursts of Burst.
+const (
+	Rate  = 20
+	Burst = 40
+)
+
+// Photo uploads are decoded and resized twice, so each client may upload
+// one every two seconds, with bursts of UploadBurst.
+const (
+	UploadRate  = 0.5
+	UploadBurst = 5
+)
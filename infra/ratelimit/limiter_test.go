@@ -0,0 +1,82 @@
+package ratelimit
+
+import (
+	"testing"
+	"time"
+)
+
+func TestLimiterAllow(t *testing.T) {
+	start := time.Date(2026, 10, 19, 9, 0, 0, 0, time.UTC)
+	type call struct {
+		key   string
+		after time.Duration // since start
+		want  bool
+	}
+	tests := []struct {
+		name  string
+		rate  float64
+		burst float64
+		calls []call
+	}{
+		{
+			name: "burst then rejected",
+			rate: 1, burst: 2,
+			calls: []call{{"a", 0, true}, {"a", 0, true}, {"a", 0, false}},
+		},
+		{
+			name: "tokens refill at the rate",
+			rate: 2, burst: 1,
+			calls: []call{{"a", 0, true}, {"a", 100 * time.Millisecond, false}, {"a", 500 * time.Millisecond, true}},
+		},
+		{
+			name: "refill stops at the burst",
+			rate: 10, burst: 2,
+			calls: []call{{"a", 0, true}, {"a", time.Hour, true}, {"a", time.Hour, true}, {"a", time.Hour, false}},
+		},
+		{
+			name: "clients have their own buckets",
+			rate: 1, burst: 1,
+			calls: []call{{"a", 0, true}, {"a", 0, false}, {"b", 0, true}, {"b", 0, false}},
+		},
+		{
+			name: "swept buckets start full",
+			rate: 1, burst: 1,
+			calls: []call{{"a", 0, true}, {"b", 2 * time.Minute, true}, {"a", 2 * time.Minute, true}, {"a", 2 * time.Minute, false}},
+		},
+		{
+			name: "rates below one per second",
+			rate: 0.5, burst: 1,
+			calls: []call{{"a", 0, true}, {"a", time.Second, false}, {"a", 2 * time.Second, true}},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			l := New(tt.rate, tt.burst)
+			for i, c := range tt.calls {
+				if got := l.Allow(c.key, start.Add(c.after)); got != c.want {
+					t.Errorf("call %d: Allow(%q, +%s) = %t, want %t", i, c.key, c.after, got, c.want)
+				}
+			}
+		})
+	}
+}
+
+func TestLimiterSweepsFullBuckets(t *testing.T) {
+	start := time.Date(2026, 10, 19, 9, 0, 0, 0, time.UTC)
+	l := New(1, 2)
+	l.Allow("idle", start)
+	l.Allow("busy", start.Add(2*time.Minute))
+	l.Allow("busy", start.Add(2*time.Minute))
+	l.Allow("other", start.Add(4*time.Minute))
+
+	if _, ok := l.buckets["idle"]; ok {
+		t.Error("bucket of an idle client was not swept")
+	}
+	if _, ok := l.buckets["busy"]; ok {
+		t.Error("bucket that refilled since the last sweep was not swept")
+	}
+	if _, ok := l.buckets["other"]; !ok {
+		t.Error("bucket of the current caller is missing")
+	}
+}
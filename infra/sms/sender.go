@@ -0,0 +1,93 @@
+package sms
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"log"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+)
+
+// Sender delivers a text message to a phone number.
+type Sender interface {
+	Send(phone, message string) error
+}
+
+// LogSender writes messages to the log instead of sending them. Messages
+// carry login codes, so NewSender only allows it in development.
+type LogSender struct{}
+
+func (LogSender) Send(phone, message string) error {
+	log.Printf("[sms] to %s: %s", phone, message)
+	return nil
+}
+
+// TwilioSender sends messages through Twilio's Messages API.
+type TwilioSender struct {
+	AccountSID string
+	AuthToken  string
+	From       string // a Twilio number or alphanumeric sender ID
+
+	client *http.Client
+}
+
+func (s TwilioSender) Send(phone, message string) error {
+	form := url.Values{"To": {phone}, "From": {s.From}, "Body": {message}}
+	endpoint := "https://api.twilio.com/2010-04-01/Accounts/" + url.PathEscape(s.AccountSID) + "/Messages.json"
+	req, err := http.NewRequest(http.MethodPost, endpoint, strings.NewReader(form.Encode()))
+	if err != nil {
+		return err
+	}
+	req.SetBasicAuth(s.AccountSID, s.AuthToken)
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+
+	resp, err := s.client.Do(req)
+	if err != nil {
+		return fmt.Errorf("error sending SMS: %w", err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode/100 != 2 {
+		body, _ := io.ReadAll(io.LimitReader(resp.Body, 1<<10))
+		return fmt.Errorf("error sending SMS: Twilio answered %d: %s", resp.StatusCode, strings.TrimSpace(string(body)))
+	}
+	io.Copy(io.Discard, io.LimitReader(resp.Body, 64<<10))
+	return nil
+}
+
+// Config selects the SMS driver and holds the settings of each.
+type Config struct {
+	Driver      string // "twilio", or "log" in development
+	Development bool
+
+	TwilioAccountSID string
+	TwilioAuthToken  string
+	TwilioFrom       string
+}
+
+// NewSender returns the Sender for the configured driver.
+func NewSender(c Config) (Sender, error) {
+	switch c.Driver {
+	case "":
+		return nil, errors.New("SMS_DRIVER is not set")
+	case "log":
+		if !c.Development {
+			return nil, errors.New("the log SMS driver writes login codes to the log and is only allowed in development")
+		}
+		return LogSender{}, nil
+	case "twilio":
+		if c.TwilioAccountSID == "" || c.TwilioAuthToken == "" || c.TwilioFrom == "" {
+			return nil, errors.New("the twilio SMS driver needs TWILIO_ACCOUNT_SID, TWILIO_AUTH_TOKEN and TWILIO_FROM")
+		}
+		return TwilioSender{
+			AccountSID: c.TwilioAccountSID,
+			AuthToken:  c.TwilioAuthToken,
+			From:       c.TwilioFrom,
+			client:     &http.Client{Timeout: 10 * time.Second},
+		}, nil
+	default:
+		return nil, fmt.Errorf("unknown SMS driver %q", c.Driver)
+	}
+}
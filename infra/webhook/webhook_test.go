@@ -0,0 +1,87 @@
+package webhook
+
+import (
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"testing"
+)
+
+func TestSign(t *testing.T) {
+	tests := []struct {
+		name      string
+		secret    string
+		timestamp int64
+		body      string
+		want      string
+	}{
+		{
+			name:      "event body",
+			secret:    "whsec_test",
+			timestamp: 1700000000,
+			body:      `{"id":"1"}`,
+			want:      "sha256=11bf4466ea17c3df3fd743af0b435368e16b7a05eb8eced85e8c4670767bdec5",
+		},
+		{
+			name:      "other secret",
+			secret:    "other",
+			timestamp: 1700000000,
+			body:      `{"id":"1"}`,
+			want:      "sha256=0c9dcd041b074d1b31727e0c1f821d11366e9db9f94c18bf202eb66cd0bd4d40",
+		},
+		{
+			name:      "other timestamp",
+			secret:    "whsec_test",
+			timestamp: 1700000001,
+			body:      `{"id":"1"}`,
+			want:      "sha256=b1feba12f212f2ce5192c1233a9427597af5f3d45ee6bb9cb6c30b434095284d",
+		},
+		{
+			name:      "empty body",
+			secret:    "whsec_test",
+			timestamp: 1700000000,
+			body:      "",
+			want:      "sha256=5967f3c560522fa40cf2876ebc3c3a08551dd6959aaade3b413460591895bdcc",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := Sign(tt.secret, tt.timestamp, []byte(tt.body)); got != tt.want {
+				t.Errorf("Sign() = %s, want %s", got, tt.want)
+			}
+		})
+	}
+}
+
+// A subscriber must be able to check a delivery with Sign and the headers
+// it received.
+func TestSendSignsDelivery(t *testing.T) {
+	const secret = "whsec_test"
+	body := []byte(`{"id":"evt_1","type":"hospital.updated"}`)
+
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		got, _ := io.ReadAll(r.Body)
+		timestamp, err := strconv.ParseInt(r.Header.Get(HeaderTimestamp), 10, 64)
+		if err != nil {
+			t.Errorf("invalid %s header: %v", HeaderTimestamp, err)
+		}
+		if sig := r.Header.Get(HeaderSignature); sig != Sign(secret, timestamp, got) {
+			t.Errorf("%s = %s, want %s", HeaderSignature, sig, Sign(secret, timestamp, got))
+		}
+		if event := r.Header.Get(HeaderEvent); event != "hospital.updated" {
+			t.Errorf("%s = %s, want hospital.updated", HeaderEvent, event)
+		}
+		if delivery := r.Header.Get(HeaderDelivery); delivery != "7" {
+			t.Errorf("%s = %s, want 7", HeaderDelivery, delivery)
+		}
+		w.WriteHeader(http.StatusNoContent)
+	}))
+	defer srv.Close()
+
+	status, err := NewSender().Send(srv.URL, secret, "hospital.updated", 7, body)
+	if err != nil || status != http.StatusNoContent {
+		t.Errorf("Send() = %d, %v, want %d, nil", status, err, http.StatusNoContent)
+	}
+}
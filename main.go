@@ -2,8 +2,19 @@ package main
 
 import (
 	"medidhaka/cmd"
+	"os"
 )
 
 func main() {
+	if len(os.Args) > 1 {
+		switch os.Args[1] {
+		case "import":
+			cmd.Import(os.Args[2:])
+			return
+		case "export":
+			cmd.Export(os.Args[2:])
+			return
+		}
+	}
 	cmd.Serve()
 }
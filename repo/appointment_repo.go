@@ -0,0 +1,450 @@
+package repo
+
+import (
+	"database/sql"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/jmoiron/sqlx"
+)
+
+var (
+	ErrAppointmentNotFound = errors.New("appointment not found")
+	ErrSlotTaken           = errors.New("slot is already booked")
+	ErrInvalidTransition   = errors.New("invalid appointment status transition")
+	ErrTooManyBookings     = errors.New("too many open bookings")
+)
+
+// Appointment statuses.
+const (
+	StatusBooked    = "booked"
+	StatusCheckedIn = "checked_in"
+	StatusCompleted = "completed"
+	StatusNoShow    = "no_show"
+	StatusCancelled = "cancelled"
+)
+
+// appointmentTransitions lists the statuses each status may move to.
+var appointmentTransitions = map[string][]string{
+	StatusBooked:    {StatusCheckedIn, StatusNoShow, StatusCancelled},
+	StatusCheckedIn: {StatusCompleted},
+}
+
+// defaultSlotMinutes is used for sessions without a max_patients cap.
+const defaultSlotMinutes = 15
+
+type Appointment struct {
+	AppointmentID int       `json:"appointment_id" db:"appointment_id"`
+	HospitalID    int       `json:"hospital_id" db:"hospital_id"`
+	DoctorID      int       `json:"doctor_id" db:"doctor_id"`
+	PatientID     *int      `json:"patient_id" db:"patient_id"`
+	Date          string    `json:"date" db:"appointment_date"`
+	StartTime     string    `json:"start_time" db:"start_time"`
+	EndTime       string    `json:"end_time" db:"end_time"`
+	PatientName   string    `json:"patient_name" db:"patient_name"`
+	PatientPhone  string    `json:"patient_phone" db:"patient_phone"`
+	Notes         string    `json:"notes" db:"notes"`
+	Status        string    `json:"status" db:"status"`
+	CreatedAt     time.Time `json:"created_at" db:"created_at"`
+	UpdatedAt     time.Time `json:"updated_at" db:"updated_at"`
+}
+
+// AppointmentFilter narrows down List results. Zero values are ignored.
+type AppointmentFilter struct {
+	HospitalID int
+	DoctorID   int
+	PatientID  int
+	Date       string
+	Status     string
+}
+
+// Slot is a bookable interval of a doctor's session.
+type Slot struct {
+	StartTime string `json:"start_time"`
+	EndTime   string `json:"end_time"`
+	Room      string `json:"room"`
+	Available bool   `json:"available"`
+}
+
+type AppointmentRepo interface {
+	Book(a Appointment, maxOpen int) (*Appointment, error)
+	Get(id int) (*Appointment, error)
+	List(filter AppointmentFilter, offset, limit int) ([]Appointment, int, error)
+	BookedStartTimes(hospitalID, doctorID int, date string) ([]string, error)
+	Reschedule(id int, date, startTime, endTime string) (*Appointment, error)
+	UpdateStatus(id int, status string) (*Appointment, error)
+}
+
+type appointmentRepo struct {
+	db *sqlx.DB
+}
+
+func NewAppointmentRepo(db *sqlx.DB) AppointmentRepo {
+	return &appointmentRepo{db: db}
+}
+
+const appointmentColumns = `
+	appointment_id,
+	hospital_id,
+	doctor_id,
+	patient_id,
+	TO_CHAR(appointment_date, 'YYYY-MM-DD') AS appointment_date,
+	TO_CHAR(start_time, 'HH24:MI') AS start_time,
+	TO_CHAR(end_time, 'HH24:MI') AS end_time,
+	patient_name,
+	patient_phone,
+	notes,
+	status,
+	created_at,
+	updated_at
+`
+
+// SplitSession divides a session into consecutive slots. The slot length is
+// the session length divided by max_patients, or defaultSlotMinutes when the
+// session is uncapped. Cancelled sessions have no slots.
+func SplitSession(s DailySession) []Slot {
+	slots := []Slot{}
+	if s.Status == "cancelled" {
+		return slots
+	}
+	start, err1 := time.Parse("15:04", s.StartTime)
+	end, err2 := time.Parse("15:04", s.EndTime)
+	if err1 != nil || err2 != nil || !start.Before(end) {
+		return slots
+	}
+
+	length := defaultSlotMinutes * time.Minute
+	if s.MaxPatients > 0 {
+		length = (end.Sub(start) / time.Duration(s.MaxPatients)).Truncate(time.Minute)
+		if length < time.Minute {
+			length = time.Minute
+		}
+	}
+
+	for t := start; !t.Add(length).After(end); t = t.Add(length) {
+		slots = append(slots, Slot{
+			StartTime: t.Format("15:04"),
+			EndTime:   t.Add(length).Format("15:04"),
+			Room:      s.Room,
+			Available: true,
+		})
+	}
+	return slots
+}
+
+// lockRelation serializes bookings of one hospital-doctor pair for the rest
+// of the transaction.
+func lockRelation(tx *sqlx.Tx, hospitalID, doctorID int) error {
+	var one int
+	err := tx.Get(&one, `
+		SELECT 1 FROM hospital_doctor
+		WHERE hospital_id = $1 AND doctor_id = $2
+		FOR UPDATE
+	`, hospitalID, doctorID)
+	if errors.Is(err, sql.ErrNoRows) {
+		return ErrNoAffiliation
+	}
+	return err
+}
+
+// slotFree reports whether the slot is held neither by a live appointment
+// other than excludeAppointmentID nor by an open waitlist offer other than
+// excludeEntryID.
+func slotFree(tx *sqlx.Tx, hospitalID, doctorID int, date, startTime string, excludeAppointmentID, excludeEntryID int) (bool, error) {
+	var taken bool
+	err := tx.Get(&taken, `
+		SELECT EXISTS (
+		  SELECT 1 FROM appointments
+		  WHERE hospital_id = $1 AND doctor_id = $2
+		    AND appointment_date = $3 AND start_time = $4
+		    AND status <> 'cancelled' AND appointment_id <> $5
+		) OR EXISTS (
+		  SELECT 1 FROM waitlist_entries
+		  WHERE hospital_id = $1 AND doctor_id = $2
+		    AND waitlist_date = $3 AND offered_start_time = $4
+		    AND status = 'offered' AND offer_expires_at > NOW()
+		    AND entry_id <> $6
+		)
+	`, hospitalID, doctorID, date, startTime, excludeAppointmentID, excludeEntryID)
+	return !taken, err
+}
+
+// insertAppointment stores a booked appointment inside the caller's
+// transaction.
+func insertAppointment(tx *sqlx.Tx, a Appointment) (*Appointment, error) {
+	a.Status = StatusBooked
+	query := `
+		INSERT INTO appointments (
+		  hospital_id,
+		  doctor_id,
+		  patient_id,
+		  appointment_date,
+		  start_time,
+		  end_time,
+		  patient_name,
+		  patient_phone,
+		  notes,
+		  status
+		) VALUES (
+		  :hospital_id,
+		  :doctor_id,
+		  :patient_id,
+		  :appointment_date,
+		  :start_time,
+		  :end_time,
+		  :patient_name,
+		  :patient_phone,
+		  :notes,
+		  :status
+		)
+		RETURNING ` + appointmentColumns
+	rows, err := tx.NamedQuery(query, a)
+	if err != nil {
+		if isUniqueViolation(err) {
+			return nil, ErrSlotTaken
+		}
+		return nil, fmt.Errorf("error booking appointment: %w", err)
+	}
+	defer rows.Close()
+
+	if !rows.Next() {
+		return nil, errors.New("failed to return booked appointment data")
+	}
+	var created Appointment
+	if err := rows.StructScan(&created); err != nil {
+		return nil, err
+	}
+	return &created, nil
+}
+
+// Book stores an appointment in a free slot unless the patient, or the phone
+// number it is booked for, already has maxOpen upcoming bookings. Bookings of
+// the same patient or number are serialised on advisory locks, so concurrent
+// ones cannot both pass the check.
+func (r *appointmentRepo) Book(a Appointment, maxOpen int) (*Appointment, error) {
+	tx, err := r.db.Beginx()
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback()
+
+	if _, err := tx.Exec(`SELECT pg_advisory_xact_lock(hashtext('booking:patient:' || $1))`, a.PatientID); err != nil {
+		return nil, fmt.Errorf("error locking patient bookings: %w", err)
+	}
+	if _, err := tx.Exec(`SELECT pg_advisory_xact_lock(hashtext('booking:phone:' || $1))`, a.PatientPhone); err != nil {
+		return nil, fmt.Errorf("error locking phone bookings: %w", err)
+	}
+	var open int
+	err = tx.Get(&open, `
+		SELECT COUNT(*) FROM appointments
+		WHERE (patient_id = $1 OR patient_phone = $2)
+		  AND status = 'booked'
+		  AND appointment_date >= (NOW() AT TIME ZONE 'Asia/Dhaka')::date
+	`, a.PatientID, a.PatientPhone)
+	if err != nil {
+		return nil, fmt.Errorf("error counting open bookings: %w", err)
+	}
+	if open >= maxOpen {
+		return nil, ErrTooManyBookings
+	}
+
+	if err := lockRelation(tx, a.HospitalID, a.DoctorID); err != nil {
+		return nil, err
+	}
+	free, err := slotFree(tx, a.HospitalID, a.DoctorID, a.Date, a.StartTime, 0, 0)
+	if err != nil {
+		return nil, fmt.Errorf("error checking slot: %w", err)
+	}
+	if !free {
+		return nil, ErrSlotTaken
+	}
+
+	created, err := insertAppointment(tx, a)
+	if err != nil {
+		return nil, err
+	}
+	if err := tx.Commit(); err != nil {
+		return nil, err
+	}
+	return created, nil
+}
+
+func (r *appointmentRepo) Get(id int) (*Appointment, error) {
+	var a Appointment
+	err := r.db.Get(&a, `SELECT `+appointmentColumns+` FROM appointments WHERE appointment_id = $1`, id)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, ErrAppointmentNotFound
+		}
+		return nil, fmt.Errorf("error fetching appointment: %w", err)
+	}
+	return &a, nil
+}
+
+func (r *appointmentRepo) List(filter AppointmentFilter, offset, limit int) ([]Appointment, int, error) {
+	list := []Appointment{}
+	conditions := []string{"TRUE"}
+	args := []interface{}{}
+	if filter.HospitalID > 0 {
+		args = append(args, filter.HospitalID)
+		conditions = append(conditions, fmt.Sprintf("hospital_id = $%d", len(args)))
+	}
+	if filter.DoctorID > 0 {
+		args = append(args, filter.DoctorID)
+		conditions = append(conditions, fmt.Sprintf("doctor_id = $%d", len(args)))
+	}
+	if filter.PatientID > 0 {
+		args = append(args, filter.PatientID)
+		conditions = append(conditions, fmt.Sprintf("patient_id = $%d", len(args)))
+	}
+	if filter.Date != "" {
+		args = append(args, filter.Date)
+		conditions = append(conditions, fmt.Sprintf("appointment_date = $%d", len(args)))
+	}
+	if filter.Status != "" {
+		args = append(args, filter.Status)
+		conditions = append(conditions, fmt.Sprintf("status = $%d", len(args)))
+	}
+	where := " WHERE " + strings.Join(conditions, " AND ")
+
+	var total int
+	if err := r.db.Get(&total, `SELECT COUNT(*) FROM appointments`+where, args...); err != nil {
+		return nil, 0, fmt.Errorf("error counting appointments: %w", err)
+	}
+
+	query := fmt.Sprintf(`
+		SELECT %s
+		FROM appointments
+		%s
+		ORDER BY appointment_date, start_time
+		LIMIT $%d OFFSET $%d
+	`, appointmentColumns, where, len(args)+1, len(args)+2)
+	if err := r.db.Select(&list, query, append(args, limit, offset)...); err != nil {
+		return nil, 0, fmt.Errorf("error fetching appointments: %w", err)
+	}
+	return list, total, nil
+}
+
+func (r *appointmentRepo) BookedStartTimes(hospitalID, doctorID int, date string) ([]string, error) {
+	var times []string
+	query := `
+		SELECT TO_CHAR(start_time, 'HH24:MI')
+		FROM appointments
+		WHERE hospital_id = $1 AND doctor_id = $2 AND appointment_date = $3
+		  AND status <> 'cancelled'
+		UNION
+		SELECT TO_CHAR(offered_start_time, 'HH24:MI')
+		FROM waitlist_entries
+		WHERE hospital_id = $1 AND doctor_id = $2 AND waitlist_date = $3
+		  AND status = 'offered' AND offer_expires_at > NOW()
+	`
+	if err := r.db.Select(&times, query, hospitalID, doctorID, date); err != nil {
+		return nil, fmt.Errorf("error fetching booked slots: %w", err)
+	}
+	return times, nil
+}
+
+// lockAppointment loads an appointment with a row lock held until the end of
+// the transaction.
+func lockAppointment(tx *sqlx.Tx, id int) (*Appointment, error) {
+	var a Appointment
+	err := tx.Get(&a, `SELECT `+appointmentColumns+` FROM appointments WHERE appointment_id = $1 FOR UPDATE`, id)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, ErrAppointmentNotFound
+		}
+		return nil, fmt.Errorf("error fetching appointment: %w", err)
+	}
+	return &a, nil
+}
+
+func (r *appointmentRepo) Reschedule(id int, date, startTime, endTime string) (*Appointment, error) {
+	tx, err := r.db.Beginx()
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback()
+
+	current, err := lockAppointment(tx, id)
+	if err != nil {
+		return nil, err
+	}
+	if current.Status != StatusBooked {
+		return nil, ErrInvalidTransition
+	}
+	if err := lockRelation(tx, current.HospitalID, current.DoctorID); err != nil {
+		return nil, err
+	}
+	free, err := slotFree(tx, current.HospitalID, current.DoctorID, date, startTime, id, 0)
+	if err != nil {
+		return nil, fmt.Errorf("error checking slot: %w", err)
+	}
+	if !free {
+		return nil, ErrSlotTaken
+	}
+
+	var updated Appointment
+	err = tx.Get(&updated, `
+		UPDATE appointments
+		SET appointment_date = $2, start_time = $3, end_time = $4, updated_at = NOW()
+		WHERE appointment_id = $1
+		RETURNING `+appointmentColumns, id, date, startTime, endTime)
+	if err != nil {
+		if isUniqueViolation(err) {
+			return nil, ErrSlotTaken
+		}
+		return nil, fmt.Errorf("error rescheduling appointment: %w", err)
+	}
+	// The old slot is free now; offer it to the waitlist.
+	if err := offerNextWaitlisted(tx, current.HospitalID, current.DoctorID, current.Date, current.StartTime, current.EndTime); err != nil {
+		return nil, err
+	}
+	if err := tx.Commit(); err != nil {
+		return nil, err
+	}
+	return &updated, nil
+}
+
+func (r *appointmentRepo) UpdateStatus(id int, status string) (*Appointment, error) {
+	tx, err := r.db.Beginx()
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback()
+
+	current, err := lockAppointment(tx, id)
+	if err != nil {
+		return nil, err
+	}
+	allowed := false
+	for _, next := range appointmentTransitions[current.Status] {
+		if next == status {
+			allowed = true
+			break
+		}
+	}
+	if !allowed {
+		return nil, ErrInvalidTransition
+	}
+
+	var updated Appointment
+	err = tx.Get(&updated, `
+		UPDATE appointments
+		SET status = $2, updated_at = NOW()
+		WHERE appointment_id = $1
+		RETURNING `+appointmentColumns, id, status)
+	if err != nil {
+		return nil, fmt.Errorf("error updating appointment status: %w", err)
+	}
+	if status == StatusCancelled {
+		if err := offerNextWaitlisted(tx, current.HospitalID, current.DoctorID, current.Date, current.StartTime, current.EndTime); err != nil {
+			return nil, err
+		}
+	}
+	if err := tx.Commit(); err != nil {
+		return nil, err
+	}
+	return &updated, nil
+}
@@ -0,0 +1,87 @@
+package repo
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestSplitSession(t *testing.T) {
+	tests := []struct {
+		name    string
+		session DailySession
+		want    []Slot
+	}{
+		{
+			name:    "uncapped session uses the default slot length",
+			session: DailySession{StartTime: "17:00", EndTime: "18:00", Room: "204"},
+			want: []Slot{
+				{StartTime: "17:00", EndTime: "17:15", Room: "204", Available: true},
+				{StartTime: "17:15", EndTime: "17:30", Room: "204", Available: true},
+				{StartTime: "17:30", EndTime: "17:45", Room: "204", Available: true},
+				{StartTime: "17:45", EndTime: "18:00", Room: "204", Available: true},
+			},
+		},
+		{
+			name:    "max_patients divides the session",
+			session: DailySession{StartTime: "09:00", EndTime: "10:00", MaxPatients: 3},
+			want: []Slot{
+				{StartTime: "09:00", EndTime: "09:20", Available: true},
+				{StartTime: "09:20", EndTime: "09:40", Available: true},
+				{StartTime: "09:40", EndTime: "10:00", Available: true},
+			},
+		},
+		{
+			name:    "slot length is truncated to whole minutes",
+			session: DailySession{StartTime: "09:00", EndTime: "09:10", MaxPatients: 3},
+			want: []Slot{
+				{StartTime: "09:00", EndTime: "09:03", Available: true},
+				{StartTime: "09:03", EndTime: "09:06", Available: true},
+				{StartTime: "09:06", EndTime: "09:09", Available: true},
+			},
+		},
+		{
+			name:    "slots are at least a minute long",
+			session: DailySession{StartTime: "09:00", EndTime: "09:02", MaxPatients: 10},
+			want: []Slot{
+				{StartTime: "09:00", EndTime: "09:01", Available: true},
+				{StartTime: "09:01", EndTime: "09:02", Available: true},
+			},
+		},
+		{
+			name:    "a trailing partial slot is dropped",
+			session: DailySession{StartTime: "10:00", EndTime: "10:20"},
+			want: []Slot{
+				{StartTime: "10:00", EndTime: "10:15", Available: true},
+			},
+		},
+		{
+			name:    "cancelled session",
+			session: DailySession{StartTime: "17:00", EndTime: "18:00", Status: "cancelled"},
+			want:    []Slot{},
+		},
+		{
+			name:    "end before start",
+			session: DailySession{StartTime: "18:00", EndTime: "17:00"},
+			want:    []Slot{},
+		},
+		{
+			name:    "empty session",
+			session: DailySession{StartTime: "17:00", EndTime: "17:00"},
+			want:    []Slot{},
+		},
+		{
+			name:    "invalid time",
+			session: DailySession{StartTime: "5pm", EndTime: "18:00"},
+			want:    []Slot{},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := SplitSession(tt.session)
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("SplitSession() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
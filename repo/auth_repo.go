@@ -0,0 +1,185 @@
+package repo
+
+import (
+	"database/sql"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/jmoiron/sqlx"
+)
+
+var (
+	ErrOTPNotFound          = errors.New("no active OTP for this number")
+	ErrRefreshTokenNotFound = errors.New("refresh token is invalid, expired or revoked")
+	ErrOTPThrottled         = errors.New("too many OTP requests for this number")
+	ErrOTPAttemptsExhausted = errors.New("too many wrong attempts for this OTP")
+)
+
+type OTPCode struct {
+	OTPID       int       `db:"otp_id"`
+	PhoneNumber string    `db:"phone_number"`
+	CodeHash    string    `db:"code_hash"`
+	Attempts    int       `db:"attempts"`
+	ExpiresAt   time.Time `db:"expires_at"`
+}
+
+// AuthRepo stores OTP login codes and refresh tokens. Codes and tokens are
+// only ever persisted as hashes.
+type AuthRepo interface {
+	CreateOTP(phone, codeHash string, expiresAt time.Time, resendInterval time.Duration, hourlyLimit int) error
+	LatestActiveOTP(phone string) (*OTPCode, error)
+	UseOTPAttempt(otpID, maxAttempts int) error
+	ConsumeOTP(otpID int) error
+	CreateRefreshToken(patientID int, tokenHash string, expiresAt time.Time) error
+	RotateRefreshToken(oldHash, newHash string, expiresAt time.Time) (int, error)
+	RevokeRefreshToken(tokenHash string) error
+}
+
+type authRepo struct {
+	db *sqlx.DB
+}
+
+func NewAuthRepo(db *sqlx.DB) AuthRepo {
+	return &authRepo{db: db}
+}
+
+// CreateOTP stores a code unless the number had one within resendInterval or
+// hourlyLimit in the last hour. Requests for the same number are serialised on
+// an advisory lock, so concurrent ones cannot both pass the check.
+func (r *authRepo) CreateOTP(phone, codeHash string, expiresAt time.Time, resendInterval time.Duration, hourlyLimit int) error {
+	tx, err := r.db.Beginx()
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	if _, err := tx.Exec(`SELECT pg_advisory_xact_lock(hashtext('otp:' || $1))`, phone); err != nil {
+		return fmt.Errorf("error locking OTP number: %w", err)
+	}
+	var recent struct {
+		LastInterval int `db:"last_interval"`
+		LastHour     int `db:"last_hour"`
+	}
+	err = tx.Get(&recent, `
+		SELECT COUNT(*) FILTER (WHERE created_at >= NOW() - $2 * INTERVAL '1 second') AS last_interval,
+		       COUNT(*) AS last_hour
+		FROM otp_codes
+		WHERE phone_number = $1 AND created_at >= NOW() - INTERVAL '1 hour'
+	`, phone, resendInterval.Seconds())
+	if err != nil {
+		return fmt.Errorf("error counting OTPs: %w", err)
+	}
+	if recent.LastInterval > 0 || recent.LastHour >= hourlyLimit {
+		return ErrOTPThrottled
+	}
+
+	_, err = tx.Exec(`
+		INSERT INTO otp_codes (phone_number, code_hash, expires_at)
+		VALUES ($1, $2, $3)
+	`, phone, codeHash, expiresAt)
+	if err != nil {
+		return fmt.Errorf("error storing OTP: %w", err)
+	}
+	return tx.Commit()
+}
+
+func (r *authRepo) LatestActiveOTP(phone string) (*OTPCode, error) {
+	var otp OTPCode
+	err := r.db.Get(&otp, `
+		SELECT otp_id, phone_number, code_hash, attempts, expires_at
+		FROM otp_codes
+		WHERE phone_number = $1 AND consumed_at IS NULL AND expires_at > NOW()
+		ORDER BY created_at DESC
+		LIMIT 1
+	`, phone)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, ErrOTPNotFound
+		}
+		return nil, fmt.Errorf("error fetching OTP: %w", err)
+	}
+	return &otp, nil
+}
+
+// UseOTPAttempt counts a verification attempt against the code before it is
+// checked. Once maxAttempts have been used it returns ErrOTPAttemptsExhausted,
+// however many requests race for the last one.
+func (r *authRepo) UseOTPAttempt(otpID, maxAttempts int) error {
+	var attempts int
+	err := r.db.Get(&attempts, `
+		UPDATE otp_codes SET attempts = attempts + 1
+		WHERE otp_id = $1 AND attempts < $2
+		RETURNING attempts
+	`, otpID, maxAttempts)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return ErrOTPAttemptsExhausted
+		}
+		return fmt.Errorf("error recording OTP attempt: %w", err)
+	}
+	return nil
+}
+
+func (r *authRepo) ConsumeOTP(otpID int) error {
+	res, err := r.db.Exec(`
+		UPDATE otp_codes SET consumed_at = NOW()
+		WHERE otp_id = $1 AND consumed_at IS NULL
+	`, otpID)
+	if err != nil {
+		return err
+	}
+	rows, _ := res.RowsAffected()
+	if rows == 0 {
+		return ErrOTPNotFound
+	}
+	return nil
+}
+
+func (r *authRepo) CreateRefreshToken(patientID int, tokenHash string, expiresAt time.Time) error {
+	_, err := r.db.Exec(`
+		INSERT INTO refresh_tokens (patient_id, token_hash, expires_at)
+		VALUES ($1, $2, $3)
+	`, patientID, tokenHash, expiresAt)
+	return err
+}
+
+// RotateRefreshToken revokes the old token and stores its replacement in one
+// transaction, returning the owning patient's ID.
+func (r *authRepo) RotateRefreshToken(oldHash, newHash string, expiresAt time.Time) (int, error) {
+	tx, err := r.db.Beginx()
+	if err != nil {
+		return 0, err
+	}
+	defer tx.Rollback()
+
+	var patientID int
+	err = tx.Get(&patientID, `
+		UPDATE refresh_tokens SET revoked_at = NOW()
+		WHERE token_hash = $1 AND revoked_at IS NULL AND expires_at > NOW()
+		RETURNING patient_id
+	`, oldHash)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return 0, ErrRefreshTokenNotFound
+		}
+		return 0, fmt.Errorf("error revoking refresh token: %w", err)
+	}
+
+	_, err = tx.Exec(`
+		INSERT INTO refresh_tokens (patient_id, token_hash, expires_at)
+		VALUES ($1, $2, $3)
+	`, patientID, newHash, expiresAt)
+	if err != nil {
+		return 0, fmt.Errorf("error storing refresh token: %w", err)
+	}
+	return patientID, tx.Commit()
+}
+
+func (r *authRepo) RevokeRefreshToken(tokenHash string) error {
+	_, err := r.db.Exec(`
+		UPDATE refresh_tokens SET revoked_at = NOW()
+		WHERE token_hash = $1 AND revoked_at IS NULL
+	`, tokenHash)
+	return err
+}
@@ -0,0 +1,137 @@
+package repo
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/jmoiron/sqlx"
+	"github.com/lib/pq"
+)
+
+// Ward types with a tracked bed inventory.
+const (
+	WardGeneral = "general"
+	WardCabin   = "cabin"
+	WardICU     = "icu"
+	WardCCU     = "ccu"
+	WardNICU    = "nicu"
+)
+
+var WardTypes = []string{WardGeneral, WardCabin, WardICU, WardCCU, WardNICU}
+
+// BedDataStaleAfter is how old a bed count can get before it is flagged as
+// stale.
+const BedDataStaleAfter = 6 * time.Hour
+
+type BedInventory struct {
+	HospitalID int       `json:"hospital_id" db:"hospital_id"`
+	WardType   string    `json:"ward_type" db:"ward_type"`
+	Total      int       `json:"total_beds" db:"total_beds"`
+	Occupied   int       `json:"occupied_beds" db:"occupied_beds"`
+	Free       int       `json:"free_beds" db:"free_beds"`
+	UpdatedAt  time.Time `json:"updated_at" db:"updated_at"`
+	Stale      bool      `json:"stale" db:"stale"`
+}
+
+// BedAvailability is a ward's inventory together with its hospital.
+type BedAvailability struct {
+	BedInventory
+	HospitalName string   `json:"hospital_name" db:"hospital_name"`
+	Address      string   `json:"address" db:"address"`
+	PhoneNumber  string   `json:"phone_number" db:"phone_number"`
+	Latitude     *float64 `json:"latitude" db:"latitude"`
+	Longitude    *float64 `json:"longitude" db:"longitude"`
+}
+
+type BedRepo interface {
+	Upsert(b BedInventory, staffKeyID int) (*BedInventory, error)
+	ListByHospital(hospitalID int) ([]BedInventory, error)
+	ListAvailability(ward string, offset, limit int) ([]BedAvailability, int, error)
+}
+
+type bedRepo struct {
+	db *sqlx.DB
+}
+
+func NewBedRepo(db *sqlx.DB) BedRepo {
+	return &bedRepo{db: db}
+}
+
+// bedColumns selects an inventory row from alias b; $1 is the staleness
+// threshold in seconds.
+const bedColumns = `
+	b.hospital_id,
+	b.ward_type,
+	b.total_beds,
+	b.occupied_beds,
+	b.total_beds - b.occupied_beds AS free_beds,
+	b.updated_at,
+	b.updated_at < NOW() - $1 * INTERVAL '1 second' AS stale
+`
+
+func staleSeconds() int {
+	return int(BedDataStaleAfter.Seconds())
+}
+
+func (r *bedRepo) Upsert(b BedInventory, staffKeyID int) (*BedInventory, error) {
+	var saved BedInventory
+	err := r.db.Get(&saved, `
+		INSERT INTO hospital_beds AS b (hospital_id, ward_type, total_beds, occupied_beds, updated_by_key_id)
+		VALUES ($2, $3, $4, $5, $6)
+		ON CONFLICT (hospital_id, ward_type) DO UPDATE
+		SET total_beds = EXCLUDED.total_beds,
+		    occupied_beds = EXCLUDED.occupied_beds,
+		    updated_by_key_id = EXCLUDED.updated_by_key_id,
+		    updated_at = NOW()
+		RETURNING `+bedColumns,
+		staleSeconds(), b.HospitalID, b.WardType, b.Total, b.Occupied, staffKeyID)
+	if err != nil {
+		if isForeignKeyViolation(err) {
+			return nil, ErrNotFound
+		}
+		return nil, fmt.Errorf("error saving bed inventory: %w", err)
+	}
+	return &saved, nil
+}
+
+func (r *bedRepo) ListByHospital(hospitalID int) ([]BedInventory, error) {
+	list := []BedInventory{}
+	err := r.db.Select(&list, `
+		SELECT `+bedColumns+`
+		FROM hospital_beds b
+		WHERE b.hospital_id = $2
+		ORDER BY array_position($3::text[], b.ward_type::text)
+	`, staleSeconds(), hospitalID, pq.Array(WardTypes))
+	if err != nil {
+		return nil, fmt.Errorf("error fetching bed inventory: %w", err)
+	}
+	return list, nil
+}
+
+// ListAvailability lists the hospitals that track the ward, most free beds
+// first.
+func (r *bedRepo) ListAvailability(ward string, offset, limit int) ([]BedAvailability, int, error) {
+	var total int
+	if err := r.db.Get(&total, `SELECT COUNT(*) FROM hospital_beds WHERE ward_type = $1`, ward); err != nil {
+		return nil, 0, fmt.Errorf("error counting bed availability: %w", err)
+	}
+
+	list := []BedAvailability{}
+	err := r.db.Select(&list, `
+		SELECT `+bedColumns+`,
+		  h.name AS hospital_name,
+		  COALESCE(h.address, '') AS address,
+		  COALESCE(h.phone_number, '') AS phone_number,
+		  h.latitude,
+		  h.longitude
+		FROM hospital_beds b
+		JOIN hospitals h ON h.hospital_id = b.hospital_id
+		WHERE b.ward_type = $2
+		ORDER BY free_beds DESC, b.updated_at DESC, b.hospital_id
+		LIMIT $3 OFFSET $4
+	`, staleSeconds(), ward, limit, offset)
+	if err != nil {
+		return nil, 0, fmt.Errorf("error fetching bed availability: %w", err)
+	}
+	return list, total, nil
+}
@@ -0,0 +1,140 @@
+package repo
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/jmoiron/sqlx"
+)
+
+var BloodGroups = []string{"A+", "A-", "B+", "B-", "AB+", "AB-", "O+", "O-"}
+
+var BloodComponents = []string{"whole_blood", "red_cells", "platelets", "plasma", "cryo"}
+
+type BloodStock struct {
+	HospitalID     int       `json:"hospital_id" db:"hospital_id"`
+	BloodGroup     string    `json:"blood_group" db:"blood_group"`
+	Component      string    `json:"component" db:"component"`
+	UnitsAvailable int       `json:"units_available" db:"units_available"`
+	UpdatedAt      time.Time `json:"updated_at" db:"updated_at"`
+}
+
+// BloodBank is a hospital's stock of a blood group and component, with the
+// distance from the searcher when a location was given.
+type BloodBank struct {
+	BloodStock
+	HospitalName string   `json:"hospital_name" db:"hospital_name"`
+	Address      string   `json:"address" db:"address"`
+	PhoneNumber  string   `json:"phone_number" db:"phone_number"`
+	Latitude     *float64 `json:"latitude" db:"latitude"`
+	Longitude    *float64 `json:"longitude" db:"longitude"`
+	DistanceKM   *float64 `json:"distance_km,omitempty" db:"distance_km"`
+}
+
+// BloodBankFilter selects the stock to look for. Component is optional; when
+// Lat and Lng are set results are ordered by distance from that point.
+type BloodBankFilter struct {
+	BloodGroup string
+	Component  string
+	Lat        *float64
+	Lng        *float64
+}
+
+type BloodRepo interface {
+	Upsert(s BloodStock, staffKeyID int) (*BloodStock, error)
+	ListByHospital(hospitalID int) ([]BloodStock, error)
+	ListBanks(filter BloodBankFilter, offset, limit int) ([]BloodBank, int, error)
+}
+
+type bloodRepo struct {
+	db *sqlx.DB
+}
+
+func NewBloodRepo(db *sqlx.DB) BloodRepo {
+	return &bloodRepo{db: db}
+}
+
+const bloodStockColumns = `b.hospital_id, b.blood_group, b.component, b.units_available, b.updated_at`
+
+func (r *bloodRepo) Upsert(s BloodStock, staffKeyID int) (*BloodStock, error) {
+	var saved BloodStock
+	err := r.db.Get(&saved, `
+		INSERT INTO hospital_blood_inventory AS b (hospital_id, blood_group, component, units_available, updated_by_key_id)
+		VALUES ($1, $2, $3, $4, $5)
+		ON CONFLICT (hospital_id, blood_group, component) DO UPDATE
+		SET units_available = EXCLUDED.units_available,
+		    updated_by_key_id = EXCLUDED.updated_by_key_id,
+		    updated_at = NOW()
+		RETURNING `+bloodStockColumns,
+		s.HospitalID, s.BloodGroup, s.Component, s.UnitsAvailable, staffKeyID)
+	if err != nil {
+		if isForeignKeyViolation(err) {
+			return nil, ErrNotFound
+		}
+		return nil, fmt.Errorf("error saving blood stock: %w", err)
+	}
+	return &saved, nil
+}
+
+func (r *bloodRepo) ListByHospital(hospitalID int) ([]BloodStock, error) {
+	list := []BloodStock{}
+	err := r.db.Select(&list, `
+		SELECT `+bloodStockColumns+`
+		FROM hospital_blood_inventory b
+		WHERE b.hospital_id = $1
+		ORDER BY b.blood_group, b.component
+	`, hospitalID)
+	if err != nil {
+		return nil, fmt.Errorf("error fetching blood stock: %w", err)
+	}
+	return list, nil
+}
+
+// ListBanks lists the hospitals that have the blood group in stock.
+func (r *bloodRepo) ListBanks(filter BloodBankFilter, offset, limit int) ([]BloodBank, int, error) {
+	conditions := []string{"b.units_available > 0", "b.blood_group = $1"}
+	args := []interface{}{filter.BloodGroup}
+	if filter.Component != "" {
+		args = append(args, filter.Component)
+		conditions = append(conditions, fmt.Sprintf("b.component = $%d", len(args)))
+	}
+	where := "WHERE " + strings.Join(conditions, " AND ")
+
+	var total int
+	if err := r.db.Get(&total, `SELECT COUNT(*) FROM hospital_blood_inventory b `+where, args...); err != nil {
+		return nil, 0, fmt.Errorf("error counting blood banks: %w", err)
+	}
+
+	distance := "NULL::float8"
+	order := "b.units_available DESC, b.updated_at DESC"
+	if filter.Lat != nil && filter.Lng != nil {
+		args = append(args, *filter.Lat, *filter.Lng)
+		lat, lng := len(args)-1, len(args)
+		distance = fmt.Sprintf(`6371 * ACOS(LEAST(1, GREATEST(-1,
+			COS(RADIANS($%[1]d)) * COS(RADIANS(h.latitude)) * COS(RADIANS(h.longitude) - RADIANS($%[2]d))
+			+ SIN(RADIANS($%[1]d)) * SIN(RADIANS(h.latitude))
+		)))`, lat, lng)
+		order = "distance_km NULLS LAST, b.units_available DESC"
+	}
+
+	list := []BloodBank{}
+	query := fmt.Sprintf(`
+		SELECT %s,
+		  h.name AS hospital_name,
+		  COALESCE(h.address, '') AS address,
+		  COALESCE(h.phone_number, '') AS phone_number,
+		  h.latitude,
+		  h.longitude,
+		  %s AS distance_km
+		FROM hospital_blood_inventory b
+		JOIN hospitals h ON h.hospital_id = b.hospital_id
+		%s
+		ORDER BY %s, b.hospital_id, b.component
+		LIMIT $%d OFFSET $%d
+	`, bloodStockColumns, distance, where, order, len(args)+1, len(args)+2)
+	if err := r.db.Select(&list, query, append(args, limit, offset)...); err != nil {
+		return nil, 0, fmt.Errorf("error fetching blood banks: %w", err)
+	}
+	return list, total, nil
+}
@@ -0,0 +1,217 @@
+package repo
+
+import (
+	"database/sql"
+	"errors"
+	"fmt"
+	"regexp"
+	"strings"
+	"time"
+
+	"github.com/jmoiron/sqlx"
+	"github.com/lib/pq"
+)
+
+var (
+	ErrQualificationNotFound = errors.New("qualification not found")
+	ErrMissingBMDC           = errors.New("doctor has no BMDC registration number")
+	ErrInvalidVerification   = errors.New("verification status cannot change this way")
+)
+
+// Doctor verification statuses. Only verified doctors are listed in public
+// search.
+const (
+	VerificationUnverified = "unverified"
+	VerificationPending    = "pending"
+	VerificationVerified   = "verified"
+	VerificationRejected   = "rejected"
+)
+
+var VerificationStatuses = []string{VerificationUnverified, VerificationPending, VerificationVerified, VerificationRejected}
+
+var bmdcPattern = regexp.MustCompile(`^[A-Z]{0,2}-?[0-9]{1,7}$`)
+
+// NormalizeBMDC upper-cases a BMDC registration number and strips spaces. It
+// reports whether the result looks like a registration number, e.g. A-12345.
+func NormalizeBMDC(reg string) (string, bool) {
+	reg = strings.ToUpper(strings.ReplaceAll(strings.TrimSpace(reg), " ", ""))
+	return reg, bmdcPattern.MatchString(reg)
+}
+
+// Qualification is a degree or diploma held by a doctor.
+type Qualification struct {
+	QualificationID int       `json:"qualification_id" db:"qualification_id"`
+	DoctorID        int       `json:"doctor_id" db:"doctor_id"`
+	Degree          string    `json:"degree" db:"degree"`
+	Institution     string    `json:"institution" db:"institution"`
+	Year            *int      `json:"year" db:"year_awarded"`
+	CreatedAt       time.Time `json:"created_at" db:"created_at"`
+}
+
+// CredentialRepo manages a doctor's qualifications and the verification of
+// their BMDC registration.
+type CredentialRepo interface {
+	ListQualifications(doctorID int) ([]Qualification, error)
+	ListQualificationsOf(doctorIDs []int) ([]Qualification, error)
+	AddQualification(q Qualification) (*Qualification, error)
+	DeleteQualification(doctorID, qualificationID int) error
+	RequestVerification(doctorID int) error
+	ReviewVerification(doctorID int, status, note string) error
+}
+
+type credentialRepo struct {
+	db *sqlx.DB
+}
+
+func NewCredentialRepo(db *sqlx.DB) CredentialRepo {
+	return &credentialRepo{db: db}
+}
+
+// listQualifications is shared with doctorRepo.Get, which embeds the
+// qualifications in the single-doctor response.
+func listQualifications(db sqlx.Queryer, doctorID int) ([]Qualification, error) {
+	list := []Qualification{}
+	err := sqlx.Select(db, &list, `
+		SELECT * FROM doctor_qualifications
+		WHERE doctor_id = $1
+		ORDER BY year_awarded DESC NULLS LAST, qualification_id
+	`, doctorID)
+	if err != nil {
+		return nil, fmt.Errorf("error fetching qualifications: %w", err)
+	}
+	return list, nil
+}
+
+func (r *credentialRepo) ListQualifications(doctorID int) ([]Qualification, error) {
+	return listQualifications(r.db, doctorID)
+}
+
+// ListQualificationsOf returns the qualifications of several doctors, in
+// ListQualifications order for each doctor.
+func (r *credentialRepo) ListQualificationsOf(doctorIDs []int) ([]Qualification, error) {
+	list := []Qualification{}
+	err := r.db.Select(&list, `
+		SELECT * FROM doctor_qualifications
+		WHERE doctor_id = ANY($1)
+		ORDER BY doctor_id, year_awarded DESC NULLS LAST, qualification_id
+	`, pq.Array(doctorIDs))
+	if err != nil {
+		return nil, fmt.Errorf("error fetching qualifications: %w", err)
+	}
+	return list, nil
+}
+
+func (r *credentialRepo) AddQualification(q Qualification) (*Qualification, error) {
+	var created Qualification
+	err := r.db.Get(&created, `
+		INSERT INTO doctor_qualifications (doctor_id, degree, institution, year_awarded)
+		VALUES ($1, $2, $3, $4)
+		RETURNING *
+	`, q.DoctorID, q.Degree, q.Institution, q.Year)
+	if err != nil {
+		if isForeignKeyViolation(err) {
+			return nil, ErrDoctorNotFound
+		}
+		return nil, fmt.Errorf("error adding qualification: %w", err)
+	}
+	return &created, nil
+}
+
+func (r *credentialRepo) DeleteQualification(doctorID, qualificationID int) error {
+	res, err := r.db.Exec(`
+		DELETE FROM doctor_qualifications
+		WHERE doctor_id = $1 AND qualification_id = $2
+	`, doctorID, qualificationID)
+	if err != nil {
+		return fmt.Errorf("error deleting qualification: %w", err)
+	}
+	if rows, _ := res.RowsAffected(); rows == 0 {
+		return ErrQualificationNotFound
+	}
+	return nil
+}
+
+// lockDoctorVerification locks the doctor row and returns its verification
+// status and BMDC number.
+func lockDoctorVerification(tx *sqlx.Tx, doctorID int) (string, *string, error) {
+	var current struct {
+		Status    string  `db:"verification_status"`
+		BMDCRegNo *string `db:"bmdc_reg_no"`
+	}
+	err := tx.Get(&current, `
+		SELECT verification_status, bmdc_reg_no FROM doctors
+		WHERE doctor_id = $1
+		FOR UPDATE
+	`, doctorID)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return "", nil, ErrDoctorNotFound
+		}
+		return "", nil, fmt.Errorf("error fetching doctor: %w", err)
+	}
+	return current.Status, current.BMDCRegNo, nil
+}
+
+// RequestVerification queues an unverified or rejected doctor for review.
+func (r *credentialRepo) RequestVerification(doctorID int) error {
+	tx, err := r.db.Beginx()
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	status, bmdc, err := lockDoctorVerification(tx, doctorID)
+	if err != nil {
+		return err
+	}
+	if bmdc == nil || *bmdc == "" {
+		return ErrMissingBMDC
+	}
+	if status != VerificationUnverified && status != VerificationRejected {
+		return ErrInvalidVerification
+	}
+
+	_, err = tx.Exec(`
+		UPDATE doctors
+		SET verification_status = $2, verification_note = '', verification_requested_at = NOW(), updated_at = NOW()
+		WHERE doctor_id = $1
+	`, doctorID, VerificationPending)
+	if err != nil {
+		return fmt.Errorf("error requesting verification: %w", err)
+	}
+	return tx.Commit()
+}
+
+// ReviewVerification records a reviewer's decision on a pending doctor. A
+// verified doctor can also be rejected later, e.g. when their registration
+// is found to be lapsed.
+func (r *credentialRepo) ReviewVerification(doctorID int, status, note string) error {
+	tx, err := r.db.Beginx()
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	current, _, err := lockDoctorVerification(tx, doctorID)
+	if err != nil {
+		return err
+	}
+	allowed := current == VerificationPending ||
+		(current == VerificationVerified && status == VerificationRejected)
+	if !allowed {
+		return ErrInvalidVerification
+	}
+
+	_, err = tx.Exec(`
+		UPDATE doctors
+		SET verification_status = $2,
+		    verification_note = $3,
+		    verified_at = CASE WHEN $4 THEN NOW() END,
+		    updated_at = NOW()
+		WHERE doctor_id = $1
+	`, doctorID, status, note, status == VerificationVerified)
+	if err != nil {
+		return fmt.Errorf("error reviewing verification: %w", err)
+	}
+	return tx.Commit()
+}
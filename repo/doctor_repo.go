@@ -1,37 +1,106 @@
 package repo
 
 import (
+	"database/sql"
 	"errors"
 	"fmt"
+	"strings"
 	"time"
 
 	"github.com/jmoiron/sqlx"
+	"github.com/lib/pq"
 )
 
 var (
 	ErrDoctorNotFound = errors.New("doctor not found")
 	ErrFailedToUpdate = errors.New("failed to update doctor")
 	ErrFailedToDelete = errors.New("failed to delete doctor")
+	ErrDuplicateBMDC  = errors.New("BMDC registration number already exists")
 )
 
 type Doctor struct {
-	DoctorID        int       `json:"doctor_id" db:"doctor_id"`
-	Name            string    `json:"name" db:"name"`
-	Specialty       string    `json:"specialty" db:"specialty"`
-	YearsExperience int       `json:"years_experience" db:"years_experience"`
-	PhoneNumber     string    `json:"phone_number" db:"phone_number"`
-	Email           string    `json:"email" db:"email"`
-	ImageURL        string    `json:"image_url" db:"image_url"`
-	CreatedAt       time.Time `json:"created_at" db:"created_at"`
-	UpdatedAt       time.Time `json:"updated_at" db:"updated_at"`
+	DoctorID        int            `json:"doctor_id" db:"doctor_id"`
+	Name            string         `json:"name" db:"name"`
+	Specialty       string         `json:"specialty" db:"specialty"`
+	YearsExperience int            `json:"years_experience" db:"years_experience"`
+	PhoneNumber     string         `json:"phone_number" db:"phone_number"`
+	Email           string         `json:"email" db:"email"`
+	ImageURL        string         `json:"image_url" db:"image_url"`
+	Images          *ImageVariants `json:"images,omitempty" db:"-"` // set by the handlers for uploaded photos
+	RatingAvg       float64        `json:"rating_avg" db:"rating_avg"`
+	RatingCount     int            `json:"rating_count" db:"rating_count"`
+	CreatedAt       time.Time      `json:"created_at" db:"created_at"`
+	UpdatedAt       time.Time      `json:"updated_at" db:"updated_at"`
+
+	BMDCRegNo *string        `json:"bmdc_reg_no" db:"bmdc_reg_no"`
+	Gender    string         `json:"gender" db:"gender"`
+	Languages pq.StringArray `json:"languages" db:"languages"`
+
+	// Verification fields are only changed through the verification workflow.
+	VerificationStatus      string     `json:"verification_status" db:"verification_status"`
+	VerificationNote        string     `json:"verification_note,omitempty" db:"verification_note"`
+	VerificationRequestedAt *time.Time `json:"verification_requested_at,omitempty" db:"verification_requested_at"`
+	VerifiedAt              *time.Time `json:"verified_at,omitempty" db:"verified_at"`
+
+	// Qualifications are only populated by Get.
+	Qualifications []Qualification `json:"qualifications,omitempty" db:"-"`
+
+	// MinFee is the lowest known new-patient fee among the affiliations that
+	// match the listing filter. Only populated by List.
+	MinFee *float64 `json:"min_fee,omitempty" db:"min_fee"`
+}
+
+// NearbyDoctor is a doctor paired with the closest affiliated hospital.
+type NearbyDoctor struct {
+	Doctor
+	HospitalID      int     `json:"hospital_id" db:"hospital_id"`
+	HospitalName    string  `json:"hospital_name" db:"hospital_name"`
+	HospitalAddress string  `json:"hospital_address" db:"hospital_address"`
+	DistanceKM      float64 `json:"distance_km" db:"distance_km"`
+}
+
+// DoctorFilter narrows down and orders List results.
+type DoctorFilter struct {
+	Search    string   // name or specialty
+	Name      string   // name only
+	Specialty string   // specialty only, expanded through the taxonomy
+	Status    string   // verification status; empty matches every doctor
+	Sort      string   // SortNewest or SortRating
+	MaxFee    *float64 // new-patient fee at some affiliation, at most this
+	Insurance string   // insurance panel code accepted by that affiliation's hospital
+}
+
+// affiliationCondition restricts hospital_doctor rows hd to the fee and
+// insurance criteria of the filter, appending its arguments to args. Both
+// criteria apply to the same affiliation, so a doctor is only matched where
+// they are affordable at a hospital that takes the patient's insurance.
+func (f DoctorFilter) affiliationCondition(args []interface{}) (string, []interface{}) {
+	conditions := []string{"hd.doctor_id = d.doctor_id"}
+	if f.MaxFee != nil {
+		args = append(args, *f.MaxFee)
+		conditions = append(conditions, fmt.Sprintf("hd.fee_new_patient <= $%d", len(args)))
+	}
+	if f.Insurance != "" {
+		args = append(args, f.Insurance)
+		conditions = append(conditions, fmt.Sprintf(`EXISTS (
+		  SELECT 1 FROM hospital_insurance hi
+		  JOIN insurance_panels ip ON ip.panel_id = hi.panel_id
+		  WHERE hi.hospital_id = hd.hospital_id AND ip.code = $%d
+		)`, len(args)))
+	}
+	return strings.Join(conditions, " AND "), args
 }
 
 type DoctorRepo interface {
 	Create(doctor Doctor) (*Doctor, error)
-	List(search string, offset, limit int) ([]Doctor, int, error)
+	List(filter DoctorFilter, offset, limit int) ([]Doctor, int, error)
+	ListNearby(lat, lng, radiusKM float64, specialty string, offset, limit int) ([]NearbyDoctor, int, error)
 	Get(id int) (*Doctor, error)
+	GetMany(ids []int) ([]Doctor, error)
 	Update(doctor Doctor) (*Doctor, error)
 	Delete(id int) error
+	SetImageURL(id int, url string) (string, error)
+	Export(filter DoctorFilter, each func(*Doctor) error) error
 }
 
 type doctorRepo struct {
@@ -42,7 +111,19 @@ func NewDoctorRepo(db *sqlx.DB) DoctorRepo {
 	return &doctorRepo{db: db}
 }
 
+// normalizeLanguages trims the languages and drops empty ones.
+func normalizeLanguages(d *Doctor) {
+	languages := pq.StringArray{}
+	for _, l := range d.Languages {
+		if l = strings.TrimSpace(l); l != "" {
+			languages = append(languages, l)
+		}
+	}
+	d.Languages = languages
+}
+
 func (r *doctorRepo) Create(d Doctor) (*Doctor, error) {
+	normalizeLanguages(&d)
 	query := `
 		INSERT INTO doctors (
 		  name,
@@ -50,26 +131,28 @@ func (r *doctorRepo) Create(d Doctor) (*Doctor, error) {
 		  years_experience,
 		  phone_number,
 		  email,
-		  image_url
+		  image_url,
+		  bmdc_reg_no,
+		  gender,
+		  languages
 		) VALUES (
 		   :name,
 		   :specialty,
 		   :years_experience,
 		   :phone_number,
 		   :email,
-		   :image_url
+		   :image_url,
+		   :bmdc_reg_no,
+		   :gender,
+		   :languages
 		)
-		RETURNING
-		  doctor_id,
-		  name,
-		  specialty,
-		  years_experience,
-		  phone_number, email,
-		  image_url, created_at,
-		  updated_at;
+		RETURNING *;
 	`
 	rows, err := r.db.NamedQuery(query, d)
 	if err != nil {
+		if isUniqueViolationOn(err, "doctors_bmdc_reg_no_key") {
+			return nil, ErrDuplicateBMDC
+		}
 		return nil, err
 	}
 	defer rows.Close()
@@ -84,32 +167,137 @@ func (r *doctorRepo) Create(d Doctor) (*Doctor, error) {
 	return nil, nil
 }
 
-func (r *doctorRepo) List(search string, offset, limit int) ([]Doctor, int, error) {
-	var doctors []Doctor
+// listQuery returns the SELECT of the doctors matching the filter without
+// ordering, together with its WHERE clause and arguments for counting.
+func (f DoctorFilter) listQuery() (string, string, []interface{}) {
 	// search pattern
 	searchQuery := "%"
-	if search != "" {
-		searchQuery = "%" + search + "%"
+	if f.Search != "" {
+		searchQuery = "%" + f.Search + "%"
+	}
+	args := []interface{}{searchQuery}
+	affiliation, args := f.affiliationCondition(args)
+
+	// Match on name, or expand the term through the specialty taxonomy so
+	// "heart specialist" also finds cardiologists.
+	where := `WHERE (d.name ILIKE $1 OR ` + specialtyMatch("$1") + `)`
+	if f.Name != "" {
+		args = append(args, "%"+f.Name+"%")
+		where += fmt.Sprintf(` AND d.name ILIKE $%d`, len(args))
+	}
+	if f.Specialty != "" {
+		args = append(args, "%"+f.Specialty+"%")
+		where += fmt.Sprintf(` AND (d.specialty ILIKE $%[1]d OR %[2]s)`, len(args), specialtyMatch(fmt.Sprintf("$%d", len(args))))
+	}
+	if f.Status != "" {
+		args = append(args, f.Status)
+		where += fmt.Sprintf(` AND d.verification_status = $%d`, len(args))
 	}
+	if f.MaxFee != nil || f.Insurance != "" {
+		where += ` AND EXISTS (SELECT 1 FROM hospital_doctor hd WHERE ` + affiliation + `)`
+	}
+
+	query := fmt.Sprintf(`
+	  SELECT d.*,
+	    (SELECT MIN(hd.fee_new_patient) FROM hospital_doctor hd WHERE %s) AS min_fee
+	  FROM doctors d
+	  %s
+	`, affiliation, where)
+	return query, where, args
+}
+
+func (r *doctorRepo) List(filter DoctorFilter, offset, limit int) ([]Doctor, int, error) {
+	var doctors []Doctor
+	query, where, args := filter.listQuery()
+
 	var total int
-	errCount := r.db.Get(&total, `SELECT COUNT(*) FROM doctors WHERE name ILIKE $1`, searchQuery)
+	errCount := r.db.Get(&total, `SELECT COUNT(*) FROM doctors d `+where, args...)
 	if errCount != nil {
 		return nil, 0, fmt.Errorf("error counting hospitals: %w", errCount)
 	}
-	query := `
-	  SELECT *
-	  FROM doctors
-	  WHERE name ILIKE $1
-	  ORDER BY created_at DESC
-	  LIMIT $2 OFFSET $3 
-	`
-	err := r.db.Select(&doctors, query, searchQuery, limit, offset)
+	query += fmt.Sprintf(`
+	  ORDER BY %s
+	  LIMIT $%d OFFSET $%d
+	`, listOrder("d", filter.Sort), len(args)+1, len(args)+2)
+	err := r.db.Select(&doctors, query, append(args, limit, offset)...)
 	if err != nil {
 		return nil, 0, fmt.Errorf("error fetching doctors: %w", err)
 	}
 	return doctors, total, nil
 }
 
+// Export calls each for every doctor matching the filter, in List order.
+func (r *doctorRepo) Export(filter DoctorFilter, each func(*Doctor) error) error {
+	query, _, args := filter.listQuery()
+	query += ` ORDER BY ` + listOrder("d", filter.Sort) + `, d.doctor_id`
+	return streamQuery(r.db, query, args, func(rows *sqlx.Rows) error {
+		var d Doctor
+		if err := rows.StructScan(&d); err != nil {
+			return fmt.Errorf("error reading doctor: %w", err)
+		}
+		return each(&d)
+	})
+}
+
+// nearestAffiliationCTE keeps, for every verified doctor, only the closest
+// affiliated hospital within $3 km of ($1, $2) using the haversine formula.
+const nearestAffiliationCTE = `
+	WITH distances AS (
+	  SELECT
+	    hd.doctor_id,
+	    h.hospital_id,
+	    h.name AS hospital_name,
+	    COALESCE(h.address, '') AS hospital_address,
+	    6371 * ACOS(LEAST(1, GREATEST(-1,
+	      COS(RADIANS($1)) * COS(RADIANS(h.latitude)) * COS(RADIANS(h.longitude) - RADIANS($2))
+	      + SIN(RADIANS($1)) * SIN(RADIANS(h.latitude))
+	    ))) AS distance_km
+	  FROM hospital_doctor hd
+	  JOIN hospitals h ON h.hospital_id = hd.hospital_id
+	  JOIN doctors vd ON vd.doctor_id = hd.doctor_id AND vd.verification_status = 'verified'
+	  WHERE h.latitude IS NOT NULL AND h.longitude IS NOT NULL
+	),
+	nearest AS (
+	  SELECT DISTINCT ON (doctor_id) *
+	  FROM distances
+	  WHERE distance_km <= $3
+	  ORDER BY doctor_id, distance_km
+	)
+`
+
+func (r *doctorRepo) ListNearby(lat, lng, radiusKM float64, specialty string, offset, limit int) ([]NearbyDoctor, int, error) {
+	var doctors []NearbyDoctor
+	specialtyQuery := "%"
+	if specialty != "" {
+		specialtyQuery = "%" + specialty + "%"
+	}
+
+	var total int
+	countQuery := nearestAffiliationCTE + `
+	  SELECT COUNT(*)
+	  FROM nearest n
+	  JOIN doctors d ON d.doctor_id = n.doctor_id
+	  WHERE (COALESCE(d.specialty, '') ILIKE $4 OR ` + specialtyMatch("$4") + `)
+	`
+	if err := r.db.Get(&total, countQuery, lat, lng, radiusKM, specialtyQuery); err != nil {
+		return nil, 0, fmt.Errorf("error counting nearby doctors: %w", err)
+	}
+
+	query := nearestAffiliationCTE + `
+	  SELECT d.*, n.hospital_id, n.hospital_name, n.hospital_address, n.distance_km
+	  FROM nearest n
+	  JOIN doctors d ON d.doctor_id = n.doctor_id
+	  WHERE (COALESCE(d.specialty, '') ILIKE $4 OR ` + specialtyMatch("$4") + `)
+	  ORDER BY n.distance_km, d.doctor_id
+	  LIMIT $5 OFFSET $6
+	`
+	err := r.db.Select(&doctors, query, lat, lng, radiusKM, specialtyQuery, limit, offset)
+	if err != nil {
+		return nil, 0, fmt.Errorf("error fetching nearby doctors: %w", err)
+	}
+	return doctors, total, nil
+}
+
 func (r *doctorRepo) Get(id int) (*Doctor, error) {
 	var doctor Doctor
 	query := `SELECT * FROM doctors WHERE doctor_id = $1`
@@ -117,10 +305,27 @@ func (r *doctorRepo) Get(id int) (*Doctor, error) {
 	if err != nil {
 		return nil, ErrDoctorNotFound
 	}
+	doctor.Qualifications, err = listQualifications(r.db, id)
+	if err != nil {
+		return nil, err
+	}
 	return &doctor, nil
 }
 
+// GetMany returns the doctors with the given IDs, in no particular order and
+// without the qualifications that Get embeds.
+func (r *doctorRepo) GetMany(ids []int) ([]Doctor, error) {
+	doctors := []Doctor{}
+	if err := r.db.Select(&doctors, `SELECT * FROM doctors WHERE doctor_id = ANY($1)`, pq.Array(ids)); err != nil {
+		return nil, fmt.Errorf("error fetching doctors: %w", err)
+	}
+	return doctors, nil
+}
+
+// Update saves the doctor's profile. Changing the BMDC registration number
+// resets the verification, which then has to be requested again.
 func (r *doctorRepo) Update(d Doctor) (*Doctor, error) {
+	normalizeLanguages(&d)
 	query := `
 		UPDATE doctors
 		SET 
@@ -130,21 +335,26 @@ func (r *doctorRepo) Update(d Doctor) (*Doctor, error) {
 		  phone_number = :phone_number,
 		  email = :email,
 		  image_url = :image_url,
+		  gender = :gender,
+		  languages = :languages,
+		  verification_status = CASE
+		    WHEN bmdc_reg_no IS DISTINCT FROM :bmdc_reg_no THEN 'unverified'
+		    ELSE verification_status
+		  END,
+		  verified_at = CASE
+		    WHEN bmdc_reg_no IS DISTINCT FROM :bmdc_reg_no THEN NULL
+		    ELSE verified_at
+		  END,
+		  bmdc_reg_no = :bmdc_reg_no,
 		  updated_at = NOW()
 		WHERE doctor_id = :doctor_id
-		RETURNING
-		  doctor_id,
-		  name,
-		  specialty,
-		  years_experience,
-		  phone_number,
-		  email,
-		  image_url,
-		  created_at,
-		  updated_at;
+		RETURNING *;
 	`
 	rows, err := r.db.NamedQuery(query, d)
 	if err != nil {
+		if isUniqueViolationOn(err, "doctors_bmdc_reg_no_key") {
+			return nil, ErrDuplicateBMDC
+		}
 		return nil, err
 	}
 	defer rows.Close()
@@ -171,3 +381,23 @@ func (r *doctorRepo) Delete(id int) error {
 	}
 	return nil
 }
+
+// SetImageURL replaces the doctor's image URL and returns the previous one so
+// the caller can clean up the old file.
+func (r *doctorRepo) SetImageURL(id int, url string) (string, error) {
+	var previous sql.NullString
+	err := r.db.Get(&previous, `
+		UPDATE doctors d
+		SET image_url = $2, updated_at = NOW()
+		FROM (SELECT doctor_id, image_url FROM doctors WHERE doctor_id = $1 FOR UPDATE) old
+		WHERE d.doctor_id = old.doctor_id
+		RETURNING old.image_url
+	`, id, url)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return "", ErrDoctorNotFound
+		}
+		return "", fmt.Errorf("error updating doctor image: %w", err)
+	}
+	return previous.String, nil
+}
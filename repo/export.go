@@ -0,0 +1,118 @@
+package repo
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/jmoiron/sqlx"
+)
+
+// exportBatchSize is how many rows an export fetches from its cursor at a
+// time.
+const exportBatchSize = 500
+
+// streamQuery runs query through a server-side cursor in a read-only
+// transaction and calls each for every row, so that exports of any size use
+// constant memory. An error from each stops the export.
+func streamQuery(db *sqlx.DB, query string, args []interface{}, each func(*sqlx.Rows) error) error {
+	tx, err := db.BeginTxx(context.Background(), &sql.TxOptions{ReadOnly: true})
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	if _, err := tx.Exec(`DECLARE export_cursor NO SCROLL CURSOR FOR `+query, args...); err != nil {
+		return fmt.Errorf("error opening export cursor: %w", err)
+	}
+	fetch := fmt.Sprintf(`FETCH %d FROM export_cursor`, exportBatchSize)
+	for {
+		rows, err := tx.Queryx(fetch)
+		if err != nil {
+			return fmt.Errorf("error fetching export rows: %w", err)
+		}
+		n := 0
+		for rows.Next() {
+			n++
+			if err := each(rows); err != nil {
+				rows.Close()
+				return err
+			}
+		}
+		rows.Close()
+		if err := rows.Err(); err != nil {
+			return fmt.Errorf("error fetching export rows: %w", err)
+		}
+		if n < exportBatchSize {
+			return nil
+		}
+	}
+}
+
+// Columns of the CSV exports, matching ExportRecord.
+var (
+	HospitalExportColumns = []string{
+		"hospital_id", "name", "address", "phone_number", "email", "latitude", "longitude",
+		"hospital_type", "dghs_licence_no", "licence_expiry", "licence_expired", "bed_capacity",
+		"accreditations", "website", "open_24_7", "has_emergency", "emergency_phone",
+		"ambulance_numbers", "rating_avg", "rating_count", "image_url", "created_at", "updated_at",
+	}
+	DoctorExportColumns = []string{
+		"doctor_id", "name", "specialty", "years_experience", "phone_number", "email",
+		"bmdc_reg_no", "gender", "languages", "verification_status", "min_fee",
+		"rating_avg", "rating_count", "image_url", "created_at", "updated_at",
+	}
+)
+
+// exportTimeFormat is how timestamps are written in CSV exports.
+const exportTimeFormat = "2006-01-02 15:04:05"
+
+func optionalFloat(f *float64) string {
+	if f == nil {
+		return ""
+	}
+	return strconv.FormatFloat(*f, 'f', -1, 64)
+}
+
+func optionalInt(n *int) string {
+	if n == nil {
+		return ""
+	}
+	return strconv.Itoa(*n)
+}
+
+func optionalString(s *string) string {
+	if s == nil {
+		return ""
+	}
+	return *s
+}
+
+// ExportRecord flattens the hospital into a CSV row; lists are joined with
+// "; ".
+func (h *Hospital) ExportRecord() []string {
+	return []string{
+		strconv.Itoa(h.HospitalID), h.Name, h.Address, h.PhoneNumber, h.Email,
+		optionalFloat(h.Latitude), optionalFloat(h.Longitude),
+		h.HospitalType, h.LicenceNo, optionalString(h.LicenceExpiry), strconv.FormatBool(h.LicenceExpired),
+		optionalInt(h.BedCapacity), strings.Join(h.Accreditations, "; "), h.Website,
+		strconv.FormatBool(h.Open247), strconv.FormatBool(h.HasEmergency), h.EmergencyPhone,
+		strings.Join(h.AmbulanceNumbers, "; "),
+		strconv.FormatFloat(h.RatingAvg, 'f', 2, 64), strconv.Itoa(h.RatingCount), h.ImageURL,
+		h.CreatedAt.Format(exportTimeFormat), h.UpdatedAt.Format(exportTimeFormat),
+	}
+}
+
+// ExportRecord flattens the doctor into a CSV row; lists are joined with
+// "; ".
+func (d *Doctor) ExportRecord() []string {
+	return []string{
+		strconv.Itoa(d.DoctorID), d.Name, d.Specialty, strconv.Itoa(d.YearsExperience), d.PhoneNumber, d.Email,
+		optionalString(d.BMDCRegNo), d.Gender, strings.Join(d.Languages, "; "), d.VerificationStatus,
+		optionalFloat(d.MinFee),
+		strconv.FormatFloat(d.RatingAvg, 'f', 2, 64), strconv.Itoa(d.RatingCount), d.ImageURL,
+		d.CreatedAt.Format(exportTimeFormat), d.UpdatedAt.Format(exportTimeFormat),
+	}
+}
@@ -1,22 +1,65 @@
 package repo
 
 import (
+	"database/sql"
+	"errors"
+	"fmt"
+	"strings"
 	"time"
 
 	"github.com/jmoiron/sqlx"
+	"github.com/lib/pq"
 )
 
+// DefaultFeeCurrency is used when a relation is saved without a currency.
+const DefaultFeeCurrency = "BDT"
+
+// HospitalDoctor is a doctor's affiliation with a hospital. Fees are what the
+// doctor charges at that hospital; nil means unknown.
 type HospitalDoctor struct {
-	HospitalID int       `json:"hospital_id" db:"hospital_id"`
-	DoctorID   int       `json:"doctor_id" db:"doctor_id"`
-	Role       string    `json:"role" db:"role"`
-	CreatedAt  time.Time `json:"created_at" db:"created_at"`
-	UpdatedAt  time.Time `json:"updated_at" db:"updated_at"`
+	HospitalID      int       `json:"hospital_id" db:"hospital_id"`
+	DoctorID        int       `json:"doctor_id" db:"doctor_id"`
+	Role            string    `json:"role" db:"role"`
+	FeeNewPatient   *float64  `json:"fee_new_patient" db:"fee_new_patient"`
+	FeeFollowUp     *float64  `json:"fee_follow_up" db:"fee_follow_up"`
+	FeeReportReview *float64  `json:"fee_report_review" db:"fee_report_review"`
+	FeeCurrency     string    `json:"fee_currency" db:"fee_currency"`
+	CreatedAt       time.Time `json:"created_at" db:"created_at"`
+	UpdatedAt       time.Time `json:"updated_at" db:"updated_at"`
+}
+
+// Affiliation is a HospitalDoctor together with the names it links.
+// Specialties are the codes and English names of the doctor's taxonomy
+// specialties, in the same order.
+type Affiliation struct {
+	HospitalDoctor
+	HospitalName   string         `json:"hospital_name" db:"hospital_name"`
+	DoctorName     string         `json:"doctor_name" db:"doctor_name"`
+	Specialty      string         `json:"specialty" db:"specialty"`
+	SpecialtyCodes pq.StringArray `json:"specialty_codes" db:"specialty_codes"`
+	SpecialtyNames pq.StringArray `json:"specialty_names" db:"specialty_names"`
+	DoctorStatus   string         `json:"verification_status" db:"verification_status"`
+}
+
+// AffiliationFilter narrows down ListAffiliations results. Zero values match
+// everything.
+type AffiliationFilter struct {
+	HospitalID  int
+	DoctorID    int
+	HospitalIDs []int  // any of these hospitals
+	DoctorIDs   []int  // any of these doctors
+	Specialty   string // doctor's specialty, expanded through the taxonomy
+	Status      string // doctor's verification status
 }
 
 type HospitalDoctorRepo interface {
 	AssignDoctor(rel HospitalDoctor) error
+	Get(hospitalID, doctorID int) (*HospitalDoctor, error)
+	UpdateFees(rel HospitalDoctor) (*HospitalDoctor, error)
 	ListDoctorsByHospital(hospitalID int) ([]Doctor, error)
+	GetAffiliation(hospitalID, doctorID int) (*Affiliation, error)
+	ListAffiliations(filter AffiliationFilter, offset, limit int) ([]Affiliation, int, error)
+	ListAllAffiliations(filter AffiliationFilter) ([]Affiliation, error)
 	DeleteDoctorRelation(hospitalID, doctorID int) error
 }
 
@@ -33,29 +76,198 @@ func (r *hospitalDoctorRepo) AssignDoctor(rel HospitalDoctor) error {
 		INSERT INTO hospital_doctor (
 		  hospital_id,
 		  doctor_id,
-		  role
+		  role,
+		  fee_new_patient,
+		  fee_follow_up,
+		  fee_report_review,
+		  fee_currency
 		) VALUES (
 		  :hospital_id,
 		  :doctor_id,
-		  :role
+		  :role,
+		  :fee_new_patient,
+		  :fee_follow_up,
+		  :fee_report_review,
+		  :fee_currency
 		)
 	`
+	if rel.FeeCurrency == "" {
+		rel.FeeCurrency = DefaultFeeCurrency
+	}
 	_, err := r.db.NamedExec(query, rel)
 	return err
 }
 
+const hospitalDoctorColumns = `
+	hospital_id,
+	doctor_id,
+	COALESCE(role, '') AS role,
+	fee_new_patient,
+	fee_follow_up,
+	fee_report_review,
+	fee_currency,
+	created_at,
+	updated_at
+`
+
+func (r *hospitalDoctorRepo) Get(hospitalID, doctorID int) (*HospitalDoctor, error) {
+	var rel HospitalDoctor
+	err := r.db.Get(&rel, `
+		SELECT `+hospitalDoctorColumns+`
+		FROM hospital_doctor
+		WHERE hospital_id = $1 AND doctor_id = $2
+	`, hospitalID, doctorID)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, ErrNoAffiliation
+		}
+		return nil, fmt.Errorf("error fetching affiliation: %w", err)
+	}
+	return &rel, nil
+}
+
+// UpdateFees replaces the fees and currency of an existing affiliation.
+func (r *hospitalDoctorRepo) UpdateFees(rel HospitalDoctor) (*HospitalDoctor, error) {
+	if rel.FeeCurrency == "" {
+		rel.FeeCurrency = DefaultFeeCurrency
+	}
+	var updated HospitalDoctor
+	err := r.db.Get(&updated, `
+		UPDATE hospital_doctor
+		SET fee_new_patient = $3,
+		    fee_follow_up = $4,
+		    fee_report_review = $5,
+		    fee_currency = $6,
+		    updated_at = NOW()
+		WHERE hospital_id = $1 AND doctor_id = $2
+		RETURNING `+hospitalDoctorColumns,
+		rel.HospitalID, rel.DoctorID, rel.FeeNewPatient, rel.FeeFollowUp, rel.FeeReportReview, rel.FeeCurrency)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, ErrNoAffiliation
+		}
+		return nil, fmt.Errorf("error updating fees: %w", err)
+	}
+	return &updated, nil
+}
+
+// ListDoctorsByHospital lists the verified doctors assigned to a hospital.
 func (r *hospitalDoctorRepo) ListDoctorsByHospital(hospitalID int) ([]Doctor, error) {
 	var doctors []Doctor
 	query := `
 		SELECT d.*
 		FROM doctors d
 		JOIN hospital_doctor hd ON d.doctor_id = hd.doctor_id
-		WHERE hd.hospital_id = $1
+		WHERE hd.hospital_id = $1 AND d.verification_status = 'verified'
 	`
 	err := r.db.Select(&doctors, query, hospitalID)
 	return doctors, err
 }
 
+const affiliationColumns = `
+	hd.hospital_id,
+	hd.doctor_id,
+	COALESCE(hd.role, '') AS role,
+	hd.fee_new_patient,
+	hd.fee_follow_up,
+	hd.fee_report_review,
+	hd.fee_currency,
+	hd.created_at,
+	hd.updated_at,
+	h.name AS hospital_name,
+	d.name AS doctor_name,
+	COALESCE(d.specialty, '') AS specialty,
+	ARRAY(
+	  SELECT s.code FROM doctor_specialty ds JOIN specialties s ON s.specialty_id = ds.specialty_id
+	  WHERE ds.doctor_id = d.doctor_id ORDER BY s.code
+	) AS specialty_codes,
+	ARRAY(
+	  SELECT s.name_en FROM doctor_specialty ds JOIN specialties s ON s.specialty_id = ds.specialty_id
+	  WHERE ds.doctor_id = d.doctor_id ORDER BY s.code
+	) AS specialty_names,
+	d.verification_status
+`
+
+const affiliationFrom = `
+	FROM hospital_doctor hd
+	JOIN hospitals h ON h.hospital_id = hd.hospital_id
+	JOIN doctors d ON d.doctor_id = hd.doctor_id
+`
+
+func (r *hospitalDoctorRepo) GetAffiliation(hospitalID, doctorID int) (*Affiliation, error) {
+	var a Affiliation
+	err := r.db.Get(&a, `SELECT `+affiliationColumns+affiliationFrom+`WHERE hd.hospital_id = $1 AND hd.doctor_id = $2`,
+		hospitalID, doctorID)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, ErrNoAffiliation
+		}
+		return nil, fmt.Errorf("error fetching affiliation: %w", err)
+	}
+	return &a, nil
+}
+
+// whereClause turns an AffiliationFilter into a WHERE clause and its arguments.
+func (f AffiliationFilter) whereClause() (string, []interface{}) {
+	conditions := []string{"TRUE"}
+	args := []interface{}{}
+	if f.HospitalID > 0 {
+		args = append(args, f.HospitalID)
+		conditions = append(conditions, fmt.Sprintf("hd.hospital_id = $%d", len(args)))
+	}
+	if f.DoctorID > 0 {
+		args = append(args, f.DoctorID)
+		conditions = append(conditions, fmt.Sprintf("hd.doctor_id = $%d", len(args)))
+	}
+	if f.HospitalIDs != nil {
+		args = append(args, pq.Array(f.HospitalIDs))
+		conditions = append(conditions, fmt.Sprintf("hd.hospital_id = ANY($%d)", len(args)))
+	}
+	if f.DoctorIDs != nil {
+		args = append(args, pq.Array(f.DoctorIDs))
+		conditions = append(conditions, fmt.Sprintf("hd.doctor_id = ANY($%d)", len(args)))
+	}
+	if f.Specialty != "" {
+		args = append(args, "%"+f.Specialty+"%")
+		param := fmt.Sprintf("$%d", len(args))
+		conditions = append(conditions, "(d.specialty ILIKE "+param+" OR "+specialtyMatch(param)+")")
+	}
+	if f.Status != "" {
+		args = append(args, f.Status)
+		conditions = append(conditions, fmt.Sprintf("d.verification_status = $%d", len(args)))
+	}
+	return "WHERE " + strings.Join(conditions, " AND "), args
+}
+
+func (r *hospitalDoctorRepo) ListAffiliations(filter AffiliationFilter, offset, limit int) ([]Affiliation, int, error) {
+	where, args := filter.whereClause()
+
+	var total int
+	if err := r.db.Get(&total, `SELECT COUNT(*) `+affiliationFrom+where, args...); err != nil {
+		return nil, 0, fmt.Errorf("error counting affiliations: %w", err)
+	}
+
+	list := []Affiliation{}
+	query := fmt.Sprintf(`SELECT %s %s %s ORDER BY hd.hospital_id, hd.doctor_id LIMIT $%d OFFSET $%d`,
+		affiliationColumns, affiliationFrom, where, len(args)+1, len(args)+2)
+	if err := r.db.Select(&list, query, append(args, limit, offset)...); err != nil {
+		return nil, 0, fmt.Errorf("error fetching affiliations: %w", err)
+	}
+	return list, total, nil
+}
+
+// ListAllAffiliations returns every affiliation matching the filter, in
+// ListAffiliations order.
+func (r *hospitalDoctorRepo) ListAllAffiliations(filter AffiliationFilter) ([]Affiliation, error) {
+	where, args := filter.whereClause()
+	list := []Affiliation{}
+	query := `SELECT ` + affiliationColumns + affiliationFrom + where + ` ORDER BY hd.hospital_id, hd.doctor_id`
+	if err := r.db.Select(&list, query, args...); err != nil {
+		return nil, fmt.Errorf("error fetching affiliations: %w", err)
+	}
+	return list, nil
+}
+
 func (r *hospitalDoctorRepo) DeleteDoctorRelation(hospitalID, doctorID int) error {
 	query := `
 		DELETE FROM hospital_doctor
@@ -0,0 +1,220 @@
+package repo
+
+import (
+	"database/sql"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/jmoiron/sqlx"
+)
+
+var ErrOverrideNotFound = errors.New("opening hours override not found")
+
+// OpeningHours is a weekly opening range of a hospital. Times are HH:MM
+// wall-clock times in Asia/Dhaka; DayOfWeek is 0 (Sunday) to 6. A range
+// cannot pass midnight: it is split into one closing at 24:00 and one opening
+// at 00:00 on the next day.
+type OpeningHours struct {
+	DayOfWeek int    `json:"day_of_week" db:"day_of_week"`
+	OpenTime  string `json:"open_time" db:"open_time"`
+	CloseTime string `json:"close_time" db:"close_time"`
+}
+
+// HoursOverride replaces the weekly hours on a date, e.g. for a holiday. An
+// open override without times means open all day.
+type HoursOverride struct {
+	OverrideID int       `json:"override_id" db:"override_id"`
+	HospitalID int       `json:"hospital_id" db:"hospital_id"`
+	Date       string    `json:"date" db:"override_date"`
+	IsOpen     bool      `json:"is_open" db:"is_open"`
+	OpenTime   *string   `json:"open_time" db:"open_time"`
+	CloseTime  *string   `json:"close_time" db:"close_time"`
+	Reason     string    `json:"reason" db:"reason"`
+	CreatedAt  time.Time `json:"created_at" db:"created_at"`
+}
+
+type HoursRepo interface {
+	ReplaceWeekly(hospitalID int, hours []OpeningHours) ([]OpeningHours, error)
+	ListWeekly(hospitalID int) ([]OpeningHours, error)
+	CreateOverride(o HoursOverride) (*HoursOverride, error)
+	GetOverride(id int) (*HoursOverride, error)
+	DeleteOverride(id int) error
+	ListOverrides(hospitalID int, from string) ([]HoursOverride, error)
+	IsOpenNow(hospitalID int) (bool, error)
+}
+
+type hoursRepo struct {
+	db *sqlx.DB
+}
+
+func NewHoursRepo(db *sqlx.DB) HoursRepo {
+	return &hoursRepo{db: db}
+}
+
+// openNowCondition is true when hospital h is open at the current time in
+// Asia/Dhaka. A date override wins over both the 24/7 flag and the weekly
+// hours.
+const openNowCondition = `(
+	CASE
+	  WHEN EXISTS (
+	    SELECT 1 FROM hospital_hour_overrides o
+	    WHERE o.hospital_id = h.hospital_id
+	      AND o.override_date = (NOW() AT TIME ZONE 'Asia/Dhaka')::date
+	  ) THEN EXISTS (
+	    SELECT 1 FROM hospital_hour_overrides o
+	    WHERE o.hospital_id = h.hospital_id
+	      AND o.override_date = (NOW() AT TIME ZONE 'Asia/Dhaka')::date
+	      AND o.is_open
+	      AND (o.open_time IS NULL
+	           OR ((NOW() AT TIME ZONE 'Asia/Dhaka')::time >= o.open_time
+	               AND (NOW() AT TIME ZONE 'Asia/Dhaka')::time < o.close_time))
+	  )
+	  ELSE h.open_24_7 OR EXISTS (
+	    SELECT 1 FROM hospital_hours hh
+	    WHERE hh.hospital_id = h.hospital_id
+	      AND hh.day_of_week = EXTRACT(DOW FROM NOW() AT TIME ZONE 'Asia/Dhaka')
+	      AND (NOW() AT TIME ZONE 'Asia/Dhaka')::time >= hh.open_time
+	      AND (NOW() AT TIME ZONE 'Asia/Dhaka')::time < hh.close_time
+	  )
+	END
+)`
+
+const overrideColumns = `
+	override_id,
+	hospital_id,
+	TO_CHAR(override_date, 'YYYY-MM-DD') AS override_date,
+	is_open,
+	TO_CHAR(open_time, 'HH24:MI') AS open_time,
+	TO_CHAR(close_time, 'HH24:MI') AS close_time,
+	reason,
+	created_at
+`
+
+func listOpeningHours(db sqlx.Queryer, hospitalID int) ([]OpeningHours, error) {
+	list := []OpeningHours{}
+	err := sqlx.Select(db, &list, `
+		SELECT day_of_week, TO_CHAR(open_time, 'HH24:MI') AS open_time, TO_CHAR(close_time, 'HH24:MI') AS close_time
+		FROM hospital_hours
+		WHERE hospital_id = $1
+		ORDER BY day_of_week, open_time
+	`, hospitalID)
+	if err != nil {
+		return nil, fmt.Errorf("error fetching opening hours: %w", err)
+	}
+	return list, nil
+}
+
+// ReplaceWeekly swaps the hospital's weekly hours for the given ranges.
+func (r *hoursRepo) ReplaceWeekly(hospitalID int, hours []OpeningHours) ([]OpeningHours, error) {
+	tx, err := r.db.Beginx()
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback()
+
+	// Lock the hospital so concurrent replacements don't interleave.
+	var locked int
+	err = tx.Get(&locked, `SELECT hospital_id FROM hospitals WHERE hospital_id = $1 FOR UPDATE`, hospitalID)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, ErrNotFound
+		}
+		return nil, fmt.Errorf("error fetching hospital: %w", err)
+	}
+
+	if _, err := tx.Exec(`DELETE FROM hospital_hours WHERE hospital_id = $1`, hospitalID); err != nil {
+		return nil, fmt.Errorf("error clearing opening hours: %w", err)
+	}
+	for _, oh := range hours {
+		_, err := tx.Exec(`
+			INSERT INTO hospital_hours (hospital_id, day_of_week, open_time, close_time)
+			VALUES ($1, $2, $3, $4)
+		`, hospitalID, oh.DayOfWeek, oh.OpenTime, oh.CloseTime)
+		if err != nil {
+			return nil, fmt.Errorf("error saving opening hours: %w", err)
+		}
+	}
+
+	saved, err := listOpeningHours(tx, hospitalID)
+	if err != nil {
+		return nil, err
+	}
+	if err := tx.Commit(); err != nil {
+		return nil, err
+	}
+	return saved, nil
+}
+
+func (r *hoursRepo) ListWeekly(hospitalID int) ([]OpeningHours, error) {
+	return listOpeningHours(r.db, hospitalID)
+}
+
+func (r *hoursRepo) CreateOverride(o HoursOverride) (*HoursOverride, error) {
+	var created HoursOverride
+	err := r.db.Get(&created, `
+		INSERT INTO hospital_hour_overrides (hospital_id, override_date, is_open, open_time, close_time, reason)
+		VALUES ($1, $2, $3, $4, $5, $6)
+		RETURNING `+overrideColumns,
+		o.HospitalID, o.Date, o.IsOpen, o.OpenTime, o.CloseTime, o.Reason)
+	if err != nil {
+		switch {
+		case isUniqueViolation(err):
+			return nil, ErrDuplicateDate
+		case isForeignKeyViolation(err):
+			return nil, ErrNotFound
+		}
+		return nil, fmt.Errorf("error creating opening hours override: %w", err)
+	}
+	return &created, nil
+}
+
+func (r *hoursRepo) GetOverride(id int) (*HoursOverride, error) {
+	var o HoursOverride
+	err := r.db.Get(&o, `SELECT `+overrideColumns+` FROM hospital_hour_overrides WHERE override_id = $1`, id)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, ErrOverrideNotFound
+		}
+		return nil, fmt.Errorf("error fetching opening hours override: %w", err)
+	}
+	return &o, nil
+}
+
+func (r *hoursRepo) DeleteOverride(id int) error {
+	res, err := r.db.Exec(`DELETE FROM hospital_hour_overrides WHERE override_id = $1`, id)
+	if err != nil {
+		return fmt.Errorf("error deleting opening hours override: %w", err)
+	}
+	if rows, _ := res.RowsAffected(); rows == 0 {
+		return ErrOverrideNotFound
+	}
+	return nil
+}
+
+// ListOverrides lists the hospital's overrides on or after from.
+func (r *hoursRepo) ListOverrides(hospitalID int, from string) ([]HoursOverride, error) {
+	list := []HoursOverride{}
+	err := r.db.Select(&list, `
+		SELECT `+overrideColumns+`
+		FROM hospital_hour_overrides
+		WHERE hospital_id = $1 AND override_date >= $2
+		ORDER BY override_date
+	`, hospitalID, from)
+	if err != nil {
+		return nil, fmt.Errorf("error fetching opening hours overrides: %w", err)
+	}
+	return list, nil
+}
+
+func (r *hoursRepo) IsOpenNow(hospitalID int) (bool, error) {
+	var open bool
+	err := r.db.Get(&open, `SELECT `+openNowCondition+` FROM hospitals h WHERE h.hospital_id = $1`, hospitalID)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return false, ErrNotFound
+		}
+		return false, fmt.Errorf("error checking opening hours: %w", err)
+	}
+	return open, nil
+}
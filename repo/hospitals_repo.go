@@ -5,27 +5,88 @@ import (
 	"errors"
 	"fmt"
 	"log"
+	"strings"
 	"time"
 
 	"github.com/jmoiron/sqlx"
+	"github.com/lib/pq"
 )
 
 // Custom public errors
 var (
-	ErrNotFound     = errors.New("record not found in the database")
-	ErrFailedUpdate = errors.New("failed to update record: zero rows affected")
+	ErrNotFound         = errors.New("record not found in the database")
+	ErrFailedUpdate     = errors.New("failed to update record: zero rows affected")
+	ErrDuplicateLicence = errors.New("DGHS licence number already exists")
 )
 
+// Hospital ownership types.
+const (
+	HospitalGovernment = "government"
+	HospitalPrivate    = "private"
+	HospitalNGO        = "ngo"
+)
+
+var HospitalTypes = []string{HospitalGovernment, HospitalPrivate, HospitalNGO}
+
+// Licence statuses accepted by HospitalFilter.
+const (
+	LicenceValid   = "valid"
+	LicenceExpired = "expired"
+)
+
+// ImageVariants are the URLs of the resized copies of an uploaded photo.
+type ImageVariants struct {
+	Thumbnail string `json:"thumbnail"`
+	Medium    string `json:"medium"`
+}
+
 // DB structure for a hospital record.
 type Hospital struct {
-	HospitalID  int       `json:"hospital_id" db:"hospital_id"`
-	Name        string    `json:"name" db:"name"`
-	Address     string    `json:"address" db:"address"`
-	PhoneNumber string    `json:"phone_number" db:"phone_number"`
-	Email       string    `json:"email" db:"email"`
-	ImageURL    string    `json:"image_url" db:"image_url"`
-	CreatedAt   time.Time `json:"created_at" db:"created_at"`
-	UpdatedAt   time.Time `json:"updated_at" db:"updated_at"`
+	HospitalID  int            `json:"hospital_id" db:"hospital_id"`
+	Name        string         `json:"name" db:"name"`
+	Address     string         `json:"address" db:"address"`
+	PhoneNumber string         `json:"phone_number" db:"phone_number"`
+	Email       string         `json:"email" db:"email"`
+	ImageURL    string         `json:"image_url" db:"image_url"`
+	Images      *ImageVariants `json:"images,omitempty" db:"-"` // set by the handlers for uploaded photos
+	Latitude    *float64       `json:"latitude" db:"latitude"`
+	Longitude   *float64       `json:"longitude" db:"longitude"`
+	RatingAvg   float64        `json:"rating_avg" db:"rating_avg"`
+	RatingCount int            `json:"rating_count" db:"rating_count"`
+
+	Open247          bool           `json:"open_24_7" db:"open_24_7"`
+	HasEmergency     bool           `json:"has_emergency" db:"has_emergency"`
+	EmergencyPhone   string         `json:"emergency_phone" db:"emergency_phone"`
+	AmbulanceNumbers pq.StringArray `json:"ambulance_numbers" db:"ambulance_numbers"`
+
+	HospitalType   string         `json:"hospital_type" db:"hospital_type"`
+	LicenceNo      string         `json:"dghs_licence_no" db:"dghs_licence_no"`
+	LicenceExpiry  *string        `json:"licence_expiry" db:"licence_expiry"` // YYYY-MM-DD
+	LicenceExpired bool           `json:"licence_expired" db:"licence_expired"`
+	BedCapacity    *int           `json:"bed_capacity" db:"bed_capacity"`
+	Accreditations pq.StringArray `json:"accreditations" db:"accreditations"`
+	Website        string         `json:"website" db:"website"`
+
+	CreatedAt time.Time `json:"created_at" db:"created_at"`
+	UpdatedAt time.Time `json:"updated_at" db:"updated_at"`
+
+	// Services, Hours and InsurancePanels are only populated by Get.
+	Services        []HospitalService   `json:"services,omitempty" db:"-"`
+	Hours           []OpeningHours      `json:"opening_hours,omitempty" db:"-"`
+	InsurancePanels []HospitalInsurance `json:"insurance_panels,omitempty" db:"-"`
+}
+
+// HospitalFilter narrows down and orders List results.
+type HospitalFilter struct {
+	Search     string
+	Services   []string // service codes; a hospital must offer all of them
+	OpenNow    bool     // only hospitals open at the current time in Asia/Dhaka
+	Emergency  bool     // only hospitals with an emergency department
+	Type       string   // one of HospitalTypes
+	Licence    string   // LicenceValid or LicenceExpired
+	MinBeds    int      // minimum bed capacity
+	Accredited string   // accreditation the hospital must hold
+	Sort       string   // SortNewest or SortRating
 }
 
 // HospitalRepo interface.
@@ -37,9 +98,13 @@ type hospitalRepo struct {
 type HospitalRepo interface {
 	Create(hospital Hospital) (*Hospital, error)
 	Get(id int) (*Hospital, error)
-	List(search string, offset, limit int) ([]*Hospital, int, error)
+	GetMany(ids []int) ([]*Hospital, error)
+	List(filter HospitalFilter, offset, limit int) ([]*Hospital, int, error)
 	Update(h Hospital) (*Hospital, error)
 	Delete(id int) error
+	SetImageURL(id int, url string) (string, error)
+	FlagExpiredLicences() (int, error)
+	Export(filter HospitalFilter, each func(*Hospital) error) error
 }
 
 // NewHospitalRepo creates a new repository instance.
@@ -49,34 +114,87 @@ func NewHospitalRepo(dbCon *sqlx.DB) HospitalRepo {
 	}
 }
 
+// hospitalColumns selects a hospital row from alias h.
+const hospitalColumns = `
+	h.hospital_id,
+	h.name,
+	h.address,
+	h.phone_number,
+	h.email,
+	h.image_url,
+	h.latitude,
+	h.longitude,
+	h.rating_avg,
+	h.rating_count,
+	h.open_24_7,
+	h.has_emergency,
+	h.emergency_phone,
+	h.ambulance_numbers,
+	h.hospital_type,
+	h.dghs_licence_no,
+	TO_CHAR(h.licence_expiry, 'YYYY-MM-DD') AS licence_expiry,
+	h.licence_expired,
+	h.bed_capacity,
+	h.accreditations,
+	h.website,
+	h.created_at,
+	h.updated_at
+`
+
+// licenceExpiredExpr is true when licence_expiry has passed in Asia/Dhaka.
+const licenceExpiredExpr = `COALESCE(licence_expiry < (NOW() AT TIME ZONE 'Asia/Dhaka')::date, FALSE)`
+
 // INSERT query
 func (r *hospitalRepo) Create(hospital Hospital) (*Hospital, error) {
+	normalizeAmbulanceNumbers(&hospital)
+	normalizeAccreditations(&hospital)
 	query := `
-		INSERT INTO hospitals (
+		INSERT INTO hospitals AS h (
 			name, 
 			address, 
 			phone_number, 
 			email,
-			image_url
+			image_url,
+			latitude,
+			longitude,
+			open_24_7,
+			has_emergency,
+			emergency_phone,
+			ambulance_numbers,
+			hospital_type,
+			dghs_licence_no,
+			licence_expiry,
+			licence_expired,
+			bed_capacity,
+			accreditations,
+			website
 		)
 		VALUES (
 			:name, 
 			:address, 
 			:phone_number, 
 			:email,
-			:image_url
+			:image_url,
+			:latitude,
+			:longitude,
+			:open_24_7,
+			:has_emergency,
+			:emergency_phone,
+			:ambulance_numbers,
+			:hospital_type,
+			:dghs_licence_no,
+			:licence_expiry,
+			COALESCE(CAST(:licence_expiry AS date) < (NOW() AT TIME ZONE 'Asia/Dhaka')::date, FALSE),
+			:bed_capacity,
+			:accreditations,
+			:website
 		)
-		RETURNING
-		  hospital_id,
-		  name, address,
-		  phone_number,
-		  email,
-		  image_url,
-		  created_at,
-		  updated_at;
-	`
+		RETURNING ` + hospitalColumns
 	rows, err := r.dbCon.NamedQuery(query, hospital)
 	if err != nil {
+		if isUniqueViolationOn(err, "idx_hospitals_dghs_licence") {
+			return nil, ErrDuplicateLicence
+		}
 		return nil, err
 	}
 	defer rows.Close()
@@ -95,7 +213,7 @@ func (r *hospitalRepo) Create(hospital Hospital) (*Hospital, error) {
 // Get a single Hospital record by ID.
 func (r *hospitalRepo) Get(id int) (*Hospital, error) {
 	var hsp Hospital
-	query := `SELECT * FROM hospitals WHERE hospital_id = $1`
+	query := `SELECT ` + hospitalColumns + ` FROM hospitals h WHERE h.hospital_id = $1`
 	err := r.dbCon.Get(&hsp, query, id)
 	if err != nil {
 		if errors.Is(err, sql.ErrNoRows) {
@@ -104,64 +222,177 @@ func (r *hospitalRepo) Get(id int) (*Hospital, error) {
 		return nil, fmt.Errorf("error fetching hospital: %w", err)
 	}
 
+	hsp.Services, err = listHospitalServices(r.dbCon, id)
+	if err != nil {
+		return nil, err
+	}
+	hsp.Hours, err = listOpeningHours(r.dbCon, id)
+	if err != nil {
+		return nil, err
+	}
+	hsp.InsurancePanels, err = listHospitalInsurance(r.dbCon, id)
+	if err != nil {
+		return nil, err
+	}
+
 	return &hsp, nil
 }
 
-// GET all Hospital records.
-func (r *hospitalRepo) List(search string, offset, limit int) ([]*Hospital, int, error) {
-	var hspList []*Hospital
+// GetMany returns the hospitals with the given IDs, in no particular order
+// and without the services, hours and panels that Get embeds.
+func (r *hospitalRepo) GetMany(ids []int) ([]*Hospital, error) {
+	list := []*Hospital{}
+	query := `SELECT ` + hospitalColumns + ` FROM hospitals h WHERE h.hospital_id = ANY($1)`
+	if err := r.dbCon.Select(&list, query, pq.Array(ids)); err != nil {
+		return nil, fmt.Errorf("error fetching hospitals: %w", err)
+	}
+	return list, nil
+}
+
+// whereClause turns a HospitalFilter into a WHERE clause and its arguments.
+func (f HospitalFilter) whereClause() (string, []interface{}) {
 	// search pattern
 	searchQuery := "%"
-	if search != "" {
-		searchQuery = "%" + search + "%"
+	if f.Search != "" {
+		searchQuery = "%" + f.Search + "%"
+	}
+	conditions := []string{"h.name ILIKE $1"}
+	args := []interface{}{searchQuery}
+
+	if len(f.Services) > 0 {
+		args = append(args, pq.Array(f.Services), len(f.Services))
+		conditions = append(conditions, fmt.Sprintf(`(
+			SELECT COUNT(DISTINCT s.code)
+			FROM hospital_service hs
+			JOIN services s ON s.service_id = hs.service_id
+			WHERE hs.hospital_id = h.hospital_id AND s.code = ANY($%d)
+		) = $%d`, len(args)-1, len(args)))
+	}
+
+	if f.OpenNow {
+		conditions = append(conditions, openNowCondition)
 	}
+	if f.Emergency {
+		conditions = append(conditions, "h.has_emergency")
+	}
+	if f.Type != "" {
+		args = append(args, f.Type)
+		conditions = append(conditions, fmt.Sprintf("h.hospital_type = $%d", len(args)))
+	}
+	switch f.Licence {
+	case LicenceValid:
+		conditions = append(conditions, "h.licence_expiry IS NOT NULL AND NOT h.licence_expired")
+	case LicenceExpired:
+		conditions = append(conditions, "h.licence_expired")
+	}
+	if f.MinBeds > 0 {
+		args = append(args, f.MinBeds)
+		conditions = append(conditions, fmt.Sprintf("h.bed_capacity >= $%d", len(args)))
+	}
+	if f.Accredited != "" {
+		args = append(args, f.Accredited)
+		conditions = append(conditions, fmt.Sprintf("$%d = ANY(h.accreditations)", len(args)))
+	}
+
+	return "WHERE " + strings.Join(conditions, " AND "), args
+}
+
+// normalizeAmbulanceNumbers trims the numbers and drops empty ones.
+func normalizeAmbulanceNumbers(h *Hospital) {
+	numbers := pq.StringArray{}
+	for _, n := range h.AmbulanceNumbers {
+		if n = strings.TrimSpace(n); n != "" {
+			numbers = append(numbers, n)
+		}
+	}
+	h.AmbulanceNumbers = numbers
+}
+
+// normalizeAccreditations trims the accreditations and drops empty ones.
+func normalizeAccreditations(h *Hospital) {
+	list := pq.StringArray{}
+	for _, a := range h.Accreditations {
+		if a = strings.TrimSpace(a); a != "" {
+			list = append(list, a)
+		}
+	}
+	h.Accreditations = list
+}
+
+// GET all Hospital records.
+func (r *hospitalRepo) List(filter HospitalFilter, offset, limit int) ([]*Hospital, int, error) {
+	var hspList []*Hospital
+	where, args := filter.whereClause()
 
 	var total int
-	err := r.dbCon.Get(&total, "SELECT COUNT(*) FROM hospitals WHERE name ILIKE $1", searchQuery)
+	err := r.dbCon.Get(&total, "SELECT COUNT(*) FROM hospitals h "+where, args...)
 	if err != nil {
 		return nil, 0, fmt.Errorf("error counting hospitals: %w", err)
 	}
 
-	query := `
-		SELECT *
-		FROM hospitals
-		WHERE name ILIKE $1
-		ORDER BY created_at DESC
-		LIMIT $2 OFFSET $3
-	`
-	err = r.dbCon.Select(&hspList, query, searchQuery, limit, offset)
+	query := fmt.Sprintf(`
+		SELECT %s
+		FROM hospitals h
+		%s
+		ORDER BY %s
+		LIMIT $%d OFFSET $%d
+	`, hospitalColumns, where, listOrder("h", filter.Sort), len(args)+1, len(args)+2)
+	err = r.dbCon.Select(&hspList, query, append(args, limit, offset)...)
 	if err != nil {
 		return nil, 0, fmt.Errorf("error fetching hospitals: %w", err)
 	}
 	return hspList, total, nil
 }
 
+// Export calls each for every hospital matching the filter, in List order.
+func (r *hospitalRepo) Export(filter HospitalFilter, each func(*Hospital) error) error {
+	where, args := filter.whereClause()
+	query := fmt.Sprintf(`SELECT %s FROM hospitals h %s ORDER BY %s, h.hospital_id`,
+		hospitalColumns, where, listOrder("h", filter.Sort))
+	return streamQuery(r.dbCon, query, args, func(rows *sqlx.Rows) error {
+		var hsp Hospital
+		if err := rows.StructScan(&hsp); err != nil {
+			return fmt.Errorf("error reading hospital: %w", err)
+		}
+		return each(&hsp)
+	})
+}
+
 // Update an existing Hospital record.
 func (r *hospitalRepo) Update(h Hospital) (*Hospital, error) {
 	h.UpdatedAt = time.Now()
+	normalizeAmbulanceNumbers(&h)
+	normalizeAccreditations(&h)
 
 	query := `
-		UPDATE hospitals
+		UPDATE hospitals h
 		SET 
 		  name = :name,
 		  address = :address,
 		  phone_number = :phone_number,
 		  email = :email,
 		  image_url = :image_url,
+		  latitude = :latitude,
+		  longitude = :longitude,
+		  open_24_7 = :open_24_7,
+		  has_emergency = :has_emergency,
+		  emergency_phone = :emergency_phone,
+		  ambulance_numbers = :ambulance_numbers,
+		  hospital_type = :hospital_type,
+		  dghs_licence_no = :dghs_licence_no,
+		  licence_expiry = :licence_expiry,
+		  licence_expired = COALESCE(CAST(:licence_expiry AS date) < (NOW() AT TIME ZONE 'Asia/Dhaka')::date, FALSE),
+		  bed_capacity = :bed_capacity,
+		  accreditations = :accreditations,
+		  website = :website,
 		  updated_at = :updated_at
 		WHERE hospital_id = :hospital_id
-		RETURNING 
-		  hospital_id,
-		  name,
-		  address,
-		  phone_number,
-		  email,
-		  image_url,
-		  created_at,
-		  updated_at;
-	`
+		RETURNING ` + hospitalColumns
 	rows, err := r.dbCon.NamedQuery(query, h)
 	if err != nil {
+		if isUniqueViolationOn(err, "idx_hospitals_dghs_licence") {
+			return nil, ErrDuplicateLicence
+		}
 		return nil, fmt.Errorf("error executing update query: %w", err)
 	}
 	defer rows.Close()
@@ -198,3 +429,37 @@ func (r *hospitalRepo) Delete(id int) error {
 
 	return nil
 }
+
+// FlagExpiredLicences brings licence_expired in line with today's date and
+// returns the number of hospitals whose flag changed.
+func (r *hospitalRepo) FlagExpiredLicences() (int, error) {
+	res, err := r.dbCon.Exec(`
+		UPDATE hospitals
+		SET licence_expired = ` + licenceExpiredExpr + `
+		WHERE licence_expired <> ` + licenceExpiredExpr)
+	if err != nil {
+		return 0, fmt.Errorf("error flagging expired licences: %w", err)
+	}
+	n, _ := res.RowsAffected()
+	return int(n), nil
+}
+
+// SetImageURL replaces the hospital's image URL and returns the previous one
+// so the caller can clean up the old file.
+func (r *hospitalRepo) SetImageURL(id int, url string) (string, error) {
+	var previous sql.NullString
+	err := r.dbCon.Get(&previous, `
+		UPDATE hospitals h
+		SET image_url = $2, updated_at = NOW()
+		FROM (SELECT hospital_id, image_url FROM hospitals WHERE hospital_id = $1 FOR UPDATE) old
+		WHERE h.hospital_id = old.hospital_id
+		RETURNING old.image_url
+	`, id, url)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return "", ErrNotFound
+		}
+		return "", fmt.Errorf("error updating hospital image: %w", err)
+	}
+	return previous.String, nil
+}
@@ -0,0 +1,663 @@
+package repo
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/csv"
+	"errors"
+	"fmt"
+	"io"
+	"maps"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/jmoiron/sqlx"
+	"github.com/lib/pq"
+)
+
+// Import kinds.
+const (
+	ImportHospitals    = "hospitals"
+	ImportDoctors      = "doctors"
+	ImportAffiliations = "affiliations"
+)
+
+var ImportKinds = []string{ImportHospitals, ImportDoctors, ImportAffiliations}
+
+// DefaultImportChunkSize is the number of rows committed together when an
+// import is not atomic.
+const DefaultImportChunkSize = 500
+
+var (
+	ErrUnknownImportKind = errors.New("unknown import kind")
+	ErrUnknownColumns    = errors.New("unknown columns")
+)
+
+// importColumns lists the CSV columns each import kind understands.
+var importColumns = map[string][]string{
+	ImportHospitals: {
+		"name", "address", "phone_number", "email", "latitude", "longitude",
+		"hospital_type", "dghs_licence_no", "licence_expiry", "bed_capacity", "website",
+		"open_24_7", "has_emergency", "emergency_phone",
+	},
+	ImportDoctors: {
+		"name", "specialty", "years_experience", "phone_number", "email",
+		"bmdc_reg_no", "gender", "languages",
+	},
+	ImportAffiliations: {
+		"hospital_id", "dghs_licence_no", "hospital_phone",
+		"doctor_id", "bmdc_reg_no", "doctor_phone",
+		"role", "fee_new_patient", "fee_follow_up", "fee_report_review", "fee_currency",
+	},
+}
+
+// ImportFile is a parsed CSV file. Line is the file line of each row, for
+// error reports.
+type ImportFile struct {
+	Columns []string
+	Rows    []ImportRow
+}
+
+type ImportRow struct {
+	Line   int
+	Fields map[string]string
+}
+
+// ImportOptions control how an import is written.
+type ImportOptions struct {
+	DryRun    bool // run every row, then roll back
+	Atomic    bool // a single transaction; any failed row rolls back everything
+	ChunkSize int  // rows per transaction when not atomic
+}
+
+type ImportRowError struct {
+	Line   int      `json:"line"`
+	Errors []string `json:"errors"`
+}
+
+// ImportReport summarizes an import. Created and Updated count the rows that
+// succeeded, whether or not they were committed.
+type ImportReport struct {
+	Kind      string           `json:"kind"`
+	DryRun    bool             `json:"dry_run"`
+	Atomic    bool             `json:"atomic"`
+	Total     int              `json:"total"`
+	Created   int              `json:"created"`
+	Updated   int              `json:"updated"`
+	Failed    int              `json:"failed"`
+	Committed bool             `json:"committed"`
+	Errors    []ImportRowError `json:"errors"`
+}
+
+type ImportRepo interface {
+	Import(kind string, file ImportFile, opts ImportOptions) (*ImportReport, error)
+}
+
+type importRepo struct {
+	db *sqlx.DB
+}
+
+func NewImportRepo(db *sqlx.DB) ImportRepo {
+	return &importRepo{db: db}
+}
+
+// ReadImportCSV reads a CSV file with a header row as exported by Excel or
+// Google Sheets: a UTF-8 byte order mark is skipped, a semicolon delimiter
+// is detected, headers are lower-cased with spaces turned into underscores
+// and cells are trimmed.
+func ReadImportCSV(r io.Reader) (ImportFile, error) {
+	br := bufio.NewReader(r)
+	if bom, _ := br.Peek(3); bytes.Equal(bom, []byte("\xef\xbb\xbf")) {
+		br.Discard(3)
+	}
+	head, _ := br.Peek(4096)
+	if i := bytes.IndexByte(head, '\n'); i >= 0 {
+		head = head[:i]
+	}
+
+	reader := csv.NewReader(br)
+	reader.FieldsPerRecord = -1
+	reader.TrimLeadingSpace = true
+	if bytes.Count(head, []byte(";")) > bytes.Count(head, []byte(",")) {
+		reader.Comma = ';'
+	}
+
+	header, err := reader.Read()
+	if err != nil {
+		if errors.Is(err, io.EOF) {
+			return ImportFile{}, errors.New("file is empty")
+		}
+		return ImportFile{}, fmt.Errorf("error reading header: %w", err)
+	}
+	file := ImportFile{}
+	for _, h := range header {
+		file.Columns = append(file.Columns, strings.ReplaceAll(strings.ToLower(strings.TrimSpace(h)), " ", "_"))
+	}
+
+	for {
+		record, err := reader.Read()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			return ImportFile{}, fmt.Errorf("error reading CSV: %w", err)
+		}
+		line, _ := reader.FieldPos(0)
+		row := ImportRow{Line: line, Fields: make(map[string]string, len(file.Columns))}
+		empty := true
+		for i, col := range file.Columns {
+			if i < len(record) {
+				row.Fields[col] = strings.TrimSpace(record[i])
+				empty = empty && row.Fields[col] == ""
+			}
+		}
+		if !empty {
+			file.Rows = append(file.Rows, row)
+		}
+	}
+	return file, nil
+}
+
+// Import validates and upserts every row of file. Hospitals are matched on
+// their DGHS licence number, phone or email, doctors on their BMDC number,
+// phone or email, and affiliations on the hospital and doctor pair. Empty
+// cells leave the existing value alone.
+func (r *importRepo) Import(kind string, file ImportFile, opts ImportOptions) (*ImportReport, error) {
+	known, ok := importColumns[kind]
+	if !ok {
+		return nil, ErrUnknownImportKind
+	}
+	var unknown []string
+	for _, col := range file.Columns {
+		if !slices.Contains(known, col) {
+			unknown = append(unknown, col)
+		}
+	}
+	if len(unknown) > 0 {
+		return nil, fmt.Errorf("%w for %s: %s", ErrUnknownColumns, kind, strings.Join(unknown, ", "))
+	}
+
+	report := &ImportReport{Kind: kind, DryRun: opts.DryRun, Atomic: opts.Atomic, Total: len(file.Rows), Errors: []ImportRowError{}}
+	chunkSize := opts.ChunkSize
+	if chunkSize <= 0 {
+		chunkSize = DefaultImportChunkSize
+	}
+	if opts.Atomic {
+		chunkSize = max(len(file.Rows), 1)
+	}
+
+	committed := 0
+	for start := 0; start < len(file.Rows); start += chunkSize {
+		end := min(start+chunkSize, len(file.Rows))
+		ok, err := r.importChunk(kind, file.Rows[start:end], opts, report)
+		if err != nil {
+			return nil, err
+		}
+		if ok {
+			committed++
+		}
+	}
+	report.Committed = committed > 0
+	return report, nil
+}
+
+// importChunk runs rows in one transaction, each inside a savepoint so that a
+// failing row doesn't abort the others. It reports whether it committed.
+func (r *importRepo) importChunk(kind string, rows []ImportRow, opts ImportOptions, report *ImportReport) (bool, error) {
+	tx, err := r.db.Beginx()
+	if err != nil {
+		return false, err
+	}
+	defer tx.Rollback()
+
+	failed := false
+	for _, row := range rows {
+		if _, err := tx.Exec(`SAVEPOINT import_row`); err != nil {
+			return false, err
+		}
+		created, errs, err := importRow(tx, kind, row.Fields)
+		if err != nil {
+			return false, fmt.Errorf("error importing line %d: %w", row.Line, err)
+		}
+		if len(errs) > 0 {
+			if _, err := tx.Exec(`ROLLBACK TO SAVEPOINT import_row`); err != nil {
+				return false, err
+			}
+			failed = true
+			report.Failed++
+			report.Errors = append(report.Errors, ImportRowError{Line: row.Line, Errors: errs})
+			continue
+		}
+		if _, err := tx.Exec(`RELEASE SAVEPOINT import_row`); err != nil {
+			return false, err
+		}
+		if created {
+			report.Created++
+		} else {
+			report.Updated++
+		}
+	}
+
+	if opts.DryRun || (opts.Atomic && failed) {
+		return false, nil
+	}
+	return true, tx.Commit()
+}
+
+// importRow validates and writes a single row. Validation and constraint
+// failures come back as messages; err is only set for failures that should
+// stop the whole import.
+func importRow(tx *sqlx.Tx, kind string, fields map[string]string) (bool, []string, error) {
+	p := &rowParser{fields: fields}
+	var created bool
+	var err error
+	switch kind {
+	case ImportHospitals:
+		created, err = importHospital(tx, p)
+	case ImportDoctors:
+		created, err = importDoctor(tx, p)
+	case ImportAffiliations:
+		created, err = importAffiliation(tx, p)
+	}
+	if len(p.errs) > 0 {
+		return false, p.errs, nil
+	}
+	if err != nil {
+		var pqErr *pq.Error
+		if errors.As(err, &pqErr) {
+			return false, []string{pqErr.Message}, nil
+		}
+		return false, nil, err
+	}
+	return created, nil, nil
+}
+
+// rowParser reads typed values out of a row and collects validation errors.
+type rowParser struct {
+	fields map[string]string
+	errs   []string
+}
+
+func (p *rowParser) fail(format string, args ...interface{}) {
+	p.errs = append(p.errs, fmt.Sprintf(format, args...))
+}
+
+func (p *rowParser) str(name string) string {
+	return p.fields[name]
+}
+
+func (p *rowParser) required(name string) string {
+	v := p.fields[name]
+	if v == "" {
+		p.fail("%s is required", name)
+	}
+	return v
+}
+
+func (p *rowParser) integer(name string) *int {
+	v := p.fields[name]
+	if v == "" {
+		return nil
+	}
+	n, err := strconv.Atoi(v)
+	if err != nil || n < 0 {
+		p.fail("%s must be a non-negative whole number", name)
+		return nil
+	}
+	return &n
+}
+
+func (p *rowParser) number(name string, min, max float64) *float64 {
+	v := p.fields[name]
+	if v == "" {
+		return nil
+	}
+	n, err := strconv.ParseFloat(v, 64)
+	if err != nil || n < min || n > max {
+		p.fail("%s must be a number between %g and %g", name, min, max)
+		return nil
+	}
+	return &n
+}
+
+func (p *rowParser) boolean(name string) *bool {
+	switch strings.ToLower(p.fields[name]) {
+	case "":
+		return nil
+	case "true", "yes", "y", "1":
+		b := true
+		return &b
+	case "false", "no", "n", "0":
+		b := false
+		return &b
+	}
+	p.fail("%s must be yes or no", name)
+	return nil
+}
+
+func (p *rowParser) date(name string) *string {
+	v := p.fields[name]
+	if v == "" {
+		return nil
+	}
+	if _, err := time.Parse("2006-01-02", v); err != nil {
+		p.fail("%s must be a YYYY-MM-DD date", name)
+		return nil
+	}
+	return &v
+}
+
+func (p *rowParser) oneOf(name string, allowed []string) string {
+	v := strings.ToLower(p.fields[name])
+	if v != "" && !slices.Contains(allowed, v) {
+		p.fail("%s must be one of %s", name, strings.Join(allowed, ", "))
+	}
+	return v
+}
+
+// list splits a cell on commas, semicolons or pipes.
+func (p *rowParser) list(name string) pq.StringArray {
+	v := p.fields[name]
+	if v == "" {
+		return nil
+	}
+	list := pq.StringArray{}
+	for _, item := range strings.FieldsFunc(v, func(r rune) bool { return r == ',' || r == ';' || r == '|' }) {
+		if item = strings.TrimSpace(item); item != "" {
+			list = append(list, item)
+		}
+	}
+	return list
+}
+
+// importValues are the non-empty columns of a row, in insertion order.
+type importValues struct {
+	columns []string
+	values  []interface{}
+}
+
+// set records value unless it is empty, so empty cells never overwrite.
+func (v *importValues) set(column string, value interface{}) {
+	switch val := value.(type) {
+	case string:
+		if val == "" {
+			return
+		}
+	case *string:
+		if val == nil {
+			return
+		}
+	case *int:
+		if val == nil {
+			return
+		}
+	case *float64:
+		if val == nil {
+			return
+		}
+	case *bool:
+		if val == nil {
+			return
+		}
+	case pq.StringArray:
+		if val == nil {
+			return
+		}
+	}
+	v.columns = append(v.columns, column)
+	v.values = append(v.values, value)
+}
+
+// matchExisting finds the single row of table whose column equals one of the
+// given keys. Empty keys are skipped; keys matching different rows are an
+// error.
+func matchExisting(tx *sqlx.Tx, table, idColumn string, keys map[string]string, p *rowParser) (int, bool, error) {
+	var conditions []string
+	var args []interface{}
+	for _, column := range slices.Sorted(maps.Keys(keys)) {
+		if keys[column] == "" {
+			continue
+		}
+		args = append(args, keys[column])
+		conditions = append(conditions, fmt.Sprintf("%s = $%d", column, len(args)))
+	}
+	if len(conditions) == 0 {
+		return 0, false, nil
+	}
+	var ids []int
+	query := fmt.Sprintf(`SELECT %s FROM %s WHERE %s ORDER BY %s LIMIT 2 FOR UPDATE`,
+		idColumn, table, strings.Join(conditions, " OR "), idColumn)
+	if err := tx.Select(&ids, query, args...); err != nil {
+		return 0, false, err
+	}
+	switch len(ids) {
+	case 0:
+		return 0, false, nil
+	case 1:
+		return ids[0], true, nil
+	}
+	p.fail("matches more than one existing record (ids %d and %d)", ids[0], ids[1])
+	return 0, false, nil
+}
+
+// upsert inserts values into table, or updates the row with the given id
+// when exists is set. It returns the row's id.
+func upsert(tx *sqlx.Tx, table, idColumn string, id int, exists bool, v importValues) (int, error) {
+	if exists {
+		sets := []string{"updated_at = NOW()"}
+		for i, column := range v.columns {
+			sets = append(sets, fmt.Sprintf("%s = $%d", column, i+1))
+		}
+		query := fmt.Sprintf(`UPDATE %s SET %s WHERE %s = $%d`, table, strings.Join(sets, ", "), idColumn, len(v.values)+1)
+		_, err := tx.Exec(query, append(v.values, id)...)
+		return id, err
+	}
+	placeholders := make([]string, len(v.columns))
+	for i := range v.columns {
+		placeholders[i] = fmt.Sprintf("$%d", i+1)
+	}
+	query := fmt.Sprintf(`INSERT INTO %s (%s) VALUES (%s) RETURNING %s`,
+		table, strings.Join(v.columns, ", "), strings.Join(placeholders, ", "), idColumn)
+	err := tx.Get(&id, query, v.values...)
+	return id, err
+}
+
+func importHospital(tx *sqlx.Tx, p *rowParser) (bool, error) {
+	var v importValues
+	v.set("name", p.required("name"))
+	v.set("address", p.str("address"))
+	v.set("phone_number", p.str("phone_number"))
+	v.set("email", p.str("email"))
+	v.set("latitude", p.number("latitude", -90, 90))
+	v.set("longitude", p.number("longitude", -180, 180))
+	v.set("hospital_type", p.oneOf("hospital_type", HospitalTypes))
+	licence := strings.ToUpper(p.str("dghs_licence_no"))
+	v.set("dghs_licence_no", licence)
+	v.set("licence_expiry", p.date("licence_expiry"))
+	v.set("bed_capacity", p.integer("bed_capacity"))
+	v.set("website", p.str("website"))
+	v.set("open_24_7", p.boolean("open_24_7"))
+	v.set("has_emergency", p.boolean("has_emergency"))
+	v.set("emergency_phone", p.str("emergency_phone"))
+	if len(p.errs) > 0 {
+		return false, nil
+	}
+
+	id, exists, err := matchExisting(tx, "hospitals", "hospital_id", map[string]string{
+		"dghs_licence_no": licence,
+		"phone_number":    p.str("phone_number"),
+		"email":           p.str("email"),
+	}, p)
+	if err != nil || len(p.errs) > 0 {
+		return false, err
+	}
+	id, err = upsert(tx, "hospitals", "hospital_id", id, exists, v)
+	if err != nil {
+		return false, err
+	}
+	_, err = tx.Exec(`UPDATE hospitals SET licence_expired = `+licenceExpiredExpr+` WHERE hospital_id = $1`, id)
+	if err != nil {
+		return false, err
+	}
+	var saved Hospital
+	if err := tx.Get(&saved, `SELECT `+hospitalColumns+` FROM hospitals h WHERE h.hospital_id = $1`, id); err != nil {
+		return false, err
+	}
+	return !exists, publishImported(tx, exists, EventHospitalCreated, EventHospitalUpdated, &saved)
+}
+
+func importDoctor(tx *sqlx.Tx, p *rowParser) (bool, error) {
+	var v importValues
+	v.set("name", p.required("name"))
+	v.set("specialty", p.str("specialty"))
+	v.set("years_experience", p.integer("years_experience"))
+	v.set("phone_number", p.str("phone_number"))
+	v.set("email", p.str("email"))
+	bmdc := p.str("bmdc_reg_no")
+	if bmdc != "" {
+		var ok bool
+		if bmdc, ok = NormalizeBMDC(bmdc); !ok {
+			p.fail("bmdc_reg_no must look like A-12345")
+		}
+	}
+	v.set("bmdc_reg_no", bmdc)
+	v.set("gender", p.oneOf("gender", []string{"male", "female", "other"}))
+	v.set("languages", p.list("languages"))
+	if len(p.errs) > 0 {
+		return false, nil
+	}
+
+	id, exists, err := matchExisting(tx, "doctors", "doctor_id", map[string]string{
+		"bmdc_reg_no":  bmdc,
+		"phone_number": p.str("phone_number"),
+		"email":        p.str("email"),
+	}, p)
+	if err != nil || len(p.errs) > 0 {
+		return false, err
+	}
+	if exists && bmdc != "" {
+		// A new registration number needs verifying again, as in doctorRepo.Update.
+		_, err = tx.Exec(`
+			UPDATE doctors SET verification_status = 'unverified', verified_at = NULL
+			WHERE doctor_id = $1 AND bmdc_reg_no IS DISTINCT FROM $2
+		`, id, bmdc)
+		if err != nil {
+			return false, err
+		}
+	}
+	id, err = upsert(tx, "doctors", "doctor_id", id, exists, v)
+	if err != nil {
+		return false, err
+	}
+	var saved Doctor
+	if err := tx.Get(&saved, `SELECT * FROM doctors WHERE doctor_id = $1`, id); err != nil {
+		return false, err
+	}
+	return !exists, publishImported(tx, exists, EventDoctorCreated, EventDoctorUpdated, &saved)
+}
+
+// resolveImportID finds a hospital or doctor for an affiliation row by its id
+// column or, failing that, by one of its unique keys.
+func resolveImportID(tx *sqlx.Tx, p *rowParser, label, table, idColumn, idField string, keys map[string]string) int {
+	before := len(p.errs)
+	if ref := p.integer(idField); ref != nil {
+		keys = map[string]string{idColumn: strconv.Itoa(*ref)}
+	}
+	id, found, err := matchExisting(tx, table, idColumn, keys, p)
+	if err != nil {
+		p.fail("error looking up %s: %v", label, err)
+		return 0
+	}
+	if !found && len(p.errs) == before {
+		p.fail("%s not found", label)
+	}
+	return id
+}
+
+func importAffiliation(tx *sqlx.Tx, p *rowParser) (bool, error) {
+	hospitalID := resolveImportID(tx, p, "hospital", "hospitals", "hospital_id", "hospital_id", map[string]string{
+		"dghs_licence_no": strings.ToUpper(p.str("dghs_licence_no")),
+		"phone_number":    p.str("hospital_phone"),
+	})
+	bmdc := p.str("bmdc_reg_no")
+	if bmdc != "" {
+		bmdc, _ = NormalizeBMDC(bmdc)
+	}
+	doctorID := resolveImportID(tx, p, "doctor", "doctors", "doctor_id", "doctor_id", map[string]string{
+		"bmdc_reg_no":  bmdc,
+		"phone_number": p.str("doctor_phone"),
+	})
+
+	var v importValues
+	v.set("role", p.str("role"))
+	v.set("fee_new_patient", p.number("fee_new_patient", 0, 1e7))
+	v.set("fee_follow_up", p.number("fee_follow_up", 0, 1e7))
+	v.set("fee_report_review", p.number("fee_report_review", 0, 1e7))
+	currency := strings.ToUpper(p.str("fee_currency"))
+	if currency != "" && len(currency) != 3 {
+		p.fail("fee_currency must be a 3-letter ISO 4217 code")
+	}
+	v.set("fee_currency", currency)
+	if len(p.errs) > 0 {
+		return false, nil
+	}
+
+	var existing []int
+	err := tx.Select(&existing, `
+		SELECT 1 FROM hospital_doctor WHERE hospital_id = $1 AND doctor_id = $2 FOR UPDATE
+	`, hospitalID, doctorID)
+	if err != nil {
+		return false, err
+	}
+	if len(existing) > 0 {
+		if len(v.columns) == 0 {
+			return false, nil
+		}
+		sets := []string{"updated_at = NOW()"}
+		for i, column := range v.columns {
+			sets = append(sets, fmt.Sprintf("%s = $%d", column, i+3))
+		}
+		_, err = tx.Exec(`UPDATE hospital_doctor SET `+strings.Join(sets, ", ")+` WHERE hospital_id = $1 AND doctor_id = $2`,
+			append([]interface{}{hospitalID, doctorID}, v.values...)...)
+		if err != nil {
+			return false, err
+		}
+		return false, publishAffiliation(tx, hospitalID, doctorID, true)
+	}
+
+	v.columns = append([]string{"hospital_id", "doctor_id"}, v.columns...)
+	v.values = append([]interface{}{hospitalID, doctorID}, v.values...)
+	placeholders := make([]string, len(v.columns))
+	for i := range v.columns {
+		placeholders[i] = fmt.Sprintf("$%d", i+1)
+	}
+	_, err = tx.Exec(`INSERT INTO hospital_doctor (`+strings.Join(v.columns, ", ")+`) VALUES (`+strings.Join(placeholders, ", ")+`)`, v.values...)
+	if err != nil {
+		return false, err
+	}
+	return true, publishAffiliation(tx, hospitalID, doctorID, false)
+}
+
+func publishAffiliation(tx *sqlx.Tx, hospitalID, doctorID int, exists bool) error {
+	var saved HospitalDoctor
+	err := tx.Get(&saved, `SELECT `+hospitalDoctorColumns+` FROM hospital_doctor WHERE hospital_id = $1 AND doctor_id = $2`,
+		hospitalID, doctorID)
+	if err != nil {
+		return err
+	}
+	return publishImported(tx, exists, EventHospitalDoctorCreated, EventHospitalDoctorUpdated, &saved)
+}
+
+// publishImported queues the created or updated event for a saved row, with
+// the same payload the REST endpoints send. It runs on tx, inside the row's
+// savepoint, so nothing is sent for failed rows, dry runs or rolled back
+// atomic imports.
+func publishImported(tx *sqlx.Tx, exists bool, created, updated string, data interface{}) error {
+	eventType := created
+	if exists {
+		eventType = updated
+	}
+	return publishEvent(tx, eventType, data)
+}
@@ -0,0 +1,108 @@
+package repo
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestRowParser(t *testing.T) {
+	tests := []struct {
+		name     string
+		value    string
+		parse    func(p *rowParser) interface{}
+		want     interface{}
+		wantErrs []string
+	}{
+		{name: "required", value: "Square Hospital", parse: func(p *rowParser) interface{} { return p.required("v") }, want: "Square Hospital"},
+		{name: "required missing", value: "", parse: func(p *rowParser) interface{} { return p.required("v") }, want: "", wantErrs: []string{"v is required"}},
+		{name: "integer", value: "250", parse: func(p *rowParser) interface{} { return *p.integer("v") }, want: 250},
+		{name: "integer empty", value: "", parse: func(p *rowParser) interface{} { return p.integer("v") == nil }, want: true},
+		{name: "integer negative", value: "-1", parse: func(p *rowParser) interface{} { return p.integer("v") == nil }, want: true, wantErrs: []string{"v must be a non-negative whole number"}},
+		{name: "integer fraction", value: "2.5", parse: func(p *rowParser) interface{} { return p.integer("v") == nil }, want: true, wantErrs: []string{"v must be a non-negative whole number"}},
+		{name: "number", value: "23.81", parse: func(p *rowParser) interface{} { return *p.number("v", -90, 90) }, want: 23.81},
+		{name: "number out of range", value: "91", parse: func(p *rowParser) interface{} { return p.number("v", -90, 90) == nil }, want: true, wantErrs: []string{"v must be a number between -90 and 90"}},
+		{name: "boolean yes", value: "Yes", parse: func(p *rowParser) interface{} { return *p.boolean("v") }, want: true},
+		{name: "boolean 0", value: "0", parse: func(p *rowParser) interface{} { return *p.boolean("v") }, want: false},
+		{name: "boolean empty", value: "", parse: func(p *rowParser) interface{} { return p.boolean("v") == nil }, want: true},
+		{name: "boolean invalid", value: "maybe", parse: func(p *rowParser) interface{} { return p.boolean("v") == nil }, want: true, wantErrs: []string{"v must be yes or no"}},
+		{name: "date", value: "2027-06-30", parse: func(p *rowParser) interface{} { return *p.date("v") }, want: "2027-06-30"},
+		{name: "date invalid", value: "30/06/2027", parse: func(p *rowParser) interface{} { return p.date("v") == nil }, want: true, wantErrs: []string{"v must be a YYYY-MM-DD date"}},
+		{name: "oneOf is case-insensitive", value: "Private", parse: func(p *rowParser) interface{} { return p.oneOf("v", HospitalTypes) }, want: "private"},
+		{name: "oneOf invalid", value: "military", parse: func(p *rowParser) interface{} { return p.oneOf("v", []string{"a", "b"}) }, want: "military", wantErrs: []string{"v must be one of a, b"}},
+		{name: "list", value: "Bangla, English;Hindi | ", parse: func(p *rowParser) interface{} { return []string(p.list("v")) }, want: []string{"Bangla", "English", "Hindi"}},
+		{name: "list empty", value: "", parse: func(p *rowParser) interface{} { return p.list("v") == nil }, want: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			p := &rowParser{fields: map[string]string{"v": tt.value}}
+			if got := tt.parse(p); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("parsed %q as %v, want %v", tt.value, got, tt.want)
+			}
+			if !reflect.DeepEqual(p.errs, tt.wantErrs) {
+				t.Errorf("errors = %q, want %q", p.errs, tt.wantErrs)
+			}
+		})
+	}
+}
+
+// Invalid rows are rejected before the database is touched, so importRow
+// runs here without a transaction.
+func TestImportRowValidation(t *testing.T) {
+	tests := []struct {
+		name     string
+		kind     string
+		fields   map[string]string
+		wantErrs []string
+	}{
+		{
+			name:     "hospital without a name",
+			kind:     ImportHospitals,
+			fields:   map[string]string{"address": "Panthapath"},
+			wantErrs: []string{"name is required"},
+		},
+		{
+			name: "hospital with invalid cells",
+			kind: ImportHospitals,
+			fields: map[string]string{
+				"name":           "Square Hospital",
+				"latitude":       "123",
+				"hospital_type":  "military",
+				"licence_expiry": "next year",
+				"bed_capacity":   "many",
+				"open_24_7":      "sometimes",
+			},
+			wantErrs: []string{
+				"latitude must be a number between -90 and 90",
+				"hospital_type must be one of government, private, ngo",
+				"licence_expiry must be a YYYY-MM-DD date",
+				"bed_capacity must be a non-negative whole number",
+				"open_24_7 must be yes or no",
+			},
+		},
+		{
+			name:     "doctor with an invalid registration number",
+			kind:     ImportDoctors,
+			fields:   map[string]string{"name": "Dr. Rahman", "bmdc_reg_no": "12-ABC", "gender": "unknown"},
+			wantErrs: []string{"bmdc_reg_no must look like A-12345", "gender must be one of male, female, other"},
+		},
+		{
+			name:     "affiliation without hospital or doctor",
+			kind:     ImportAffiliations,
+			fields:   map[string]string{"fee_new_patient": "-5", "fee_currency": "TAKA"},
+			wantErrs: []string{"hospital not found", "doctor not found", "fee_new_patient must be a number between 0 and 1e+07", "fee_currency must be a 3-letter ISO 4217 code"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			created, errs, err := importRow(nil, tt.kind, tt.fields)
+			if err != nil || created {
+				t.Fatalf("importRow() = %t, %v, want false, nil", created, err)
+			}
+			if !reflect.DeepEqual(errs, tt.wantErrs) {
+				t.Errorf("importRow() errors = %q, want %q", errs, tt.wantErrs)
+			}
+		})
+	}
+}
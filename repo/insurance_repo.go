@@ -0,0 +1,128 @@
+package repo
+
+import (
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/jmoiron/sqlx"
+)
+
+var (
+	ErrPanelNotFound  = errors.New("insurance panel not found")
+	ErrDuplicatePanel = errors.New("insurance panel code already exists")
+)
+
+// InsurancePanel is an insurance company or corporate panel whose members
+// hospitals may treat on credit.
+type InsurancePanel struct {
+	PanelID   int       `json:"panel_id" db:"panel_id"`
+	Code      string    `json:"code" db:"code"`
+	Name      string    `json:"name" db:"name"`
+	PanelType string    `json:"panel_type" db:"panel_type"`
+	CreatedAt time.Time `json:"created_at" db:"created_at"`
+	UpdatedAt time.Time `json:"updated_at" db:"updated_at"`
+}
+
+// HospitalInsurance is a panel accepted by a specific hospital.
+type HospitalInsurance struct {
+	InsurancePanel
+	Notes string `json:"notes" db:"notes"`
+}
+
+type InsuranceRepo interface {
+	Create(p InsurancePanel) (*InsurancePanel, error)
+	List() ([]InsurancePanel, error)
+	Delete(id int) error
+	ListByHospital(hospitalID int) ([]HospitalInsurance, error)
+	AddToHospital(hospitalID, panelID int, notes string) error
+	RemoveFromHospital(hospitalID, panelID int) error
+}
+
+type insuranceRepo struct {
+	db *sqlx.DB
+}
+
+func NewInsuranceRepo(db *sqlx.DB) InsuranceRepo {
+	return &insuranceRepo{db: db}
+}
+
+func (r *insuranceRepo) Create(p InsurancePanel) (*InsurancePanel, error) {
+	var created InsurancePanel
+	err := r.db.Get(&created, `
+		INSERT INTO insurance_panels (code, name, panel_type)
+		VALUES ($1, $2, $3)
+		RETURNING *
+	`, p.Code, p.Name, p.PanelType)
+	if err != nil {
+		if isUniqueViolation(err) {
+			return nil, ErrDuplicatePanel
+		}
+		return nil, fmt.Errorf("error creating insurance panel: %w", err)
+	}
+	return &created, nil
+}
+
+func (r *insuranceRepo) List() ([]InsurancePanel, error) {
+	list := []InsurancePanel{}
+	err := r.db.Select(&list, `SELECT * FROM insurance_panels ORDER BY panel_type, name`)
+	if err != nil {
+		return nil, fmt.Errorf("error fetching insurance panels: %w", err)
+	}
+	return list, nil
+}
+
+func (r *insuranceRepo) Delete(id int) error {
+	res, err := r.db.Exec(`DELETE FROM insurance_panels WHERE panel_id = $1`, id)
+	if err != nil {
+		return fmt.Errorf("error deleting insurance panel: %w", err)
+	}
+	if rows, _ := res.RowsAffected(); rows == 0 {
+		return ErrPanelNotFound
+	}
+	return nil
+}
+
+func (r *insuranceRepo) ListByHospital(hospitalID int) ([]HospitalInsurance, error) {
+	return listHospitalInsurance(r.db, hospitalID)
+}
+
+func (r *insuranceRepo) AddToHospital(hospitalID, panelID int, notes string) error {
+	_, err := r.db.Exec(`
+		INSERT INTO hospital_insurance (hospital_id, panel_id, notes)
+		VALUES ($1, $2, $3)
+		ON CONFLICT (hospital_id, panel_id) DO UPDATE SET notes = EXCLUDED.notes
+	`, hospitalID, panelID, notes)
+	if isForeignKeyViolation(err) {
+		return ErrNotFound
+	}
+	return err
+}
+
+func (r *insuranceRepo) RemoveFromHospital(hospitalID, panelID int) error {
+	res, err := r.db.Exec(`DELETE FROM hospital_insurance WHERE hospital_id = $1 AND panel_id = $2`, hospitalID, panelID)
+	if err != nil {
+		return err
+	}
+	if rows, _ := res.RowsAffected(); rows == 0 {
+		return ErrPanelNotFound
+	}
+	return nil
+}
+
+// listHospitalInsurance is shared with hospitalRepo.Get, which embeds the
+// accepted panels in the single-hospital response.
+func listHospitalInsurance(db *sqlx.DB, hospitalID int) ([]HospitalInsurance, error) {
+	list := []HospitalInsurance{}
+	query := `
+		SELECT p.*, hi.notes
+		FROM insurance_panels p
+		JOIN hospital_insurance hi ON hi.panel_id = p.panel_id
+		WHERE hi.hospital_id = $1
+		ORDER BY p.panel_type, p.name
+	`
+	if err := db.Select(&list, query, hospitalID); err != nil {
+		return nil, fmt.Errorf("error fetching hospital insurance panels: %w", err)
+	}
+	return list, nil
+}
@@ -0,0 +1,110 @@
+package repo
+
+import (
+	"database/sql"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/jmoiron/sqlx"
+)
+
+var ErrPatientNotFound = errors.New("patient not found")
+
+type Patient struct {
+	PatientID   int       `json:"patient_id" db:"patient_id"`
+	PhoneNumber string    `json:"phone_number" db:"phone_number"`
+	Name        string    `json:"name" db:"name"`
+	Email       string    `json:"email" db:"email"`
+	Gender      string    `json:"gender" db:"gender"`
+	DateOfBirth *string   `json:"date_of_birth" db:"date_of_birth"`
+	CreatedAt   time.Time `json:"created_at" db:"created_at"`
+	UpdatedAt   time.Time `json:"updated_at" db:"updated_at"`
+}
+
+type PatientRepo interface {
+	GetOrCreateByPhone(phone string) (*Patient, bool, error)
+	Get(id int) (*Patient, error)
+	Update(p Patient) (*Patient, error)
+}
+
+type patientRepo struct {
+	db *sqlx.DB
+}
+
+func NewPatientRepo(db *sqlx.DB) PatientRepo {
+	return &patientRepo{db: db}
+}
+
+const patientColumns = `
+	patient_id,
+	phone_number,
+	name,
+	email,
+	gender,
+	TO_CHAR(date_of_birth, 'YYYY-MM-DD') AS date_of_birth,
+	created_at,
+	updated_at
+`
+
+// GetOrCreateByPhone returns the patient registered with phone, registering
+// one on first login. The bool reports whether the patient was just created.
+func (r *patientRepo) GetOrCreateByPhone(phone string) (*Patient, bool, error) {
+	var p Patient
+	err := r.db.Get(&p, `
+		INSERT INTO patients (phone_number)
+		VALUES ($1)
+		ON CONFLICT (phone_number) DO NOTHING
+		RETURNING `+patientColumns, phone)
+	if err == nil {
+		return &p, true, nil
+	}
+	if !errors.Is(err, sql.ErrNoRows) {
+		return nil, false, fmt.Errorf("error registering patient: %w", err)
+	}
+
+	err = r.db.Get(&p, `SELECT `+patientColumns+` FROM patients WHERE phone_number = $1`, phone)
+	if err != nil {
+		return nil, false, fmt.Errorf("error fetching patient: %w", err)
+	}
+	return &p, false, nil
+}
+
+func (r *patientRepo) Get(id int) (*Patient, error) {
+	var p Patient
+	err := r.db.Get(&p, `SELECT `+patientColumns+` FROM patients WHERE patient_id = $1`, id)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, ErrPatientNotFound
+		}
+		return nil, fmt.Errorf("error fetching patient: %w", err)
+	}
+	return &p, nil
+}
+
+func (r *patientRepo) Update(p Patient) (*Patient, error) {
+	query := `
+		UPDATE patients
+		SET
+		  name = :name,
+		  email = :email,
+		  gender = :gender,
+		  date_of_birth = :date_of_birth,
+		  updated_at = NOW()
+		WHERE patient_id = :patient_id
+		RETURNING ` + patientColumns
+	rows, err := r.db.NamedQuery(query, p)
+	if err != nil {
+		return nil, fmt.Errorf("error updating patient: %w", err)
+	}
+	defer rows.Close()
+
+	if rows.Next() {
+		var updated Patient
+		if err := rows.StructScan(&updated); err != nil {
+			return nil, err
+		}
+		return &updated, nil
+	}
+	return nil, ErrPatientNotFound
+}
@@ -0,0 +1,30 @@
+package repo
+
+import (
+	"errors"
+
+	"github.com/lib/pq"
+)
+
+func pqErrorCode(err error) pq.ErrorCode {
+	var pqErr *pq.Error
+	if errors.As(err, &pqErr) {
+		return pqErr.Code
+	}
+	return ""
+}
+
+func isUniqueViolation(err error) bool {
+	return pqErrorCode(err) == "23505"
+}
+
+func isForeignKeyViolation(err error) bool {
+	return pqErrorCode(err) == "23503"
+}
+
+// isUniqueViolationOn reports whether err violates the named unique
+// constraint, for tables with more than one.
+func isUniqueViolationOn(err error, constraint string) bool {
+	var pqErr *pq.Error
+	return errors.As(err, &pqErr) && pqErr.Code == "23505" && pqErr.Constraint == constraint
+}
@@ -0,0 +1,292 @@
+package repo
+
+import (
+	"database/sql"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/jmoiron/sqlx"
+)
+
+var (
+	ErrSerialNotFound = errors.New("serial not found in today's queue")
+	ErrQueueEmpty     = errors.New("no waiting serials in the queue")
+	ErrAlreadyQueued  = errors.New("patient already holds a serial in this queue")
+	ErrTooManySerials = errors.New("too many open serials")
+)
+
+// Serial statuses.
+const (
+	SerialWaiting = "waiting"
+	SerialServing = "serving"
+	SerialDone    = "done"
+	SerialSkipped = "skipped"
+)
+
+type QueueSerial struct {
+	SerialID     int       `json:"serial_id" db:"serial_id"`
+	SerialNumber int       `json:"serial_number" db:"serial_number"`
+	PatientName  string    `json:"patient_name" db:"patient_name"`
+	PatientPhone string    `json:"patient_phone" db:"patient_phone"`
+	PatientID    *int      `json:"patient_id" db:"patient_id"`
+	Status       string    `json:"status" db:"status"`
+	CreatedAt    time.Time `json:"created_at" db:"created_at"`
+	UpdatedAt    time.Time `json:"updated_at" db:"updated_at"`
+}
+
+// QueueStatus is the public view of a day's queue.
+type QueueStatus struct {
+	HospitalID    int    `json:"hospital_id" db:"hospital_id"`
+	DoctorID      int    `json:"doctor_id" db:"doctor_id"`
+	Date          string `json:"date" db:"queue_date"`
+	CurrentSerial int    `json:"current_serial" db:"current_serial"`
+	LastSerial    int    `json:"last_serial" db:"last_serial"`
+	WaitingCount  int    `json:"waiting_count" db:"waiting_count"`
+	Skipped       []int  `json:"skipped" db:"-"`
+}
+
+type QueueRepo interface {
+	Status(hospitalID, doctorID int, date string) (*QueueStatus, error)
+	ListSerials(hospitalID, doctorID int, date string) ([]QueueSerial, error)
+	Issue(hospitalID, doctorID int, date string, s QueueSerial, maxOpen int) (*QueueSerial, error)
+	CallNext(hospitalID, doctorID int, date string) (*QueueStatus, error)
+	Call(hospitalID, doctorID int, date string, serial int) (*QueueStatus, error)
+	Skip(hospitalID, doctorID int, date string, serial int) (*QueueStatus, error)
+}
+
+type queueRepo struct {
+	db *sqlx.DB
+}
+
+func NewQueueRepo(db *sqlx.DB) QueueRepo {
+	return &queueRepo{db: db}
+}
+
+func (r *queueRepo) Status(hospitalID, doctorID int, date string) (*QueueStatus, error) {
+	status := QueueStatus{
+		HospitalID: hospitalID,
+		DoctorID:   doctorID,
+		Date:       date,
+		Skipped:    []int{},
+	}
+	err := r.db.Get(&status, `
+		SELECT
+		  q.hospital_id,
+		  q.doctor_id,
+		  TO_CHAR(q.queue_date, 'YYYY-MM-DD') AS queue_date,
+		  q.current_serial,
+		  q.last_serial,
+		  (SELECT COUNT(*) FROM queue_serials s WHERE s.queue_id = q.queue_id AND s.status = 'waiting') AS waiting_count
+		FROM chamber_queues q
+		WHERE q.hospital_id = $1 AND q.doctor_id = $2 AND q.queue_date = $3
+	`, hospitalID, doctorID, date)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			// No serial issued yet today.
+			return &status, nil
+		}
+		return nil, fmt.Errorf("error fetching queue: %w", err)
+	}
+
+	err = r.db.Select(&status.Skipped, `
+		SELECT s.serial_number
+		FROM queue_serials s
+		JOIN chamber_queues q ON q.queue_id = s.queue_id
+		WHERE q.hospital_id = $1 AND q.doctor_id = $2 AND q.queue_date = $3
+		  AND s.status = 'skipped'
+		ORDER BY s.serial_number
+	`, hospitalID, doctorID, date)
+	if err != nil {
+		return nil, fmt.Errorf("error fetching skipped serials: %w", err)
+	}
+	return &status, nil
+}
+
+func (r *queueRepo) ListSerials(hospitalID, doctorID int, date string) ([]QueueSerial, error) {
+	list := []QueueSerial{}
+	err := r.db.Select(&list, `
+		SELECT s.serial_id, s.serial_number, s.patient_name, s.patient_phone,
+		       s.patient_id, s.status, s.created_at, s.updated_at
+		FROM queue_serials s
+		JOIN chamber_queues q ON q.queue_id = s.queue_id
+		WHERE q.hospital_id = $1 AND q.doctor_id = $2 AND q.queue_date = $3
+		ORDER BY s.serial_number
+	`, hospitalID, doctorID, date)
+	if err != nil {
+		return nil, fmt.Errorf("error fetching serials: %w", err)
+	}
+	return list, nil
+}
+
+// Issue hands out the next serial number. Incrementing last_serial in the
+// upsert makes concurrent issues get distinct numbers. A patient may hold one
+// unfinished serial per queue and maxOpen across the day's queues; the
+// patient's lock keeps two concurrent issues from both passing the check.
+func (r *queueRepo) Issue(hospitalID, doctorID int, date string, s QueueSerial, maxOpen int) (*QueueSerial, error) {
+	tx, err := r.db.Beginx()
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback()
+
+	if _, err := tx.Exec(`SELECT pg_advisory_xact_lock(hashtext('serial:patient:' || $1))`, s.PatientID); err != nil {
+		return nil, fmt.Errorf("error locking patient serials: %w", err)
+	}
+	var held struct {
+		Open int `db:"open"`
+		Here int `db:"here"`
+	}
+	err = tx.Get(&held, `
+		SELECT COUNT(*) AS open,
+		       COUNT(*) FILTER (WHERE q.hospital_id = $2 AND q.doctor_id = $3) AS here
+		FROM queue_serials s
+		JOIN chamber_queues q ON q.queue_id = s.queue_id
+		WHERE s.patient_id = $1 AND q.queue_date = $4 AND s.status <> 'done'
+	`, s.PatientID, hospitalID, doctorID, date)
+	if err != nil {
+		return nil, fmt.Errorf("error counting open serials: %w", err)
+	}
+	if held.Here > 0 {
+		return nil, ErrAlreadyQueued
+	}
+	if held.Open >= maxOpen {
+		return nil, ErrTooManySerials
+	}
+
+	var queue struct {
+		QueueID    int `db:"queue_id"`
+		LastSerial int `db:"last_serial"`
+	}
+	err = tx.Get(&queue, `
+		INSERT INTO chamber_queues (hospital_id, doctor_id, queue_date, last_serial)
+		VALUES ($1, $2, $3, 1)
+		ON CONFLICT (hospital_id, doctor_id, queue_date)
+		DO UPDATE SET last_serial = chamber_queues.last_serial + 1, updated_at = NOW()
+		RETURNING queue_id, last_serial
+	`, hospitalID, doctorID, date)
+	if err != nil {
+		if isForeignKeyViolation(err) {
+			return nil, ErrNoAffiliation
+		}
+		return nil, fmt.Errorf("error issuing serial: %w", err)
+	}
+
+	var issued QueueSerial
+	err = tx.Get(&issued, `
+		INSERT INTO queue_serials (queue_id, serial_number, patient_name, patient_phone, patient_id)
+		VALUES ($1, $2, $3, $4, $5)
+		RETURNING serial_id, serial_number, patient_name, patient_phone,
+		          patient_id, status, created_at, updated_at
+	`, queue.QueueID, queue.LastSerial, s.PatientName, s.PatientPhone, s.PatientID)
+	if err != nil {
+		return nil, fmt.Errorf("error storing serial: %w", err)
+	}
+	if err := tx.Commit(); err != nil {
+		return nil, err
+	}
+	return &issued, nil
+}
+
+// lockQueue locks the day's queue row and returns its ID.
+func lockQueue(tx *sqlx.Tx, hospitalID, doctorID int, date string) (int, error) {
+	var queueID int
+	err := tx.Get(&queueID, `
+		SELECT queue_id FROM chamber_queues
+		WHERE hospital_id = $1 AND doctor_id = $2 AND queue_date = $3
+		FOR UPDATE
+	`, hospitalID, doctorID, date)
+	if errors.Is(err, sql.ErrNoRows) {
+		return 0, ErrSerialNotFound
+	}
+	return queueID, err
+}
+
+// call makes serial the one being seen, finishing the previous one.
+func (r *queueRepo) call(hospitalID, doctorID int, date string, pick func(tx *sqlx.Tx, queueID int) (int, error)) (*QueueStatus, error) {
+	tx, err := r.db.Beginx()
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback()
+
+	queueID, err := lockQueue(tx, hospitalID, doctorID, date)
+	if err != nil {
+		return nil, err
+	}
+	serial, err := pick(tx, queueID)
+	if err != nil {
+		return nil, err
+	}
+
+	_, err = tx.Exec(`
+		UPDATE queue_serials SET status = 'done', updated_at = NOW()
+		WHERE queue_id = $1 AND status = 'serving'
+	`, queueID)
+	if err != nil {
+		return nil, fmt.Errorf("error finishing current serial: %w", err)
+	}
+	res, err := tx.Exec(`
+		UPDATE queue_serials SET status = 'serving', updated_at = NOW()
+		WHERE queue_id = $1 AND serial_number = $2 AND status IN ('waiting', 'skipped')
+	`, queueID, serial)
+	if err != nil {
+		return nil, fmt.Errorf("error calling serial: %w", err)
+	}
+	if rows, _ := res.RowsAffected(); rows == 0 {
+		return nil, ErrSerialNotFound
+	}
+	_, err = tx.Exec(`
+		UPDATE chamber_queues SET current_serial = $2, updated_at = NOW()
+		WHERE queue_id = $1
+	`, queueID, serial)
+	if err != nil {
+		return nil, fmt.Errorf("error updating queue: %w", err)
+	}
+	if err := tx.Commit(); err != nil {
+		return nil, err
+	}
+	return r.Status(hospitalID, doctorID, date)
+}
+
+// CallNext calls the lowest waiting serial.
+func (r *queueRepo) CallNext(hospitalID, doctorID int, date string) (*QueueStatus, error) {
+	return r.call(hospitalID, doctorID, date, func(tx *sqlx.Tx, queueID int) (int, error) {
+		var serial int
+		err := tx.Get(&serial, `
+			SELECT serial_number FROM queue_serials
+			WHERE queue_id = $1 AND status = 'waiting'
+			ORDER BY serial_number
+			LIMIT 1
+		`, queueID)
+		if errors.Is(err, sql.ErrNoRows) {
+			return 0, ErrQueueEmpty
+		}
+		return serial, err
+	})
+}
+
+// Call calls a specific waiting serial, or recalls a skipped one.
+func (r *queueRepo) Call(hospitalID, doctorID int, date string, serial int) (*QueueStatus, error) {
+	return r.call(hospitalID, doctorID, date, func(*sqlx.Tx, int) (int, error) {
+		return serial, nil
+	})
+}
+
+// Skip marks a waiting or serving serial as skipped so it can be recalled.
+func (r *queueRepo) Skip(hospitalID, doctorID int, date string, serial int) (*QueueStatus, error) {
+	res, err := r.db.Exec(`
+		UPDATE queue_serials s SET status = 'skipped', updated_at = NOW()
+		FROM chamber_queues q
+		WHERE q.queue_id = s.queue_id
+		  AND q.hospital_id = $1 AND q.doctor_id = $2 AND q.queue_date = $3
+		  AND s.serial_number = $4 AND s.status IN ('waiting', 'serving')
+	`, hospitalID, doctorID, date, serial)
+	if err != nil {
+		return nil, fmt.Errorf("error skipping serial: %w", err)
+	}
+	if rows, _ := res.RowsAffected(); rows == 0 {
+		return nil, ErrSerialNotFound
+	}
+	return r.Status(hospitalID, doctorID, date)
+}
@@ -0,0 +1,257 @@
+package repo
+
+import (
+	"database/sql"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/jmoiron/sqlx"
+)
+
+var (
+	ErrReviewNotFound    = errors.New("review not found")
+	ErrAlreadyReviewed   = errors.New("appointment has already been reviewed")
+	ErrVisitNotCompleted = errors.New("appointment is not a completed visit")
+)
+
+// Review moderation statuses.
+const (
+	ReviewPending  = "pending"
+	ReviewApproved = "approved"
+	ReviewRejected = "rejected"
+)
+
+// List orders shared by the doctor and hospital listings.
+const (
+	SortNewest = ""
+	SortRating = "rating"
+)
+
+type Review struct {
+	ReviewID       int        `json:"review_id" db:"review_id"`
+	AppointmentID  int        `json:"appointment_id" db:"appointment_id"`
+	PatientID      int        `json:"patient_id" db:"patient_id"`
+	PatientName    string     `json:"patient_name" db:"patient_name"`
+	HospitalID     int        `json:"hospital_id" db:"hospital_id"`
+	DoctorID       int        `json:"doctor_id" db:"doctor_id"`
+	Rating         int        `json:"rating" db:"rating"`
+	Text           string     `json:"text" db:"review_text"`
+	Status         string     `json:"status" db:"status"`
+	ModerationNote string     `json:"moderation_note,omitempty" db:"moderation_note"`
+	ModeratedAt    *time.Time `json:"moderated_at,omitempty" db:"moderated_at"`
+	CreatedAt      time.Time  `json:"created_at" db:"created_at"`
+	UpdatedAt      time.Time  `json:"updated_at" db:"updated_at"`
+}
+
+// ReviewFilter narrows down List results. Zero values match everything.
+type ReviewFilter struct {
+	HospitalID int
+	DoctorID   int
+	PatientID  int
+	Status     string
+}
+
+type ReviewRepo interface {
+	Create(patientID, appointmentID, rating int, text string) (*Review, error)
+	Get(id int) (*Review, error)
+	List(filter ReviewFilter, offset, limit int) ([]Review, int, error)
+	Moderate(id int, status, note string) (*Review, error)
+}
+
+type reviewRepo struct {
+	db *sqlx.DB
+}
+
+func NewReviewRepo(db *sqlx.DB) ReviewRepo {
+	return &reviewRepo{db: db}
+}
+
+// listOrder is the ORDER BY clause of a doctor or hospital listing on alias.
+func listOrder(alias, sort string) string {
+	if sort == SortRating {
+		return alias + ".rating_avg DESC, " + alias + ".rating_count DESC, " + alias + ".created_at DESC"
+	}
+	return alias + ".created_at DESC"
+}
+
+const reviewColumns = `
+	rv.review_id,
+	rv.appointment_id,
+	rv.patient_id,
+	COALESCE(p.name, '') AS patient_name,
+	rv.hospital_id,
+	rv.doctor_id,
+	rv.rating,
+	rv.review_text,
+	rv.status,
+	rv.moderation_note,
+	rv.moderated_at,
+	rv.created_at,
+	rv.updated_at
+`
+
+const reviewFrom = ` FROM reviews rv LEFT JOIN patients p ON p.patient_id = rv.patient_id `
+
+// Create stores a pending review of one of the patient's completed visits.
+func (r *reviewRepo) Create(patientID, appointmentID, rating int, text string) (*Review, error) {
+	tx, err := r.db.Beginx()
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback()
+
+	a, err := lockAppointment(tx, appointmentID)
+	if err != nil {
+		return nil, err
+	}
+	if a.PatientID == nil || *a.PatientID != patientID {
+		return nil, ErrAppointmentNotFound
+	}
+	if a.Status != StatusCompleted {
+		return nil, ErrVisitNotCompleted
+	}
+
+	var id int
+	err = tx.Get(&id, `
+		INSERT INTO reviews (appointment_id, patient_id, hospital_id, doctor_id, rating, review_text)
+		VALUES ($1, $2, $3, $4, $5, $6)
+		RETURNING review_id
+	`, appointmentID, patientID, a.HospitalID, a.DoctorID, rating, text)
+	if err != nil {
+		if isUniqueViolation(err) {
+			return nil, ErrAlreadyReviewed
+		}
+		return nil, fmt.Errorf("error creating review: %w", err)
+	}
+	if err := tx.Commit(); err != nil {
+		return nil, err
+	}
+	return r.Get(id)
+}
+
+func (r *reviewRepo) Get(id int) (*Review, error) {
+	var rv Review
+	err := r.db.Get(&rv, `SELECT `+reviewColumns+reviewFrom+`WHERE rv.review_id = $1`, id)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, ErrReviewNotFound
+		}
+		return nil, fmt.Errorf("error fetching review: %w", err)
+	}
+	return &rv, nil
+}
+
+func (r *reviewRepo) List(filter ReviewFilter, offset, limit int) ([]Review, int, error) {
+	conditions := []string{"TRUE"}
+	args := []interface{}{}
+	if filter.HospitalID > 0 {
+		args = append(args, filter.HospitalID)
+		conditions = append(conditions, fmt.Sprintf("rv.hospital_id = $%d", len(args)))
+	}
+	if filter.DoctorID > 0 {
+		args = append(args, filter.DoctorID)
+		conditions = append(conditions, fmt.Sprintf("rv.doctor_id = $%d", len(args)))
+	}
+	if filter.PatientID > 0 {
+		args = append(args, filter.PatientID)
+		conditions = append(conditions, fmt.Sprintf("rv.patient_id = $%d", len(args)))
+	}
+	if filter.Status != "" {
+		args = append(args, filter.Status)
+		conditions = append(conditions, fmt.Sprintf("rv.status = $%d", len(args)))
+	}
+	where := "WHERE " + strings.Join(conditions, " AND ")
+
+	var total int
+	if err := r.db.Get(&total, `SELECT COUNT(*) FROM reviews rv `+where, args...); err != nil {
+		return nil, 0, fmt.Errorf("error counting reviews: %w", err)
+	}
+
+	// Pending reviews are moderated oldest first; published ones are shown newest first.
+	order := "rv.created_at DESC"
+	if filter.Status == ReviewPending {
+		order = "rv.created_at"
+	}
+	list := []Review{}
+	query := fmt.Sprintf(`SELECT %s %s %s ORDER BY %s, rv.review_id LIMIT $%d OFFSET $%d`,
+		reviewColumns, reviewFrom, where, order, len(args)+1, len(args)+2)
+	if err := r.db.Select(&list, query, append(args, limit, offset)...); err != nil {
+		return nil, 0, fmt.Errorf("error fetching reviews: %w", err)
+	}
+	return list, total, nil
+}
+
+// refreshRatings recomputes the denormalized rating aggregates of a doctor
+// and a hospital from their approved reviews. Both rows are locked first so
+// that concurrent moderations see each other's changes.
+func refreshRatings(tx *sqlx.Tx, doctorID, hospitalID int) error {
+	_, err := tx.Exec(`SELECT 1 FROM doctors WHERE doctor_id = $1 FOR UPDATE`, doctorID)
+	if err == nil {
+		_, err = tx.Exec(`SELECT 1 FROM hospitals WHERE hospital_id = $1 FOR UPDATE`, hospitalID)
+	}
+	if err != nil {
+		return fmt.Errorf("error locking rated records: %w", err)
+	}
+
+	_, err = tx.Exec(`
+		UPDATE doctors d
+		SET rating_avg = agg.avg, rating_count = agg.count
+		FROM (
+		  SELECT COALESCE(ROUND(AVG(rating), 2), 0) AS avg, COUNT(*) AS count
+		  FROM reviews WHERE doctor_id = $1 AND status = 'approved'
+		) agg
+		WHERE d.doctor_id = $1
+	`, doctorID)
+	if err != nil {
+		return fmt.Errorf("error updating doctor rating: %w", err)
+	}
+	_, err = tx.Exec(`
+		UPDATE hospitals h
+		SET rating_avg = agg.avg, rating_count = agg.count
+		FROM (
+		  SELECT COALESCE(ROUND(AVG(rating), 2), 0) AS avg, COUNT(*) AS count
+		  FROM reviews WHERE hospital_id = $1 AND status = 'approved'
+		) agg
+		WHERE h.hospital_id = $1
+	`, hospitalID)
+	if err != nil {
+		return fmt.Errorf("error updating hospital rating: %w", err)
+	}
+	return nil
+}
+
+// Moderate approves or rejects a review and refreshes the ratings it counts
+// towards. A decision can be revised later.
+func (r *reviewRepo) Moderate(id int, status, note string) (*Review, error) {
+	tx, err := r.db.Beginx()
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback()
+
+	var target struct {
+		DoctorID   int `db:"doctor_id"`
+		HospitalID int `db:"hospital_id"`
+	}
+	err = tx.Get(&target, `
+		UPDATE reviews
+		SET status = $2, moderation_note = $3, moderated_at = NOW(), updated_at = NOW()
+		WHERE review_id = $1
+		RETURNING doctor_id, hospital_id
+	`, id, status, note)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, ErrReviewNotFound
+		}
+		return nil, fmt.Errorf("error moderating review: %w", err)
+	}
+	if err := refreshRatings(tx, target.DoctorID, target.HospitalID); err != nil {
+		return nil, err
+	}
+	if err := tx.Commit(); err != nil {
+		return nil, err
+	}
+	return r.Get(id)
+}
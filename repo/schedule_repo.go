@@ -0,0 +1,444 @@
+package repo
+
+import (
+	"database/sql"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/jmoiron/sqlx"
+)
+
+var (
+	ErrScheduleNotFound  = errors.New("schedule not found")
+	ErrExceptionNotFound = errors.New("schedule exception not found")
+	ErrDuplicateDate     = errors.New("an exception already exists for this date")
+	ErrNoAffiliation     = errors.New("doctor is not assigned to this hospital")
+)
+
+// Schedule is a weekly chamber session of a doctor at a hospital. Times are
+// HH:MM wall-clock times in Asia/Dhaka; DayOfWeek is 0 (Sunday) to 6.
+type Schedule struct {
+	ScheduleID  int       `json:"schedule_id" db:"schedule_id"`
+	HospitalID  int       `json:"hospital_id" db:"hospital_id"`
+	DoctorID    int       `json:"doctor_id" db:"doctor_id"`
+	DayOfWeek   int       `json:"day_of_week" db:"day_of_week"`
+	StartTime   string    `json:"start_time" db:"start_time"`
+	EndTime     string    `json:"end_time" db:"end_time"`
+	Room        string    `json:"room" db:"room"`
+	MaxPatients int       `json:"max_patients" db:"max_patients"`
+	CreatedAt   time.Time `json:"created_at" db:"created_at"`
+	UpdatedAt   time.Time `json:"updated_at" db:"updated_at"`
+}
+
+// ScheduleException overrides the weekly schedule on a specific date.
+type ScheduleException struct {
+	ExceptionID int       `json:"exception_id" db:"exception_id"`
+	HospitalID  int       `json:"hospital_id" db:"hospital_id"`
+	DoctorID    int       `json:"doctor_id" db:"doctor_id"`
+	Date        string    `json:"date" db:"exception_date"`
+	IsAvailable bool      `json:"is_available" db:"is_available"`
+	StartTime   *string   `json:"start_time" db:"start_time"`
+	EndTime     *string   `json:"end_time" db:"end_time"`
+	Room        string    `json:"room" db:"room"`
+	Reason      string    `json:"reason" db:"reason"`
+	CreatedAt   time.Time `json:"created_at" db:"created_at"`
+}
+
+// DoctorSchedule is a weekly session together with the hospital it is held at.
+type DoctorSchedule struct {
+	Schedule
+	HospitalName string `json:"hospital_name" db:"hospital_name"`
+}
+
+// DailySession is a doctor's effective session at a hospital on a given date,
+// after applying exceptions. Status is scheduled, rescheduled or cancelled.
+type DailySession struct {
+	ScheduleID  *int   `json:"schedule_id" db:"schedule_id"`
+	DoctorID    int    `json:"doctor_id" db:"doctor_id"`
+	DoctorName  string `json:"doctor_name" db:"doctor_name"`
+	Specialty   string `json:"specialty" db:"specialty"`
+	StartTime   string `json:"start_time" db:"start_time"`
+	EndTime     string `json:"end_time" db:"end_time"`
+	Room        string `json:"room" db:"room"`
+	MaxPatients int    `json:"max_patients" db:"max_patients"`
+	Status      string `json:"status" db:"-"`
+	Reason      string `json:"reason,omitempty" db:"-"`
+}
+
+type ScheduleRepo interface {
+	Create(s Schedule) (*Schedule, error)
+	Get(id int) (*Schedule, error)
+	Update(s Schedule) (*Schedule, error)
+	Delete(id int) error
+	ListByRelation(hospitalID, doctorID int) ([]Schedule, error)
+	ListByDoctor(doctorID int) ([]DoctorSchedule, error)
+	CreateException(e ScheduleException) (*ScheduleException, error)
+	GetException(id int) (*ScheduleException, error)
+	DeleteException(id int) error
+	ListExceptions(hospitalID, doctorID int, from string) ([]ScheduleException, error)
+	ListDoctorExceptions(doctorID int, from string) ([]ScheduleException, error)
+	ListForHospitalOnDate(hospitalID int, date time.Time) ([]DailySession, error)
+}
+
+type scheduleRepo struct {
+	db *sqlx.DB
+}
+
+func NewScheduleRepo(db *sqlx.DB) ScheduleRepo {
+	return &scheduleRepo{db: db}
+}
+
+const scheduleColumns = `
+	schedule_id,
+	hospital_id,
+	doctor_id,
+	day_of_week,
+	TO_CHAR(start_time, 'HH24:MI') AS start_time,
+	TO_CHAR(end_time, 'HH24:MI') AS end_time,
+	room,
+	max_patients,
+	created_at,
+	updated_at
+`
+
+const exceptionColumns = `
+	exception_id,
+	hospital_id,
+	doctor_id,
+	TO_CHAR(exception_date, 'YYYY-MM-DD') AS exception_date,
+	is_available,
+	TO_CHAR(start_time, 'HH24:MI') AS start_time,
+	TO_CHAR(end_time, 'HH24:MI') AS end_time,
+	room,
+	reason,
+	created_at
+`
+
+func (r *scheduleRepo) Create(s Schedule) (*Schedule, error) {
+	query := `
+		INSERT INTO doctor_schedules (
+		  hospital_id,
+		  doctor_id,
+		  day_of_week,
+		  start_time,
+		  end_time,
+		  room,
+		  max_patients
+		) VALUES (
+		  :hospital_id,
+		  :doctor_id,
+		  :day_of_week,
+		  :start_time,
+		  :end_time,
+		  :room,
+		  :max_patients
+		)
+		RETURNING ` + scheduleColumns
+	rows, err := r.db.NamedQuery(query, s)
+	if err != nil {
+		if isForeignKeyViolation(err) {
+			return nil, ErrNoAffiliation
+		}
+		return nil, fmt.Errorf("error creating schedule: %w", err)
+	}
+	defer rows.Close()
+
+	if rows.Next() {
+		var created Schedule
+		if err := rows.StructScan(&created); err != nil {
+			return nil, err
+		}
+		return &created, nil
+	}
+	return nil, errors.New("failed to return created schedule data")
+}
+
+func (r *scheduleRepo) Get(id int) (*Schedule, error) {
+	var s Schedule
+	err := r.db.Get(&s, `SELECT `+scheduleColumns+` FROM doctor_schedules WHERE schedule_id = $1`, id)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, ErrScheduleNotFound
+		}
+		return nil, fmt.Errorf("error fetching schedule: %w", err)
+	}
+	return &s, nil
+}
+
+func (r *scheduleRepo) Update(s Schedule) (*Schedule, error) {
+	query := `
+		UPDATE doctor_schedules
+		SET
+		  day_of_week = :day_of_week,
+		  start_time = :start_time,
+		  end_time = :end_time,
+		  room = :room,
+		  max_patients = :max_patients,
+		  updated_at = NOW()
+		WHERE schedule_id = :schedule_id
+		RETURNING ` + scheduleColumns
+	rows, err := r.db.NamedQuery(query, s)
+	if err != nil {
+		return nil, fmt.Errorf("error updating schedule: %w", err)
+	}
+	defer rows.Close()
+
+	if rows.Next() {
+		var updated Schedule
+		if err := rows.StructScan(&updated); err != nil {
+			return nil, err
+		}
+		return &updated, nil
+	}
+	return nil, ErrScheduleNotFound
+}
+
+func (r *scheduleRepo) Delete(id int) error {
+	res, err := r.db.Exec(`DELETE FROM doctor_schedules WHERE schedule_id = $1`, id)
+	if err != nil {
+		return fmt.Errorf("error deleting schedule: %w", err)
+	}
+	rows, _ := res.RowsAffected()
+	if rows == 0 {
+		return ErrScheduleNotFound
+	}
+	return nil
+}
+
+func (r *scheduleRepo) ListByRelation(hospitalID, doctorID int) ([]Schedule, error) {
+	list := []Schedule{}
+	query := `
+		SELECT ` + scheduleColumns + `
+		FROM doctor_schedules
+		WHERE hospital_id = $1 AND doctor_id = $2
+		ORDER BY day_of_week, start_time
+	`
+	if err := r.db.Select(&list, query, hospitalID, doctorID); err != nil {
+		return nil, fmt.Errorf("error fetching schedules: %w", err)
+	}
+	return list, nil
+}
+
+func (r *scheduleRepo) ListByDoctor(doctorID int) ([]DoctorSchedule, error) {
+	list := []DoctorSchedule{}
+	query := `
+		SELECT
+		  s.schedule_id,
+		  s.hospital_id,
+		  s.doctor_id,
+		  s.day_of_week,
+		  TO_CHAR(s.start_time, 'HH24:MI') AS start_time,
+		  TO_CHAR(s.end_time, 'HH24:MI') AS end_time,
+		  s.room,
+		  s.max_patients,
+		  s.created_at,
+		  s.updated_at,
+		  h.name AS hospital_name
+		FROM doctor_schedules s
+		JOIN hospitals h ON h.hospital_id = s.hospital_id
+		WHERE s.doctor_id = $1
+		ORDER BY s.day_of_week, s.start_time
+	`
+	if err := r.db.Select(&list, query, doctorID); err != nil {
+		return nil, fmt.Errorf("error fetching doctor schedule: %w", err)
+	}
+	return list, nil
+}
+
+func (r *scheduleRepo) CreateException(e ScheduleException) (*ScheduleException, error) {
+	query := `
+		INSERT INTO schedule_exceptions (
+		  hospital_id,
+		  doctor_id,
+		  exception_date,
+		  is_available,
+		  start_time,
+		  end_time,
+		  room,
+		  reason
+		) VALUES (
+		  :hospital_id,
+		  :doctor_id,
+		  :exception_date,
+		  :is_available,
+		  :start_time,
+		  :end_time,
+		  :room,
+		  :reason
+		)
+		RETURNING ` + exceptionColumns
+	rows, err := r.db.NamedQuery(query, e)
+	if err != nil {
+		switch {
+		case isForeignKeyViolation(err):
+			return nil, ErrNoAffiliation
+		case isUniqueViolation(err):
+			return nil, ErrDuplicateDate
+		}
+		return nil, fmt.Errorf("error creating schedule exception: %w", err)
+	}
+	defer rows.Close()
+
+	if rows.Next() {
+		var created ScheduleException
+		if err := rows.StructScan(&created); err != nil {
+			return nil, err
+		}
+		return &created, nil
+	}
+	return nil, errors.New("failed to return created schedule exception data")
+}
+
+func (r *scheduleRepo) GetException(id int) (*ScheduleException, error) {
+	var e ScheduleException
+	err := r.db.Get(&e, `SELECT `+exceptionColumns+` FROM schedule_exceptions WHERE exception_id = $1`, id)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, ErrExceptionNotFound
+		}
+		return nil, fmt.Errorf("error fetching schedule exception: %w", err)
+	}
+	return &e, nil
+}
+
+func (r *scheduleRepo) DeleteException(id int) error {
+	res, err := r.db.Exec(`DELETE FROM schedule_exceptions WHERE exception_id = $1`, id)
+	if err != nil {
+		return fmt.Errorf("error deleting schedule exception: %w", err)
+	}
+	rows, _ := res.RowsAffected()
+	if rows == 0 {
+		return ErrExceptionNotFound
+	}
+	return nil
+}
+
+func (r *scheduleRepo) ListExceptions(hospitalID, doctorID int, from string) ([]ScheduleException, error) {
+	list := []ScheduleException{}
+	query := `
+		SELECT ` + exceptionColumns + `
+		FROM schedule_exceptions
+		WHERE hospital_id = $1 AND doctor_id = $2 AND exception_date >= $3
+		ORDER BY exception_date
+	`
+	if err := r.db.Select(&list, query, hospitalID, doctorID, from); err != nil {
+		return nil, fmt.Errorf("error fetching schedule exceptions: %w", err)
+	}
+	return list, nil
+}
+
+func (r *scheduleRepo) ListDoctorExceptions(doctorID int, from string) ([]ScheduleException, error) {
+	list := []ScheduleException{}
+	query := `
+		SELECT ` + exceptionColumns + `
+		FROM schedule_exceptions
+		WHERE doctor_id = $1 AND exception_date >= $2
+		ORDER BY exception_date
+	`
+	if err := r.db.Select(&list, query, doctorID, from); err != nil {
+		return nil, fmt.Errorf("error fetching schedule exceptions: %w", err)
+	}
+	return list, nil
+}
+
+// ListForHospitalOnDate returns every doctor session held at the hospital on
+// date. Weekly sessions are cancelled or replaced by that date's exceptions,
+// and available exceptions without a weekly session are added as extra ones.
+func (r *scheduleRepo) ListForHospitalOnDate(hospitalID int, date time.Time) ([]DailySession, error) {
+	day := date.Format("2006-01-02")
+
+	weekly := []DailySession{}
+	weeklyQuery := `
+		SELECT
+		  s.schedule_id,
+		  s.doctor_id,
+		  d.name AS doctor_name,
+		  COALESCE(d.specialty, '') AS specialty,
+		  TO_CHAR(s.start_time, 'HH24:MI') AS start_time,
+		  TO_CHAR(s.end_time, 'HH24:MI') AS end_time,
+		  s.room,
+		  s.max_patients
+		FROM doctor_schedules s
+		JOIN doctors d ON d.doctor_id = s.doctor_id
+		WHERE s.hospital_id = $1 AND s.day_of_week = $2
+		ORDER BY s.start_time, d.name
+	`
+	if err := r.db.Select(&weekly, weeklyQuery, hospitalID, int(date.Weekday())); err != nil {
+		return nil, fmt.Errorf("error fetching daily schedule: %w", err)
+	}
+
+	type exceptionRow struct {
+		ScheduleException
+		DoctorName string `db:"doctor_name"`
+		Specialty  string `db:"specialty"`
+	}
+	var exceptions []exceptionRow
+	exceptionQuery := `
+		SELECT
+		  e.exception_id,
+		  e.hospital_id,
+		  e.doctor_id,
+		  TO_CHAR(e.exception_date, 'YYYY-MM-DD') AS exception_date,
+		  e.is_available,
+		  TO_CHAR(e.start_time, 'HH24:MI') AS start_time,
+		  TO_CHAR(e.end_time, 'HH24:MI') AS end_time,
+		  e.room,
+		  e.reason,
+		  e.created_at,
+		  d.name AS doctor_name,
+		  COALESCE(d.specialty, '') AS specialty
+		FROM schedule_exceptions e
+		JOIN doctors d ON d.doctor_id = e.doctor_id
+		WHERE e.hospital_id = $1 AND e.exception_date = $2
+	`
+	if err := r.db.Select(&exceptions, exceptionQuery, hospitalID, day); err != nil {
+		return nil, fmt.Errorf("error fetching schedule exceptions: %w", err)
+	}
+
+	byDoctor := make(map[int]exceptionRow, len(exceptions))
+	for _, e := range exceptions {
+		byDoctor[e.DoctorID] = e
+	}
+
+	sessions := make([]DailySession, 0, len(weekly)+len(exceptions))
+	applied := make(map[int]bool, len(exceptions))
+	for _, s := range weekly {
+		s.Status = "scheduled"
+		if e, ok := byDoctor[s.DoctorID]; ok {
+			s.Reason = e.Reason
+			if !e.IsAvailable {
+				s.Status = "cancelled"
+			} else {
+				// The override replaces all of the day's weekly sessions.
+				if applied[s.DoctorID] {
+					continue
+				}
+				s.Status = "rescheduled"
+				s.StartTime, s.EndTime = *e.StartTime, *e.EndTime
+				if e.Room != "" {
+					s.Room = e.Room
+				}
+			}
+			applied[s.DoctorID] = true
+		}
+		sessions = append(sessions, s)
+	}
+
+	for _, e := range exceptions {
+		if !e.IsAvailable || applied[e.DoctorID] {
+			continue
+		}
+		sessions = append(sessions, DailySession{
+			DoctorID:   e.DoctorID,
+			DoctorName: e.DoctorName,
+			Specialty:  e.Specialty,
+			StartTime:  *e.StartTime,
+			EndTime:    *e.EndTime,
+			Room:       e.Room,
+			Status:     "rescheduled",
+			Reason:     e.Reason,
+		})
+	}
+	return sessions, nil
+}
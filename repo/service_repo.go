@@ -0,0 +1,180 @@
+package repo
+
+import (
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/jmoiron/sqlx"
+)
+
+var (
+	ErrServiceNotFound  = errors.New("service not found")
+	ErrDuplicateService = errors.New("service code already exists")
+)
+
+// Service is an entry of the hospital department/service catalog (ICU,
+// dialysis, cath lab, ...).
+type Service struct {
+	ServiceID   int       `json:"service_id" db:"service_id"`
+	Code        string    `json:"code" db:"code"`
+	Name        string    `json:"name" db:"name"`
+	Department  string    `json:"department" db:"department"`
+	Description string    `json:"description" db:"description"`
+	CreatedAt   time.Time `json:"created_at" db:"created_at"`
+	UpdatedAt   time.Time `json:"updated_at" db:"updated_at"`
+}
+
+// HospitalService is a catalog service offered by a specific hospital.
+type HospitalService struct {
+	Service
+	Notes string `json:"notes" db:"notes"`
+}
+
+type ServiceRepo interface {
+	Create(s Service) (*Service, error)
+	List() ([]Service, error)
+	Update(s Service) (*Service, error)
+	Delete(id int) error
+	ListByHospital(hospitalID int) ([]HospitalService, error)
+	AddToHospital(hospitalID, serviceID int, notes string) error
+	RemoveFromHospital(hospitalID, serviceID int) error
+}
+
+type serviceRepo struct {
+	db *sqlx.DB
+}
+
+func NewServiceRepo(db *sqlx.DB) ServiceRepo {
+	return &serviceRepo{db: db}
+}
+
+func (r *serviceRepo) Create(s Service) (*Service, error) {
+	query := `
+		INSERT INTO services (
+		  code,
+		  name,
+		  department,
+		  description
+		) VALUES (
+		  :code,
+		  :name,
+		  :department,
+		  :description
+		)
+		RETURNING *;
+	`
+	rows, err := r.db.NamedQuery(query, s)
+	if err != nil {
+		if isUniqueViolation(err) {
+			return nil, ErrDuplicateService
+		}
+		return nil, fmt.Errorf("error creating service: %w", err)
+	}
+	defer rows.Close()
+
+	if rows.Next() {
+		var created Service
+		if err := rows.StructScan(&created); err != nil {
+			return nil, err
+		}
+		return &created, nil
+	}
+	return nil, errors.New("failed to return created service data")
+}
+
+func (r *serviceRepo) List() ([]Service, error) {
+	list := []Service{}
+	err := r.db.Select(&list, `SELECT * FROM services ORDER BY department, name`)
+	if err != nil {
+		return nil, fmt.Errorf("error fetching services: %w", err)
+	}
+	return list, nil
+}
+
+func (r *serviceRepo) Update(s Service) (*Service, error) {
+	query := `
+		UPDATE services
+		SET
+		  code = :code,
+		  name = :name,
+		  department = :department,
+		  description = :description,
+		  updated_at = NOW()
+		WHERE service_id = :service_id
+		RETURNING *;
+	`
+	rows, err := r.db.NamedQuery(query, s)
+	if err != nil {
+		if isUniqueViolation(err) {
+			return nil, ErrDuplicateService
+		}
+		return nil, fmt.Errorf("error updating service: %w", err)
+	}
+	defer rows.Close()
+
+	if rows.Next() {
+		var updated Service
+		if err := rows.StructScan(&updated); err != nil {
+			return nil, err
+		}
+		return &updated, nil
+	}
+	return nil, ErrServiceNotFound
+}
+
+func (r *serviceRepo) Delete(id int) error {
+	res, err := r.db.Exec(`DELETE FROM services WHERE service_id = $1`, id)
+	if err != nil {
+		return fmt.Errorf("error deleting service: %w", err)
+	}
+	rows, _ := res.RowsAffected()
+	if rows == 0 {
+		return ErrServiceNotFound
+	}
+	return nil
+}
+
+func (r *serviceRepo) ListByHospital(hospitalID int) ([]HospitalService, error) {
+	return listHospitalServices(r.db, hospitalID)
+}
+
+func (r *serviceRepo) AddToHospital(hospitalID, serviceID int, notes string) error {
+	query := `
+		INSERT INTO hospital_service (hospital_id, service_id, notes)
+		VALUES ($1, $2, $3)
+		ON CONFLICT (hospital_id, service_id) DO UPDATE SET notes = EXCLUDED.notes
+	`
+	_, err := r.db.Exec(query, hospitalID, serviceID, notes)
+	return err
+}
+
+func (r *serviceRepo) RemoveFromHospital(hospitalID, serviceID int) error {
+	query := `DELETE FROM hospital_service WHERE hospital_id = $1 AND service_id = $2`
+	res, err := r.db.Exec(query, hospitalID, serviceID)
+	if err != nil {
+		return err
+	}
+	rows, _ := res.RowsAffected()
+	if rows == 0 {
+		return ErrServiceNotFound
+	}
+	return nil
+}
+
+// listHospitalServices is shared with hospitalRepo.Get, which embeds the
+// services in the single-hospital response.
+func listHospitalServices(db *sqlx.DB, hospitalID int) ([]HospitalService, error) {
+	list := []HospitalService{}
+	query := `
+		SELECT s.*, hs.notes
+		FROM services s
+		JOIN hospital_service hs ON hs.service_id = s.service_id
+		WHERE hs.hospital_id = $1
+		ORDER BY s.department, s.name
+	`
+	if err := db.Select(&list, query, hospitalID); err != nil {
+		return nil, fmt.Errorf("error fetching hospital services: %w", err)
+	}
+	return list, nil
+}
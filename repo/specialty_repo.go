@@ -0,0 +1,234 @@
+package repo
+
+import (
+	"database/sql"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/jmoiron/sqlx"
+	"github.com/lib/pq"
+)
+
+var (
+	ErrSpecialtyNotFound  = errors.New("specialty not found")
+	ErrDuplicateSpecialty = errors.New("specialty code already exists")
+)
+
+// Specialty is a node of the normalized medical specialty taxonomy.
+type Specialty struct {
+	SpecialtyID int            `json:"specialty_id" db:"specialty_id"`
+	Code        string         `json:"code" db:"code"`
+	NameEn      string         `json:"name_en" db:"name_en"`
+	NameBn      string         `json:"name_bn" db:"name_bn"`
+	ParentID    *int           `json:"parent_id" db:"parent_id"`
+	Synonyms    pq.StringArray `json:"synonyms" db:"synonyms"`
+	CreatedAt   time.Time      `json:"created_at" db:"created_at"`
+	UpdatedAt   time.Time      `json:"updated_at" db:"updated_at"`
+}
+
+type SpecialtyRepo interface {
+	Create(s Specialty) (*Specialty, error)
+	Get(id int) (*Specialty, error)
+	List(search string, offset, limit int) ([]Specialty, int, error)
+	Update(s Specialty) (*Specialty, error)
+	Delete(id int) error
+	AssignToDoctor(doctorID, specialtyID int) error
+	RemoveFromDoctor(doctorID, specialtyID int) error
+	ListByDoctor(doctorID int) ([]Specialty, error)
+}
+
+type specialtyRepo struct {
+	db *sqlx.DB
+}
+
+func NewSpecialtyRepo(db *sqlx.DB) SpecialtyRepo {
+	return &specialtyRepo{db: db}
+}
+
+// specialtyMatch builds a predicate that is true when doctor d has a
+// specialty whose code, names or synonyms match the ILIKE pattern in param.
+func specialtyMatch(param string) string {
+	return fmt.Sprintf(`EXISTS (
+		SELECT 1
+		FROM doctor_specialty ds
+		JOIN specialties s ON s.specialty_id = ds.specialty_id
+		WHERE ds.doctor_id = d.doctor_id
+		  AND (
+		    s.code ILIKE %[1]s
+		    OR s.name_en ILIKE %[1]s
+		    OR s.name_bn ILIKE %[1]s
+		    OR EXISTS (SELECT 1 FROM UNNEST(s.synonyms) syn WHERE syn ILIKE %[1]s)
+		  )
+	)`, param)
+}
+
+// normalizeSynonyms lower-cases synonyms so the migration mapping and
+// search expansion can compare them directly.
+func normalizeSynonyms(s *Specialty) {
+	if s.Synonyms == nil {
+		s.Synonyms = pq.StringArray{}
+	}
+	for i, syn := range s.Synonyms {
+		s.Synonyms[i] = strings.ToLower(strings.TrimSpace(syn))
+	}
+}
+
+func (r *specialtyRepo) Create(s Specialty) (*Specialty, error) {
+	normalizeSynonyms(&s)
+	query := `
+		INSERT INTO specialties (
+		  code,
+		  name_en,
+		  name_bn,
+		  parent_id,
+		  synonyms
+		) VALUES (
+		  :code,
+		  :name_en,
+		  :name_bn,
+		  :parent_id,
+		  :synonyms
+		)
+		RETURNING *;
+	`
+	rows, err := r.db.NamedQuery(query, s)
+	if err != nil {
+		if isUniqueViolation(err) {
+			return nil, ErrDuplicateSpecialty
+		}
+		return nil, fmt.Errorf("error creating specialty: %w", err)
+	}
+	defer rows.Close()
+
+	if rows.Next() {
+		var created Specialty
+		if err := rows.StructScan(&created); err != nil {
+			return nil, err
+		}
+		return &created, nil
+	}
+	return nil, errors.New("failed to return created specialty data")
+}
+
+func (r *specialtyRepo) Get(id int) (*Specialty, error) {
+	var s Specialty
+	err := r.db.Get(&s, `SELECT * FROM specialties WHERE specialty_id = $1`, id)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, ErrSpecialtyNotFound
+		}
+		return nil, fmt.Errorf("error fetching specialty: %w", err)
+	}
+	return &s, nil
+}
+
+func (r *specialtyRepo) List(search string, offset, limit int) ([]Specialty, int, error) {
+	var list []Specialty
+	searchQuery := "%"
+	if search != "" {
+		searchQuery = "%" + search + "%"
+	}
+	where := `
+	  WHERE code ILIKE $1
+	     OR name_en ILIKE $1
+	     OR name_bn ILIKE $1
+	     OR EXISTS (SELECT 1 FROM UNNEST(synonyms) syn WHERE syn ILIKE $1)
+	`
+
+	var total int
+	if err := r.db.Get(&total, `SELECT COUNT(*) FROM specialties`+where, searchQuery); err != nil {
+		return nil, 0, fmt.Errorf("error counting specialties: %w", err)
+	}
+
+	query := `SELECT * FROM specialties` + where + `
+	  ORDER BY name_en
+	  LIMIT $2 OFFSET $3
+	`
+	if err := r.db.Select(&list, query, searchQuery, limit, offset); err != nil {
+		return nil, 0, fmt.Errorf("error fetching specialties: %w", err)
+	}
+	return list, total, nil
+}
+
+func (r *specialtyRepo) Update(s Specialty) (*Specialty, error) {
+	normalizeSynonyms(&s)
+	query := `
+		UPDATE specialties
+		SET
+		  code = :code,
+		  name_en = :name_en,
+		  name_bn = :name_bn,
+		  parent_id = :parent_id,
+		  synonyms = :synonyms,
+		  updated_at = NOW()
+		WHERE specialty_id = :specialty_id
+		RETURNING *;
+	`
+	rows, err := r.db.NamedQuery(query, s)
+	if err != nil {
+		if isUniqueViolation(err) {
+			return nil, ErrDuplicateSpecialty
+		}
+		return nil, fmt.Errorf("error updating specialty: %w", err)
+	}
+	defer rows.Close()
+
+	if rows.Next() {
+		var updated Specialty
+		if err := rows.StructScan(&updated); err != nil {
+			return nil, err
+		}
+		return &updated, nil
+	}
+	return nil, ErrSpecialtyNotFound
+}
+
+func (r *specialtyRepo) Delete(id int) error {
+	res, err := r.db.Exec(`DELETE FROM specialties WHERE specialty_id = $1`, id)
+	if err != nil {
+		return fmt.Errorf("error deleting specialty: %w", err)
+	}
+	rows, _ := res.RowsAffected()
+	if rows == 0 {
+		return ErrSpecialtyNotFound
+	}
+	return nil
+}
+
+func (r *specialtyRepo) AssignToDoctor(doctorID, specialtyID int) error {
+	query := `
+		INSERT INTO doctor_specialty (doctor_id, specialty_id)
+		VALUES ($1, $2)
+		ON CONFLICT DO NOTHING
+	`
+	_, err := r.db.Exec(query, doctorID, specialtyID)
+	return err
+}
+
+func (r *specialtyRepo) RemoveFromDoctor(doctorID, specialtyID int) error {
+	query := `DELETE FROM doctor_specialty WHERE doctor_id = $1 AND specialty_id = $2`
+	res, err := r.db.Exec(query, doctorID, specialtyID)
+	if err != nil {
+		return err
+	}
+	rows, _ := res.RowsAffected()
+	if rows == 0 {
+		return ErrSpecialtyNotFound
+	}
+	return nil
+}
+
+func (r *specialtyRepo) ListByDoctor(doctorID int) ([]Specialty, error) {
+	list := []Specialty{}
+	query := `
+		SELECT s.*
+		FROM specialties s
+		JOIN doctor_specialty ds ON ds.specialty_id = s.specialty_id
+		WHERE ds.doctor_id = $1
+		ORDER BY s.name_en
+	`
+	err := r.db.Select(&list, query, doctorID)
+	return list, err
+}
@@ -0,0 +1,101 @@
+package repo
+
+import (
+	"database/sql"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/jmoiron/sqlx"
+)
+
+var ErrStaffKeyNotFound = errors.New("staff key is invalid or revoked")
+
+// StaffKey identifies a hospital's staff when they update live data such as
+// bed counts.
+type StaffKey struct {
+	KeyID      int        `json:"key_id" db:"key_id"`
+	HospitalID int        `json:"hospital_id" db:"hospital_id"`
+	Label      string     `json:"label" db:"label"`
+	LastUsedAt *time.Time `json:"last_used_at" db:"last_used_at"`
+	RevokedAt  *time.Time `json:"revoked_at" db:"revoked_at"`
+	CreatedAt  time.Time  `json:"created_at" db:"created_at"`
+}
+
+// StaffKeyRepo stores hospital staff API keys. Keys are only persisted as
+// hashes.
+type StaffKeyRepo interface {
+	Create(hospitalID int, label, keyHash string) (*StaffKey, error)
+	ListByHospital(hospitalID int) ([]StaffKey, error)
+	Revoke(id int) error
+	Authenticate(keyHash string) (*StaffKey, error)
+}
+
+type staffKeyRepo struct {
+	db *sqlx.DB
+}
+
+func NewStaffKeyRepo(db *sqlx.DB) StaffKeyRepo {
+	return &staffKeyRepo{db: db}
+}
+
+const staffKeyColumns = `key_id, hospital_id, label, last_used_at, revoked_at, created_at`
+
+func (r *staffKeyRepo) Create(hospitalID int, label, keyHash string) (*StaffKey, error) {
+	var k StaffKey
+	err := r.db.Get(&k, `
+		INSERT INTO hospital_staff_keys (hospital_id, label, key_hash)
+		VALUES ($1, $2, $3)
+		RETURNING `+staffKeyColumns, hospitalID, label, keyHash)
+	if err != nil {
+		if isForeignKeyViolation(err) {
+			return nil, ErrNotFound
+		}
+		return nil, fmt.Errorf("error creating staff key: %w", err)
+	}
+	return &k, nil
+}
+
+func (r *staffKeyRepo) ListByHospital(hospitalID int) ([]StaffKey, error) {
+	list := []StaffKey{}
+	err := r.db.Select(&list, `
+		SELECT `+staffKeyColumns+`
+		FROM hospital_staff_keys
+		WHERE hospital_id = $1
+		ORDER BY created_at DESC
+	`, hospitalID)
+	if err != nil {
+		return nil, fmt.Errorf("error fetching staff keys: %w", err)
+	}
+	return list, nil
+}
+
+func (r *staffKeyRepo) Revoke(id int) error {
+	res, err := r.db.Exec(`
+		UPDATE hospital_staff_keys SET revoked_at = NOW()
+		WHERE key_id = $1 AND revoked_at IS NULL
+	`, id)
+	if err != nil {
+		return fmt.Errorf("error revoking staff key: %w", err)
+	}
+	if rows, _ := res.RowsAffected(); rows == 0 {
+		return ErrStaffKeyNotFound
+	}
+	return nil
+}
+
+// Authenticate resolves an active key by its hash and records its use.
+func (r *staffKeyRepo) Authenticate(keyHash string) (*StaffKey, error) {
+	var k StaffKey
+	err := r.db.Get(&k, `
+		UPDATE hospital_staff_keys SET last_used_at = NOW()
+		WHERE key_hash = $1 AND revoked_at IS NULL
+		RETURNING `+staffKeyColumns, keyHash)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, ErrStaffKeyNotFound
+		}
+		return nil, fmt.Errorf("error authenticating staff key: %w", err)
+	}
+	return &k, nil
+}
@@ -0,0 +1,329 @@
+package repo
+
+import (
+	"database/sql"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/jmoiron/sqlx"
+)
+
+var (
+	ErrWaitlistEntryNotFound = errors.New("waitlist entry not found")
+	ErrAlreadyWaitlisted     = errors.New("patient is already on this waitlist")
+	ErrNoActiveOffer         = errors.New("waitlist entry has no active offer")
+)
+
+// Waitlist entry statuses.
+const (
+	WaitlistWaiting = "waiting"
+	WaitlistOffered = "offered"
+	WaitlistBooked  = "booked"
+	WaitlistExpired = "expired"
+	WaitlistLeft    = "left"
+)
+
+// WaitlistOfferTTL is how long a promoted patient has to claim a freed slot.
+const WaitlistOfferTTL = 30 * time.Minute
+
+type WaitlistEntry struct {
+	EntryID          int        `json:"entry_id" db:"entry_id"`
+	HospitalID       int        `json:"hospital_id" db:"hospital_id"`
+	DoctorID         int        `json:"doctor_id" db:"doctor_id"`
+	Date             string     `json:"date" db:"waitlist_date"`
+	PatientID        int        `json:"patient_id" db:"patient_id"`
+	Status           string     `json:"status" db:"status"`
+	OfferedStartTime *string    `json:"offered_start_time" db:"offered_start_time"`
+	OfferedEndTime   *string    `json:"offered_end_time" db:"offered_end_time"`
+	OfferExpiresAt   *time.Time `json:"offer_expires_at" db:"offer_expires_at"`
+	AppointmentID    *int       `json:"appointment_id" db:"appointment_id"`
+	Position         *int       `json:"position" db:"position"`
+	CreatedAt        time.Time  `json:"created_at" db:"created_at"`
+	UpdatedAt        time.Time  `json:"updated_at" db:"updated_at"`
+}
+
+type WaitlistRepo interface {
+	Join(e WaitlistEntry) (*WaitlistEntry, error)
+	Get(id int) (*WaitlistEntry, error)
+	ListByPatient(patientID int) ([]WaitlistEntry, error)
+	ListByRelation(hospitalID, doctorID int, date string) ([]WaitlistEntry, error)
+	Leave(id int) error
+	Claim(id int) (*Appointment, error)
+	ExpireOffers() (int, error)
+}
+
+type waitlistRepo struct {
+	db *sqlx.DB
+}
+
+func NewWaitlistRepo(db *sqlx.DB) WaitlistRepo {
+	return &waitlistRepo{db: db}
+}
+
+// waitlistColumns selects an entry from alias w, including its 1-based
+// position among the waiting entries of the same queue.
+const waitlistColumns = `
+	w.entry_id,
+	w.hospital_id,
+	w.doctor_id,
+	TO_CHAR(w.waitlist_date, 'YYYY-MM-DD') AS waitlist_date,
+	w.patient_id,
+	w.status,
+	TO_CHAR(w.offered_start_time, 'HH24:MI') AS offered_start_time,
+	TO_CHAR(w.offered_end_time, 'HH24:MI') AS offered_end_time,
+	w.offer_expires_at,
+	w.appointment_id,
+	CASE WHEN w.status = 'waiting' THEN (
+	  SELECT COUNT(*) + 1
+	  FROM waitlist_entries ahead
+	  WHERE ahead.hospital_id = w.hospital_id
+	    AND ahead.doctor_id = w.doctor_id
+	    AND ahead.waitlist_date = w.waitlist_date
+	    AND ahead.status = 'waiting'
+	    AND (ahead.created_at, ahead.entry_id) < (w.created_at, w.entry_id)
+	) END AS position,
+	w.created_at,
+	w.updated_at
+`
+
+// offerNextWaitlisted offers a freed slot to the first waiting patient of the
+// queue, inside the caller's transaction. Slots that have already started are
+// not offered.
+func offerNextWaitlisted(tx *sqlx.Tx, hospitalID, doctorID int, date, startTime, endTime string) error {
+	_, err := tx.Exec(`
+		UPDATE waitlist_entries
+		SET status = 'offered',
+		    offered_start_time = $4,
+		    offered_end_time = $5,
+		    offer_expires_at = NOW() + $6 * INTERVAL '1 second',
+		    updated_at = NOW()
+		WHERE entry_id = (
+		  SELECT entry_id
+		  FROM waitlist_entries
+		  WHERE hospital_id = $1 AND doctor_id = $2 AND waitlist_date = $3
+		    AND status = 'waiting'
+		  ORDER BY created_at, entry_id
+		  LIMIT 1
+		  FOR UPDATE SKIP LOCKED
+		)
+		AND ($3::date + $4::time) > (NOW() AT TIME ZONE 'Asia/Dhaka')
+	`, hospitalID, doctorID, date, startTime, endTime, int(WaitlistOfferTTL.Seconds()))
+	if err != nil {
+		return fmt.Errorf("error promoting waitlist entry: %w", err)
+	}
+	return nil
+}
+
+func (r *waitlistRepo) Join(e WaitlistEntry) (*WaitlistEntry, error) {
+	var id int
+	err := r.db.Get(&id, `
+		INSERT INTO waitlist_entries (hospital_id, doctor_id, waitlist_date, patient_id)
+		VALUES ($1, $2, $3, $4)
+		RETURNING entry_id
+	`, e.HospitalID, e.DoctorID, e.Date, e.PatientID)
+	if err != nil {
+		switch {
+		case isUniqueViolation(err):
+			return nil, ErrAlreadyWaitlisted
+		case isForeignKeyViolation(err):
+			return nil, ErrNoAffiliation
+		}
+		return nil, fmt.Errorf("error joining waitlist: %w", err)
+	}
+	return r.Get(id)
+}
+
+func (r *waitlistRepo) Get(id int) (*WaitlistEntry, error) {
+	var e WaitlistEntry
+	err := r.db.Get(&e, `SELECT `+waitlistColumns+` FROM waitlist_entries w WHERE w.entry_id = $1`, id)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, ErrWaitlistEntryNotFound
+		}
+		return nil, fmt.Errorf("error fetching waitlist entry: %w", err)
+	}
+	return &e, nil
+}
+
+func (r *waitlistRepo) ListByPatient(patientID int) ([]WaitlistEntry, error) {
+	list := []WaitlistEntry{}
+	query := `
+		SELECT ` + waitlistColumns + `
+		FROM waitlist_entries w
+		WHERE w.patient_id = $1
+		ORDER BY w.waitlist_date DESC, w.created_at DESC
+	`
+	if err := r.db.Select(&list, query, patientID); err != nil {
+		return nil, fmt.Errorf("error fetching waitlist entries: %w", err)
+	}
+	return list, nil
+}
+
+func (r *waitlistRepo) ListByRelation(hospitalID, doctorID int, date string) ([]WaitlistEntry, error) {
+	list := []WaitlistEntry{}
+	query := `
+		SELECT ` + waitlistColumns + `
+		FROM waitlist_entries w
+		WHERE w.hospital_id = $1 AND w.doctor_id = $2 AND w.waitlist_date = $3
+		ORDER BY w.created_at, w.entry_id
+	`
+	if err := r.db.Select(&list, query, hospitalID, doctorID, date); err != nil {
+		return nil, fmt.Errorf("error fetching waitlist: %w", err)
+	}
+	return list, nil
+}
+
+// lockWaitlistEntry loads an entry with a row lock held until the end of the
+// transaction.
+func lockWaitlistEntry(tx *sqlx.Tx, id int) (*WaitlistEntry, error) {
+	var e WaitlistEntry
+	err := tx.Get(&e, `
+		SELECT `+waitlistColumns+`
+		FROM waitlist_entries w
+		WHERE w.entry_id = $1
+		FOR UPDATE OF w
+	`, id)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, ErrWaitlistEntryNotFound
+		}
+		return nil, fmt.Errorf("error fetching waitlist entry: %w", err)
+	}
+	return &e, nil
+}
+
+// Leave removes the patient from the queue. An outstanding offer is passed on
+// to the next waiting patient.
+func (r *waitlistRepo) Leave(id int) error {
+	tx, err := r.db.Beginx()
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	e, err := lockWaitlistEntry(tx, id)
+	if err != nil {
+		return err
+	}
+	if e.Status != WaitlistWaiting && e.Status != WaitlistOffered {
+		return ErrWaitlistEntryNotFound
+	}
+
+	_, err = tx.Exec(`UPDATE waitlist_entries SET status = 'left', updated_at = NOW() WHERE entry_id = $1`, id)
+	if err != nil {
+		return fmt.Errorf("error leaving waitlist: %w", err)
+	}
+	if e.Status == WaitlistOffered {
+		if err := offerNextWaitlisted(tx, e.HospitalID, e.DoctorID, e.Date, *e.OfferedStartTime, *e.OfferedEndTime); err != nil {
+			return err
+		}
+	}
+	return tx.Commit()
+}
+
+// Claim books the offered slot for the waitlisted patient.
+func (r *waitlistRepo) Claim(id int) (*Appointment, error) {
+	tx, err := r.db.Beginx()
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback()
+
+	// offer_expires_at is a TIMESTAMP set from the database's NOW(), so it is
+	// compared there too, like the expiry job, not against this server's clock.
+	var e WaitlistEntry
+	err = tx.Get(&e, `
+		SELECT `+waitlistColumns+`
+		FROM waitlist_entries w
+		WHERE w.entry_id = $1 AND w.status = 'offered' AND w.offer_expires_at > NOW()
+		FOR UPDATE OF w
+	`, id)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, ErrNoActiveOffer
+		}
+		return nil, fmt.Errorf("error fetching waitlist entry: %w", err)
+	}
+	if err := lockRelation(tx, e.HospitalID, e.DoctorID); err != nil {
+		return nil, err
+	}
+	free, err := slotFree(tx, e.HospitalID, e.DoctorID, e.Date, *e.OfferedStartTime, 0, e.EntryID)
+	if err != nil {
+		return nil, fmt.Errorf("error checking slot: %w", err)
+	}
+	if !free {
+		return nil, ErrSlotTaken
+	}
+
+	var patient struct {
+		Name  string `db:"name"`
+		Phone string `db:"phone_number"`
+	}
+	if err := tx.Get(&patient, `SELECT name, phone_number FROM patients WHERE patient_id = $1`, e.PatientID); err != nil {
+		return nil, fmt.Errorf("error fetching patient: %w", err)
+	}
+
+	patientID := e.PatientID
+	created, err := insertAppointment(tx, Appointment{
+		HospitalID:   e.HospitalID,
+		DoctorID:     e.DoctorID,
+		PatientID:    &patientID,
+		Date:         e.Date,
+		StartTime:    *e.OfferedStartTime,
+		EndTime:      *e.OfferedEndTime,
+		PatientName:  patient.Name,
+		PatientPhone: patient.Phone,
+		Notes:        "Booked from waitlist",
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	_, err = tx.Exec(`
+		UPDATE waitlist_entries
+		SET status = 'booked', appointment_id = $2, updated_at = NOW()
+		WHERE entry_id = $1
+	`, id, created.AppointmentID)
+	if err != nil {
+		return nil, fmt.Errorf("error updating waitlist entry: %w", err)
+	}
+	if err := tx.Commit(); err != nil {
+		return nil, err
+	}
+	return created, nil
+}
+
+// ExpireOffers expires unclaimed offers and passes each freed slot on to the
+// next waiting patient. It returns the number of expired offers.
+func (r *waitlistRepo) ExpireOffers() (int, error) {
+	tx, err := r.db.Beginx()
+	if err != nil {
+		return 0, err
+	}
+	defer tx.Rollback()
+
+	var expired []WaitlistEntry
+	err = tx.Select(&expired, `
+		UPDATE waitlist_entries w
+		SET status = 'expired', updated_at = NOW()
+		WHERE w.entry_id IN (
+		  SELECT entry_id FROM waitlist_entries
+		  WHERE status = 'offered' AND offer_expires_at <= NOW()
+		  FOR UPDATE SKIP LOCKED
+		)
+		RETURNING `+waitlistColumns)
+	if err != nil {
+		return 0, fmt.Errorf("error expiring waitlist offers: %w", err)
+	}
+
+	for _, e := range expired {
+		if err := offerNextWaitlisted(tx, e.HospitalID, e.DoctorID, e.Date, *e.OfferedStartTime, *e.OfferedEndTime); err != nil {
+			return 0, err
+		}
+	}
+	if err := tx.Commit(); err != nil {
+		return 0, err
+	}
+	return len(expired), nil
+}
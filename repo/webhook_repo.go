@@ -0,0 +1,464 @@
+package repo
+
+import (
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/jmoiron/sqlx"
+	"github.com/lib/pq"
+)
+
+var (
+	ErrWebhookNotFound  = errors.New("webhook subscription not found")
+	ErrWebhookDisabled  = errors.New("webhook subscription is disabled")
+	ErrDeliveryNotFound = errors.New("webhook delivery not found")
+)
+
+// Webhook event types.
+const (
+	EventHospitalCreated       = "hospital.created"
+	EventHospitalUpdated       = "hospital.updated"
+	EventHospitalDeleted       = "hospital.deleted"
+	EventDoctorCreated         = "doctor.created"
+	EventDoctorUpdated         = "doctor.updated"
+	EventDoctorDeleted         = "doctor.deleted"
+	EventHospitalDoctorCreated = "hospital_doctor.created"
+	EventHospitalDoctorUpdated = "hospital_doctor.updated"
+	EventHospitalDoctorDeleted = "hospital_doctor.deleted"
+)
+
+var WebhookEventTypes = []string{
+	EventHospitalCreated, EventHospitalUpdated, EventHospitalDeleted,
+	EventDoctorCreated, EventDoctorUpdated, EventDoctorDeleted,
+	EventHospitalDoctorCreated, EventHospitalDoctorUpdated, EventHospitalDoctorDeleted,
+}
+
+// Webhook delivery statuses.
+const (
+	DeliveryPending   = "pending"
+	DeliverySucceeded = "succeeded"
+	DeliveryFailed    = "failed"
+)
+
+var DeliveryStatuses = []string{DeliveryPending, DeliverySucceeded, DeliveryFailed}
+
+// Delivery retry policy. A failed attempt is retried after WebhookRetryBase,
+// doubling every time, until the delivery has had WebhookMaxAttempts
+// attempts. A subscription whose every attempt has failed for
+// WebhookDisableAfter is disabled.
+const (
+	WebhookMaxAttempts  = 8
+	WebhookRetryBase    = time.Minute
+	WebhookDisableAfter = 72 * time.Hour
+)
+
+// webhookClaimLease keeps a claimed delivery from being claimed again while
+// it is being sent.
+const webhookClaimLease = 5 * time.Minute
+
+// WebhookRetryDelay is the wait after the given failed attempt, counted from 1.
+func WebhookRetryDelay(attempt int) time.Duration {
+	return WebhookRetryBase << (attempt - 1)
+}
+
+// WebhookSubscription is a partner endpoint that receives the events of the
+// listed types. The secret signs the deliveries and is never listed.
+type WebhookSubscription struct {
+	SubscriptionID      int            `json:"subscription_id" db:"subscription_id"`
+	URL                 string         `json:"url" db:"url"`
+	Secret              string         `json:"-" db:"secret"`
+	EventTypes          pq.StringArray `json:"event_types" db:"event_types"`
+	Description         string         `json:"description" db:"description"`
+	Active              bool           `json:"active" db:"active"`
+	ConsecutiveFailures int            `json:"consecutive_failures" db:"consecutive_failures"`
+	FailingSince        *time.Time     `json:"failing_since" db:"failing_since"`
+	DisabledAt          *time.Time     `json:"disabled_at" db:"disabled_at"`
+	DisabledReason      string         `json:"disabled_reason,omitempty" db:"disabled_reason"`
+	CreatedAt           time.Time      `json:"created_at" db:"created_at"`
+	UpdatedAt           time.Time      `json:"updated_at" db:"updated_at"`
+}
+
+// WebhookDelivery is one event sent, or to be sent, to one subscription.
+// A replay is a new delivery of the same event.
+type WebhookDelivery struct {
+	DeliveryID     int64      `json:"delivery_id" db:"delivery_id"`
+	SubscriptionID int        `json:"subscription_id" db:"subscription_id"`
+	EventID        int64      `json:"event_id" db:"event_id"`
+	EventType      string     `json:"event_type" db:"event_type"`
+	ReplayOf       *int64     `json:"replay_of,omitempty" db:"replay_of"`
+	Status         string     `json:"status" db:"status"`
+	Attempts       int        `json:"attempts" db:"attempts"`
+	NextAttemptAt  *time.Time `json:"next_attempt_at" db:"next_attempt_at"`
+	LastAttemptAt  *time.Time `json:"last_attempt_at" db:"last_attempt_at"`
+	ResponseStatus *int       `json:"response_status" db:"response_status"`
+	LastError      string     `json:"last_error,omitempty" db:"last_error"`
+	CreatedAt      time.Time  `json:"created_at" db:"created_at"`
+}
+
+// WebhookJob is a delivery claimed for an attempt, with the endpoint and the
+// event it carries.
+type WebhookJob struct {
+	WebhookDelivery
+	URL            string    `db:"url"`
+	Secret         string    `db:"secret"`
+	Payload        []byte    `db:"payload"`
+	EventCreatedAt time.Time `db:"event_created_at"`
+}
+
+// WebhookRepo stores webhook subscriptions and queues their deliveries.
+type WebhookRepo interface {
+	Create(s WebhookSubscription) (*WebhookSubscription, error)
+	Get(id int) (*WebhookSubscription, error)
+	List(offset, limit int) ([]WebhookSubscription, int, error)
+	Update(s WebhookSubscription) (*WebhookSubscription, error)
+	Delete(id int) error
+	Publish(eventType string, data interface{}) error
+	ClaimDue(limit int) ([]WebhookJob, error)
+	RecordAttempt(job WebhookJob, responseStatus *int, attemptErr string) (bool, error)
+	GetDelivery(id int64) (*WebhookDelivery, error)
+	ListDeliveries(subscriptionID int, status string, offset, limit int) ([]WebhookDelivery, int, error)
+	Replay(deliveryID int64) (*WebhookDelivery, error)
+}
+
+type webhookRepo struct {
+	db *sqlx.DB
+}
+
+func NewWebhookRepo(db *sqlx.DB) WebhookRepo {
+	return &webhookRepo{db: db}
+}
+
+const webhookColumns = `
+	subscription_id, url, secret, event_types, description, active,
+	consecutive_failures, failing_since, disabled_at, disabled_reason,
+	created_at, updated_at
+`
+
+// deliveryColumns selects a delivery from alias d joined with its event e.
+const deliveryColumns = `
+	d.delivery_id, d.subscription_id, d.event_id, e.event_type, d.replay_of,
+	d.status, d.attempts, d.next_attempt_at, d.last_attempt_at,
+	d.response_status, d.last_error, d.created_at
+`
+
+func (r *webhookRepo) Create(s WebhookSubscription) (*WebhookSubscription, error) {
+	var created WebhookSubscription
+	err := r.db.Get(&created, `
+		INSERT INTO webhook_subscriptions (url, secret, event_types, description)
+		VALUES ($1, $2, $3, $4)
+		RETURNING `+webhookColumns, s.URL, s.Secret, s.EventTypes, s.Description)
+	if err != nil {
+		return nil, fmt.Errorf("error creating webhook subscription: %w", err)
+	}
+	return &created, nil
+}
+
+func (r *webhookRepo) Get(id int) (*WebhookSubscription, error) {
+	var s WebhookSubscription
+	err := r.db.Get(&s, `SELECT `+webhookColumns+` FROM webhook_subscriptions WHERE subscription_id = $1`, id)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, ErrWebhookNotFound
+		}
+		return nil, fmt.Errorf("error fetching webhook subscription: %w", err)
+	}
+	return &s, nil
+}
+
+func (r *webhookRepo) List(offset, limit int) ([]WebhookSubscription, int, error) {
+	var total int
+	if err := r.db.Get(&total, `SELECT COUNT(*) FROM webhook_subscriptions`); err != nil {
+		return nil, 0, fmt.Errorf("error counting webhook subscriptions: %w", err)
+	}
+	list := []WebhookSubscription{}
+	err := r.db.Select(&list, `
+		SELECT `+webhookColumns+`
+		FROM webhook_subscriptions
+		ORDER BY created_at DESC, subscription_id DESC
+		LIMIT $1 OFFSET $2
+	`, limit, offset)
+	if err != nil {
+		return nil, 0, fmt.Errorf("error fetching webhook subscriptions: %w", err)
+	}
+	return list, total, nil
+}
+
+// Update saves the URL, event types, description and active flag, and the
+// secret unless it is empty. Re-enabling a subscription clears its failure
+// record; disabling it by hand records that as the reason.
+func (r *webhookRepo) Update(s WebhookSubscription) (*WebhookSubscription, error) {
+	var updated WebhookSubscription
+	err := r.db.Get(&updated, `
+		UPDATE webhook_subscriptions
+		SET
+		  url = $2,
+		  event_types = $3,
+		  description = $4,
+		  secret = CASE WHEN $5 = '' THEN secret ELSE $5 END,
+		  consecutive_failures = CASE WHEN $6 AND NOT active THEN 0 ELSE consecutive_failures END,
+		  failing_since = CASE WHEN $6 AND NOT active THEN NULL ELSE failing_since END,
+		  disabled_at = CASE WHEN $6 THEN NULL WHEN active THEN NOW() ELSE disabled_at END,
+		  disabled_reason = CASE WHEN $6 THEN '' WHEN active THEN 'Disabled by an admin' ELSE disabled_reason END,
+		  active = $6,
+		  updated_at = NOW()
+		WHERE subscription_id = $1
+		RETURNING `+webhookColumns, s.SubscriptionID, s.URL, s.EventTypes, s.Description, s.Secret, s.Active)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, ErrWebhookNotFound
+		}
+		return nil, fmt.Errorf("error updating webhook subscription: %w", err)
+	}
+	return &updated, nil
+}
+
+// Delete removes the subscription along with its delivery log.
+func (r *webhookRepo) Delete(id int) error {
+	res, err := r.db.Exec(`DELETE FROM webhook_subscriptions WHERE subscription_id = $1`, id)
+	if err != nil {
+		return fmt.Errorf("error deleting webhook subscription: %w", err)
+	}
+	if rows, _ := res.RowsAffected(); rows == 0 {
+		return ErrWebhookNotFound
+	}
+	return nil
+}
+
+// Publish records an event with data as its JSON payload and queues a
+// delivery to every active subscription of its type. Events nobody
+// subscribes to are not stored.
+func (r *webhookRepo) Publish(eventType string, data interface{}) error {
+	return publishEvent(r.db, eventType, data)
+}
+
+// publishEvent is Publish on db, which may be a transaction so that the event
+// is only queued if the change it describes commits.
+func publishEvent(db sqlx.Execer, eventType string, data interface{}) error {
+	payload, err := json.Marshal(data)
+	if err != nil {
+		return fmt.Errorf("error encoding %s event: %w", eventType, err)
+	}
+	_, err = db.Exec(`
+		WITH event AS (
+		  INSERT INTO webhook_events (event_type, payload)
+		  SELECT $1::text, $2::jsonb
+		  WHERE EXISTS (
+		    SELECT 1 FROM webhook_subscriptions WHERE active AND $1::text = ANY(event_types)
+		  )
+		  RETURNING event_id
+		)
+		INSERT INTO webhook_deliveries (subscription_id, event_id, next_attempt_at)
+		SELECT s.subscription_id, event.event_id, NOW()
+		FROM webhook_subscriptions s, event
+		WHERE s.active AND $1::text = ANY(s.event_types)
+	`, eventType, string(payload))
+	if err != nil {
+		return fmt.Errorf("error publishing %s event: %w", eventType, err)
+	}
+	return nil
+}
+
+// ClaimDue returns up to limit pending deliveries of active subscriptions
+// whose next attempt is due, oldest first, and leases them so that no other
+// worker sends them meanwhile.
+func (r *webhookRepo) ClaimDue(limit int) ([]WebhookJob, error) {
+	jobs := []WebhookJob{}
+	err := r.db.Select(&jobs, `
+		WITH due AS (
+		  SELECT d.delivery_id
+		  FROM webhook_deliveries d
+		  JOIN webhook_subscriptions s ON s.subscription_id = d.subscription_id
+		  WHERE d.status = 'pending' AND d.next_attempt_at <= NOW() AND s.active
+		  ORDER BY d.next_attempt_at
+		  LIMIT $1
+		  FOR UPDATE OF d SKIP LOCKED
+		)
+		UPDATE webhook_deliveries d
+		SET next_attempt_at = NOW() + $2 * INTERVAL '1 second'
+		FROM due, webhook_subscriptions s, webhook_events e
+		WHERE d.delivery_id = due.delivery_id
+		  AND s.subscription_id = d.subscription_id
+		  AND e.event_id = d.event_id
+		RETURNING `+deliveryColumns+`, s.url, s.secret, e.payload, e.created_at AS event_created_at
+	`, limit, webhookClaimLease.Seconds())
+	if err != nil {
+		return nil, fmt.Errorf("error claiming webhook deliveries: %w", err)
+	}
+	return jobs, nil
+}
+
+// RecordAttempt saves the outcome of sending job; an empty attemptErr means
+// it succeeded. A failed delivery is rescheduled with backoff until it runs
+// out of attempts. It reports whether the failure got the subscription
+// disabled, in which case its pending deliveries fail too.
+func (r *webhookRepo) RecordAttempt(job WebhookJob, responseStatus *int, attemptErr string) (bool, error) {
+	tx, err := r.db.Beginx()
+	if err != nil {
+		return false, err
+	}
+	defer tx.Rollback()
+
+	if attemptErr == "" {
+		_, err = tx.Exec(`
+			UPDATE webhook_deliveries
+			SET status = 'succeeded', attempts = attempts + 1, last_attempt_at = NOW(),
+			    next_attempt_at = NULL, response_status = $2, last_error = ''
+			WHERE delivery_id = $1
+		`, job.DeliveryID, responseStatus)
+		if err != nil {
+			return false, fmt.Errorf("error recording webhook delivery: %w", err)
+		}
+		_, err = tx.Exec(`
+			UPDATE webhook_subscriptions SET consecutive_failures = 0, failing_since = NULL
+			WHERE subscription_id = $1
+		`, job.SubscriptionID)
+		if err != nil {
+			return false, fmt.Errorf("error recording webhook delivery: %w", err)
+		}
+		return false, tx.Commit()
+	}
+
+	var attempts int
+	err = tx.Get(&attempts, `
+		UPDATE webhook_deliveries
+		SET attempts = attempts + 1, last_attempt_at = NOW(), response_status = $2, last_error = $3
+		WHERE delivery_id = $1
+		RETURNING attempts
+	`, job.DeliveryID, responseStatus, attemptErr)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return false, ErrDeliveryNotFound
+		}
+		return false, fmt.Errorf("error recording webhook delivery: %w", err)
+	}
+	if attempts >= WebhookMaxAttempts {
+		_, err = tx.Exec(`UPDATE webhook_deliveries SET status = 'failed', next_attempt_at = NULL WHERE delivery_id = $1`, job.DeliveryID)
+	} else {
+		_, err = tx.Exec(`
+			UPDATE webhook_deliveries SET next_attempt_at = NOW() + $2 * INTERVAL '1 second'
+			WHERE delivery_id = $1
+		`, job.DeliveryID, WebhookRetryDelay(attempts).Seconds())
+	}
+	if err != nil {
+		return false, fmt.Errorf("error scheduling webhook retry: %w", err)
+	}
+
+	var disable bool
+	err = tx.Get(&disable, `
+		UPDATE webhook_subscriptions
+		SET consecutive_failures = consecutive_failures + 1, failing_since = COALESCE(failing_since, NOW())
+		WHERE subscription_id = $1
+		RETURNING active AND failing_since <= NOW() - $2 * INTERVAL '1 second'
+	`, job.SubscriptionID, WebhookDisableAfter.Seconds())
+	if err != nil && !errors.Is(err, sql.ErrNoRows) {
+		return false, fmt.Errorf("error recording webhook failure: %w", err)
+	}
+	if disable {
+		reason := fmt.Sprintf("Every delivery attempt failed for %d hours", int(WebhookDisableAfter.Hours()))
+		_, err = tx.Exec(`
+			UPDATE webhook_subscriptions
+			SET active = FALSE, disabled_at = NOW(), disabled_reason = $2, updated_at = NOW()
+			WHERE subscription_id = $1
+		`, job.SubscriptionID, reason)
+		if err == nil {
+			_, err = tx.Exec(`
+				UPDATE webhook_deliveries
+				SET status = 'failed', next_attempt_at = NULL,
+				    last_error = CASE WHEN last_error = '' THEN 'Subscription disabled' ELSE last_error END
+				WHERE subscription_id = $1 AND status = 'pending'
+			`, job.SubscriptionID)
+		}
+		if err != nil {
+			return false, fmt.Errorf("error disabling webhook subscription: %w", err)
+		}
+	}
+	return disable, tx.Commit()
+}
+
+func (r *webhookRepo) GetDelivery(id int64) (*WebhookDelivery, error) {
+	var d WebhookDelivery
+	err := r.db.Get(&d, `
+		SELECT `+deliveryColumns+`
+		FROM webhook_deliveries d
+		JOIN webhook_events e ON e.event_id = d.event_id
+		WHERE d.delivery_id = $1
+	`, id)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, ErrDeliveryNotFound
+		}
+		return nil, fmt.Errorf("error fetching webhook delivery: %w", err)
+	}
+	return &d, nil
+}
+
+// ListDeliveries is the delivery log of a subscription, newest first,
+// optionally narrowed down to one status.
+func (r *webhookRepo) ListDeliveries(subscriptionID int, status string, offset, limit int) ([]WebhookDelivery, int, error) {
+	conditions := []string{"d.subscription_id = $1"}
+	args := []interface{}{subscriptionID}
+	if status != "" {
+		args = append(args, status)
+		conditions = append(conditions, fmt.Sprintf("d.status = $%d", len(args)))
+	}
+	where := "WHERE " + strings.Join(conditions, " AND ")
+
+	var total int
+	if err := r.db.Get(&total, `SELECT COUNT(*) FROM webhook_deliveries d `+where, args...); err != nil {
+		return nil, 0, fmt.Errorf("error counting webhook deliveries: %w", err)
+	}
+
+	list := []WebhookDelivery{}
+	query := fmt.Sprintf(`
+		SELECT %s
+		FROM webhook_deliveries d
+		JOIN webhook_events e ON e.event_id = d.event_id
+		%s
+		ORDER BY d.created_at DESC, d.delivery_id DESC
+		LIMIT $%d OFFSET $%d`, deliveryColumns, where, len(args)+1, len(args)+2)
+	if err := r.db.Select(&list, query, append(args, limit, offset)...); err != nil {
+		return nil, 0, fmt.Errorf("error fetching webhook deliveries: %w", err)
+	}
+	return list, total, nil
+}
+
+// Replay queues a new delivery of the same event to the same subscription,
+// due at once. The subscription has to be active.
+func (r *webhookRepo) Replay(deliveryID int64) (*WebhookDelivery, error) {
+	var active bool
+	err := r.db.Get(&active, `
+		SELECT s.active
+		FROM webhook_deliveries d
+		JOIN webhook_subscriptions s ON s.subscription_id = d.subscription_id
+		WHERE d.delivery_id = $1
+	`, deliveryID)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, ErrDeliveryNotFound
+		}
+		return nil, fmt.Errorf("error fetching webhook delivery: %w", err)
+	}
+	if !active {
+		return nil, ErrWebhookDisabled
+	}
+
+	var id int64
+	err = r.db.Get(&id, `
+		INSERT INTO webhook_deliveries (subscription_id, event_id, replay_of, next_attempt_at)
+		SELECT subscription_id, event_id, delivery_id, NOW()
+		FROM webhook_deliveries
+		WHERE delivery_id = $1
+		RETURNING delivery_id
+	`, deliveryID)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, ErrDeliveryNotFound
+		}
+		return nil, fmt.Errorf("error replaying webhook delivery: %w", err)
+	}
+	return r.GetDelivery(id)
+}
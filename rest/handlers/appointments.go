@@ -0,0 +1,337 @@
+package handlers
+
+import (
+	"encoding/json"
+	"errors"
+	"log"
+	"medidhaka/repo"
+	"medidhaka/util"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/gorilla/mux"
+)
+
+// maxOpenBookings is how many upcoming appointments a patient, or a phone
+// number they book for, may hold at once.
+const maxOpenBookings = 3
+
+type AppointmentHandler struct {
+	repo         repo.AppointmentRepo
+	scheduleRepo repo.ScheduleRepo
+}
+
+func NewAppointmentHandler(r repo.AppointmentRepo, sRepo repo.ScheduleRepo) *AppointmentHandler {
+	return &AppointmentHandler{
+		repo:         r,
+		scheduleRepo: sRepo,
+	}
+}
+
+// slots generates the doctor's slots at the hospital on date and marks the
+// booked and already-started ones as unavailable.
+func (h *AppointmentHandler) slots(hospitalID, doctorID int, date time.Time) ([]repo.Slot, error) {
+	sessions, err := h.scheduleRepo.ListForHospitalOnDate(hospitalID, date)
+	if err != nil {
+		return nil, err
+	}
+	day := date.Format("2006-01-02")
+	booked, err := h.repo.BookedStartTimes(hospitalID, doctorID, day)
+	if err != nil {
+		return nil, err
+	}
+	taken := make(map[string]bool, len(booked))
+	for _, t := range booked {
+		taken[t] = true
+	}
+
+	now := time.Now().In(util.Dhaka)
+	slots := []repo.Slot{}
+	for _, s := range sessions {
+		if s.DoctorID != doctorID {
+			continue
+		}
+		for _, slot := range repo.SplitSession(s) {
+			start, _ := time.ParseInLocation("2006-01-02 15:04", day+" "+slot.StartTime, util.Dhaka)
+			slot.Available = !taken[slot.StartTime] && start.After(now)
+			slots = append(slots, slot)
+		}
+	}
+	return slots, nil
+}
+
+// findSlot returns the slot starting at startTime, if any.
+func findSlot(slots []repo.Slot, startTime string) (repo.Slot, bool) {
+	for _, s := range slots {
+		if s.StartTime == startTime {
+			return s, true
+		}
+	}
+	return repo.Slot{}, false
+}
+
+// resolveSlot validates that date/startTime is a bookable slot and returns it.
+// On failure it writes the error response and returns false.
+func (h *AppointmentHandler) resolveSlot(w http.ResponseWriter, hospitalID, doctorID int, day, startTime string) (repo.Slot, bool) {
+	date, err := time.ParseInLocation("2006-01-02", day, util.Dhaka)
+	if err != nil {
+		util.SendData(w, map[string]string{"error": "date must be YYYY-MM-DD"}, http.StatusBadRequest)
+		return repo.Slot{}, false
+	}
+	slots, err := h.slots(hospitalID, doctorID, date)
+	if err != nil {
+		log.Printf("Failed to generate slots: %v", err)
+		util.SendData(w, map[string]string{"error": "Failed to check slot availability"}, http.StatusInternalServerError)
+		return repo.Slot{}, false
+	}
+	slot, ok := findSlot(slots, startTime)
+	if !ok {
+		util.SendData(w, map[string]string{"error": "No such slot in the doctor's schedule"}, http.StatusBadRequest)
+		return repo.Slot{}, false
+	}
+	if !slot.Available {
+		util.SendData(w, map[string]string{"error": "Slot is not available"}, http.StatusConflict)
+		return repo.Slot{}, false
+	}
+	return slot, true
+}
+
+// List bookable slots of a hospital-doctor relation on a date
+func (h *AppointmentHandler) ListSlots(w http.ResponseWriter, r *http.Request) {
+	hospitalID, doctorID, ok := relationIDs(r)
+	if !ok {
+		util.SendData(w, map[string]string{"error": "Invalid ID format"}, http.StatusBadRequest)
+		return
+	}
+
+	day := r.URL.Query().Get("date")
+	if day == "" {
+		day = util.TodayInDhaka()
+	}
+	date, err := time.ParseInLocation("2006-01-02", day, util.Dhaka)
+	if err != nil {
+		util.SendData(w, map[string]string{"error": "date must be YYYY-MM-DD"}, http.StatusBadRequest)
+		return
+	}
+
+	slots, err := h.slots(hospitalID, doctorID, date)
+	if err != nil {
+		log.Printf("Failed to generate slots: %v", err)
+		util.SendData(w, map[string]string{"error": "Failed to fetch slots"}, http.StatusInternalServerError)
+		return
+	}
+	util.SendData(w, map[string]interface{}{"date": day, "timezone": "Asia/Dhaka", "slots": slots}, http.StatusOK)
+}
+
+func (h *AppointmentHandler) BookAppointment(w http.ResponseWriter, r *http.Request) {
+	var a repo.Appointment
+	if err := json.NewDecoder(r.Body).Decode(&a); err != nil {
+		util.SendData(w, map[string]string{"error": "Invalid input format"}, http.StatusBadRequest)
+		return
+	}
+	a.PatientName = strings.TrimSpace(a.PatientName)
+	if a.HospitalID <= 0 || a.DoctorID <= 0 || a.PatientName == "" || a.PatientPhone == "" {
+		util.SendData(w, map[string]string{"error": "hospital_id, doctor_id, patient_name and patient_phone are required"}, http.StatusBadRequest)
+		return
+	}
+	phone, ok := util.NormalizePhone(a.PatientPhone)
+	if !ok {
+		util.SendData(w, map[string]string{"error": "Invalid Bangladeshi mobile number"}, http.StatusBadRequest)
+		return
+	}
+	a.PatientPhone = phone
+
+	slot, ok := h.resolveSlot(w, a.HospitalID, a.DoctorID, a.Date, a.StartTime)
+	if !ok {
+		return
+	}
+	a.EndTime = slot.EndTime
+	patientID, _ := util.PatientID(r.Context())
+	a.PatientID = &patientID
+
+	created, err := h.repo.Book(a, maxOpenBookings)
+	if err != nil {
+		switch {
+		case errors.Is(err, repo.ErrTooManyBookings):
+			util.SendData(w, map[string]string{"error": "Too many upcoming appointments, cancel one before booking another"}, http.StatusTooManyRequests)
+		case errors.Is(err, repo.ErrSlotTaken):
+			util.SendData(w, map[string]string{"error": "Slot is already booked"}, http.StatusConflict)
+		case errors.Is(err, repo.ErrNoAffiliation):
+			util.SendData(w, map[string]string{"error": "Doctor is not assigned to this hospital"}, http.StatusNotFound)
+		default:
+			log.Printf("Failed to book appointment: %v", err)
+			util.SendData(w, map[string]string{"error": "Failed to book appointment"}, http.StatusInternalServerError)
+		}
+		return
+	}
+	util.SendData(w, created, http.StatusCreated)
+	log.Printf("Appointment booked: ID %d (doctor %d, %s %s)", created.AppointmentID, created.DoctorID, created.Date, created.StartTime)
+}
+
+// ownAppointment loads the appointment in the URL if it belongs to the
+// logged-in patient. Other patients' appointments are reported as not found.
+func (h *AppointmentHandler) ownAppointment(w http.ResponseWriter, r *http.Request) (*repo.Appointment, bool) {
+	id, err := strconv.Atoi(mux.Vars(r)["id"])
+	if err != nil {
+		util.SendData(w, map[string]string{"error": "Invalid appointment ID format"}, http.StatusBadRequest)
+		return nil, false
+	}
+	patientID, _ := util.PatientID(r.Context())
+
+	a, err := h.repo.Get(id)
+	if err != nil || a.PatientID == nil || *a.PatientID != patientID {
+		if err == nil || errors.Is(err, repo.ErrAppointmentNotFound) {
+			util.SendData(w, map[string]string{"error": "Appointment not found"}, http.StatusNotFound)
+			return nil, false
+		}
+		log.Printf("Failed to get appointment ID %d: %v", id, err)
+		util.SendData(w, map[string]string{"error": "Server error"}, http.StatusInternalServerError)
+		return nil, false
+	}
+	return a, true
+}
+
+func (h *AppointmentHandler) GetAppointment(w http.ResponseWriter, r *http.Request) {
+	a, ok := h.ownAppointment(w, r)
+	if !ok {
+		return
+	}
+	util.SendData(w, a, http.StatusOK)
+}
+
+// sendStatusError maps repo errors of status changes to responses.
+func sendStatusError(w http.ResponseWriter, id int, err error) {
+	switch {
+	case errors.Is(err, repo.ErrAppointmentNotFound):
+		util.SendData(w, map[string]string{"error": "Appointment not found"}, http.StatusNotFound)
+	case errors.Is(err, repo.ErrInvalidTransition):
+		util.SendData(w, map[string]string{"error": "Appointment cannot be changed from its current status"}, http.StatusConflict)
+	case errors.Is(err, repo.ErrSlotTaken):
+		util.SendData(w, map[string]string{"error": "Slot is already booked"}, http.StatusConflict)
+	default:
+		log.Printf("Failed to update appointment ID %d: %v", id, err)
+		util.SendData(w, map[string]string{"error": "Failed to update appointment"}, http.StatusInternalServerError)
+	}
+}
+
+func (h *AppointmentHandler) CancelAppointment(w http.ResponseWriter, r *http.Request) {
+	current, ok := h.ownAppointment(w, r)
+	if !ok {
+		return
+	}
+
+	updated, err := h.repo.UpdateStatus(current.AppointmentID, repo.StatusCancelled)
+	if err != nil {
+		sendStatusError(w, current.AppointmentID, err)
+		return
+	}
+	util.SendData(w, updated, http.StatusOK)
+}
+
+func (h *AppointmentHandler) RescheduleAppointment(w http.ResponseWriter, r *http.Request) {
+	current, ok := h.ownAppointment(w, r)
+	if !ok {
+		return
+	}
+
+	var body struct {
+		Date      string `json:"date"`
+		StartTime string `json:"start_time"`
+	}
+	if err := json.NewDecoder(r.Body).Decode(&body); err != nil {
+		util.SendData(w, map[string]string{"error": "Invalid input format"}, http.StatusBadRequest)
+		return
+	}
+
+	slot, ok := h.resolveSlot(w, current.HospitalID, current.DoctorID, body.Date, body.StartTime)
+	if !ok {
+		return
+	}
+
+	updated, err := h.repo.Reschedule(current.AppointmentID, body.Date, slot.StartTime, slot.EndTime)
+	if err != nil {
+		sendStatusError(w, current.AppointmentID, err)
+		return
+	}
+	util.SendData(w, updated, http.StatusOK)
+}
+
+// Move an appointment through its lifecycle (checked_in, completed, no_show).
+// Only the staff of the appointment's hospital may do so.
+func (h *AppointmentHandler) UpdateAppointmentStatus(w http.ResponseWriter, r *http.Request) {
+	id, err := strconv.Atoi(mux.Vars(r)["id"])
+	if err != nil {
+		util.SendData(w, map[string]string{"error": "Invalid appointment ID format"}, http.StatusBadRequest)
+		return
+	}
+
+	var body struct {
+		Status string `json:"status"`
+	}
+	if err := json.NewDecoder(r.Body).Decode(&body); err != nil || body.Status == "" {
+		util.SendData(w, map[string]string{"error": "status is required"}, http.StatusBadRequest)
+		return
+	}
+
+	current, err := h.repo.Get(id)
+	if err != nil {
+		sendStatusError(w, id, err)
+		return
+	}
+	if !staffOwnsHospital(w, r, current.HospitalID) {
+		return
+	}
+
+	updated, err := h.repo.UpdateStatus(id, body.Status)
+	if err != nil {
+		sendStatusError(w, id, err)
+		return
+	}
+	util.SendData(w, updated, http.StatusOK)
+}
+
+func (h *AppointmentHandler) list(w http.ResponseWriter, r *http.Request, filter repo.AppointmentFilter) {
+	query := r.URL.Query()
+	filter.Date = query.Get("date")
+	filter.Status = query.Get("status")
+	page, limit, offset := parsePagination(query, 20)
+
+	list, total, err := h.repo.List(filter, offset, limit)
+	if err != nil {
+		log.Printf("Failed to list appointments: %v", err)
+		util.SendData(w, map[string]string{"error": "Failed to fetch appointments"}, http.StatusInternalServerError)
+		return
+	}
+	util.SendData(w, paginated(list, total, page, limit), http.StatusOK)
+}
+
+// Appointments of a doctor at the staff key's hospital
+func (h *AppointmentHandler) ListDoctorAppointments(w http.ResponseWriter, r *http.Request) {
+	doctorID, err := strconv.Atoi(mux.Vars(r)["id"])
+	if err != nil {
+		util.SendData(w, map[string]string{"error": "Invalid doctor ID format"}, http.StatusBadRequest)
+		return
+	}
+	staff, _ := util.Staff(r.Context())
+	h.list(w, r, repo.AppointmentFilter{DoctorID: doctorID, HospitalID: staff.HospitalID})
+}
+
+func (h *AppointmentHandler) ListHospitalAppointments(w http.ResponseWriter, r *http.Request) {
+	hospitalID, err := strconv.Atoi(mux.Vars(r)["id"])
+	if err != nil {
+		util.SendData(w, map[string]string{"error": "Invalid hospital ID format"}, http.StatusBadRequest)
+		return
+	}
+	if !staffOwnsHospital(w, r, hospitalID) {
+		return
+	}
+	h.list(w, r, repo.AppointmentFilter{HospitalID: hospitalID})
+}
+
+// Appointments of the logged-in patient
+func (h *AppointmentHandler) ListMyAppointments(w http.ResponseWriter, r *http.Request) {
+	patientID, _ := util.PatientID(r.Context())
+	h.list(w, r, repo.AppointmentFilter{PatientID: patientID})
+}
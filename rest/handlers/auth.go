@@ -0,0 +1,255 @@
+package handlers
+
+import (
+	"crypto/rand"
+	"crypto/subtle"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log"
+	"math/big"
+	"medidhaka/infra/sms"
+	"medidhaka/repo"
+	middleware "medidhaka/rest/middlewares"
+	"medidhaka/util"
+	"net/http"
+	"time"
+)
+
+const (
+	otpTTL             = 5 * time.Minute
+	otpMaxAttempts     = 5
+	otpResendInterval  = time.Minute
+	otpHourlyLimit     = 5
+	accessTokenTTL     = 15 * time.Minute
+	refreshTokenTTL    = 30 * 24 * time.Hour
+	refreshTokenLength = 32
+)
+
+type AuthHandler struct {
+	patientRepo repo.PatientRepo
+	authRepo    repo.AuthRepo
+	sender      sms.Sender
+	secret      string
+}
+
+func NewAuthHandler(pRepo repo.PatientRepo, aRepo repo.AuthRepo, sender sms.Sender, secret string) *AuthHandler {
+	return &AuthHandler{
+		patientRepo: pRepo,
+		authRepo:    aRepo,
+		sender:      sender,
+		secret:      secret,
+	}
+}
+
+// generateOTP returns a random 6-digit code.
+func generateOTP() (string, error) {
+	n, err := rand.Int(rand.Reader, big.NewInt(1000000))
+	if err != nil {
+		return "", err
+	}
+	return fmt.Sprintf("%06d", n.Int64()), nil
+}
+
+// issueTokens creates a fresh access/refresh token pair for the patient.
+func (h *AuthHandler) issueTokens(patientID int) (map[string]interface{}, error) {
+	access, err := util.SignAccessToken(h.secret, middleware.PatientRole, patientID, accessTokenTTL)
+	if err != nil {
+		return nil, err
+	}
+	refresh, err := util.RandomToken(refreshTokenLength)
+	if err != nil {
+		return nil, err
+	}
+	if err := h.authRepo.CreateRefreshToken(patientID, util.HashToken(refresh), time.Now().Add(refreshTokenTTL)); err != nil {
+		return nil, err
+	}
+	return map[string]interface{}{
+		"access_token":  access,
+		"refresh_token": refresh,
+		"token_type":    "Bearer",
+		"expires_in":    int(accessTokenTTL.Seconds()),
+	}, nil
+}
+
+// loginEnabled reports whether tokens can be issued. Without a JWT secret
+// patient login is off and the login routes answer 503.
+func (h *AuthHandler) loginEnabled(w http.ResponseWriter) bool {
+	if h.secret == "" {
+		util.SendData(w, map[string]string{"error": "Patient login is not enabled"}, http.StatusServiceUnavailable)
+		return false
+	}
+	return true
+}
+
+// Send a one-time login code to a phone number
+func (h *AuthHandler) RequestOTP(w http.ResponseWriter, r *http.Request) {
+	if !h.loginEnabled(w) {
+		return
+	}
+	var body struct {
+		PhoneNumber string `json:"phone_number"`
+	}
+	if err := json.NewDecoder(r.Body).Decode(&body); err != nil {
+		util.SendData(w, map[string]string{"error": "Invalid input format"}, http.StatusBadRequest)
+		return
+	}
+	phone, ok := util.NormalizePhone(body.PhoneNumber)
+	if !ok {
+		util.SendData(w, map[string]string{"error": "Invalid Bangladeshi mobile number"}, http.StatusBadRequest)
+		return
+	}
+
+	code, err := generateOTP()
+	if err == nil {
+		// Throttle per number: one code per minute and a few per hour.
+		err = h.authRepo.CreateOTP(phone, util.HashToken(code), time.Now().Add(otpTTL), otpResendInterval, otpHourlyLimit)
+	}
+	if errors.Is(err, repo.ErrOTPThrottled) {
+		util.SendData(w, map[string]string{"error": "Too many OTP requests, please try again later"}, http.StatusTooManyRequests)
+		return
+	}
+	if err == nil {
+		err = h.sender.Send(phone, fmt.Sprintf("Your MediDhaka login code is %s. It expires in %d minutes.", code, int(otpTTL.Minutes())))
+	}
+	if err != nil {
+		log.Printf("Failed to send OTP: %v", err)
+		util.SendData(w, map[string]string{"error": "Failed to send OTP"}, http.StatusInternalServerError)
+		return
+	}
+
+	util.SendData(w, map[string]interface{}{"message": "OTP sent", "expires_in": int(otpTTL.Seconds())}, http.StatusOK)
+}
+
+// Verify a login code, registering the patient on first login
+func (h *AuthHandler) VerifyOTP(w http.ResponseWriter, r *http.Request) {
+	if !h.loginEnabled(w) {
+		return
+	}
+	var body struct {
+		PhoneNumber string `json:"phone_number"`
+		Code        string `json:"code"`
+	}
+	if err := json.NewDecoder(r.Body).Decode(&body); err != nil {
+		util.SendData(w, map[string]string{"error": "Invalid input format"}, http.StatusBadRequest)
+		return
+	}
+	phone, ok := util.NormalizePhone(body.PhoneNumber)
+	if !ok || body.Code == "" {
+		util.SendData(w, map[string]string{"error": "phone_number and code are required"}, http.StatusBadRequest)
+		return
+	}
+
+	otp, err := h.authRepo.LatestActiveOTP(phone)
+	if err != nil {
+		if errors.Is(err, repo.ErrOTPNotFound) {
+			util.SendData(w, map[string]string{"error": "Invalid or expired code"}, http.StatusUnauthorized)
+			return
+		}
+		log.Printf("Failed to fetch OTP: %v", err)
+		util.SendData(w, map[string]string{"error": "Failed to verify OTP"}, http.StatusInternalServerError)
+		return
+	}
+	// The attempt is counted before the code is compared, so concurrent
+	// guesses cannot get past the limit.
+	if err := h.authRepo.UseOTPAttempt(otp.OTPID, otpMaxAttempts); err != nil {
+		if errors.Is(err, repo.ErrOTPAttemptsExhausted) {
+			util.SendData(w, map[string]string{"error": "Too many wrong attempts, request a new code"}, http.StatusTooManyRequests)
+			return
+		}
+		log.Printf("Failed to record OTP attempt: %v", err)
+		util.SendData(w, map[string]string{"error": "Failed to verify OTP"}, http.StatusInternalServerError)
+		return
+	}
+	if subtle.ConstantTimeCompare([]byte(otp.CodeHash), []byte(util.HashToken(body.Code))) != 1 {
+		util.SendData(w, map[string]string{"error": "Invalid or expired code"}, http.StatusUnauthorized)
+		return
+	}
+	if err := h.authRepo.ConsumeOTP(otp.OTPID); err != nil {
+		// Another request used the same code first.
+		util.SendData(w, map[string]string{"error": "Invalid or expired code"}, http.StatusUnauthorized)
+		return
+	}
+
+	patient, created, err := h.patientRepo.GetOrCreateByPhone(phone)
+	if err != nil {
+		log.Printf("Failed to load patient: %v", err)
+		util.SendData(w, map[string]string{"error": "Failed to log in"}, http.StatusInternalServerError)
+		return
+	}
+	tokens, err := h.issueTokens(patient.PatientID)
+	if err != nil {
+		log.Printf("Failed to issue tokens: %v", err)
+		util.SendData(w, map[string]string{"error": "Failed to log in"}, http.StatusInternalServerError)
+		return
+	}
+	tokens["patient"] = patient
+	tokens["is_new"] = created
+
+	status := http.StatusOK
+	if created {
+		status = http.StatusCreated
+	}
+	util.SendData(w, tokens, status)
+}
+
+// Exchange a refresh token for a new token pair
+func (h *AuthHandler) Refresh(w http.ResponseWriter, r *http.Request) {
+	if !h.loginEnabled(w) {
+		return
+	}
+	var body struct {
+		RefreshToken string `json:"refresh_token"`
+	}
+	if err := json.NewDecoder(r.Body).Decode(&body); err != nil || body.RefreshToken == "" {
+		util.SendData(w, map[string]string{"error": "refresh_token is required"}, http.StatusBadRequest)
+		return
+	}
+
+	refresh, err := util.RandomToken(refreshTokenLength)
+	if err != nil {
+		log.Printf("Failed to generate refresh token: %v", err)
+		util.SendData(w, map[string]string{"error": "Failed to refresh token"}, http.StatusInternalServerError)
+		return
+	}
+	patientID, err := h.authRepo.RotateRefreshToken(util.HashToken(body.RefreshToken), util.HashToken(refresh), time.Now().Add(refreshTokenTTL))
+	if err != nil {
+		if errors.Is(err, repo.ErrRefreshTokenNotFound) {
+			util.SendData(w, map[string]string{"error": "Invalid refresh token"}, http.StatusUnauthorized)
+			return
+		}
+		log.Printf("Failed to rotate refresh token: %v", err)
+		util.SendData(w, map[string]string{"error": "Failed to refresh token"}, http.StatusInternalServerError)
+		return
+	}
+
+	access, err := util.SignAccessToken(h.secret, middleware.PatientRole, patientID, accessTokenTTL)
+	if err != nil {
+		log.Printf("Failed to sign access token: %v", err)
+		util.SendData(w, map[string]string{"error": "Failed to refresh token"}, http.StatusInternalServerError)
+		return
+	}
+	util.SendData(w, map[string]interface{}{
+		"access_token":  access,
+		"refresh_token": refresh,
+		"token_type":    "Bearer",
+		"expires_in":    int(accessTokenTTL.Seconds()),
+	}, http.StatusOK)
+}
+
+// Revoke a refresh token
+func (h *AuthHandler) Logout(w http.ResponseWriter, r *http.Request) {
+	var body struct {
+		RefreshToken string `json:"refresh_token"`
+	}
+	if err := json.NewDecoder(r.Body).Decode(&body); err != nil || body.RefreshToken == "" {
+		util.SendData(w, map[string]string{"error": "refresh_token is required"}, http.StatusBadRequest)
+		return
+	}
+	if err := h.authRepo.RevokeRefreshToken(util.HashToken(body.RefreshToken)); err != nil {
+		log.Printf("Failed to revoke refresh token: %v", err)
+		util.SendData(w, map[string]string{"error": "Failed to log out"}, http.StatusInternalServerError)
+		return
+	}
+	util.SendData(w, map[string]string{"message": "Logged out successfully"}, http.StatusOK)
+}
@@ -0,0 +1,105 @@
+package handlers
+
+import (
+	"encoding/json"
+	"errors"
+	"log"
+	"medidhaka/repo"
+	"medidhaka/util"
+	"net/http"
+	"strconv"
+
+	"github.com/gorilla/mux"
+)
+
+type BedHandler struct {
+	repo repo.BedRepo
+}
+
+func NewBedHandler(r repo.BedRepo) *BedHandler {
+	return &BedHandler{repo: r}
+}
+
+// Bed inventory of a hospital, per ward type
+func (h *BedHandler) ListHospitalBeds(w http.ResponseWriter, r *http.Request) {
+	hospitalID, err := strconv.Atoi(mux.Vars(r)["id"])
+	if err != nil {
+		util.SendData(w, map[string]string{"error": "Invalid hospital ID format"}, http.StatusBadRequest)
+		return
+	}
+	list, err := h.repo.ListByHospital(hospitalID)
+	if err != nil {
+		log.Printf("Failed to list beds of hospital ID %d: %v", hospitalID, err)
+		util.SendData(w, map[string]string{"error": "Failed to fetch bed availability"}, http.StatusInternalServerError)
+		return
+	}
+	util.SendData(w, list, http.StatusOK)
+}
+
+// Set the total and occupied beds of a ward; staff of the hospital only
+func (h *BedHandler) UpdateHospitalBeds(w http.ResponseWriter, r *http.Request) {
+	vars := mux.Vars(r)
+	hospitalID, err := strconv.Atoi(vars["id"])
+	if err != nil {
+		util.SendData(w, map[string]string{"error": "Invalid hospital ID format"}, http.StatusBadRequest)
+		return
+	}
+	ward := vars["ward"]
+	if !contains(repo.WardTypes, ward) {
+		util.SendData(w, map[string]string{"error": "ward must be one of general, cabin, icu, ccu, nicu"}, http.StatusBadRequest)
+		return
+	}
+	if !staffOwnsHospital(w, r, hospitalID) {
+		return
+	}
+
+	var body struct {
+		Total    *int `json:"total_beds"`
+		Occupied *int `json:"occupied_beds"`
+	}
+	if err := json.NewDecoder(r.Body).Decode(&body); err != nil {
+		util.SendData(w, map[string]string{"error": "Invalid input format"}, http.StatusBadRequest)
+		return
+	}
+	if body.Total == nil || body.Occupied == nil || *body.Total < 0 || *body.Occupied < 0 || *body.Occupied > *body.Total {
+		util.SendData(w, map[string]string{"error": "total_beds and occupied_beds are required, with 0 <= occupied_beds <= total_beds"}, http.StatusBadRequest)
+		return
+	}
+
+	staff, _ := util.Staff(r.Context())
+	saved, err := h.repo.Upsert(repo.BedInventory{
+		HospitalID: hospitalID,
+		WardType:   ward,
+		Total:      *body.Total,
+		Occupied:   *body.Occupied,
+	}, staff.KeyID)
+	if err != nil {
+		if errors.Is(err, repo.ErrNotFound) {
+			util.SendData(w, map[string]string{"error": "Hospital not found"}, http.StatusNotFound)
+			return
+		}
+		log.Printf("Failed to update beds of hospital ID %d: %v", hospitalID, err)
+		util.SendData(w, map[string]string{"error": "Failed to update bed availability"}, http.StatusInternalServerError)
+		return
+	}
+	util.SendData(w, saved, http.StatusOK)
+}
+
+// Hospitals with beds of a ward type, most free beds first
+func (h *BedHandler) ListAvailability(w http.ResponseWriter, r *http.Request) {
+	query := r.URL.Query()
+	ward := query.Get("ward")
+	if !contains(repo.WardTypes, ward) {
+		util.SendData(w, map[string]string{"error": "ward must be one of general, cabin, icu, ccu, nicu"}, http.StatusBadRequest)
+		return
+	}
+	page, limit, offset := parsePagination(query, 20)
+
+	list, total, err := h.repo.ListAvailability(ward, offset, limit)
+	if err != nil {
+		log.Printf("Failed to list bed availability: %v", err)
+		util.SendData(w, map[string]string{"error": "Failed to fetch bed availability"}, http.StatusInternalServerError)
+		return
+	}
+	util.SendData(w, paginated(list, total, page, limit), http.StatusOK)
+}
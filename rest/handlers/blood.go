@@ -0,0 +1,146 @@
+package handlers
+
+import (
+	"encoding/json"
+	"errors"
+	"log"
+	"medidhaka/repo"
+	"medidhaka/util"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/gorilla/mux"
+)
+
+type BloodHandler struct {
+	repo repo.BloodRepo
+}
+
+func NewBloodHandler(r repo.BloodRepo) *BloodHandler {
+	return &BloodHandler{repo: r}
+}
+
+func contains(list []string, value string) bool {
+	for _, v := range list {
+		if v == value {
+			return true
+		}
+	}
+	return false
+}
+
+// normalizeBloodGroup upper-cases a blood group. An unencoded "+" in a query
+// string arrives as a space, so a trailing space is read as "+".
+func normalizeBloodGroup(group string) string {
+	group = strings.ToUpper(strings.TrimLeft(group, " "))
+	if strings.HasSuffix(group, " ") {
+		group = strings.TrimRight(group, " ") + "+"
+	}
+	return group
+}
+
+// Blood stock of a hospital
+func (h *BloodHandler) ListHospitalBlood(w http.ResponseWriter, r *http.Request) {
+	hospitalID, err := strconv.Atoi(mux.Vars(r)["id"])
+	if err != nil {
+		util.SendData(w, map[string]string{"error": "Invalid hospital ID format"}, http.StatusBadRequest)
+		return
+	}
+	list, err := h.repo.ListByHospital(hospitalID)
+	if err != nil {
+		log.Printf("Failed to list blood stock of hospital ID %d: %v", hospitalID, err)
+		util.SendData(w, map[string]string{"error": "Failed to fetch blood stock"}, http.StatusInternalServerError)
+		return
+	}
+	util.SendData(w, list, http.StatusOK)
+}
+
+// Set the units available of a blood group and component; staff of the
+// hospital only
+func (h *BloodHandler) UpdateHospitalBlood(w http.ResponseWriter, r *http.Request) {
+	hospitalID, err := strconv.Atoi(mux.Vars(r)["id"])
+	if err != nil {
+		util.SendData(w, map[string]string{"error": "Invalid hospital ID format"}, http.StatusBadRequest)
+		return
+	}
+	if !staffOwnsHospital(w, r, hospitalID) {
+		return
+	}
+
+	var body struct {
+		BloodGroup     string `json:"blood_group"`
+		Component      string `json:"component"`
+		UnitsAvailable *int   `json:"units_available"`
+	}
+	if err := json.NewDecoder(r.Body).Decode(&body); err != nil {
+		util.SendData(w, map[string]string{"error": "Invalid input format"}, http.StatusBadRequest)
+		return
+	}
+	group := normalizeBloodGroup(body.BloodGroup)
+	if !contains(repo.BloodGroups, group) {
+		util.SendData(w, map[string]string{"error": "blood_group must be one of A+, A-, B+, B-, AB+, AB-, O+, O-"}, http.StatusBadRequest)
+		return
+	}
+	if !contains(repo.BloodComponents, body.Component) {
+		util.SendData(w, map[string]string{"error": "component must be one of whole_blood, red_cells, platelets, plasma, cryo"}, http.StatusBadRequest)
+		return
+	}
+	if body.UnitsAvailable == nil || *body.UnitsAvailable < 0 {
+		util.SendData(w, map[string]string{"error": "units_available must be zero or more"}, http.StatusBadRequest)
+		return
+	}
+
+	staff, _ := util.Staff(r.Context())
+	saved, err := h.repo.Upsert(repo.BloodStock{
+		HospitalID:     hospitalID,
+		BloodGroup:     group,
+		Component:      body.Component,
+		UnitsAvailable: *body.UnitsAvailable,
+	}, staff.KeyID)
+	if err != nil {
+		if errors.Is(err, repo.ErrNotFound) {
+			util.SendData(w, map[string]string{"error": "Hospital not found"}, http.StatusNotFound)
+			return
+		}
+		log.Printf("Failed to update blood stock of hospital ID %d: %v", hospitalID, err)
+		util.SendData(w, map[string]string{"error": "Failed to update blood stock"}, http.StatusInternalServerError)
+		return
+	}
+	util.SendData(w, saved, http.StatusOK)
+}
+
+// Hospitals with a blood group in stock, nearest first when lat/lng are given
+func (h *BloodHandler) ListBloodBanks(w http.ResponseWriter, r *http.Request) {
+	query := r.URL.Query()
+	filter := repo.BloodBankFilter{
+		BloodGroup: normalizeBloodGroup(query.Get("group")),
+		Component:  query.Get("component"),
+	}
+	if !contains(repo.BloodGroups, filter.BloodGroup) {
+		util.SendData(w, map[string]string{"error": "group must be one of A+, A-, B+, B-, AB+, AB-, O+, O-"}, http.StatusBadRequest)
+		return
+	}
+	if filter.Component != "" && !contains(repo.BloodComponents, filter.Component) {
+		util.SendData(w, map[string]string{"error": "component must be one of whole_blood, red_cells, platelets, plasma, cryo"}, http.StatusBadRequest)
+		return
+	}
+	if query.Get("lat") != "" || query.Get("lng") != "" {
+		lat, errLat := strconv.ParseFloat(query.Get("lat"), 64)
+		lng, errLng := strconv.ParseFloat(query.Get("lng"), 64)
+		if errLat != nil || errLng != nil || lat < -90 || lat > 90 || lng < -180 || lng > 180 {
+			util.SendData(w, map[string]string{"error": "lat and lng must both be valid coordinates"}, http.StatusBadRequest)
+			return
+		}
+		filter.Lat, filter.Lng = &lat, &lng
+	}
+	page, limit, offset := parsePagination(query, 20)
+
+	list, total, err := h.repo.ListBanks(filter, offset, limit)
+	if err != nil {
+		log.Printf("Failed to list blood banks: %v", err)
+		util.SendData(w, map[string]string{"error": "Failed to fetch blood banks"}, http.StatusInternalServerError)
+		return
+	}
+	util.SendData(w, paginated(list, total, page, limit), http.StatusOK)
+}
@@ -0,0 +1,165 @@
+package handlers
+
+import (
+	"encoding/json"
+	"errors"
+	"log"
+	"medidhaka/repo"
+	"medidhaka/util"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/gorilla/mux"
+)
+
+type CredentialHandler struct {
+	repo repo.CredentialRepo
+}
+
+func NewCredentialHandler(r repo.CredentialRepo) *CredentialHandler {
+	return &CredentialHandler{repo: r}
+}
+
+// List the degrees and diplomas of a doctor
+func (h *CredentialHandler) ListQualifications(w http.ResponseWriter, r *http.Request) {
+	doctorID, err := strconv.Atoi(mux.Vars(r)["id"])
+	if err != nil {
+		util.SendData(w, map[string]string{"error": "Invalid doctor ID format"}, http.StatusBadRequest)
+		return
+	}
+
+	list, err := h.repo.ListQualifications(doctorID)
+	if err != nil {
+		log.Printf("Failed to list qualifications of doctor ID %d: %v", doctorID, err)
+		util.SendData(w, map[string]string{"error": "Failed to fetch qualifications"}, http.StatusInternalServerError)
+		return
+	}
+	util.SendData(w, list, http.StatusOK)
+}
+
+// Add a degree or diploma to a doctor
+func (h *CredentialHandler) AddQualification(w http.ResponseWriter, r *http.Request) {
+	doctorID, err := strconv.Atoi(mux.Vars(r)["id"])
+	if err != nil {
+		util.SendData(w, map[string]string{"error": "Invalid doctor ID format"}, http.StatusBadRequest)
+		return
+	}
+
+	var q repo.Qualification
+	if err := json.NewDecoder(r.Body).Decode(&q); err != nil {
+		util.SendData(w, map[string]string{"error": "Invalid input format"}, http.StatusBadRequest)
+		return
+	}
+	q.DoctorID = doctorID
+	q.Degree = strings.TrimSpace(q.Degree)
+	q.Institution = strings.TrimSpace(q.Institution)
+	if q.Degree == "" {
+		util.SendData(w, map[string]string{"error": "degree is required"}, http.StatusBadRequest)
+		return
+	}
+	if q.Year != nil && (*q.Year < 1900 || *q.Year > time.Now().Year()) {
+		util.SendData(w, map[string]string{"error": "year must be between 1900 and the current year"}, http.StatusBadRequest)
+		return
+	}
+
+	created, err := h.repo.AddQualification(q)
+	if err != nil {
+		if errors.Is(err, repo.ErrDoctorNotFound) {
+			util.SendData(w, map[string]string{"error": "Doctor not found"}, http.StatusNotFound)
+			return
+		}
+		log.Printf("Failed to add qualification to doctor ID %d: %v", doctorID, err)
+		util.SendData(w, map[string]string{"error": "Failed to add qualification"}, http.StatusInternalServerError)
+		return
+	}
+	util.SendData(w, created, http.StatusCreated)
+}
+
+// Remove a degree or diploma from a doctor
+func (h *CredentialHandler) DeleteQualification(w http.ResponseWriter, r *http.Request) {
+	vars := mux.Vars(r)
+	doctorID, errConv1 := strconv.Atoi(vars["id"])
+	qualificationID, errConv2 := strconv.Atoi(vars["qualification_id"])
+	if errConv1 != nil || errConv2 != nil {
+		util.SendData(w, map[string]string{"error": "Invalid ID format"}, http.StatusBadRequest)
+		return
+	}
+
+	if err := h.repo.DeleteQualification(doctorID, qualificationID); err != nil {
+		if errors.Is(err, repo.ErrQualificationNotFound) {
+			util.SendData(w, map[string]string{"error": "Qualification not found"}, http.StatusNotFound)
+			return
+		}
+		log.Printf("Failed to delete qualification ID %d: %v", qualificationID, err)
+		util.SendData(w, map[string]string{"error": "Failed to delete qualification"}, http.StatusInternalServerError)
+		return
+	}
+	util.SendData(w, map[string]string{"message": "Qualification deleted successfully"}, http.StatusOK)
+}
+
+// Submit a doctor's BMDC registration for verification
+func (h *CredentialHandler) RequestVerification(w http.ResponseWriter, r *http.Request) {
+	doctorID, err := strconv.Atoi(mux.Vars(r)["id"])
+	if err != nil {
+		util.SendData(w, map[string]string{"error": "Invalid doctor ID format"}, http.StatusBadRequest)
+		return
+	}
+
+	if err := h.repo.RequestVerification(doctorID); err != nil {
+		switch {
+		case errors.Is(err, repo.ErrDoctorNotFound):
+			util.SendData(w, map[string]string{"error": "Doctor not found"}, http.StatusNotFound)
+		case errors.Is(err, repo.ErrMissingBMDC):
+			util.SendData(w, map[string]string{"error": "Add a BMDC registration number before requesting verification"}, http.StatusConflict)
+		case errors.Is(err, repo.ErrInvalidVerification):
+			util.SendData(w, map[string]string{"error": "Doctor is already pending or verified"}, http.StatusConflict)
+		default:
+			log.Printf("Failed to request verification of doctor ID %d: %v", doctorID, err)
+			util.SendData(w, map[string]string{"error": "Failed to request verification"}, http.StatusInternalServerError)
+		}
+		return
+	}
+	util.SendData(w, map[string]string{"message": "Verification requested successfully"}, http.StatusAccepted)
+}
+
+// Verify or reject a doctor's registration
+func (h *CredentialHandler) ReviewVerification(w http.ResponseWriter, r *http.Request) {
+	doctorID, err := strconv.Atoi(mux.Vars(r)["id"])
+	if err != nil {
+		util.SendData(w, map[string]string{"error": "Invalid doctor ID format"}, http.StatusBadRequest)
+		return
+	}
+	var body struct {
+		Status string `json:"status"`
+		Note   string `json:"note"`
+	}
+	if err := json.NewDecoder(r.Body).Decode(&body); err != nil {
+		util.SendData(w, map[string]string{"error": "Invalid input format"}, http.StatusBadRequest)
+		return
+	}
+	body.Note = strings.TrimSpace(body.Note)
+	if body.Status != repo.VerificationVerified && body.Status != repo.VerificationRejected {
+		util.SendData(w, map[string]string{"error": "status must be verified or rejected"}, http.StatusBadRequest)
+		return
+	}
+	if body.Status == repo.VerificationRejected && body.Note == "" {
+		util.SendData(w, map[string]string{"error": "note is required when rejecting"}, http.StatusBadRequest)
+		return
+	}
+
+	if err := h.repo.ReviewVerification(doctorID, body.Status, body.Note); err != nil {
+		switch {
+		case errors.Is(err, repo.ErrDoctorNotFound):
+			util.SendData(w, map[string]string{"error": "Doctor not found"}, http.StatusNotFound)
+		case errors.Is(err, repo.ErrInvalidVerification):
+			util.SendData(w, map[string]string{"error": "Only pending doctors can be reviewed, and verified doctors rejected"}, http.StatusConflict)
+		default:
+			log.Printf("Failed to review verification of doctor ID %d: %v", doctorID, err)
+			util.SendData(w, map[string]string{"error": "Failed to review verification"}, http.StatusInternalServerError)
+		}
+		return
+	}
+	util.SendData(w, map[string]string{"message": "Verification updated successfully"}, http.StatusOK)
+}
@@ -3,6 +3,9 @@ package handlers
 import (
 	"encoding/json"
 	"errors"
+	"log"
+	"medidhaka/directory"
+	"medidhaka/infra/storage"
 	"medidhaka/repo"
 	"medidhaka/util"
 	"net/http"
@@ -12,11 +15,13 @@ import (
 )
 
 type DoctorHandler struct {
-	repo repo.DoctorRepo
+	repo     repo.DoctorRepo
+	store    storage.Storage
+	webhooks repo.WebhookRepo
 }
 
-func NewDoctorHandler(r repo.DoctorRepo) *DoctorHandler {
-	return &DoctorHandler{repo: r}
+func NewDoctorHandler(r repo.DoctorRepo, store storage.Storage, webhooks repo.WebhookRepo) *DoctorHandler {
+	return &DoctorHandler{repo: r, store: store, webhooks: webhooks}
 }
 
 func (h *DoctorHandler) CreateDoctor(w http.ResponseWriter, r *http.Request) {
@@ -25,17 +30,49 @@ func (h *DoctorHandler) CreateDoctor(w http.ResponseWriter, r *http.Request) {
 		util.SendData(w, map[string]string{"error": "Invalid input format"}, http.StatusBadRequest)
 		return
 	}
+	if msg := directory.NormalizeCredentials(&doc); msg != "" {
+		util.SendData(w, map[string]string{"error": msg}, http.StatusBadRequest)
+		return
+	}
 	created, err := h.repo.Create(doc)
 	if err != nil {
+		if errors.Is(err, repo.ErrDuplicateBMDC) {
+			util.SendData(w, map[string]string{"error": "BMDC registration number already exists"}, http.StatusConflict)
+			return
+		}
 		util.SendData(w, map[string]string{"error": "Failed to create doctor"}, http.StatusInternalServerError)
 		return
 	}
+	directory.PublishEvent(h.webhooks, repo.EventDoctorCreated, created)
 	util.SendData(w, created, http.StatusCreated)
 }
 
+// ListDoctors lists verified doctors only.
 func (h *DoctorHandler) ListDoctors(w http.ResponseWriter, r *http.Request) {
+	h.list(w, r, repo.VerificationVerified)
+}
+
+// ListDoctorsForReview lists doctors of any verification status for admins;
+// pending doctors unless another status is asked for.
+func (h *DoctorHandler) ListDoctorsForReview(w http.ResponseWriter, r *http.Request) {
+	status := r.URL.Query().Get("status")
+	if status == "" {
+		status = repo.VerificationPending
+	}
+	if !contains(repo.VerificationStatuses, status) {
+		util.SendData(w, map[string]string{"error": "status must be unverified, pending, verified or rejected"}, http.StatusBadRequest)
+		return
+	}
+	h.list(w, r, status)
+}
+
+func (h *DoctorHandler) list(w http.ResponseWriter, r *http.Request, status string) {
 	query := r.URL.Query()
-	search := query.Get("search")
+	filter, msg := directory.ParseDoctorFilter(query, status)
+	if msg != "" {
+		util.SendData(w, map[string]string{"error": msg}, http.StatusBadRequest)
+		return
+	}
 	page := 1
 	limit := 10
 
@@ -51,11 +88,14 @@ func (h *DoctorHandler) ListDoctors(w http.ResponseWriter, r *http.Request) {
 	}
 	offset := (page - 1) * limit
 
-	list, total, err := h.repo.List(search, offset, limit)
+	list, total, err := h.repo.List(filter, offset, limit)
 	if err != nil {
 		util.SendData(w, map[string]string{"error": "Failed to fetch doctors"}, http.StatusInternalServerError)
 		return
 	}
+	for i := range list {
+		list[i].Images = imageVariants(h.store, list[i].ImageURL)
+	}
 
 	response := map[string]interface{}{
 		"data":       list,
@@ -68,6 +108,72 @@ func (h *DoctorHandler) ListDoctors(w http.ResponseWriter, r *http.Request) {
 	util.SendData(w, response, http.StatusOK)
 }
 
+// ExportDoctors streams every verified doctor matching the listing filters as
+// a file download.
+func (h *DoctorHandler) ExportDoctors(w http.ResponseWriter, r *http.Request) {
+	query := r.URL.Query()
+	filter, msg := directory.ParseDoctorFilter(query, repo.VerificationVerified)
+	if msg != "" {
+		util.SendData(w, map[string]string{"error": msg}, http.StatusBadRequest)
+		return
+	}
+	format, compress, msg := parseExportOptions(query)
+	if msg != "" {
+		util.SendData(w, map[string]string{"error": msg}, http.StatusBadRequest)
+		return
+	}
+
+	ew := startExport(w, "doctors", format, compress)
+	rw, err := util.NewRecordWriter(ew, format, repo.DoctorExportColumns)
+	if err == nil {
+		err = h.repo.Export(filter, func(d *repo.Doctor) error {
+			d.Images = imageVariants(h.store, d.ImageURL)
+			return rw.Write(d, d.ExportRecord())
+		})
+	}
+	if err == nil {
+		err = rw.Flush()
+	}
+	ew.finish(err, "doctors")
+}
+
+// ListNearbyDoctors returns doctors affiliated with a hospital within
+// radius_km of the given point, each paired with its closest hospital.
+func (h *DoctorHandler) ListNearbyDoctors(w http.ResponseWriter, r *http.Request) {
+	query := r.URL.Query()
+	lat, errLat := strconv.ParseFloat(query.Get("lat"), 64)
+	lng, errLng := strconv.ParseFloat(query.Get("lng"), 64)
+	if errLat != nil || errLng != nil || lat < -90 || lat > 90 || lng < -180 || lng > 180 {
+		util.SendData(w, map[string]string{"error": "Valid lat and lng query parameters are required"}, http.StatusBadRequest)
+		return
+	}
+
+	radius := 5.0
+	if rk := query.Get("radius_km"); rk != "" {
+		v, err := strconv.ParseFloat(rk, 64)
+		if err != nil || v <= 0 || v > 100 {
+			util.SendData(w, map[string]string{"error": "radius_km must be between 0 and 100"}, http.StatusBadRequest)
+			return
+		}
+		radius = v
+	}
+
+	specialty := query.Get("specialty")
+	page, limit, offset := parsePagination(query, 10)
+
+	list, total, err := h.repo.ListNearby(lat, lng, radius, specialty, offset, limit)
+	if err != nil {
+		log.Printf("Failed to list nearby doctors: %v", err)
+		util.SendData(w, map[string]string{"error": "Failed to fetch nearby doctors"}, http.StatusInternalServerError)
+		return
+	}
+	for i := range list {
+		list[i].Images = imageVariants(h.store, list[i].ImageURL)
+	}
+
+	util.SendData(w, paginated(list, total, page, limit), http.StatusOK)
+}
+
 func (h *DoctorHandler) GetDoctor(w http.ResponseWriter, r *http.Request) {
 	vars := mux.Vars(r)
 	idx, ok := vars["id"]
@@ -90,6 +196,7 @@ func (h *DoctorHandler) GetDoctor(w http.ResponseWriter, r *http.Request) {
 		util.SendData(w, map[string]string{"error": "Server error"}, http.StatusInternalServerError)
 		return
 	}
+	doctor.Images = imageVariants(h.store, doctor.ImageURL)
 	util.SendData(w, doctor, http.StatusOK)
 }
 
@@ -108,11 +215,20 @@ func (h *DoctorHandler) UpdateDoctor(w http.ResponseWriter, r *http.Request) {
 	var doc repo.Doctor
 	json.NewDecoder(r.Body).Decode(&doc)
 	doc.DoctorID = id
+	if msg := directory.NormalizeCredentials(&doc); msg != "" {
+		util.SendData(w, map[string]string{"error": msg}, http.StatusBadRequest)
+		return
+	}
 	updated, err := h.repo.Update(doc)
 	if err != nil {
+		if errors.Is(err, repo.ErrDuplicateBMDC) {
+			util.SendData(w, map[string]string{"error": "BMDC registration number already exists"}, http.StatusConflict)
+			return
+		}
 		util.SendData(w, map[string]string{"error": "Failed to update"}, http.StatusInternalServerError)
 		return
 	}
+	directory.PublishEvent(h.webhooks, repo.EventDoctorUpdated, updated)
 	util.SendData(w, updated, http.StatusOK)
 }
 
@@ -128,10 +244,19 @@ func (h *DoctorHandler) DeleteDoctor(w http.ResponseWriter, r *http.Request) {
 		util.SendData(w, map[string]string{"error": "Invalid doctor ID format"}, http.StatusBadRequest)
 		return
 	}
+	// Remember the photo so the stored file can go with the record.
+	var imageURL string
+	if existing, err := h.repo.Get(id); err == nil {
+		imageURL = existing.ImageURL
+	}
 	err := h.repo.Delete(id)
 	if err != nil {
 		util.SendData(w, map[string]string{"error": "Failed to delete"}, http.StatusInternalServerError)
 		return
 	}
+	if imageURL != "" {
+		directory.RemoveStoredImage(h.store, imageURL, "doctors", id)
+	}
+	directory.PublishEvent(h.webhooks, repo.EventDoctorDeleted, map[string]int{"doctor_id": id})
 	util.SendData(w, map[string]string{"message": "Doctor deleted successfully"}, http.StatusOK)
 }
@@ -0,0 +1,95 @@
+package handlers
+
+import (
+	"compress/gzip"
+	"log"
+	"medidhaka/util"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+)
+
+// parseExportOptions reads the format and compression of an export request
+// and reports the first invalid one, if any.
+func parseExportOptions(query url.Values) (string, bool, string) {
+	format := strings.ToLower(query.Get("format"))
+	if format == "" {
+		format = util.ExportCSV
+	}
+	if !contains(util.ExportFormats, format) {
+		return "", false, "format must be csv, excel or ndjson"
+	}
+	compress := query.Get("compress")
+	if compress != "" && compress != "gzip" {
+		return "", false, "compress must be gzip"
+	}
+	return format, compress == "gzip", ""
+}
+
+// exportWriter sends an export download. Headers are only committed with the
+// first byte of the body, so a failure before any row is written can still
+// be answered with a JSON error.
+type exportWriter struct {
+	w     http.ResponseWriter
+	gz    *gzip.Writer
+	wrote bool
+}
+
+// startExport prepares a download of the file name in the format,
+// gzip-compressed when compress is set.
+func startExport(w http.ResponseWriter, name, format string, compress bool) *exportWriter {
+	// Large exports outlive the server's write timeout, if one is set.
+	_ = http.NewResponseController(w).SetWriteDeadline(time.Time{})
+
+	filename := name + util.ExportExtension(format)
+	w.Header().Set("Content-Type", util.ExportContentType(format))
+	ew := &exportWriter{w: w}
+	if compress {
+		filename += ".gz"
+		w.Header().Set("Content-Type", "application/gzip")
+		ew.gz = gzip.NewWriter(writerFunc(ew.writeBody))
+	}
+	w.Header().Set("Content-Disposition", `attachment; filename="`+filename+`"`)
+	w.Header().Set("Cache-Control", "no-store")
+	return ew
+}
+
+type writerFunc func([]byte) (int, error)
+
+func (f writerFunc) Write(p []byte) (int, error) {
+	return f(p)
+}
+
+func (ew *exportWriter) writeBody(p []byte) (int, error) {
+	ew.wrote = true
+	return ew.w.Write(p)
+}
+
+func (ew *exportWriter) Write(p []byte) (int, error) {
+	if ew.gz != nil {
+		return ew.gz.Write(p)
+	}
+	return ew.writeBody(p)
+}
+
+// finish completes the download, or reports err. Once part of the body has
+// been sent the connection is aborted instead, so that the client sees a
+// truncated transfer rather than a short file.
+func (ew *exportWriter) finish(err error, what string) {
+	if err == nil && ew.gz != nil {
+		err = ew.gz.Close()
+	}
+	if err == nil {
+		if !ew.wrote {
+			ew.w.WriteHeader(http.StatusOK)
+		}
+		return
+	}
+	log.Printf("Failed to export %s: %v", what, err)
+	if ew.wrote {
+		panic(http.ErrAbortHandler)
+	}
+	ew.w.Header().Del("Content-Disposition")
+	util.SendData(ew.w, map[string]string{"error": "Failed to export " + what}, http.StatusInternalServerError)
+}
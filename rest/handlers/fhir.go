@@ -0,0 +1,371 @@
+package handlers
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log"
+	"medidhaka/repo"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/gorilla/mux"
+)
+
+// FHIRHandler serves hospitals, doctors and their affiliations as a read-only
+// FHIR R4 API: Organization and Location, Practitioner and PractitionerRole.
+type FHIRHandler struct {
+	hospitalRepo       repo.HospitalRepo
+	doctorRepo         repo.DoctorRepo
+	hospitalDoctorRepo repo.HospitalDoctorRepo
+}
+
+func NewFHIRHandler(hospitalRepo repo.HospitalRepo, doctorRepo repo.DoctorRepo, hospitalDoctorRepo repo.HospitalDoctorRepo) *FHIRHandler {
+	return &FHIRHandler{hospitalRepo: hospitalRepo, doctorRepo: doctorRepo, hospitalDoctorRepo: hospitalDoctorRepo}
+}
+
+const (
+	fhirDefaultCount = 20
+	fhirMaxCount     = 100
+)
+
+func sendFHIR(w http.ResponseWriter, data interface{}, statusCode int) {
+	w.Header().Set("Content-Type", "application/fhir+json; charset=utf-8")
+	w.WriteHeader(statusCode)
+	json.NewEncoder(w).Encode(data)
+}
+
+// fhirError answers with an OperationOutcome; code is a FHIR issue type such
+// as not-found or invalid.
+func fhirError(w http.ResponseWriter, statusCode int, code, diagnostics string) {
+	sendFHIR(w, fhirOperationOutcome{
+		ResourceType: "OperationOutcome",
+		Issue:        []fhirIssue{{Severity: "error", Code: code, Diagnostics: diagnostics}},
+	}, statusCode)
+}
+
+func fhirNotFound(w http.ResponseWriter, resourceType, id string) {
+	fhirError(w, http.StatusNotFound, "not-found", fmt.Sprintf("%s/%s not found", resourceType, id))
+}
+
+func fhirServerError(w http.ResponseWriter, what string, err error) {
+	log.Printf("Failed to %s: %v", what, err)
+	fhirError(w, http.StatusInternalServerError, "exception", "Failed to "+what)
+}
+
+// fhirBaseURL is the absolute URL of the FHIR API as seen by the client.
+func fhirBaseURL(r *http.Request) string {
+	scheme := "http"
+	if r.TLS != nil {
+		scheme = "https"
+	}
+	if proto := r.Header.Get("X-Forwarded-Proto"); proto != "" {
+		scheme = proto
+	}
+	return scheme + "://" + r.Host + "/fhir/R4"
+}
+
+// fhirPaging reads _count and _offset and reports an invalid one, if any.
+func fhirPaging(query url.Values) (int, int, string) {
+	count, offset := fhirDefaultCount, 0
+	if v := query.Get("_count"); v != "" {
+		n, err := strconv.Atoi(v)
+		if err != nil || n < 0 {
+			return 0, 0, "_count must be a non-negative integer"
+		}
+		count = min(n, fhirMaxCount)
+	}
+	if v := query.Get("_offset"); v != "" {
+		n, err := strconv.Atoi(v)
+		if err != nil || n < 0 {
+			return 0, 0, "_offset must be a non-negative integer"
+		}
+		offset = n
+	}
+	return count, offset, ""
+}
+
+// fhirReferenceID reads a reference search parameter such as
+// "Organization/12" or "12". It returns -1 for references that can't match.
+func fhirReferenceID(value, resourceType string) int {
+	id, err := strconv.Atoi(strings.TrimPrefix(value, resourceType+"/"))
+	if err != nil || id <= 0 {
+		return -1
+	}
+	return id
+}
+
+// searchBundle wraps a page of resources in a searchset Bundle with self,
+// previous and next links.
+func searchBundle(r *http.Request, resourceType string, ids []string, resources []interface{}, total, count, offset int) fhirBundle {
+	base := fhirBaseURL(r)
+	pageURL := func(offset int) string {
+		query := r.URL.Query()
+		query.Set("_count", strconv.Itoa(count))
+		query.Set("_offset", strconv.Itoa(offset))
+		return base + "/" + resourceType + "?" + query.Encode()
+	}
+
+	bundle := fhirBundle{
+		ResourceType: "Bundle",
+		Type:         "searchset",
+		Total:        total,
+		Link:         []fhirBundleLink{{Relation: "self", URL: pageURL(offset)}},
+	}
+	if offset > 0 {
+		bundle.Link = append(bundle.Link, fhirBundleLink{Relation: "previous", URL: pageURL(max(offset-count, 0))})
+	}
+	if count > 0 && offset+count < total {
+		bundle.Link = append(bundle.Link, fhirBundleLink{Relation: "next", URL: pageURL(offset + count)})
+	}
+	for i, res := range resources {
+		bundle.Entry = append(bundle.Entry, fhirBundleEntry{
+			FullURL:  base + "/" + resourceType + "/" + ids[i],
+			Resource: res,
+			Search:   map[string]string{"mode": "match"},
+		})
+	}
+	return bundle
+}
+
+// readHospital loads the hospital behind an Organization or Location id.
+func (h *FHIRHandler) readHospital(w http.ResponseWriter, r *http.Request, resourceType string) (*repo.Hospital, bool) {
+	id := mux.Vars(r)["id"]
+	hospitalID, err := strconv.Atoi(id)
+	if err != nil {
+		fhirNotFound(w, resourceType, id)
+		return nil, false
+	}
+	hsp, err := h.hospitalRepo.Get(hospitalID)
+	if err != nil {
+		if errors.Is(err, repo.ErrNotFound) {
+			fhirNotFound(w, resourceType, id)
+			return nil, false
+		}
+		fhirServerError(w, "fetch hospital", err)
+		return nil, false
+	}
+	return hsp, true
+}
+
+// searchHospitals runs an Organization or Location search by name, or by
+// organization for Locations, and converts each hospital with convert.
+func (h *FHIRHandler) searchHospitals(w http.ResponseWriter, r *http.Request, resourceType string, convert func(*repo.Hospital) interface{}) {
+	query := r.URL.Query()
+	count, offset, msg := fhirPaging(query)
+	if msg != "" {
+		fhirError(w, http.StatusBadRequest, "invalid", msg)
+		return
+	}
+
+	var hospitals []*repo.Hospital
+	var total int
+	if org := query.Get("organization"); org != "" && resourceType == "Location" {
+		// Every hospital has exactly one Location, with the same id.
+		hsp, err := h.hospitalRepo.Get(fhirReferenceID(org, "Organization"))
+		switch {
+		case err == nil:
+			total = 1
+			if offset == 0 && count > 0 {
+				hospitals = []*repo.Hospital{hsp}
+			}
+		case !errors.Is(err, repo.ErrNotFound):
+			fhirServerError(w, "search hospitals", err)
+			return
+		}
+	} else {
+		var err error
+		hospitals, total, err = h.hospitalRepo.List(repo.HospitalFilter{Search: query.Get("name")}, offset, count)
+		if err != nil {
+			fhirServerError(w, "search hospitals", err)
+			return
+		}
+	}
+
+	ids := make([]string, len(hospitals))
+	resources := make([]interface{}, len(hospitals))
+	for i, hsp := range hospitals {
+		ids[i] = strconv.Itoa(hsp.HospitalID)
+		resources[i] = convert(hsp)
+	}
+	sendFHIR(w, searchBundle(r, resourceType, ids, resources, total, count, offset), http.StatusOK)
+}
+
+func (h *FHIRHandler) ReadOrganization(w http.ResponseWriter, r *http.Request) {
+	if hsp, ok := h.readHospital(w, r, "Organization"); ok {
+		sendFHIR(w, fhirOrganizationOf(hsp), http.StatusOK)
+	}
+}
+
+// Organizations by name
+func (h *FHIRHandler) SearchOrganizations(w http.ResponseWriter, r *http.Request) {
+	h.searchHospitals(w, r, "Organization", func(hsp *repo.Hospital) interface{} { return fhirOrganizationOf(hsp) })
+}
+
+func (h *FHIRHandler) ReadLocation(w http.ResponseWriter, r *http.Request) {
+	if hsp, ok := h.readHospital(w, r, "Location"); ok {
+		sendFHIR(w, fhirLocationOf(hsp), http.StatusOK)
+	}
+}
+
+// Locations by name or managing organization
+func (h *FHIRHandler) SearchLocations(w http.ResponseWriter, r *http.Request) {
+	h.searchHospitals(w, r, "Location", func(hsp *repo.Hospital) interface{} { return fhirLocationOf(hsp) })
+}
+
+func (h *FHIRHandler) ReadPractitioner(w http.ResponseWriter, r *http.Request) {
+	id := mux.Vars(r)["id"]
+	doctorID, err := strconv.Atoi(id)
+	if err != nil {
+		fhirNotFound(w, "Practitioner", id)
+		return
+	}
+	doctor, err := h.doctorRepo.Get(doctorID)
+	if err != nil {
+		if errors.Is(err, repo.ErrDoctorNotFound) {
+			fhirNotFound(w, "Practitioner", id)
+			return
+		}
+		fhirServerError(w, "fetch doctor", err)
+		return
+	}
+	sendFHIR(w, fhirPractitionerOf(doctor), http.StatusOK)
+}
+
+// Verified practitioners by name or specialty
+func (h *FHIRHandler) SearchPractitioners(w http.ResponseWriter, r *http.Request) {
+	query := r.URL.Query()
+	count, offset, msg := fhirPaging(query)
+	if msg != "" {
+		fhirError(w, http.StatusBadRequest, "invalid", msg)
+		return
+	}
+	doctors, total, err := h.doctorRepo.List(repo.DoctorFilter{
+		Name:      query.Get("name"),
+		Specialty: query.Get("specialty"),
+		Status:    repo.VerificationVerified,
+	}, offset, count)
+	if err != nil {
+		fhirServerError(w, "search doctors", err)
+		return
+	}
+
+	ids := make([]string, len(doctors))
+	resources := make([]interface{}, len(doctors))
+	for i := range doctors {
+		ids[i] = strconv.Itoa(doctors[i].DoctorID)
+		resources[i] = fhirPractitionerOf(&doctors[i])
+	}
+	sendFHIR(w, searchBundle(r, "Practitioner", ids, resources, total, count, offset), http.StatusOK)
+}
+
+// ReadPractitionerRole reads an affiliation by its "<hospital>-<doctor>" id.
+func (h *FHIRHandler) ReadPractitionerRole(w http.ResponseWriter, r *http.Request) {
+	id := mux.Vars(r)["id"]
+	hospital, doctor, _ := strings.Cut(id, "-")
+	hospitalID, errH := strconv.Atoi(hospital)
+	doctorID, errD := strconv.Atoi(doctor)
+	if errH != nil || errD != nil {
+		fhirNotFound(w, "PractitionerRole", id)
+		return
+	}
+	a, err := h.hospitalDoctorRepo.GetAffiliation(hospitalID, doctorID)
+	if err != nil {
+		if errors.Is(err, repo.ErrNoAffiliation) {
+			fhirNotFound(w, "PractitionerRole", id)
+			return
+		}
+		fhirServerError(w, "fetch affiliation", err)
+		return
+	}
+	sendFHIR(w, fhirPractitionerRoleOf(a), http.StatusOK)
+}
+
+// Roles of verified practitioners by organization, practitioner or specialty
+func (h *FHIRHandler) SearchPractitionerRoles(w http.ResponseWriter, r *http.Request) {
+	query := r.URL.Query()
+	count, offset, msg := fhirPaging(query)
+	if msg != "" {
+		fhirError(w, http.StatusBadRequest, "invalid", msg)
+		return
+	}
+	filter := repo.AffiliationFilter{
+		Specialty: query.Get("specialty"),
+		Status:    repo.VerificationVerified,
+	}
+	if v := query.Get("organization"); v != "" {
+		filter.HospitalID = fhirReferenceID(v, "Organization")
+	}
+	if v := query.Get("practitioner"); v != "" {
+		filter.DoctorID = fhirReferenceID(v, "Practitioner")
+	}
+
+	var list []repo.Affiliation
+	var total int
+	// An unknown reference matches nothing rather than everything.
+	if filter.HospitalID >= 0 && filter.DoctorID >= 0 {
+		var err error
+		list, total, err = h.hospitalDoctorRepo.ListAffiliations(filter, offset, count)
+		if err != nil {
+			fhirServerError(w, "search affiliations", err)
+			return
+		}
+	}
+
+	ids := make([]string, len(list))
+	resources := make([]interface{}, len(list))
+	for i := range list {
+		ids[i] = fhirRoleID(list[i].HospitalID, list[i].DoctorID)
+		resources[i] = fhirPractitionerRoleOf(&list[i])
+	}
+	sendFHIR(w, searchBundle(r, "PractitionerRole", ids, resources, total, count, offset), http.StatusOK)
+}
+
+// fhirSearchParams are the search parameters of each resource type, with
+// their FHIR types, as listed in the CapabilityStatement.
+var fhirSearchParams = []struct {
+	resourceType string
+	params       [][2]string
+}{
+	{"Organization", [][2]string{{"name", "string"}}},
+	{"Location", [][2]string{{"name", "string"}, {"organization", "reference"}}},
+	{"Practitioner", [][2]string{{"name", "string"}, {"specialty", "token"}}},
+	{"PractitionerRole", [][2]string{{"organization", "reference"}, {"practitioner", "reference"}, {"specialty", "token"}}},
+}
+
+// CapabilityStatement describing the FHIR API
+func (h *FHIRHandler) Metadata(w http.ResponseWriter, r *http.Request) {
+	resources := []map[string]interface{}{}
+	for _, res := range fhirSearchParams {
+		params := []map[string]string{
+			{"name": "_count", "type": "number"},
+			{"name": "_offset", "type": "number"},
+		}
+		for _, p := range res.params {
+			params = append(params, map[string]string{"name": p[0], "type": p[1]})
+		}
+		resources = append(resources, map[string]interface{}{
+			"type":        res.resourceType,
+			"interaction": []map[string]string{{"code": "read"}, {"code": "search-type"}},
+			"searchParam": params,
+		})
+	}
+
+	sendFHIR(w, map[string]interface{}{
+		"resourceType":   "CapabilityStatement",
+		"status":         "active",
+		"date":           time.Now().UTC().Format("2006-01-02"),
+		"kind":           "instance",
+		"software":       map[string]string{"name": "medidhaka"},
+		"implementation": map[string]string{"description": "MediDhaka hospital and doctor directory", "url": fhirBaseURL(r)},
+		"fhirVersion":    fhirVersion,
+		"format":         []string{"json"},
+		"rest": []map[string]interface{}{{
+			"mode":     "server",
+			"resource": resources,
+		}},
+	}, http.StatusOK)
+}
@@ -0,0 +1,304 @@
+package handlers
+
+import (
+	"fmt"
+	"medidhaka/repo"
+	"time"
+)
+
+// FHIR R4 data types, limited to the elements this API fills in.
+
+// fhirVersion is the FHIR release the /fhir/R4 API implements.
+const fhirVersion = "4.0.1"
+
+// Identifier systems for the Bangladeshi registries. They are URNs of this
+// service because neither registry publishes a FHIR namespace.
+const (
+	fhirDGHSLicenceSystem = "urn:medidhaka:dghs-licence"
+	fhirBMDCSystem        = "urn:medidhaka:bmdc-registration"
+	fhirSpecialtySystem   = "urn:medidhaka:specialty"
+)
+
+type fhirMeta struct {
+	LastUpdated string `json:"lastUpdated,omitempty"`
+}
+
+type fhirIdentifier struct {
+	System string `json:"system,omitempty"`
+	Value  string `json:"value"`
+}
+
+type fhirCoding struct {
+	System  string `json:"system,omitempty"`
+	Code    string `json:"code"`
+	Display string `json:"display,omitempty"`
+}
+
+type fhirCodeableConcept struct {
+	Coding []fhirCoding `json:"coding,omitempty"`
+	Text   string       `json:"text,omitempty"`
+}
+
+type fhirContactPoint struct {
+	System string `json:"system"`
+	Value  string `json:"value"`
+	Use    string `json:"use,omitempty"`
+}
+
+type fhirAddress struct {
+	Text    string `json:"text,omitempty"`
+	City    string `json:"city,omitempty"`
+	Country string `json:"country,omitempty"`
+}
+
+type fhirReference struct {
+	Reference string `json:"reference"`
+	Display   string `json:"display,omitempty"`
+}
+
+type fhirHumanName struct {
+	Text string `json:"text"`
+}
+
+type fhirAttachment struct {
+	URL string `json:"url"`
+}
+
+type fhirPosition struct {
+	Longitude float64 `json:"longitude"`
+	Latitude  float64 `json:"latitude"`
+}
+
+type fhirAvailableTime struct {
+	DaysOfWeek []string `json:"daysOfWeek,omitempty"`
+	AllDay     bool     `json:"allDay,omitempty"`
+}
+
+type fhirQualification struct {
+	Code   fhirCodeableConcept `json:"code"`
+	Issuer *fhirReference      `json:"issuer,omitempty"`
+	Period *fhirPeriod         `json:"period,omitempty"`
+}
+
+type fhirPeriod struct {
+	Start string `json:"start,omitempty"`
+}
+
+type fhirOrganization struct {
+	ResourceType string                `json:"resourceType"`
+	ID           string                `json:"id"`
+	Meta         fhirMeta              `json:"meta"`
+	Identifier   []fhirIdentifier      `json:"identifier,omitempty"`
+	Active       bool                  `json:"active"`
+	Type         []fhirCodeableConcept `json:"type,omitempty"`
+	Name         string                `json:"name"`
+	Telecom      []fhirContactPoint    `json:"telecom,omitempty"`
+	Address      []fhirAddress         `json:"address,omitempty"`
+}
+
+type fhirLocation struct {
+	ResourceType         string              `json:"resourceType"`
+	ID                   string              `json:"id"`
+	Meta                 fhirMeta            `json:"meta"`
+	Status               string              `json:"status"`
+	Name                 string              `json:"name"`
+	Mode                 string              `json:"mode"`
+	Telecom              []fhirContactPoint  `json:"telecom,omitempty"`
+	Address              *fhirAddress        `json:"address,omitempty"`
+	Position             *fhirPosition       `json:"position,omitempty"`
+	ManagingOrganization fhirReference       `json:"managingOrganization"`
+	HoursOfOperation     []fhirAvailableTime `json:"hoursOfOperation,omitempty"`
+}
+
+type fhirPractitioner struct {
+	ResourceType  string                `json:"resourceType"`
+	ID            string                `json:"id"`
+	Meta          fhirMeta              `json:"meta"`
+	Identifier    []fhirIdentifier      `json:"identifier,omitempty"`
+	Active        bool                  `json:"active"`
+	Name          []fhirHumanName       `json:"name"`
+	Telecom       []fhirContactPoint    `json:"telecom,omitempty"`
+	Gender        string                `json:"gender,omitempty"`
+	Photo         []fhirAttachment      `json:"photo,omitempty"`
+	Qualification []fhirQualification   `json:"qualification,omitempty"`
+	Communication []fhirCodeableConcept `json:"communication,omitempty"`
+}
+
+type fhirPractitionerRole struct {
+	ResourceType string                `json:"resourceType"`
+	ID           string                `json:"id"`
+	Meta         fhirMeta              `json:"meta"`
+	Active       bool                  `json:"active"`
+	Practitioner fhirReference         `json:"practitioner"`
+	Organization fhirReference         `json:"organization"`
+	Code         []fhirCodeableConcept `json:"code,omitempty"`
+	Specialty    []fhirCodeableConcept `json:"specialty,omitempty"`
+	Location     []fhirReference       `json:"location"`
+}
+
+type fhirBundleLink struct {
+	Relation string `json:"relation"`
+	URL      string `json:"url"`
+}
+
+type fhirBundleEntry struct {
+	FullURL  string            `json:"fullUrl"`
+	Resource interface{}       `json:"resource"`
+	Search   map[string]string `json:"search"`
+}
+
+type fhirBundle struct {
+	ResourceType string            `json:"resourceType"`
+	Type         string            `json:"type"`
+	Total        int               `json:"total"`
+	Link         []fhirBundleLink  `json:"link"`
+	Entry        []fhirBundleEntry `json:"entry,omitempty"`
+}
+
+type fhirIssue struct {
+	Severity    string `json:"severity"`
+	Code        string `json:"code"`
+	Diagnostics string `json:"diagnostics"`
+}
+
+type fhirOperationOutcome struct {
+	ResourceType string      `json:"resourceType"`
+	Issue        []fhirIssue `json:"issue"`
+}
+
+func fhirInstant(t time.Time) string {
+	return t.UTC().Format(time.RFC3339)
+}
+
+// fhirTelecom lists the non-empty phone, email and website contact points.
+func fhirTelecom(phone, email, website string) []fhirContactPoint {
+	var list []fhirContactPoint
+	if phone != "" {
+		list = append(list, fhirContactPoint{System: "phone", Value: phone, Use: "work"})
+	}
+	if email != "" {
+		list = append(list, fhirContactPoint{System: "email", Value: email, Use: "work"})
+	}
+	if website != "" {
+		list = append(list, fhirContactPoint{System: "url", Value: website, Use: "work"})
+	}
+	return list
+}
+
+func fhirHospitalAddress(h *repo.Hospital) *fhirAddress {
+	if h.Address == "" {
+		return nil
+	}
+	return &fhirAddress{Text: h.Address, Country: "BD"}
+}
+
+func fhirOrganizationOf(h *repo.Hospital) fhirOrganization {
+	org := fhirOrganization{
+		ResourceType: "Organization",
+		ID:           fmt.Sprint(h.HospitalID),
+		Meta:         fhirMeta{LastUpdated: fhirInstant(h.UpdatedAt)},
+		Active:       !h.LicenceExpired,
+		Type: []fhirCodeableConcept{{
+			Coding: []fhirCoding{{
+				System:  "http://terminology.hl7.org/CodeSystem/organization-type",
+				Code:    "prov",
+				Display: "Healthcare Provider",
+			}},
+			Text: h.HospitalType,
+		}},
+		Name:    h.Name,
+		Telecom: fhirTelecom(h.PhoneNumber, h.Email, h.Website),
+	}
+	if h.LicenceNo != "" {
+		org.Identifier = []fhirIdentifier{{System: fhirDGHSLicenceSystem, Value: h.LicenceNo}}
+	}
+	if addr := fhirHospitalAddress(h); addr != nil {
+		org.Address = []fhirAddress{*addr}
+	}
+	return org
+}
+
+func fhirLocationOf(h *repo.Hospital) fhirLocation {
+	loc := fhirLocation{
+		ResourceType:         "Location",
+		ID:                   fmt.Sprint(h.HospitalID),
+		Meta:                 fhirMeta{LastUpdated: fhirInstant(h.UpdatedAt)},
+		Status:               "active",
+		Name:                 h.Name,
+		Mode:                 "instance",
+		Telecom:              fhirTelecom(h.PhoneNumber, "", ""),
+		Address:              fhirHospitalAddress(h),
+		ManagingOrganization: fhirReference{Reference: "Organization/" + fmt.Sprint(h.HospitalID), Display: h.Name},
+	}
+	if h.Latitude != nil && h.Longitude != nil {
+		loc.Position = &fhirPosition{Latitude: *h.Latitude, Longitude: *h.Longitude}
+	}
+	if h.Open247 {
+		loc.HoursOfOperation = []fhirAvailableTime{{
+			DaysOfWeek: []string{"mon", "tue", "wed", "thu", "fri", "sat", "sun"},
+			AllDay:     true,
+		}}
+	}
+	return loc
+}
+
+func fhirPractitionerOf(d *repo.Doctor) fhirPractitioner {
+	p := fhirPractitioner{
+		ResourceType: "Practitioner",
+		ID:           fmt.Sprint(d.DoctorID),
+		Meta:         fhirMeta{LastUpdated: fhirInstant(d.UpdatedAt)},
+		Active:       d.VerificationStatus == repo.VerificationVerified,
+		Name:         []fhirHumanName{{Text: d.Name}},
+		Telecom:      fhirTelecom(d.PhoneNumber, d.Email, ""),
+		Gender:       d.Gender,
+	}
+	if d.BMDCRegNo != nil {
+		p.Identifier = []fhirIdentifier{{System: fhirBMDCSystem, Value: *d.BMDCRegNo}}
+	}
+	if d.ImageURL != "" {
+		p.Photo = []fhirAttachment{{URL: d.ImageURL}}
+	}
+	for _, q := range d.Qualifications {
+		fq := fhirQualification{Code: fhirCodeableConcept{Text: q.Degree}}
+		if q.Institution != "" {
+			fq.Issuer = &fhirReference{Display: q.Institution}
+		}
+		if q.Year != nil {
+			fq.Period = &fhirPeriod{Start: fmt.Sprint(*q.Year)}
+		}
+		p.Qualification = append(p.Qualification, fq)
+	}
+	for _, lang := range d.Languages {
+		p.Communication = append(p.Communication, fhirCodeableConcept{Text: lang})
+	}
+	return p
+}
+
+// fhirRoleID is the PractitionerRole id of an affiliation.
+func fhirRoleID(hospitalID, doctorID int) string {
+	return fmt.Sprintf("%d-%d", hospitalID, doctorID)
+}
+
+func fhirPractitionerRoleOf(a *repo.Affiliation) fhirPractitionerRole {
+	hospital := fmt.Sprint(a.HospitalID)
+	role := fhirPractitionerRole{
+		ResourceType: "PractitionerRole",
+		ID:           fhirRoleID(a.HospitalID, a.DoctorID),
+		Meta:         fhirMeta{LastUpdated: fhirInstant(a.UpdatedAt)},
+		Active:       a.DoctorStatus == repo.VerificationVerified,
+		Practitioner: fhirReference{Reference: "Practitioner/" + fmt.Sprint(a.DoctorID), Display: a.DoctorName},
+		Organization: fhirReference{Reference: "Organization/" + hospital, Display: a.HospitalName},
+		Location:     []fhirReference{{Reference: "Location/" + hospital, Display: a.HospitalName}},
+	}
+	if a.Role != "" {
+		role.Code = []fhirCodeableConcept{{Text: a.Role}}
+	}
+	specialty := fhirCodeableConcept{Text: a.Specialty}
+	for i, code := range a.SpecialtyCodes {
+		specialty.Coding = append(specialty.Coding, fhirCoding{System: fhirSpecialtySystem, Code: code, Display: a.SpecialtyNames[i]})
+	}
+	if specialty.Text != "" || len(specialty.Coding) > 0 {
+		role.Specialty = []fhirCodeableConcept{specialty}
+	}
+	return role
+}
@@ -0,0 +1,199 @@
+package handlers
+
+import (
+	"context"
+	"encoding/json"
+	"medidhaka/infra/storage"
+	"medidhaka/repo"
+	"medidhaka/util"
+	"net/http"
+
+	graphql "github.com/graph-gophers/graphql-go"
+)
+
+const graphqlSchema = `
+schema {
+	query: Query
+}
+
+type Query {
+	hospital(id: ID!): Hospital
+	hospitals(search: String, type: String, emergency: Boolean = false, openNow: Boolean = false, sort: String, page: Int = 1, limit: Int = 10): HospitalPage!
+	doctor(id: ID!): Doctor
+	doctors(search: String, specialty: String, sort: String, page: Int = 1, limit: Int = 10): DoctorPage!
+	affiliation(hospitalId: ID!, doctorId: ID!): Affiliation
+}
+
+type Hospital {
+	id: ID!
+	name: String!
+	address: String!
+	phoneNumber: String!
+	email: String!
+	website: String!
+	imageUrl: String!
+	images: ImageVariants
+	latitude: Float
+	longitude: Float
+	ratingAvg: Float!
+	ratingCount: Int!
+	open247: Boolean!
+	hasEmergency: Boolean!
+	emergencyPhone: String!
+	ambulanceNumbers: [String!]!
+	hospitalType: String!
+	dghsLicenceNo: String!
+	licenceExpiry: String
+	licenceExpired: Boolean!
+	bedCapacity: Int
+	accreditations: [String!]!
+	"Verified doctors working at the hospital."
+	affiliations(page: Int = 1, limit: Int = 20): AffiliationPage!
+}
+
+type Doctor {
+	id: ID!
+	name: String!
+	specialty: String!
+	yearsExperience: Int!
+	phoneNumber: String!
+	email: String!
+	imageUrl: String!
+	images: ImageVariants
+	ratingAvg: Float!
+	ratingCount: Int!
+	bmdcRegNo: String
+	gender: String!
+	languages: [String!]!
+	verificationStatus: String!
+	qualifications: [Qualification!]!
+	"Hospitals the doctor works at; empty unless the doctor is verified."
+	affiliations(page: Int = 1, limit: Int = 20): AffiliationPage!
+}
+
+"A doctor's role and fees at a hospital."
+type Affiliation {
+	hospital: Hospital!
+	doctor: Doctor!
+	role: String!
+	feeNewPatient: Float
+	feeFollowUp: Float
+	feeReportReview: Float
+	feeCurrency: String!
+}
+
+type Qualification {
+	id: ID!
+	degree: String!
+	institution: String!
+	year: Int
+}
+
+type ImageVariants {
+	thumbnail: String!
+	medium: String!
+}
+
+type HospitalPage {
+	data: [Hospital!]!
+	total: Int!
+	page: Int!
+	limit: Int!
+	totalPages: Int!
+}
+
+type DoctorPage {
+	data: [Doctor!]!
+	total: Int!
+	page: Int!
+	limit: Int!
+	totalPages: Int!
+}
+
+type AffiliationPage {
+	data: [Affiliation!]!
+	total: Int!
+	page: Int!
+	limit: Int!
+	totalPages: Int!
+}
+`
+
+const (
+	// graphqlMaxDepth bounds how deeply hospitals and doctors can be nested
+	// through their affiliations.
+	graphqlMaxDepth = 10
+	graphqlMaxLimit = 100
+	graphqlMaxBody  = 1 << 20
+	// graphqlMaxCost bounds the items a request may ask for, counting the
+	// limit of every list it resolves; see graphqlLoaders.charge.
+	graphqlMaxCost = 1000
+)
+
+// GraphQLHandler serves hospitals, doctors and their affiliations over
+// GraphQL, so that a page can fetch a hospital, its doctors and their details
+// in a single request.
+type GraphQLHandler struct {
+	hospitalRepo       repo.HospitalRepo
+	doctorRepo         repo.DoctorRepo
+	hospitalDoctorRepo repo.HospitalDoctorRepo
+	credentialRepo     repo.CredentialRepo
+	store              storage.Storage
+	schema             *graphql.Schema
+}
+
+func NewGraphQLHandler(hospitalRepo repo.HospitalRepo, doctorRepo repo.DoctorRepo, hospitalDoctorRepo repo.HospitalDoctorRepo, credentialRepo repo.CredentialRepo, store storage.Storage) *GraphQLHandler {
+	h := &GraphQLHandler{
+		hospitalRepo:       hospitalRepo,
+		doctorRepo:         doctorRepo,
+		hospitalDoctorRepo: hospitalDoctorRepo,
+		credentialRepo:     credentialRepo,
+		store:              store,
+	}
+	h.schema = graphql.MustParseSchema(graphqlSchema, &graphqlQuery{h: h}, graphql.MaxDepth(graphqlMaxDepth))
+	return h
+}
+
+type graphqlLoadersKey struct{}
+
+func loadersFrom(ctx context.Context) *graphqlLoaders {
+	return ctx.Value(graphqlLoadersKey{}).(*graphqlLoaders)
+}
+
+// Execute a query sent as JSON in a POST body or as GET parameters
+func (h *GraphQLHandler) Serve(w http.ResponseWriter, r *http.Request) {
+	var params struct {
+		Query         string                 `json:"query"`
+		OperationName string                 `json:"operationName"`
+		Variables     map[string]interface{} `json:"variables"`
+	}
+	if r.Method == http.MethodGet {
+		query := r.URL.Query()
+		params.Query = query.Get("query")
+		params.OperationName = query.Get("operationName")
+		if v := query.Get("variables"); v != "" {
+			if err := json.Unmarshal([]byte(v), &params.Variables); err != nil {
+				sendGraphQLError(w, "variables must be a JSON object")
+				return
+			}
+		}
+	} else if err := json.NewDecoder(http.MaxBytesReader(w, r.Body, graphqlMaxBody)).Decode(&params); err != nil {
+		sendGraphQLError(w, "Invalid request body")
+		return
+	}
+	if params.Query == "" {
+		sendGraphQLError(w, "query is required")
+		return
+	}
+
+	ctx := context.WithValue(r.Context(), graphqlLoadersKey{}, h.newLoaders())
+	response := h.schema.Exec(ctx, params.Query, params.OperationName, params.Variables)
+	util.SendData(w, response, http.StatusOK)
+}
+
+// sendGraphQLError answers a request that could not be executed at all.
+func sendGraphQLError(w http.ResponseWriter, message string) {
+	util.SendData(w, map[string]interface{}{
+		"errors": []map[string]string{{"message": message}},
+	}, http.StatusBadRequest)
+}
@@ -0,0 +1,212 @@
+package handlers
+
+import (
+	"fmt"
+	"medidhaka/repo"
+	"sync"
+	"sync/atomic"
+)
+
+// batchLoader loads values by key, many keys per query. Resolvers queue the
+// keys they will need as soon as they know them, typically for a whole list,
+// and the first Load fetches every queued key at once, so resolving a field
+// over N parents costs one query instead of N. Results are cached for the
+// lifetime of the loader, which is a single GraphQL request.
+type batchLoader[K comparable, V any] struct {
+	fetch func(keys []K) (map[K]V, error)
+
+	fetchMu sync.Mutex // serializes fetches
+	mu      sync.Mutex // guards the fields below
+	queued  []K
+	fetched map[K]bool
+	values  map[K]V
+}
+
+func newBatchLoader[K comparable, V any](fetch func(keys []K) (map[K]V, error)) *batchLoader[K, V] {
+	return &batchLoader[K, V]{fetch: fetch, fetched: map[K]bool{}, values: map[K]V{}}
+}
+
+// Queue adds keys to the next batch.
+func (l *batchLoader[K, V]) Queue(keys ...K) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	for _, k := range keys {
+		if !l.fetched[k] {
+			l.queued = append(l.queued, k)
+		}
+	}
+}
+
+// Prime caches a value that was loaded some other way.
+func (l *batchLoader[K, V]) Prime(key K, value V) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	l.fetched[key] = true
+	l.values[key] = value
+}
+
+// Load returns the value of key, fetching it together with the queued keys
+// unless it is cached. ok is false if the key doesn't exist.
+func (l *batchLoader[K, V]) Load(key K) (value V, ok bool, err error) {
+	l.fetchMu.Lock()
+	defer l.fetchMu.Unlock()
+
+	l.mu.Lock()
+	if l.fetched[key] {
+		value, ok = l.values[key]
+		l.mu.Unlock()
+		return value, ok, nil
+	}
+	seen := map[K]bool{}
+	keys := []K{}
+	for _, k := range append(l.queued, key) {
+		if !l.fetched[k] && !seen[k] {
+			seen[k] = true
+			keys = append(keys, k)
+		}
+	}
+	l.queued = nil
+	l.mu.Unlock()
+
+	values, err := l.fetch(keys)
+	if err != nil {
+		return value, false, err
+	}
+
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	for _, k := range keys {
+		l.fetched[k] = true
+	}
+	for k, v := range values {
+		l.values[k] = v
+	}
+	value, ok = l.values[key]
+	return value, ok, nil
+}
+
+// graphqlLoaders are the loaders of one GraphQL request. Loading a batch
+// queues the keys its results lead to, so nested lists are batched level by
+// level: the doctors of a page of hospitals are fetched in one query, and so
+// are their qualifications.
+type graphqlLoaders struct {
+	hospitals            *batchLoader[int, *repo.Hospital]
+	doctors              *batchLoader[int, *repo.Doctor]
+	hospitalAffiliations *batchLoader[int, []repo.Affiliation]
+	doctorAffiliations   *batchLoader[int, []repo.Affiliation]
+	qualifications       *batchLoader[int, []repo.Qualification]
+
+	cost atomic.Int64 // items asked for so far
+}
+
+var errGraphQLTooCostly = fmt.Errorf("query asks for more than %d items; lower the limits or nest fewer lists", graphqlMaxCost)
+
+// charge adds a list's limit to the request's cost before the list is
+// resolved. A list nested in another is charged once per parent, so the cost
+// of nested lists is the product of their limits, and a query that would fan
+// out past graphqlMaxCost items fails instead.
+func (l *graphqlLoaders) charge(limit int) error {
+	if l.cost.Add(int64(limit)) > graphqlMaxCost {
+		return errGraphQLTooCostly
+	}
+	return nil
+}
+
+func (h *GraphQLHandler) newLoaders() *graphqlLoaders {
+	l := &graphqlLoaders{}
+
+	l.hospitals = newBatchLoader(func(ids []int) (map[int]*repo.Hospital, error) {
+		list, err := h.hospitalRepo.GetMany(ids)
+		if err != nil {
+			return nil, err
+		}
+		found := map[int]*repo.Hospital{}
+		for _, hsp := range list {
+			found[hsp.HospitalID] = hsp
+		}
+		l.queueHospitals(list)
+		return found, nil
+	})
+
+	l.doctors = newBatchLoader(func(ids []int) (map[int]*repo.Doctor, error) {
+		list, err := h.doctorRepo.GetMany(ids)
+		if err != nil {
+			return nil, err
+		}
+		found := map[int]*repo.Doctor{}
+		doctors := make([]*repo.Doctor, len(list))
+		for i := range list {
+			doctors[i] = &list[i]
+			found[list[i].DoctorID] = &list[i]
+		}
+		l.queueDoctors(doctors)
+		return found, nil
+	})
+
+	// Like the REST and FHIR listings, affiliations only include verified
+	// doctors.
+	l.hospitalAffiliations = newBatchLoader(func(ids []int) (map[int][]repo.Affiliation, error) {
+		list, err := h.hospitalDoctorRepo.ListAllAffiliations(repo.AffiliationFilter{HospitalIDs: ids, Status: repo.VerificationVerified})
+		if err != nil {
+			return nil, err
+		}
+		found := map[int][]repo.Affiliation{}
+		for _, a := range list {
+			found[a.HospitalID] = append(found[a.HospitalID], a)
+		}
+		l.queueAffiliations(list)
+		return found, nil
+	})
+
+	l.doctorAffiliations = newBatchLoader(func(ids []int) (map[int][]repo.Affiliation, error) {
+		list, err := h.hospitalDoctorRepo.ListAllAffiliations(repo.AffiliationFilter{DoctorIDs: ids, Status: repo.VerificationVerified})
+		if err != nil {
+			return nil, err
+		}
+		found := map[int][]repo.Affiliation{}
+		for _, a := range list {
+			found[a.DoctorID] = append(found[a.DoctorID], a)
+		}
+		l.queueAffiliations(list)
+		return found, nil
+	})
+
+	l.qualifications = newBatchLoader(func(ids []int) (map[int][]repo.Qualification, error) {
+		list, err := h.credentialRepo.ListQualificationsOf(ids)
+		if err != nil {
+			return nil, err
+		}
+		found := map[int][]repo.Qualification{}
+		for _, q := range list {
+			found[q.DoctorID] = append(found[q.DoctorID], q)
+		}
+		return found, nil
+	})
+
+	return l
+}
+
+// queueHospitals caches hospitals and queues the keys of their fields.
+func (l *graphqlLoaders) queueHospitals(list []*repo.Hospital) {
+	for _, hsp := range list {
+		l.hospitals.Prime(hsp.HospitalID, hsp)
+		l.hospitalAffiliations.Queue(hsp.HospitalID)
+	}
+}
+
+// queueDoctors caches doctors and queues the keys of their fields.
+func (l *graphqlLoaders) queueDoctors(list []*repo.Doctor) {
+	for _, d := range list {
+		l.doctors.Prime(d.DoctorID, d)
+		l.doctorAffiliations.Queue(d.DoctorID)
+		l.qualifications.Queue(d.DoctorID)
+	}
+}
+
+// queueAffiliations queues the hospitals and doctors that affiliations link.
+func (l *graphqlLoaders) queueAffiliations(list []repo.Affiliation) {
+	for _, a := range list {
+		l.hospitals.Queue(a.HospitalID)
+		l.doctors.Queue(a.DoctorID)
+	}
+}
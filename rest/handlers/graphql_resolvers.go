@@ -0,0 +1,395 @@
+package handlers
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"medidhaka/directory"
+	"medidhaka/infra/storage"
+	"medidhaka/repo"
+	"strconv"
+
+	graphql "github.com/graph-gophers/graphql-go"
+)
+
+// errGraphQLServer hides repository errors from clients; the details are
+// logged instead.
+var errGraphQLServer = errors.New("server error")
+
+func graphqlServerError(what string, err error) error {
+	log.Printf("GraphQL: failed to %s: %v", what, err)
+	return errGraphQLServer
+}
+
+// graphqlID parses a numeric ID; ok is false for anything else.
+func graphqlID(id graphql.ID) (int, bool) {
+	n, err := strconv.Atoi(string(id))
+	return n, err == nil && n > 0
+}
+
+func toGraphQLID(id int) graphql.ID {
+	return graphql.ID(strconv.Itoa(id))
+}
+
+func optionalInt32(v *int) *int32 {
+	if v == nil {
+		return nil
+	}
+	n := int32(*v)
+	return &n
+}
+
+// graphqlPaging clamps the page and limit arguments and returns the offset.
+func graphqlPaging(page, limit int32) (int, int, int) {
+	p, l := max(int(page), 1), min(max(int(limit), 1), graphqlMaxLimit)
+	return p, l, (p - 1) * l
+}
+
+// pageInfo resolves the fields shared by the page types.
+type pageInfo struct {
+	total, page, limit int
+}
+
+func (p pageInfo) Total() int32      { return int32(p.total) }
+func (p pageInfo) Page() int32       { return int32(p.page) }
+func (p pageInfo) Limit() int32      { return int32(p.limit) }
+func (p pageInfo) TotalPages() int32 { return int32((p.total + p.limit - 1) / p.limit) }
+
+// ---------- Query ----------
+
+type graphqlQuery struct {
+	h *GraphQLHandler
+}
+
+func (q *graphqlQuery) Hospital(ctx context.Context, args struct{ ID graphql.ID }) (*hospitalResolver, error) {
+	id, ok := graphqlID(args.ID)
+	if !ok {
+		return nil, nil
+	}
+	l := loadersFrom(ctx)
+	hsp, ok, err := l.hospitals.Load(id)
+	if err != nil {
+		return nil, graphqlServerError("fetch hospital", err)
+	}
+	if !ok {
+		return nil, nil
+	}
+	return &hospitalResolver{hsp: hsp, l: l, store: q.h.store}, nil
+}
+
+func (q *graphqlQuery) Hospitals(ctx context.Context, args struct {
+	Search    *string
+	Type      *string
+	Emergency bool
+	OpenNow   bool
+	Sort      *string
+	Page      int32
+	Limit     int32
+}) (*hospitalPageResolver, error) {
+	filter := repo.HospitalFilter{Emergency: args.Emergency, OpenNow: args.OpenNow}
+	if args.Search != nil {
+		filter.Search = *args.Search
+	}
+	if args.Type != nil {
+		if filter.Type = *args.Type; !contains(repo.HospitalTypes, filter.Type) {
+			return nil, errors.New("type must be government, private or ngo")
+		}
+	}
+	if args.Sort != nil {
+		sort, ok := directory.ParseListSort(*args.Sort)
+		if !ok {
+			return nil, errors.New("sort must be newest or rating")
+		}
+		filter.Sort = sort
+	}
+
+	page, limit, offset := graphqlPaging(args.Page, args.Limit)
+	l := loadersFrom(ctx)
+	if err := l.charge(limit); err != nil {
+		return nil, err
+	}
+	list, total, err := q.h.hospitalRepo.List(filter, offset, limit)
+	if err != nil {
+		return nil, graphqlServerError("list hospitals", err)
+	}
+	l.queueHospitals(list)
+	result := &hospitalPageResolver{pageInfo: pageInfo{total, page, limit}, data: []*hospitalResolver{}}
+	for _, hsp := range list {
+		result.data = append(result.data, &hospitalResolver{hsp: hsp, l: l, store: q.h.store})
+	}
+	return result, nil
+}
+
+func (q *graphqlQuery) Doctor(ctx context.Context, args struct{ ID graphql.ID }) (*doctorResolver, error) {
+	id, ok := graphqlID(args.ID)
+	if !ok {
+		return nil, nil
+	}
+	l := loadersFrom(ctx)
+	d, ok, err := l.doctors.Load(id)
+	if err != nil {
+		return nil, graphqlServerError("fetch doctor", err)
+	}
+	if !ok {
+		return nil, nil
+	}
+	return &doctorResolver{d: d, l: l, store: q.h.store}, nil
+}
+
+// Verified doctors, like GET /doctors
+func (q *graphqlQuery) Doctors(ctx context.Context, args struct {
+	Search    *string
+	Specialty *string
+	Sort      *string
+	Page      int32
+	Limit     int32
+}) (*doctorPageResolver, error) {
+	filter := repo.DoctorFilter{Status: repo.VerificationVerified}
+	if args.Search != nil {
+		filter.Search = *args.Search
+	}
+	if args.Specialty != nil {
+		filter.Specialty = *args.Specialty
+	}
+	if args.Sort != nil {
+		sort, ok := directory.ParseListSort(*args.Sort)
+		if !ok {
+			return nil, errors.New("sort must be newest or rating")
+		}
+		filter.Sort = sort
+	}
+
+	page, limit, offset := graphqlPaging(args.Page, args.Limit)
+	l := loadersFrom(ctx)
+	if err := l.charge(limit); err != nil {
+		return nil, err
+	}
+	list, total, err := q.h.doctorRepo.List(filter, offset, limit)
+	if err != nil {
+		return nil, graphqlServerError("list doctors", err)
+	}
+	doctors := make([]*repo.Doctor, len(list))
+	for i := range list {
+		doctors[i] = &list[i]
+	}
+	l.queueDoctors(doctors)
+	result := &doctorPageResolver{pageInfo: pageInfo{total, page, limit}, data: []*doctorResolver{}}
+	for _, d := range doctors {
+		result.data = append(result.data, &doctorResolver{d: d, l: l, store: q.h.store})
+	}
+	return result, nil
+}
+
+func (q *graphqlQuery) Affiliation(ctx context.Context, args struct {
+	HospitalID graphql.ID
+	DoctorID   graphql.ID
+}) (*affiliationResolver, error) {
+	hospitalID, ok := graphqlID(args.HospitalID)
+	doctorID, ok2 := graphqlID(args.DoctorID)
+	if !ok || !ok2 {
+		return nil, nil
+	}
+	a, err := q.h.hospitalDoctorRepo.GetAffiliation(hospitalID, doctorID)
+	if err != nil {
+		if errors.Is(err, repo.ErrNoAffiliation) {
+			return nil, nil
+		}
+		return nil, graphqlServerError("fetch affiliation", err)
+	}
+	l := loadersFrom(ctx)
+	l.queueAffiliations([]repo.Affiliation{*a})
+	return &affiliationResolver{a: *a, l: l, store: q.h.store}, nil
+}
+
+// ---------- Hospital ----------
+
+type hospitalResolver struct {
+	hsp   *repo.Hospital
+	l     *graphqlLoaders
+	store storage.Storage
+}
+
+func (r *hospitalResolver) ID() graphql.ID         { return toGraphQLID(r.hsp.HospitalID) }
+func (r *hospitalResolver) Name() string           { return r.hsp.Name }
+func (r *hospitalResolver) Address() string        { return r.hsp.Address }
+func (r *hospitalResolver) PhoneNumber() string    { return r.hsp.PhoneNumber }
+func (r *hospitalResolver) Email() string          { return r.hsp.Email }
+func (r *hospitalResolver) Website() string        { return r.hsp.Website }
+func (r *hospitalResolver) ImageURL() string       { return r.hsp.ImageURL }
+func (r *hospitalResolver) Latitude() *float64     { return r.hsp.Latitude }
+func (r *hospitalResolver) Longitude() *float64    { return r.hsp.Longitude }
+func (r *hospitalResolver) RatingAvg() float64     { return r.hsp.RatingAvg }
+func (r *hospitalResolver) RatingCount() int32     { return int32(r.hsp.RatingCount) }
+func (r *hospitalResolver) Open247() bool          { return r.hsp.Open247 }
+func (r *hospitalResolver) HasEmergency() bool     { return r.hsp.HasEmergency }
+func (r *hospitalResolver) EmergencyPhone() string { return r.hsp.EmergencyPhone }
+func (r *hospitalResolver) AmbulanceNumbers() []string {
+	return append([]string{}, r.hsp.AmbulanceNumbers...)
+}
+func (r *hospitalResolver) HospitalType() string   { return r.hsp.HospitalType }
+func (r *hospitalResolver) DghsLicenceNo() string  { return r.hsp.LicenceNo }
+func (r *hospitalResolver) LicenceExpiry() *string { return r.hsp.LicenceExpiry }
+func (r *hospitalResolver) LicenceExpired() bool   { return r.hsp.LicenceExpired }
+func (r *hospitalResolver) BedCapacity() *int32    { return optionalInt32(r.hsp.BedCapacity) }
+func (r *hospitalResolver) Accreditations() []string {
+	return append([]string{}, r.hsp.Accreditations...)
+}
+
+func (r *hospitalResolver) Images() *imageVariantsResolver {
+	return newImageVariantsResolver(r.store, r.hsp.ImageURL)
+}
+
+func (r *hospitalResolver) Affiliations(args struct{ Page, Limit int32 }) (*affiliationPageResolver, error) {
+	if _, limit, _ := graphqlPaging(args.Page, args.Limit); r.l.charge(limit) != nil {
+		return nil, errGraphQLTooCostly
+	}
+	list, _, err := r.l.hospitalAffiliations.Load(r.hsp.HospitalID)
+	if err != nil {
+		return nil, graphqlServerError(fmt.Sprintf("list doctors of hospital %d", r.hsp.HospitalID), err)
+	}
+	return newAffiliationPage(list, args.Page, args.Limit, r.l, r.store), nil
+}
+
+// ---------- Doctor ----------
+
+type doctorResolver struct {
+	d     *repo.Doctor
+	l     *graphqlLoaders
+	store storage.Storage
+}
+
+func (r *doctorResolver) ID() graphql.ID             { return toGraphQLID(r.d.DoctorID) }
+func (r *doctorResolver) Name() string               { return r.d.Name }
+func (r *doctorResolver) Specialty() string          { return r.d.Specialty }
+func (r *doctorResolver) YearsExperience() int32     { return int32(r.d.YearsExperience) }
+func (r *doctorResolver) PhoneNumber() string        { return r.d.PhoneNumber }
+func (r *doctorResolver) Email() string              { return r.d.Email }
+func (r *doctorResolver) ImageURL() string           { return r.d.ImageURL }
+func (r *doctorResolver) RatingAvg() float64         { return r.d.RatingAvg }
+func (r *doctorResolver) RatingCount() int32         { return int32(r.d.RatingCount) }
+func (r *doctorResolver) BmdcRegNo() *string         { return r.d.BMDCRegNo }
+func (r *doctorResolver) Gender() string             { return r.d.Gender }
+func (r *doctorResolver) Languages() []string        { return append([]string{}, r.d.Languages...) }
+func (r *doctorResolver) VerificationStatus() string { return r.d.VerificationStatus }
+
+func (r *doctorResolver) Images() *imageVariantsResolver {
+	return newImageVariantsResolver(r.store, r.d.ImageURL)
+}
+
+func (r *doctorResolver) Qualifications() ([]*qualificationResolver, error) {
+	list, _, err := r.l.qualifications.Load(r.d.DoctorID)
+	if err != nil {
+		return nil, graphqlServerError(fmt.Sprintf("list qualifications of doctor %d", r.d.DoctorID), err)
+	}
+	result := []*qualificationResolver{}
+	for _, q := range list {
+		result = append(result, &qualificationResolver{q: q})
+	}
+	return result, nil
+}
+
+func (r *doctorResolver) Affiliations(args struct{ Page, Limit int32 }) (*affiliationPageResolver, error) {
+	if _, limit, _ := graphqlPaging(args.Page, args.Limit); r.l.charge(limit) != nil {
+		return nil, errGraphQLTooCostly
+	}
+	list, _, err := r.l.doctorAffiliations.Load(r.d.DoctorID)
+	if err != nil {
+		return nil, graphqlServerError(fmt.Sprintf("list hospitals of doctor %d", r.d.DoctorID), err)
+	}
+	return newAffiliationPage(list, args.Page, args.Limit, r.l, r.store), nil
+}
+
+// ---------- Affiliation ----------
+
+type affiliationResolver struct {
+	a     repo.Affiliation
+	l     *graphqlLoaders
+	store storage.Storage
+}
+
+func (r *affiliationResolver) Hospital() (*hospitalResolver, error) {
+	hsp, ok, err := r.l.hospitals.Load(r.a.HospitalID)
+	if err != nil {
+		return nil, graphqlServerError("fetch hospital", err)
+	}
+	if !ok {
+		return nil, fmt.Errorf("hospital %d not found", r.a.HospitalID)
+	}
+	return &hospitalResolver{hsp: hsp, l: r.l, store: r.store}, nil
+}
+
+func (r *affiliationResolver) Doctor() (*doctorResolver, error) {
+	d, ok, err := r.l.doctors.Load(r.a.DoctorID)
+	if err != nil {
+		return nil, graphqlServerError("fetch doctor", err)
+	}
+	if !ok {
+		return nil, fmt.Errorf("doctor %d not found", r.a.DoctorID)
+	}
+	return &doctorResolver{d: d, l: r.l, store: r.store}, nil
+}
+
+func (r *affiliationResolver) Role() string              { return r.a.Role }
+func (r *affiliationResolver) FeeNewPatient() *float64   { return r.a.FeeNewPatient }
+func (r *affiliationResolver) FeeFollowUp() *float64     { return r.a.FeeFollowUp }
+func (r *affiliationResolver) FeeReportReview() *float64 { return r.a.FeeReportReview }
+func (r *affiliationResolver) FeeCurrency() string       { return r.a.FeeCurrency }
+
+// ---------- Pages and small types ----------
+
+type hospitalPageResolver struct {
+	pageInfo
+	data []*hospitalResolver
+}
+
+func (p *hospitalPageResolver) Data() []*hospitalResolver { return p.data }
+
+type doctorPageResolver struct {
+	pageInfo
+	data []*doctorResolver
+}
+
+func (p *doctorPageResolver) Data() []*doctorResolver { return p.data }
+
+type affiliationPageResolver struct {
+	pageInfo
+	data []*affiliationResolver
+}
+
+func (p *affiliationPageResolver) Data() []*affiliationResolver { return p.data }
+
+// newAffiliationPage pages through all affiliations of a hospital or doctor,
+// which the loaders fetch in one go.
+func newAffiliationPage(list []repo.Affiliation, page, limit int32, l *graphqlLoaders, store storage.Storage) *affiliationPageResolver {
+	p, lim, offset := graphqlPaging(page, limit)
+	result := &affiliationPageResolver{pageInfo: pageInfo{len(list), p, lim}, data: []*affiliationResolver{}}
+	for _, a := range list[min(offset, len(list)):min(offset+lim, len(list))] {
+		result.data = append(result.data, &affiliationResolver{a: a, l: l, store: store})
+	}
+	return result
+}
+
+type qualificationResolver struct {
+	q repo.Qualification
+}
+
+func (r *qualificationResolver) ID() graphql.ID      { return toGraphQLID(r.q.QualificationID) }
+func (r *qualificationResolver) Degree() string      { return r.q.Degree }
+func (r *qualificationResolver) Institution() string { return r.q.Institution }
+func (r *qualificationResolver) Year() *int32        { return optionalInt32(r.q.Year) }
+
+type imageVariantsResolver struct {
+	v *repo.ImageVariants
+}
+
+// newImageVariantsResolver returns nil unless url is an uploaded photo.
+func newImageVariantsResolver(store storage.Storage, url string) *imageVariantsResolver {
+	if v := imageVariants(store, url); v != nil {
+		return &imageVariantsResolver{v: v}
+	}
+	return nil
+}
+
+func (r *imageVariantsResolver) Thumbnail() string { return r.v.Thumbnail }
+func (r *imageVariantsResolver) Medium() string    { return r.v.Medium }
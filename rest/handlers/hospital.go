@@ -5,6 +5,8 @@ import (
 	"errors"
 	"fmt"
 	"log"
+	"medidhaka/directory"
+	"medidhaka/infra/storage"
 	"medidhaka/repo"
 	"medidhaka/util"
 	"net/http"
@@ -15,12 +17,14 @@ import (
 
 // HospitalHandler holds the dependency on the HospitalRepo interface.
 type HospitalHandler struct {
-	repo repo.HospitalRepo
+	repo     repo.HospitalRepo
+	store    storage.Storage
+	webhooks repo.WebhookRepo
 }
 
 // NewHospitalHandler creates and returns a new HospitalHandler instance.
-func NewHospitalHandler(r repo.HospitalRepo) *HospitalHandler {
-	return &HospitalHandler{repo: r}
+func NewHospitalHandler(r repo.HospitalRepo, store storage.Storage, webhooks repo.WebhookRepo) *HospitalHandler {
+	return &HospitalHandler{repo: r, store: store, webhooks: webhooks}
 }
 
 // POST requests to create a new Hospital record.
@@ -36,14 +40,23 @@ func (h *HospitalHandler) CreateHospital(w http.ResponseWriter, r *http.Request)
 		util.SendData(w, map[string]string{"error": "Hospital name is required"}, http.StatusBadRequest)
 		return
 	}
+	if msg := directory.ValidateHospitalDetails(&hospital); msg != "" {
+		util.SendData(w, map[string]string{"error": msg}, http.StatusBadRequest)
+		return
+	}
 
 	createdHospital, err := h.repo.Create(hospital)
 	if err != nil {
+		if errors.Is(err, repo.ErrDuplicateLicence) {
+			util.SendData(w, map[string]string{"error": "DGHS licence number already exists"}, http.StatusConflict)
+			return
+		}
 		log.Printf("Failed to create hospital: %v", err)
 		util.SendData(w, map[string]string{"error": "Failed to create hospital record"}, http.StatusInternalServerError)
 		return
 	}
 
+	directory.PublishEvent(h.webhooks, repo.EventHospitalCreated, createdHospital)
 	util.SendData(w, createdHospital, http.StatusCreated)
 	log.Printf("Hospital created: %s (ID: %d)", createdHospital.Name, createdHospital.HospitalID)
 }
@@ -51,7 +64,11 @@ func (h *HospitalHandler) CreateHospital(w http.ResponseWriter, r *http.Request)
 // GET requests to retrieve a list of all Hospital records.
 func (h *HospitalHandler) ListHospitals(w http.ResponseWriter, r *http.Request) {
 	query := r.URL.Query()
-	search := query.Get("search")
+	filter, msg := directory.ParseHospitalFilter(query)
+	if msg != "" {
+		util.SendData(w, map[string]string{"error": msg}, http.StatusBadRequest)
+		return
+	}
 	page := 1
 	limit := 10
 
@@ -67,13 +84,16 @@ func (h *HospitalHandler) ListHospitals(w http.ResponseWriter, r *http.Request)
 	}
 
 	offset := (page - 1) * limit
-	hospitals, total, err := h.repo.List(search, offset, limit)
+	hospitals, total, err := h.repo.List(filter, offset, limit)
 
 	if err != nil {
 		log.Printf("Failed to list hospitals: %v", err)
 		util.SendData(w, map[string]string{"error": "Internal server error listing hospitals"}, http.StatusInternalServerError)
 		return
 	}
+	for _, hsp := range hospitals {
+		hsp.Images = imageVariants(h.store, hsp.ImageURL)
+	}
 
 	response := map[string]interface{}{
 		"data":       hospitals,
@@ -87,6 +107,34 @@ func (h *HospitalHandler) ListHospitals(w http.ResponseWriter, r *http.Request)
 	util.SendData(w, response, http.StatusOK)
 }
 
+// GET requests to stream every Hospital matching the list filters as a file.
+func (h *HospitalHandler) ExportHospitals(w http.ResponseWriter, r *http.Request) {
+	query := r.URL.Query()
+	filter, msg := directory.ParseHospitalFilter(query)
+	if msg != "" {
+		util.SendData(w, map[string]string{"error": msg}, http.StatusBadRequest)
+		return
+	}
+	format, compress, msg := parseExportOptions(query)
+	if msg != "" {
+		util.SendData(w, map[string]string{"error": msg}, http.StatusBadRequest)
+		return
+	}
+
+	ew := startExport(w, "hospitals", format, compress)
+	rw, err := util.NewRecordWriter(ew, format, repo.HospitalExportColumns)
+	if err == nil {
+		err = h.repo.Export(filter, func(hsp *repo.Hospital) error {
+			hsp.Images = imageVariants(h.store, hsp.ImageURL)
+			return rw.Write(hsp, hsp.ExportRecord())
+		})
+	}
+	if err == nil {
+		err = rw.Flush()
+	}
+	ew.finish(err, "hospitals")
+}
+
 // GET a single Hospital by ID.
 func (h *HospitalHandler) GetHospital(w http.ResponseWriter, r *http.Request) {
 	// Extract ID from URL using gorilla/mux
@@ -114,6 +162,7 @@ func (h *HospitalHandler) GetHospital(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	hospital.Images = imageVariants(h.store, hospital.ImageURL)
 	util.SendData(w, hospital, http.StatusOK)
 }
 
@@ -140,6 +189,10 @@ func (h *HospitalHandler) UpdateHospital(w http.ResponseWriter, r *http.Request)
 
 	// Ensure the ID from the URL is used for the update operation
 	hospital.HospitalID = id
+	if msg := directory.ValidateHospitalDetails(&hospital); msg != "" {
+		util.SendData(w, map[string]string{"error": msg}, http.StatusBadRequest)
+		return
+	}
 
 	updatedHospital, err := h.repo.Update(hospital)
 	if err != nil {
@@ -147,11 +200,16 @@ func (h *HospitalHandler) UpdateHospital(w http.ResponseWriter, r *http.Request)
 			util.SendData(w, map[string]string{"error": fmt.Sprintf("Hospital with ID %d not found for update", id)}, http.StatusNotFound)
 			return
 		}
+		if errors.Is(err, repo.ErrDuplicateLicence) {
+			util.SendData(w, map[string]string{"error": "DGHS licence number already exists"}, http.StatusConflict)
+			return
+		}
 		log.Printf("Failed to update hospital ID %d: %v", id, err)
 		util.SendData(w, map[string]string{"error": "Internal server error updating hospital"}, http.StatusInternalServerError)
 		return
 	}
 
+	directory.PublishEvent(h.webhooks, repo.EventHospitalUpdated, updatedHospital)
 	util.SendData(w, updatedHospital, http.StatusOK)
 	log.Printf("Hospital updated: %s (ID: %d)", updatedHospital.Name, updatedHospital.HospitalID)
 }
@@ -171,6 +229,12 @@ func (h *HospitalHandler) DeleteHospital(w http.ResponseWriter, r *http.Request)
 		return
 	}
 
+	// Remember the photo so the stored file can go with the record.
+	var imageURL string
+	if existing, err := h.repo.Get(id); err == nil {
+		imageURL = existing.ImageURL
+	}
+
 	err = h.repo.Delete(id)
 	if err != nil {
 		if errors.Is(err, repo.ErrNotFound) {
@@ -181,6 +245,10 @@ func (h *HospitalHandler) DeleteHospital(w http.ResponseWriter, r *http.Request)
 		util.SendData(w, map[string]string{"error": "Internal server error deleting hospital"}, http.StatusInternalServerError)
 		return
 	}
+	if imageURL != "" {
+		directory.RemoveStoredImage(h.store, imageURL, "hospitals", id)
+	}
+	directory.PublishEvent(h.webhooks, repo.EventHospitalDeleted, map[string]int{"hospital_id": id})
 
 	util.SendData(w, map[string]string{"message": fmt.Sprintf("Hospital ID %d deleted successfully", id)}, http.StatusOK)
 	log.Printf("🗑️ Hospital deleted: ID %d", id)
@@ -2,6 +2,9 @@ package handlers
 
 import (
 	"encoding/json"
+	"errors"
+	"log"
+	"medidhaka/directory"
 	"medidhaka/repo"
 	"medidhaka/util"
 	"net/http"
@@ -11,11 +14,12 @@ import (
 )
 
 type HospitalDoctorHandler struct {
-	repo repo.HospitalDoctorRepo
+	repo     repo.HospitalDoctorRepo
+	webhooks repo.WebhookRepo
 }
 
-func NewHospitalDoctorHandler(r repo.HospitalDoctorRepo) *HospitalDoctorHandler {
-	return &HospitalDoctorHandler{repo: r}
+func NewHospitalDoctorHandler(r repo.HospitalDoctorRepo, webhooks repo.WebhookRepo) *HospitalDoctorHandler {
+	return &HospitalDoctorHandler{repo: r, webhooks: webhooks}
 }
 
 // Assign a doctor with a hospital
@@ -25,16 +29,23 @@ func (h *HospitalDoctorHandler) AssignDoctor(w http.ResponseWriter, r *http.Requ
 		util.SendData(w, map[string]string{"error": "Invalid input"}, http.StatusBadRequest)
 		return
 	}
+	if msg := directory.ValidateFees(&rel); msg != "" {
+		util.SendData(w, map[string]string{"error": msg}, http.StatusBadRequest)
+		return
+	}
 
 	if err := h.repo.AssignDoctor(rel); err != nil {
 		util.SendData(w, map[string]string{"error": "Failed to assign doctor"}, http.StatusInternalServerError)
 		return
 	}
+	if assigned, err := h.repo.Get(rel.HospitalID, rel.DoctorID); err == nil {
+		directory.PublishEvent(h.webhooks, repo.EventHospitalDoctorCreated, assigned)
+	}
 
 	util.SendData(w, map[string]string{"message": "Doctor assigned successfully"}, http.StatusCreated)
 }
 
-// List doctors of a hospital
+// List verified doctors of a hospital
 func (h *HospitalDoctorHandler) ListDoctorsByHospital(w http.ResponseWriter, r *http.Request) {
 	vars := mux.Vars(r)
 	idx, ok := vars["id"]
@@ -71,9 +82,71 @@ func (h *HospitalDoctorHandler) DeleteDoctorRelation(w http.ResponseWriter, r *h
 		util.SendData(w, map[string]string{"error": "Invalid ID format"}, http.StatusBadRequest)
 		return
 	}
+	// Only announce relations that existed; deleting a missing one succeeds.
+	_, errExisting := h.repo.Get(hospitalID, doctorID)
 	if err := h.repo.DeleteDoctorRelation(hospitalID, doctorID); err != nil {
 		util.SendData(w, map[string]string{"error": "Failed to delete relation"}, http.StatusInternalServerError)
 		return
 	}
+	if errExisting == nil {
+		directory.PublishEvent(h.webhooks, repo.EventHospitalDoctorDeleted, map[string]int{"hospital_id": hospitalID, "doctor_id": doctorID})
+	}
 	util.SendData(w, map[string]string{"message": "Relation deleted successfully"}, http.StatusOK)
 }
+
+// Fees of a doctor at a hospital
+func (h *HospitalDoctorHandler) GetFees(w http.ResponseWriter, r *http.Request) {
+	hospitalID, doctorID, ok := relationIDs(r)
+	if !ok {
+		util.SendData(w, map[string]string{"error": "Invalid ID format"}, http.StatusBadRequest)
+		return
+	}
+
+	rel, err := h.repo.Get(hospitalID, doctorID)
+	if err != nil {
+		if errors.Is(err, repo.ErrNoAffiliation) {
+			util.SendData(w, map[string]string{"error": "Doctor is not assigned to this hospital"}, http.StatusNotFound)
+			return
+		}
+		log.Printf("Failed to fetch fees of doctor %d at hospital %d: %v", doctorID, hospitalID, err)
+		util.SendData(w, map[string]string{"error": "Failed to fetch fees"}, http.StatusInternalServerError)
+		return
+	}
+	util.SendData(w, rel, http.StatusOK)
+}
+
+// Replace the fees of a doctor at a hospital; omitted fees become unknown
+func (h *HospitalDoctorHandler) UpdateFees(w http.ResponseWriter, r *http.Request) {
+	hospitalID, doctorID, ok := relationIDs(r)
+	if !ok {
+		util.SendData(w, map[string]string{"error": "Invalid ID format"}, http.StatusBadRequest)
+		return
+	}
+	if !staffOwnsHospital(w, r, hospitalID) {
+		return
+	}
+
+	var rel repo.HospitalDoctor
+	if err := json.NewDecoder(r.Body).Decode(&rel); err != nil {
+		util.SendData(w, map[string]string{"error": "Invalid input format"}, http.StatusBadRequest)
+		return
+	}
+	rel.HospitalID, rel.DoctorID = hospitalID, doctorID
+	if msg := directory.ValidateFees(&rel); msg != "" {
+		util.SendData(w, map[string]string{"error": msg}, http.StatusBadRequest)
+		return
+	}
+
+	updated, err := h.repo.UpdateFees(rel)
+	if err != nil {
+		if errors.Is(err, repo.ErrNoAffiliation) {
+			util.SendData(w, map[string]string{"error": "Doctor is not assigned to this hospital"}, http.StatusNotFound)
+			return
+		}
+		log.Printf("Failed to update fees of doctor %d at hospital %d: %v", doctorID, hospitalID, err)
+		util.SendData(w, map[string]string{"error": "Failed to update fees"}, http.StatusInternalServerError)
+		return
+	}
+	directory.PublishEvent(h.webhooks, repo.EventHospitalDoctorUpdated, updated)
+	util.SendData(w, updated, http.StatusOK)
+}
@@ -0,0 +1,202 @@
+package handlers
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log"
+	"medidhaka/repo"
+	"medidhaka/util"
+	"net/http"
+	"sort"
+	"strconv"
+	"time"
+
+	"github.com/gorilla/mux"
+)
+
+type HoursHandler struct {
+	repo repo.HoursRepo
+}
+
+func NewHoursHandler(r repo.HoursRepo) *HoursHandler {
+	return &HoursHandler{repo: r}
+}
+
+// validOpeningRange is validClockRange that also accepts 24:00 as a closing
+// time, so hours running past midnight can be split at midnight.
+func validOpeningRange(open, close string) bool {
+	if close == "24:00" {
+		_, err := time.Parse("15:04", open)
+		return err == nil
+	}
+	return validClockRange(open, close)
+}
+
+// validateWeeklyHours checks every range and that ranges of the same day do
+// not overlap.
+func validateWeeklyHours(hours []repo.OpeningHours) string {
+	sorted := append([]repo.OpeningHours(nil), hours...)
+	sort.Slice(sorted, func(i, j int) bool {
+		if sorted[i].DayOfWeek != sorted[j].DayOfWeek {
+			return sorted[i].DayOfWeek < sorted[j].DayOfWeek
+		}
+		return sorted[i].OpenTime < sorted[j].OpenTime
+	})
+	for i, oh := range sorted {
+		if oh.DayOfWeek < 0 || oh.DayOfWeek > 6 {
+			return "day_of_week must be between 0 (Sunday) and 6 (Saturday)"
+		}
+		if !validOpeningRange(oh.OpenTime, oh.CloseTime) {
+			return "open_time and close_time must be HH:MM with open_time before close_time; split hours past midnight into a range closing at 24:00 and one opening at 00:00 the next day"
+		}
+		if i > 0 && sorted[i-1].DayOfWeek == oh.DayOfWeek && sorted[i-1].CloseTime > oh.OpenTime {
+			return fmt.Sprintf("opening hours overlap on day %d", oh.DayOfWeek)
+		}
+	}
+	return ""
+}
+
+// Weekly opening hours, upcoming overrides and whether the hospital is open now
+func (h *HoursHandler) GetHospitalHours(w http.ResponseWriter, r *http.Request) {
+	hospitalID, err := strconv.Atoi(mux.Vars(r)["id"])
+	if err != nil {
+		util.SendData(w, map[string]string{"error": "Invalid hospital ID format"}, http.StatusBadRequest)
+		return
+	}
+
+	openNow, err := h.repo.IsOpenNow(hospitalID)
+	if err != nil {
+		if errors.Is(err, repo.ErrNotFound) {
+			util.SendData(w, map[string]string{"error": "Hospital not found"}, http.StatusNotFound)
+			return
+		}
+		log.Printf("Failed to check opening hours of hospital ID %d: %v", hospitalID, err)
+		util.SendData(w, map[string]string{"error": "Failed to fetch opening hours"}, http.StatusInternalServerError)
+		return
+	}
+	weekly, err := h.repo.ListWeekly(hospitalID)
+	var overrides []repo.HoursOverride
+	if err == nil {
+		overrides, err = h.repo.ListOverrides(hospitalID, util.TodayInDhaka())
+	}
+	if err != nil {
+		log.Printf("Failed to fetch opening hours of hospital ID %d: %v", hospitalID, err)
+		util.SendData(w, map[string]string{"error": "Failed to fetch opening hours"}, http.StatusInternalServerError)
+		return
+	}
+
+	util.SendData(w, map[string]interface{}{
+		"hospital_id":  hospitalID,
+		"timezone":     "Asia/Dhaka",
+		"open_now":     openNow,
+		"weekly_hours": weekly,
+		"overrides":    overrides,
+	}, http.StatusOK)
+}
+
+// Replace the weekly opening hours of a hospital
+func (h *HoursHandler) ReplaceHospitalHours(w http.ResponseWriter, r *http.Request) {
+	hospitalID, err := strconv.Atoi(mux.Vars(r)["id"])
+	if err != nil {
+		util.SendData(w, map[string]string{"error": "Invalid hospital ID format"}, http.StatusBadRequest)
+		return
+	}
+	if !staffOwnsHospital(w, r, hospitalID) {
+		return
+	}
+	var body struct {
+		Hours []repo.OpeningHours `json:"weekly_hours"`
+	}
+	if err := json.NewDecoder(r.Body).Decode(&body); err != nil {
+		util.SendData(w, map[string]string{"error": "Invalid input format"}, http.StatusBadRequest)
+		return
+	}
+	if msg := validateWeeklyHours(body.Hours); msg != "" {
+		util.SendData(w, map[string]string{"error": msg}, http.StatusBadRequest)
+		return
+	}
+
+	saved, err := h.repo.ReplaceWeekly(hospitalID, body.Hours)
+	if err != nil {
+		if errors.Is(err, repo.ErrNotFound) {
+			util.SendData(w, map[string]string{"error": "Hospital not found"}, http.StatusNotFound)
+			return
+		}
+		log.Printf("Failed to save opening hours of hospital ID %d: %v", hospitalID, err)
+		util.SendData(w, map[string]string{"error": "Failed to save opening hours"}, http.StatusInternalServerError)
+		return
+	}
+	util.SendData(w, saved, http.StatusOK)
+}
+
+// Override the opening hours on a date, e.g. close for a holiday
+func (h *HoursHandler) CreateHoursOverride(w http.ResponseWriter, r *http.Request) {
+	hospitalID, err := strconv.Atoi(mux.Vars(r)["id"])
+	if err != nil {
+		util.SendData(w, map[string]string{"error": "Invalid hospital ID format"}, http.StatusBadRequest)
+		return
+	}
+	if !staffOwnsHospital(w, r, hospitalID) {
+		return
+	}
+	var o repo.HoursOverride
+	if err := json.NewDecoder(r.Body).Decode(&o); err != nil {
+		util.SendData(w, map[string]string{"error": "Invalid input format"}, http.StatusBadRequest)
+		return
+	}
+	o.HospitalID = hospitalID
+	if _, err := time.Parse("2006-01-02", o.Date); err != nil {
+		util.SendData(w, map[string]string{"error": "date must be YYYY-MM-DD"}, http.StatusBadRequest)
+		return
+	}
+	if o.IsOpen && (o.OpenTime != nil || o.CloseTime != nil) {
+		if o.OpenTime == nil || o.CloseTime == nil || !validOpeningRange(*o.OpenTime, *o.CloseTime) {
+			util.SendData(w, map[string]string{"error": "open_time and close_time must both be HH:MM with open_time before close_time (24:00 closes at midnight)"}, http.StatusBadRequest)
+			return
+		}
+	}
+	if !o.IsOpen {
+		o.OpenTime, o.CloseTime = nil, nil
+	}
+
+	created, err := h.repo.CreateOverride(o)
+	if err != nil {
+		switch {
+		case errors.Is(err, repo.ErrNotFound):
+			util.SendData(w, map[string]string{"error": "Hospital not found"}, http.StatusNotFound)
+		case errors.Is(err, repo.ErrDuplicateDate):
+			util.SendData(w, map[string]string{"error": "An override already exists for this date"}, http.StatusConflict)
+		default:
+			log.Printf("Failed to create opening hours override: %v", err)
+			util.SendData(w, map[string]string{"error": "Failed to create opening hours override"}, http.StatusInternalServerError)
+		}
+		return
+	}
+	util.SendData(w, created, http.StatusCreated)
+}
+
+func (h *HoursHandler) DeleteHoursOverride(w http.ResponseWriter, r *http.Request) {
+	id, err := strconv.Atoi(mux.Vars(r)["id"])
+	if err != nil {
+		util.SendData(w, map[string]string{"error": "Invalid override ID format"}, http.StatusBadRequest)
+		return
+	}
+	o, err := h.repo.GetOverride(id)
+	if err == nil && !staffOwnsHospital(w, r, o.HospitalID) {
+		return
+	}
+	if err == nil {
+		err = h.repo.DeleteOverride(id)
+	}
+	if err != nil {
+		if errors.Is(err, repo.ErrOverrideNotFound) {
+			util.SendData(w, map[string]string{"error": "Override not found"}, http.StatusNotFound)
+			return
+		}
+		log.Printf("Failed to delete opening hours override ID %d: %v", id, err)
+		util.SendData(w, map[string]string{"error": "Failed to delete opening hours override"}, http.StatusInternalServerError)
+		return
+	}
+	util.SendData(w, map[string]string{"message": "Override deleted"}, http.StatusOK)
+}
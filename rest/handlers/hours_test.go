@@ -0,0 +1,88 @@
+package handlers
+
+import (
+	"medidhaka/repo"
+	"testing"
+)
+
+func TestValidOpeningRange(t *testing.T) {
+	tests := []struct {
+		open, close string
+		want        bool
+	}{
+		{"09:00", "17:00", true},
+		{"00:00", "08:00", true},
+		{"20:00", "24:00", true},
+		{"00:00", "24:00", true},
+		{"17:00", "09:00", false},
+		{"09:00", "09:00", false},
+		{"24:00", "24:00", false},
+		{"9am", "17:00", false},
+		{"09:00", "25:00", false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.open+"-"+tt.close, func(t *testing.T) {
+			if got := validOpeningRange(tt.open, tt.close); got != tt.want {
+				t.Errorf("validOpeningRange(%s, %s) = %t, want %t", tt.open, tt.close, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestValidateWeeklyHours(t *testing.T) {
+	tests := []struct {
+		name    string
+		hours   []repo.OpeningHours
+		wantErr bool
+	}{
+		{name: "no hours", hours: nil},
+		{
+			name: "split day",
+			hours: []repo.OpeningHours{
+				{DayOfWeek: 0, OpenTime: "14:00", CloseTime: "20:00"},
+				{DayOfWeek: 0, OpenTime: "09:00", CloseTime: "13:00"},
+			},
+		},
+		{
+			name: "overnight split at midnight",
+			hours: []repo.OpeningHours{
+				{DayOfWeek: 5, OpenTime: "20:00", CloseTime: "24:00"},
+				{DayOfWeek: 6, OpenTime: "00:00", CloseTime: "02:00"},
+			},
+		},
+		{
+			name: "ranges may touch",
+			hours: []repo.OpeningHours{
+				{DayOfWeek: 1, OpenTime: "09:00", CloseTime: "13:00"},
+				{DayOfWeek: 1, OpenTime: "13:00", CloseTime: "17:00"},
+			},
+		},
+		{
+			name: "overlapping ranges",
+			hours: []repo.OpeningHours{
+				{DayOfWeek: 1, OpenTime: "12:00", CloseTime: "18:00"},
+				{DayOfWeek: 1, OpenTime: "09:00", CloseTime: "13:00"},
+			},
+			wantErr: true,
+		},
+		{
+			name:    "range past midnight",
+			hours:   []repo.OpeningHours{{DayOfWeek: 5, OpenTime: "20:00", CloseTime: "02:00"}},
+			wantErr: true,
+		},
+		{
+			name:    "day out of range",
+			hours:   []repo.OpeningHours{{DayOfWeek: 7, OpenTime: "09:00", CloseTime: "17:00"}},
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if msg := validateWeeklyHours(tt.hours); (msg != "") != tt.wantErr {
+				t.Errorf("validateWeeklyHours() = %q, want error: %t", msg, tt.wantErr)
+			}
+		})
+	}
+}
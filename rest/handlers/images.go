@@ -0,0 +1,298 @@
+package handlers
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"log"
+	"medidhaka/directory"
+	"medidhaka/infra/storage"
+	"medidhaka/repo"
+	"medidhaka/util"
+	"mime"
+	"net/http"
+	"path"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/gorilla/mux"
+)
+
+const maxImageSize = 5 << 20 // 5 MB
+
+// imageExtensions maps the sniffed content types that are accepted as photos
+// to the extension they are stored with.
+var imageExtensions = map[string]string{
+	"image/jpeg": ".jpg",
+	"image/png":  ".png",
+	"image/webp": ".webp",
+}
+
+var originalExtensions = []string{".jpg", ".png", ".webp"}
+
+// parseVariantKey reverses util.VariantKey, returning the variant and the keys of
+// the originals it could have been made from.
+func parseVariantKey(key string) (util.ImageVariant, []string, bool) {
+	dir, file := path.Split(key)
+	parent, name := path.Split(strings.TrimSuffix(dir, "/"))
+	for _, v := range util.ImageVariants {
+		if v.Name != name || parent == "" {
+			continue
+		}
+		ext := path.Ext(file)
+		base := strings.TrimSuffix(file, ext)
+		var originals []string
+		for _, origExt := range originalExtensions {
+			if util.VariantExt(origExt) == ext {
+				originals = append(originals, parent+base+origExt)
+			}
+		}
+		return v, originals, true
+	}
+	return util.ImageVariant{}, nil, false
+}
+
+// imageVariants returns the URLs of the resized copies of a photo, or nil
+// for photos hosted elsewhere.
+func imageVariants(store storage.Storage, url string) *repo.ImageVariants {
+	key, ok := store.KeyFromURL(url)
+	if !ok {
+		return nil
+	}
+	return &repo.ImageVariants{
+		Thumbnail: store.URL(util.VariantKey(key, util.ThumbnailVariant)),
+		Medium:    store.URL(util.VariantKey(key, util.MediumVariant)),
+	}
+}
+
+// storeVariant resizes an original photo into v and stores it.
+func storeVariant(store storage.Storage, key string, data []byte, v util.ImageVariant) error {
+	ext := util.VariantExt(path.Ext(key))
+	resized, err := util.ResizeImage(data, v.MaxSide, ext)
+	if err != nil {
+		return err
+	}
+	return store.Put(util.VariantKey(key, v), bytes.NewReader(resized))
+}
+
+type ImageHandler struct {
+	hospitalRepo repo.HospitalRepo
+	doctorRepo   repo.DoctorRepo
+	store        storage.Storage
+}
+
+func NewImageHandler(hospitalRepo repo.HospitalRepo, doctorRepo repo.DoctorRepo, store storage.Storage) *ImageHandler {
+	return &ImageHandler{hospitalRepo: hospitalRepo, doctorRepo: doctorRepo, store: store}
+}
+
+// readImage reads the "image" part of a multipart upload. The content type is
+// sniffed from the bytes rather than trusted from the client.
+func readImage(w http.ResponseWriter, r *http.Request) ([]byte, string, bool) {
+	r.Body = http.MaxBytesReader(w, r.Body, maxImageSize+1<<20)
+	if err := r.ParseMultipartForm(maxImageSize); err != nil {
+		var tooLarge *http.MaxBytesError
+		if errors.As(err, &tooLarge) {
+			util.SendData(w, map[string]string{"error": "Image must be at most 5 MB"}, http.StatusRequestEntityTooLarge)
+			return nil, "", false
+		}
+		util.SendData(w, map[string]string{"error": "Expected a multipart/form-data upload"}, http.StatusBadRequest)
+		return nil, "", false
+	}
+	defer r.MultipartForm.RemoveAll()
+
+	file, _, err := r.FormFile("image")
+	if err != nil {
+		util.SendData(w, map[string]string{"error": "image file is required"}, http.StatusBadRequest)
+		return nil, "", false
+	}
+	defer file.Close()
+
+	data, err := io.ReadAll(io.LimitReader(file, maxImageSize+1))
+	if err != nil {
+		util.SendData(w, map[string]string{"error": "Failed to read image"}, http.StatusBadRequest)
+		return nil, "", false
+	}
+	if len(data) > maxImageSize {
+		util.SendData(w, map[string]string{"error": "Image must be at most 5 MB"}, http.StatusRequestEntityTooLarge)
+		return nil, "", false
+	}
+	ext, ok := imageExtensions[http.DetectContentType(data)]
+	if !ok {
+		util.SendData(w, map[string]string{"error": "Image must be a JPEG, PNG or WebP file"}, http.StatusUnsupportedMediaType)
+		return nil, "", false
+	}
+	if err := util.CheckImage(data); err != nil {
+		if errors.Is(err, util.ErrImageTooLarge) {
+			util.SendData(w, map[string]string{"error": "Image must be at most 40 megapixels"}, http.StatusRequestEntityTooLarge)
+			return nil, "", false
+		}
+		util.SendData(w, map[string]string{"error": "Image file is corrupt"}, http.StatusBadRequest)
+		return nil, "", false
+	}
+	return data, ext, true
+}
+
+// replaceImage stores an uploaded image under prefix/id, points the record at
+// it through setURL and removes the file it replaces.
+func (h *ImageHandler) replaceImage(w http.ResponseWriter, r *http.Request, prefix string, setURL func(id int, url string) (string, error), notFound error) {
+	id, err := strconv.Atoi(mux.Vars(r)["id"])
+	if err != nil {
+		util.SendData(w, map[string]string{"error": "Invalid ID format"}, http.StatusBadRequest)
+		return
+	}
+	data, ext, ok := readImage(w, r)
+	if !ok {
+		return
+	}
+
+	name, err := util.RandomToken(12)
+	if err != nil {
+		log.Printf("Failed to name uploaded image: %v", err)
+		util.SendData(w, map[string]string{"error": "Failed to store image"}, http.StatusInternalServerError)
+		return
+	}
+	key := fmt.Sprintf("%s/%d/%s%s", prefix, id, name, ext)
+	if err := h.store.Put(key, bytes.NewReader(data)); err != nil {
+		log.Printf("Failed to store image %s: %v", key, err)
+		util.SendData(w, map[string]string{"error": "Failed to store image"}, http.StatusInternalServerError)
+		return
+	}
+	// Variants that fail here are retried when they are first requested.
+	for _, v := range util.ImageVariants {
+		if err := storeVariant(h.store, key, data, v); err != nil {
+			log.Printf("Failed to create %s variant of %s: %v", v.Name, key, err)
+		}
+	}
+
+	url := h.store.URL(key)
+	previous, err := setURL(id, url)
+	if err != nil {
+		directory.RemoveStoredImage(h.store, url, prefix, id)
+		if errors.Is(err, notFound) {
+			util.SendData(w, map[string]string{"error": "Record not found"}, http.StatusNotFound)
+			return
+		}
+		log.Printf("Failed to save image URL for %s %d: %v", prefix, id, err)
+		util.SendData(w, map[string]string{"error": "Failed to save image"}, http.StatusInternalServerError)
+		return
+	}
+	if previous != "" && previous != url {
+		directory.RemoveStoredImage(h.store, previous, prefix, id)
+	}
+	util.SendData(w, map[string]interface{}{"image_url": url, "images": imageVariants(h.store, url)}, http.StatusOK)
+}
+
+// clearImage unsets the record's image and removes the stored file.
+func (h *ImageHandler) clearImage(w http.ResponseWriter, r *http.Request, prefix string, setURL func(id int, url string) (string, error), notFound error) {
+	id, err := strconv.Atoi(mux.Vars(r)["id"])
+	if err != nil {
+		util.SendData(w, map[string]string{"error": "Invalid ID format"}, http.StatusBadRequest)
+		return
+	}
+	previous, err := setURL(id, "")
+	if err != nil {
+		if errors.Is(err, notFound) {
+			util.SendData(w, map[string]string{"error": "Record not found"}, http.StatusNotFound)
+			return
+		}
+		log.Printf("Failed to clear image of %s %d: %v", prefix, id, err)
+		util.SendData(w, map[string]string{"error": "Failed to delete image"}, http.StatusInternalServerError)
+		return
+	}
+	if previous != "" {
+		directory.RemoveStoredImage(h.store, previous, prefix, id)
+	}
+	util.SendData(w, map[string]string{"message": "Image deleted successfully"}, http.StatusOK)
+}
+
+// staffOwnsHospitalImage checks that the staff key belongs to the hospital
+// whose photo is changed. Malformed IDs are left to the upload and delete
+// handlers to reject.
+func staffOwnsHospitalImage(w http.ResponseWriter, r *http.Request) bool {
+	id, err := strconv.Atoi(mux.Vars(r)["id"])
+	return err != nil || staffOwnsHospital(w, r, id)
+}
+
+// Upload a hospital photo
+func (h *ImageHandler) UploadHospitalImage(w http.ResponseWriter, r *http.Request) {
+	if !staffOwnsHospitalImage(w, r) {
+		return
+	}
+	h.replaceImage(w, r, "hospitals", h.hospitalRepo.SetImageURL, repo.ErrNotFound)
+}
+
+// Remove a hospital photo
+func (h *ImageHandler) DeleteHospitalImage(w http.ResponseWriter, r *http.Request) {
+	if !staffOwnsHospitalImage(w, r) {
+		return
+	}
+	h.clearImage(w, r, "hospitals", h.hospitalRepo.SetImageURL, repo.ErrNotFound)
+}
+
+// Upload a doctor photo
+func (h *ImageHandler) UploadDoctorImage(w http.ResponseWriter, r *http.Request) {
+	h.replaceImage(w, r, "doctors", h.doctorRepo.SetImageURL, repo.ErrDoctorNotFound)
+}
+
+// Remove a doctor photo
+func (h *ImageHandler) DeleteDoctorImage(w http.ResponseWriter, r *http.Request) {
+	h.clearImage(w, r, "doctors", h.doctorRepo.SetImageURL, repo.ErrDoctorNotFound)
+}
+
+// generateVariant creates a missing variant from its original, for photos
+// uploaded before variants existed or whose variants failed on upload.
+func (h *ImageHandler) generateVariant(key string) bool {
+	v, originals, ok := parseVariantKey(key)
+	if !ok {
+		return false
+	}
+	for _, orig := range originals {
+		file, err := h.store.Open(orig)
+		if err != nil {
+			continue
+		}
+		data, err := io.ReadAll(io.LimitReader(file, maxImageSize+1))
+		file.Close()
+		if err != nil || len(data) > maxImageSize {
+			return false
+		}
+		if err := storeVariant(h.store, orig, data, v); err != nil {
+			log.Printf("Failed to create %s variant of %s: %v", v.Name, orig, err)
+			return false
+		}
+		return true
+	}
+	return false
+}
+
+// ServeMedia serves stored files, generating missing photo variants on first
+// request. Keys are random and never reused, so responses can be cached
+// indefinitely.
+func (h *ImageHandler) ServeMedia(w http.ResponseWriter, r *http.Request) {
+	key := mux.Vars(r)["key"]
+	file, err := h.store.Open(key)
+	if errors.Is(err, storage.ErrNotFound) && h.generateVariant(key) {
+		file, err = h.store.Open(key)
+	}
+	if err != nil {
+		if errors.Is(err, storage.ErrNotFound) || errors.Is(err, storage.ErrInvalidKey) {
+			http.NotFound(w, r)
+			return
+		}
+		log.Printf("Failed to open media %s: %v", key, err)
+		http.Error(w, "Failed to read media", http.StatusInternalServerError)
+		return
+	}
+	defer file.Close()
+
+	w.Header().Set("Content-Type", mime.TypeByExtension(path.Ext(key)))
+	w.Header().Set("Cache-Control", "public, max-age=31536000, immutable")
+	w.Header().Set("X-Content-Type-Options", "nosniff")
+	if rs, ok := file.(io.ReadSeeker); ok {
+		http.ServeContent(w, r, key, time.Time{}, rs)
+		return
+	}
+	io.Copy(w, file)
+}
@@ -0,0 +1,57 @@
+package handlers
+
+import (
+	"medidhaka/util"
+	"reflect"
+	"testing"
+)
+
+func TestParseVariantKey(t *testing.T) {
+	tests := []struct {
+		key           string
+		wantVariant   util.ImageVariant
+		wantOriginals []string
+		wantOK        bool
+	}{
+		{
+			key:           "hospitals/1/thumb/abc.jpg",
+			wantVariant:   util.ThumbnailVariant,
+			wantOriginals: []string{"hospitals/1/abc.jpg", "hospitals/1/abc.webp"},
+			wantOK:        true,
+		},
+		{
+			key:           "doctors/7/medium/abc.png",
+			wantVariant:   util.MediumVariant,
+			wantOriginals: []string{"doctors/7/abc.png"},
+			wantOK:        true,
+		},
+		{key: "hospitals/1/abc.jpg"},
+		{key: "hospitals/1/large/abc.jpg"},
+		{key: "thumb/abc.jpg"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.key, func(t *testing.T) {
+			variant, originals, ok := parseVariantKey(tt.key)
+			if ok != tt.wantOK || variant != tt.wantVariant || !reflect.DeepEqual(originals, tt.wantOriginals) {
+				t.Errorf("parseVariantKey() = %v, %v, %t, want %v, %v, %t", variant, originals, ok, tt.wantVariant, tt.wantOriginals, tt.wantOK)
+			}
+		})
+	}
+}
+
+// Every variant key must lead back to the photo it was made from.
+func TestParseVariantKeyReversesVariantKey(t *testing.T) {
+	for _, key := range []string{"hospitals/1/abc.jpg", "hospitals/1/abc.png", "doctors/2/abc.webp"} {
+		for _, v := range util.ImageVariants {
+			variant, originals, ok := parseVariantKey(util.VariantKey(key, v))
+			found := false
+			for _, o := range originals {
+				found = found || o == key
+			}
+			if !ok || variant != v || !found {
+				t.Errorf("parseVariantKey(VariantKey(%s, %s)) = %v, %v, %t", key, v.Name, variant, originals, ok)
+			}
+		}
+	}
+}
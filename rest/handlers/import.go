@@ -0,0 +1,82 @@
+package handlers
+
+import (
+	"errors"
+	"io"
+	"log"
+	"medidhaka/repo"
+	"medidhaka/util"
+	"net/http"
+	"strconv"
+	"strings"
+)
+
+const maxImportSize = 20 << 20
+
+type ImportHandler struct {
+	repo repo.ImportRepo
+}
+
+func NewImportHandler(r repo.ImportRepo) *ImportHandler {
+	return &ImportHandler{repo: r}
+}
+
+// Import hospitals, doctors or affiliations from a CSV file, sent either as
+// the request body or as the "file" part of a multipart upload
+func (h *ImportHandler) Import(w http.ResponseWriter, r *http.Request) {
+	query := r.URL.Query()
+	kind := query.Get("kind")
+	if !contains(repo.ImportKinds, kind) {
+		util.SendData(w, map[string]string{"error": "kind must be one of " + strings.Join(repo.ImportKinds, ", ")}, http.StatusBadRequest)
+		return
+	}
+	opts := repo.ImportOptions{
+		DryRun:    query.Get("dry_run") == "true",
+		Atomic:    query.Get("atomic") == "true",
+		ChunkSize: repo.DefaultImportChunkSize,
+	}
+	if v := query.Get("chunk_size"); v != "" {
+		n, err := strconv.Atoi(v)
+		if err != nil || n < 1 {
+			util.SendData(w, map[string]string{"error": "chunk_size must be a positive number"}, http.StatusBadRequest)
+			return
+		}
+		opts.ChunkSize = n
+	}
+
+	r.Body = http.MaxBytesReader(w, r.Body, maxImportSize)
+	var in io.Reader = r.Body
+	if strings.HasPrefix(r.Header.Get("Content-Type"), "multipart/form-data") {
+		file, _, err := r.FormFile("file")
+		if err != nil {
+			util.SendData(w, map[string]string{"error": "file is required"}, http.StatusBadRequest)
+			return
+		}
+		defer file.Close()
+		defer r.MultipartForm.RemoveAll()
+		in = file
+	}
+
+	file, err := repo.ReadImportCSV(in)
+	if err != nil {
+		var tooLarge *http.MaxBytesError
+		if errors.As(err, &tooLarge) {
+			util.SendData(w, map[string]string{"error": "Import file must be at most 20 MB"}, http.StatusRequestEntityTooLarge)
+			return
+		}
+		util.SendData(w, map[string]string{"error": err.Error()}, http.StatusBadRequest)
+		return
+	}
+
+	report, err := h.repo.Import(kind, file, opts)
+	if err != nil {
+		if errors.Is(err, repo.ErrUnknownColumns) {
+			util.SendData(w, map[string]string{"error": err.Error()}, http.StatusBadRequest)
+			return
+		}
+		log.Printf("Failed to import %s: %v", kind, err)
+		util.SendData(w, map[string]string{"error": "Failed to import file"}, http.StatusInternalServerError)
+		return
+	}
+	util.SendData(w, report, http.StatusOK)
+}
@@ -0,0 +1,158 @@
+package handlers
+
+import (
+	"encoding/json"
+	"errors"
+	"log"
+	"medidhaka/repo"
+	"medidhaka/util"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/gorilla/mux"
+)
+
+type InsuranceHandler struct {
+	repo repo.InsuranceRepo
+}
+
+func NewInsuranceHandler(r repo.InsuranceRepo) *InsuranceHandler {
+	return &InsuranceHandler{repo: r}
+}
+
+func (h *InsuranceHandler) CreatePanel(w http.ResponseWriter, r *http.Request) {
+	var p repo.InsurancePanel
+	if err := json.NewDecoder(r.Body).Decode(&p); err != nil {
+		util.SendData(w, map[string]string{"error": "Invalid input format"}, http.StatusBadRequest)
+		return
+	}
+	p.Code = strings.ToLower(strings.TrimSpace(p.Code))
+	p.Name = strings.TrimSpace(p.Name)
+	if p.Code == "" || p.Name == "" {
+		util.SendData(w, map[string]string{"error": "Panel code and name are required"}, http.StatusBadRequest)
+		return
+	}
+	if p.PanelType == "" {
+		p.PanelType = "insurance"
+	}
+	if p.PanelType != "insurance" && p.PanelType != "corporate" {
+		util.SendData(w, map[string]string{"error": "panel_type must be insurance or corporate"}, http.StatusBadRequest)
+		return
+	}
+
+	created, err := h.repo.Create(p)
+	if err != nil {
+		if errors.Is(err, repo.ErrDuplicatePanel) {
+			util.SendData(w, map[string]string{"error": "Panel code already exists"}, http.StatusConflict)
+			return
+		}
+		log.Printf("Failed to create insurance panel: %v", err)
+		util.SendData(w, map[string]string{"error": "Failed to create insurance panel"}, http.StatusInternalServerError)
+		return
+	}
+	util.SendData(w, created, http.StatusCreated)
+}
+
+func (h *InsuranceHandler) ListPanels(w http.ResponseWriter, r *http.Request) {
+	list, err := h.repo.List()
+	if err != nil {
+		log.Printf("Failed to list insurance panels: %v", err)
+		util.SendData(w, map[string]string{"error": "Failed to fetch insurance panels"}, http.StatusInternalServerError)
+		return
+	}
+	util.SendData(w, list, http.StatusOK)
+}
+
+func (h *InsuranceHandler) DeletePanel(w http.ResponseWriter, r *http.Request) {
+	id, err := strconv.Atoi(mux.Vars(r)["id"])
+	if err != nil {
+		util.SendData(w, map[string]string{"error": "Invalid panel ID format"}, http.StatusBadRequest)
+		return
+	}
+
+	if err := h.repo.Delete(id); err != nil {
+		if errors.Is(err, repo.ErrPanelNotFound) {
+			util.SendData(w, map[string]string{"error": "Insurance panel not found"}, http.StatusNotFound)
+			return
+		}
+		log.Printf("Failed to delete insurance panel ID %d: %v", id, err)
+		util.SendData(w, map[string]string{"error": "Failed to delete insurance panel"}, http.StatusInternalServerError)
+		return
+	}
+	util.SendData(w, map[string]string{"message": "Insurance panel deleted successfully"}, http.StatusOK)
+}
+
+// List the insurance and corporate panels a hospital accepts
+func (h *InsuranceHandler) ListHospitalPanels(w http.ResponseWriter, r *http.Request) {
+	hospitalID, err := strconv.Atoi(mux.Vars(r)["id"])
+	if err != nil {
+		util.SendData(w, map[string]string{"error": "Invalid hospital ID format"}, http.StatusBadRequest)
+		return
+	}
+
+	list, err := h.repo.ListByHospital(hospitalID)
+	if err != nil {
+		log.Printf("Failed to list insurance panels of hospital ID %d: %v", hospitalID, err)
+		util.SendData(w, map[string]string{"error": "Failed to fetch insurance panels"}, http.StatusInternalServerError)
+		return
+	}
+	util.SendData(w, list, http.StatusOK)
+}
+
+// Accept a panel at a hospital, or update its notes
+func (h *InsuranceHandler) AddHospitalPanel(w http.ResponseWriter, r *http.Request) {
+	hospitalID, err := strconv.Atoi(mux.Vars(r)["id"])
+	if err != nil {
+		util.SendData(w, map[string]string{"error": "Invalid hospital ID format"}, http.StatusBadRequest)
+		return
+	}
+	if !staffOwnsHospital(w, r, hospitalID) {
+		return
+	}
+
+	var body struct {
+		PanelID int    `json:"panel_id"`
+		Notes   string `json:"notes"`
+	}
+	if err := json.NewDecoder(r.Body).Decode(&body); err != nil || body.PanelID <= 0 {
+		util.SendData(w, map[string]string{"error": "panel_id is required"}, http.StatusBadRequest)
+		return
+	}
+
+	if err := h.repo.AddToHospital(hospitalID, body.PanelID, body.Notes); err != nil {
+		if errors.Is(err, repo.ErrNotFound) {
+			util.SendData(w, map[string]string{"error": "Hospital or insurance panel not found"}, http.StatusNotFound)
+			return
+		}
+		log.Printf("Failed to add insurance panel %d to hospital %d: %v", body.PanelID, hospitalID, err)
+		util.SendData(w, map[string]string{"error": "Failed to add insurance panel"}, http.StatusInternalServerError)
+		return
+	}
+	util.SendData(w, map[string]string{"message": "Insurance panel added successfully"}, http.StatusCreated)
+}
+
+// Stop accepting a panel at a hospital
+func (h *InsuranceHandler) RemoveHospitalPanel(w http.ResponseWriter, r *http.Request) {
+	vars := mux.Vars(r)
+	hospitalID, errConv1 := strconv.Atoi(vars["id"])
+	panelID, errConv2 := strconv.Atoi(vars["panel_id"])
+	if errConv1 != nil || errConv2 != nil {
+		util.SendData(w, map[string]string{"error": "Invalid ID format"}, http.StatusBadRequest)
+		return
+	}
+	if !staffOwnsHospital(w, r, hospitalID) {
+		return
+	}
+
+	if err := h.repo.RemoveFromHospital(hospitalID, panelID); err != nil {
+		if errors.Is(err, repo.ErrPanelNotFound) {
+			util.SendData(w, map[string]string{"error": "Hospital does not accept this panel"}, http.StatusNotFound)
+			return
+		}
+		log.Printf("Failed to remove insurance panel %d from hospital %d: %v", panelID, hospitalID, err)
+		util.SendData(w, map[string]string{"error": "Failed to remove insurance panel"}, http.StatusInternalServerError)
+		return
+	}
+	util.SendData(w, map[string]string{"message": "Insurance panel removed successfully"}, http.StatusOK)
+}
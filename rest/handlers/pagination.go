@@ -0,0 +1,36 @@
+package handlers
+
+import (
+	"net/url"
+	"strconv"
+)
+
+// parsePagination reads page and limit from the query string, falling back
+// to page 1 and defaultLimit, and returns the matching offset.
+func parsePagination(query url.Values, defaultLimit int) (page, limit, offset int) {
+	page = 1
+	limit = defaultLimit
+
+	if p := query.Get("page"); p != "" {
+		if v, err := strconv.Atoi(p); err == nil && v > 0 {
+			page = v
+		}
+	}
+	if l := query.Get("limit"); l != "" {
+		if v, err := strconv.Atoi(l); err == nil && v > 0 {
+			limit = v
+		}
+	}
+	return page, limit, (page - 1) * limit
+}
+
+// paginated builds the list envelope shared by all paginated endpoints.
+func paginated(data interface{}, total, page, limit int) map[string]interface{} {
+	return map[string]interface{}{
+		"data":       data,
+		"total":      total,
+		"page":       page,
+		"limit":      limit,
+		"totalPages": (total + limit - 1) / limit,
+	}
+}
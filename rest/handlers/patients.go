@@ -0,0 +1,67 @@
+package handlers
+
+import (
+	"encoding/json"
+	"errors"
+	"log"
+	"medidhaka/repo"
+	"medidhaka/util"
+	"net/http"
+	"strings"
+	"time"
+)
+
+type PatientHandler struct {
+	repo repo.PatientRepo
+}
+
+func NewPatientHandler(r repo.PatientRepo) *PatientHandler {
+	return &PatientHandler{repo: r}
+}
+
+// Profile of the logged-in patient
+func (h *PatientHandler) GetProfile(w http.ResponseWriter, r *http.Request) {
+	patientID, _ := util.PatientID(r.Context())
+	patient, err := h.repo.Get(patientID)
+	if err != nil {
+		if errors.Is(err, repo.ErrPatientNotFound) {
+			util.SendData(w, map[string]string{"error": "Patient not found"}, http.StatusNotFound)
+			return
+		}
+		log.Printf("Failed to get patient ID %d: %v", patientID, err)
+		util.SendData(w, map[string]string{"error": "Server error"}, http.StatusInternalServerError)
+		return
+	}
+	util.SendData(w, patient, http.StatusOK)
+}
+
+// Update the logged-in patient's profile. The phone number cannot be changed.
+func (h *PatientHandler) UpdateProfile(w http.ResponseWriter, r *http.Request) {
+	patientID, _ := util.PatientID(r.Context())
+
+	var p repo.Patient
+	if err := json.NewDecoder(r.Body).Decode(&p); err != nil {
+		util.SendData(w, map[string]string{"error": "Invalid input format"}, http.StatusBadRequest)
+		return
+	}
+	p.PatientID = patientID
+	p.Name = strings.TrimSpace(p.Name)
+	if p.DateOfBirth != nil {
+		if _, err := time.Parse("2006-01-02", *p.DateOfBirth); err != nil {
+			util.SendData(w, map[string]string{"error": "date_of_birth must be YYYY-MM-DD"}, http.StatusBadRequest)
+			return
+		}
+	}
+
+	updated, err := h.repo.Update(p)
+	if err != nil {
+		if errors.Is(err, repo.ErrPatientNotFound) {
+			util.SendData(w, map[string]string{"error": "Patient not found"}, http.StatusNotFound)
+			return
+		}
+		log.Printf("Failed to update patient ID %d: %v", patientID, err)
+		util.SendData(w, map[string]string{"error": "Failed to update profile"}, http.StatusInternalServerError)
+		return
+	}
+	util.SendData(w, updated, http.StatusOK)
+}
@@ -0,0 +1,252 @@
+package handlers
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log"
+	"medidhaka/infra/pubsub"
+	"medidhaka/repo"
+	"medidhaka/util"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/gorilla/mux"
+)
+
+// queueHeartbeat keeps idle SSE connections from being closed by proxies.
+const queueHeartbeat = 15 * time.Second
+
+// maxOpenSerials is how many of the day's queues a patient may wait in at
+// once, so one account cannot fill every chamber's queue.
+const maxOpenSerials = 3
+
+type QueueHandler struct {
+	repo   repo.QueueRepo
+	broker *pubsub.Broker
+}
+
+func NewQueueHandler(r repo.QueueRepo, broker *pubsub.Broker) *QueueHandler {
+	return &QueueHandler{
+		repo:   r,
+		broker: broker,
+	}
+}
+
+func queueTopic(hospitalID, doctorID int, date string) string {
+	return fmt.Sprintf("queue:%d:%d:%s", hospitalID, doctorID, date)
+}
+
+// publish pushes the latest queue status to live subscribers.
+func (h *QueueHandler) publish(status *repo.QueueStatus) {
+	msg, err := json.Marshal(status)
+	if err != nil {
+		log.Printf("Failed to encode queue status: %v", err)
+		return
+	}
+	h.broker.Publish(queueTopic(status.HospitalID, status.DoctorID, status.Date), msg)
+}
+
+// sendQueueError maps repo errors of queue changes to responses.
+func sendQueueError(w http.ResponseWriter, err error) {
+	switch {
+	case errors.Is(err, repo.ErrSerialNotFound):
+		util.SendData(w, map[string]string{"error": "Serial not found or cannot be changed from its current status"}, http.StatusNotFound)
+	case errors.Is(err, repo.ErrQueueEmpty):
+		util.SendData(w, map[string]string{"error": "No patients are waiting"}, http.StatusConflict)
+	case errors.Is(err, repo.ErrNoAffiliation):
+		util.SendData(w, map[string]string{"error": "Doctor is not assigned to this hospital"}, http.StatusNotFound)
+	case errors.Is(err, repo.ErrAlreadyQueued):
+		util.SendData(w, map[string]string{"error": "You already hold a serial in this queue"}, http.StatusConflict)
+	case errors.Is(err, repo.ErrTooManySerials):
+		util.SendData(w, map[string]string{"error": "Too many open serials today, wait until one is seen"}, http.StatusTooManyRequests)
+	default:
+		log.Printf("Failed to update queue: %v", err)
+		util.SendData(w, map[string]string{"error": "Failed to update queue"}, http.StatusInternalServerError)
+	}
+}
+
+// Today's queue of a hospital-doctor relation
+func (h *QueueHandler) GetTodayQueue(w http.ResponseWriter, r *http.Request) {
+	hospitalID, doctorID, ok := relationIDs(r)
+	if !ok {
+		util.SendData(w, map[string]string{"error": "Invalid ID format"}, http.StatusBadRequest)
+		return
+	}
+	status, err := h.repo.Status(hospitalID, doctorID, util.TodayInDhaka())
+	if err != nil {
+		log.Printf("Failed to get queue: %v", err)
+		util.SendData(w, map[string]string{"error": "Failed to fetch queue"}, http.StatusInternalServerError)
+		return
+	}
+	util.SendData(w, status, http.StatusOK)
+}
+
+// Stream today's queue as Server-Sent Events. The current status is sent on
+// connect and again after every change. The stream ends at midnight in Dhaka,
+// when the day's queue closes; clients reconnect to follow the new day.
+func (h *QueueHandler) StreamTodayQueue(w http.ResponseWriter, r *http.Request) {
+	hospitalID, doctorID, ok := relationIDs(r)
+	if !ok {
+		util.SendData(w, map[string]string{"error": "Invalid ID format"}, http.StatusBadRequest)
+		return
+	}
+	day := util.TodayInDhaka()
+
+	// Subscribe before reading the status so no change is missed in between.
+	updates, unsubscribe := h.broker.Subscribe(queueTopic(hospitalID, doctorID, day))
+	defer unsubscribe()
+
+	status, err := h.repo.Status(hospitalID, doctorID, day)
+	if err != nil {
+		log.Printf("Failed to get queue: %v", err)
+		util.SendData(w, map[string]string{"error": "Failed to fetch queue"}, http.StatusInternalServerError)
+		return
+	}
+	initial, err := json.Marshal(status)
+	if err != nil {
+		log.Printf("Failed to encode queue status: %v", err)
+		util.SendData(w, map[string]string{"error": "Failed to fetch queue"}, http.StatusInternalServerError)
+		return
+	}
+
+	rc := http.NewResponseController(w)
+	// The stream outlives the server's write timeout, if one is set.
+	_ = rc.SetWriteDeadline(time.Time{})
+
+	w.Header().Set("Content-Type", "text/event-stream")
+	w.Header().Set("Cache-Control", "no-cache")
+	w.Header().Set("Connection", "keep-alive")
+	w.Header().Set("X-Accel-Buffering", "no")
+	w.WriteHeader(http.StatusOK)
+
+	fmt.Fprintf(w, "event: queue\ndata: %s\n\n", initial)
+	if err := rc.Flush(); err != nil {
+		log.Printf("Queue stream does not support flushing: %v", err)
+		return
+	}
+
+	heartbeat := time.NewTicker(queueHeartbeat)
+	defer heartbeat.Stop()
+	// day came from TodayInDhaka, so it always parses.
+	end, _ := util.EndOfDayInDhaka(day)
+	dayEnd := time.NewTimer(time.Until(end))
+	defer dayEnd.Stop()
+
+	for {
+		select {
+		case <-r.Context().Done():
+			return
+		case <-dayEnd.C:
+			return
+		case msg := <-updates:
+			fmt.Fprintf(w, "event: queue\ndata: %s\n\n", msg)
+		case <-heartbeat.C:
+			fmt.Fprint(w, ": ping\n\n")
+		}
+		if err := rc.Flush(); err != nil {
+			return
+		}
+	}
+}
+
+// All serials issued today, for the chamber desk
+func (h *QueueHandler) ListTodaySerials(w http.ResponseWriter, r *http.Request) {
+	hospitalID, doctorID, ok := relationIDs(r)
+	if !ok {
+		util.SendData(w, map[string]string{"error": "Invalid ID format"}, http.StatusBadRequest)
+		return
+	}
+	if !staffOwnsHospital(w, r, hospitalID) {
+		return
+	}
+	list, err := h.repo.ListSerials(hospitalID, doctorID, util.TodayInDhaka())
+	if err != nil {
+		log.Printf("Failed to list serials: %v", err)
+		util.SendData(w, map[string]string{"error": "Failed to fetch serials"}, http.StatusInternalServerError)
+		return
+	}
+	util.SendData(w, list, http.StatusOK)
+}
+
+// Issue the next serial number of today's queue to the logged-in patient
+func (h *QueueHandler) IssueSerial(w http.ResponseWriter, r *http.Request) {
+	hospitalID, doctorID, ok := relationIDs(r)
+	if !ok {
+		util.SendData(w, map[string]string{"error": "Invalid ID format"}, http.StatusBadRequest)
+		return
+	}
+	var s repo.QueueSerial
+	if err := json.NewDecoder(r.Body).Decode(&s); err != nil {
+		util.SendData(w, map[string]string{"error": "Invalid input format"}, http.StatusBadRequest)
+		return
+	}
+	s.PatientName = strings.TrimSpace(s.PatientName)
+	s.PatientPhone = strings.TrimSpace(s.PatientPhone)
+	if s.PatientName == "" {
+		util.SendData(w, map[string]string{"error": "patient_name is required"}, http.StatusBadRequest)
+		return
+	}
+	patientID, _ := util.PatientID(r.Context())
+	s.PatientID = &patientID
+
+	day := util.TodayInDhaka()
+	issued, err := h.repo.Issue(hospitalID, doctorID, day, s, maxOpenSerials)
+	if err != nil {
+		sendQueueError(w, err)
+		return
+	}
+	util.SendData(w, issued, http.StatusCreated)
+
+	if status, err := h.repo.Status(hospitalID, doctorID, day); err == nil {
+		h.publish(status)
+	}
+}
+
+// change runs a queue mutation and publishes the resulting status.
+func (h *QueueHandler) change(w http.ResponseWriter, r *http.Request, fn func(hospitalID, doctorID int, date string, serial int) (*repo.QueueStatus, error)) {
+	hospitalID, doctorID, ok := relationIDs(r)
+	if !ok {
+		util.SendData(w, map[string]string{"error": "Invalid ID format"}, http.StatusBadRequest)
+		return
+	}
+	serial := 0
+	if v, found := mux.Vars(r)["serial"]; found {
+		n, err := strconv.Atoi(v)
+		if err != nil || n <= 0 {
+			util.SendData(w, map[string]string{"error": "Invalid serial number"}, http.StatusBadRequest)
+			return
+		}
+		serial = n
+	}
+	if !staffOwnsHospital(w, r, hospitalID) {
+		return
+	}
+
+	status, err := fn(hospitalID, doctorID, util.TodayInDhaka(), serial)
+	if err != nil {
+		sendQueueError(w, err)
+		return
+	}
+	h.publish(status)
+	util.SendData(w, status, http.StatusOK)
+}
+
+// Call the next waiting serial; the one being seen is marked done
+func (h *QueueHandler) CallNext(w http.ResponseWriter, r *http.Request) {
+	h.change(w, r, func(hospitalID, doctorID int, date string, _ int) (*repo.QueueStatus, error) {
+		return h.repo.CallNext(hospitalID, doctorID, date)
+	})
+}
+
+// Call a specific serial out of order, or recall a skipped one
+func (h *QueueHandler) CallSerial(w http.ResponseWriter, r *http.Request) {
+	h.change(w, r, h.repo.Call)
+}
+
+// Skip a serial whose patient is not present; it can be recalled later
+func (h *QueueHandler) SkipSerial(w http.ResponseWriter, r *http.Request) {
+	h.change(w, r, h.repo.Skip)
+}
@@ -0,0 +1,149 @@
+package handlers
+
+import (
+	"encoding/json"
+	"errors"
+	"log"
+	"medidhaka/repo"
+	"medidhaka/util"
+	"net/http"
+	"strconv"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/gorilla/mux"
+)
+
+const maxReviewLength = 2000
+
+type ReviewHandler struct {
+	repo repo.ReviewRepo
+}
+
+func NewReviewHandler(r repo.ReviewRepo) *ReviewHandler {
+	return &ReviewHandler{repo: r}
+}
+
+// Review a completed visit of the logged-in patient
+func (h *ReviewHandler) CreateReview(w http.ResponseWriter, r *http.Request) {
+	var body struct {
+		AppointmentID int    `json:"appointment_id"`
+		Rating        int    `json:"rating"`
+		Text          string `json:"text"`
+	}
+	if err := json.NewDecoder(r.Body).Decode(&body); err != nil {
+		util.SendData(w, map[string]string{"error": "Invalid input format"}, http.StatusBadRequest)
+		return
+	}
+	body.Text = strings.TrimSpace(body.Text)
+	if body.AppointmentID <= 0 || body.Rating < 1 || body.Rating > 5 {
+		util.SendData(w, map[string]string{"error": "appointment_id and a rating between 1 and 5 are required"}, http.StatusBadRequest)
+		return
+	}
+	if utf8.RuneCountInString(body.Text) > maxReviewLength {
+		util.SendData(w, map[string]string{"error": "text must be at most 2000 characters"}, http.StatusBadRequest)
+		return
+	}
+	patientID, _ := util.PatientID(r.Context())
+
+	created, err := h.repo.Create(patientID, body.AppointmentID, body.Rating, body.Text)
+	if err != nil {
+		switch {
+		case errors.Is(err, repo.ErrAppointmentNotFound):
+			util.SendData(w, map[string]string{"error": "Appointment not found"}, http.StatusNotFound)
+		case errors.Is(err, repo.ErrVisitNotCompleted):
+			util.SendData(w, map[string]string{"error": "Only completed visits can be reviewed"}, http.StatusConflict)
+		case errors.Is(err, repo.ErrAlreadyReviewed):
+			util.SendData(w, map[string]string{"error": "This visit has already been reviewed"}, http.StatusConflict)
+		default:
+			log.Printf("Failed to create review: %v", err)
+			util.SendData(w, map[string]string{"error": "Failed to create review"}, http.StatusInternalServerError)
+		}
+		return
+	}
+	util.SendData(w, created, http.StatusCreated)
+}
+
+func (h *ReviewHandler) list(w http.ResponseWriter, r *http.Request, filter repo.ReviewFilter) {
+	page, limit, offset := parsePagination(r.URL.Query(), 20)
+	list, total, err := h.repo.List(filter, offset, limit)
+	if err != nil {
+		log.Printf("Failed to list reviews: %v", err)
+		util.SendData(w, map[string]string{"error": "Failed to fetch reviews"}, http.StatusInternalServerError)
+		return
+	}
+	util.SendData(w, paginated(list, total, page, limit), http.StatusOK)
+}
+
+// Approved reviews of a doctor
+func (h *ReviewHandler) ListDoctorReviews(w http.ResponseWriter, r *http.Request) {
+	doctorID, err := strconv.Atoi(mux.Vars(r)["id"])
+	if err != nil {
+		util.SendData(w, map[string]string{"error": "Invalid doctor ID format"}, http.StatusBadRequest)
+		return
+	}
+	h.list(w, r, repo.ReviewFilter{DoctorID: doctorID, Status: repo.ReviewApproved})
+}
+
+// Approved reviews of a hospital
+func (h *ReviewHandler) ListHospitalReviews(w http.ResponseWriter, r *http.Request) {
+	hospitalID, err := strconv.Atoi(mux.Vars(r)["id"])
+	if err != nil {
+		util.SendData(w, map[string]string{"error": "Invalid hospital ID format"}, http.StatusBadRequest)
+		return
+	}
+	h.list(w, r, repo.ReviewFilter{HospitalID: hospitalID, Status: repo.ReviewApproved})
+}
+
+// Reviews written by the logged-in patient, in any status
+func (h *ReviewHandler) ListMyReviews(w http.ResponseWriter, r *http.Request) {
+	patientID, _ := util.PatientID(r.Context())
+	h.list(w, r, repo.ReviewFilter{PatientID: patientID})
+}
+
+// Moderation queue; pending reviews unless another status is asked for
+func (h *ReviewHandler) ListModerationQueue(w http.ResponseWriter, r *http.Request) {
+	status := r.URL.Query().Get("status")
+	switch status {
+	case "":
+		status = repo.ReviewPending
+	case repo.ReviewPending, repo.ReviewApproved, repo.ReviewRejected:
+	default:
+		util.SendData(w, map[string]string{"error": "status must be pending, approved or rejected"}, http.StatusBadRequest)
+		return
+	}
+	h.list(w, r, repo.ReviewFilter{Status: status})
+}
+
+// Approve or reject a review
+func (h *ReviewHandler) ModerateReview(w http.ResponseWriter, r *http.Request) {
+	id, err := strconv.Atoi(mux.Vars(r)["id"])
+	if err != nil {
+		util.SendData(w, map[string]string{"error": "Invalid review ID format"}, http.StatusBadRequest)
+		return
+	}
+	var body struct {
+		Status string `json:"status"`
+		Note   string `json:"note"`
+	}
+	if err := json.NewDecoder(r.Body).Decode(&body); err != nil {
+		util.SendData(w, map[string]string{"error": "Invalid input format"}, http.StatusBadRequest)
+		return
+	}
+	if body.Status != repo.ReviewApproved && body.Status != repo.ReviewRejected {
+		util.SendData(w, map[string]string{"error": "status must be approved or rejected"}, http.StatusBadRequest)
+		return
+	}
+
+	updated, err := h.repo.Moderate(id, body.Status, strings.TrimSpace(body.Note))
+	if err != nil {
+		if errors.Is(err, repo.ErrReviewNotFound) {
+			util.SendData(w, map[string]string{"error": "Review not found"}, http.StatusNotFound)
+			return
+		}
+		log.Printf("Failed to moderate review ID %d: %v", id, err)
+		util.SendData(w, map[string]string{"error": "Failed to moderate review"}, http.StatusInternalServerError)
+		return
+	}
+	util.SendData(w, updated, http.StatusOK)
+}
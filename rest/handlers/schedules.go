@@ -0,0 +1,320 @@
+package handlers
+
+import (
+	"encoding/json"
+	"errors"
+	"log"
+	"medidhaka/repo"
+	"medidhaka/util"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/gorilla/mux"
+)
+
+type ScheduleHandler struct {
+	repo repo.ScheduleRepo
+}
+
+func NewScheduleHandler(r repo.ScheduleRepo) *ScheduleHandler {
+	return &ScheduleHandler{repo: r}
+}
+
+// validClockRange reports whether start and end are HH:MM times with start
+// before end.
+func validClockRange(start, end string) bool {
+	s, err1 := time.Parse("15:04", start)
+	e, err2 := time.Parse("15:04", end)
+	return err1 == nil && err2 == nil && s.Before(e)
+}
+
+// relationIDs reads the {hospital_id}/{doctor_id} pair from the URL.
+func relationIDs(r *http.Request) (int, int, bool) {
+	vars := mux.Vars(r)
+	hospitalID, errConv1 := strconv.Atoi(vars["hospital_id"])
+	doctorID, errConv2 := strconv.Atoi(vars["doctor_id"])
+	return hospitalID, doctorID, errConv1 == nil && errConv2 == nil
+}
+
+func validateSchedule(s repo.Schedule) string {
+	if s.DayOfWeek < 0 || s.DayOfWeek > 6 {
+		return "day_of_week must be between 0 (Sunday) and 6 (Saturday)"
+	}
+	if !validClockRange(s.StartTime, s.EndTime) {
+		return "start_time and end_time must be HH:MM with start_time before end_time"
+	}
+	if s.MaxPatients < 0 {
+		return "max_patients cannot be negative"
+	}
+	return ""
+}
+
+// List the weekly schedule of a hospital-doctor relation
+func (h *ScheduleHandler) ListRelationSchedules(w http.ResponseWriter, r *http.Request) {
+	hospitalID, doctorID, ok := relationIDs(r)
+	if !ok {
+		util.SendData(w, map[string]string{"error": "Invalid ID format"}, http.StatusBadRequest)
+		return
+	}
+
+	list, err := h.repo.ListByRelation(hospitalID, doctorID)
+	if err != nil {
+		log.Printf("Failed to list schedules: %v", err)
+		util.SendData(w, map[string]string{"error": "Failed to fetch schedules"}, http.StatusInternalServerError)
+		return
+	}
+	util.SendData(w, list, http.StatusOK)
+}
+
+// Add a weekly session to a hospital-doctor relation
+func (h *ScheduleHandler) CreateSchedule(w http.ResponseWriter, r *http.Request) {
+	hospitalID, doctorID, ok := relationIDs(r)
+	if !ok {
+		util.SendData(w, map[string]string{"error": "Invalid ID format"}, http.StatusBadRequest)
+		return
+	}
+
+	if !staffOwnsHospital(w, r, hospitalID) {
+		return
+	}
+
+	var s repo.Schedule
+	if err := json.NewDecoder(r.Body).Decode(&s); err != nil {
+		util.SendData(w, map[string]string{"error": "Invalid input format"}, http.StatusBadRequest)
+		return
+	}
+	s.HospitalID, s.DoctorID = hospitalID, doctorID
+	if msg := validateSchedule(s); msg != "" {
+		util.SendData(w, map[string]string{"error": msg}, http.StatusBadRequest)
+		return
+	}
+
+	created, err := h.repo.Create(s)
+	if err != nil {
+		if errors.Is(err, repo.ErrNoAffiliation) {
+			util.SendData(w, map[string]string{"error": "Doctor is not assigned to this hospital"}, http.StatusNotFound)
+			return
+		}
+		log.Printf("Failed to create schedule: %v", err)
+		util.SendData(w, map[string]string{"error": "Failed to create schedule"}, http.StatusInternalServerError)
+		return
+	}
+	util.SendData(w, created, http.StatusCreated)
+}
+
+func (h *ScheduleHandler) UpdateSchedule(w http.ResponseWriter, r *http.Request) {
+	id, err := strconv.Atoi(mux.Vars(r)["id"])
+	if err != nil {
+		util.SendData(w, map[string]string{"error": "Invalid schedule ID format"}, http.StatusBadRequest)
+		return
+	}
+
+	var s repo.Schedule
+	if err := json.NewDecoder(r.Body).Decode(&s); err != nil {
+		util.SendData(w, map[string]string{"error": "Invalid input format"}, http.StatusBadRequest)
+		return
+	}
+	s.ScheduleID = id
+	if msg := validateSchedule(s); msg != "" {
+		util.SendData(w, map[string]string{"error": msg}, http.StatusBadRequest)
+		return
+	}
+
+	current, err := h.repo.Get(id)
+	if err == nil && !staffOwnsHospital(w, r, current.HospitalID) {
+		return
+	}
+	var updated *repo.Schedule
+	if err == nil {
+		updated, err = h.repo.Update(s)
+	}
+	if err != nil {
+		if errors.Is(err, repo.ErrScheduleNotFound) {
+			util.SendData(w, map[string]string{"error": "Schedule not found"}, http.StatusNotFound)
+			return
+		}
+		log.Printf("Failed to update schedule ID %d: %v", id, err)
+		util.SendData(w, map[string]string{"error": "Failed to update schedule"}, http.StatusInternalServerError)
+		return
+	}
+	util.SendData(w, updated, http.StatusOK)
+}
+
+func (h *ScheduleHandler) DeleteSchedule(w http.ResponseWriter, r *http.Request) {
+	id, err := strconv.Atoi(mux.Vars(r)["id"])
+	if err != nil {
+		util.SendData(w, map[string]string{"error": "Invalid schedule ID format"}, http.StatusBadRequest)
+		return
+	}
+
+	current, err := h.repo.Get(id)
+	if err == nil && !staffOwnsHospital(w, r, current.HospitalID) {
+		return
+	}
+	if err == nil {
+		err = h.repo.Delete(id)
+	}
+	if err != nil {
+		if errors.Is(err, repo.ErrScheduleNotFound) {
+			util.SendData(w, map[string]string{"error": "Schedule not found"}, http.StatusNotFound)
+			return
+		}
+		log.Printf("Failed to delete schedule ID %d: %v", id, err)
+		util.SendData(w, map[string]string{"error": "Failed to delete schedule"}, http.StatusInternalServerError)
+		return
+	}
+	util.SendData(w, map[string]string{"message": "Schedule deleted successfully"}, http.StatusOK)
+}
+
+// List upcoming date exceptions of a hospital-doctor relation
+func (h *ScheduleHandler) ListRelationExceptions(w http.ResponseWriter, r *http.Request) {
+	hospitalID, doctorID, ok := relationIDs(r)
+	if !ok {
+		util.SendData(w, map[string]string{"error": "Invalid ID format"}, http.StatusBadRequest)
+		return
+	}
+
+	list, err := h.repo.ListExceptions(hospitalID, doctorID, util.TodayInDhaka())
+	if err != nil {
+		log.Printf("Failed to list schedule exceptions: %v", err)
+		util.SendData(w, map[string]string{"error": "Failed to fetch schedule exceptions"}, http.StatusInternalServerError)
+		return
+	}
+	util.SendData(w, list, http.StatusOK)
+}
+
+// Add a date exception (day off or changed hours) to a hospital-doctor relation
+func (h *ScheduleHandler) CreateException(w http.ResponseWriter, r *http.Request) {
+	hospitalID, doctorID, ok := relationIDs(r)
+	if !ok {
+		util.SendData(w, map[string]string{"error": "Invalid ID format"}, http.StatusBadRequest)
+		return
+	}
+
+	if !staffOwnsHospital(w, r, hospitalID) {
+		return
+	}
+
+	var e repo.ScheduleException
+	if err := json.NewDecoder(r.Body).Decode(&e); err != nil {
+		util.SendData(w, map[string]string{"error": "Invalid input format"}, http.StatusBadRequest)
+		return
+	}
+	e.HospitalID, e.DoctorID = hospitalID, doctorID
+	if _, err := time.Parse("2006-01-02", e.Date); err != nil {
+		util.SendData(w, map[string]string{"error": "date must be YYYY-MM-DD"}, http.StatusBadRequest)
+		return
+	}
+	if e.IsAvailable {
+		if e.StartTime == nil || e.EndTime == nil || !validClockRange(*e.StartTime, *e.EndTime) {
+			util.SendData(w, map[string]string{"error": "start_time and end_time (HH:MM) are required when is_available is true"}, http.StatusBadRequest)
+			return
+		}
+	} else {
+		e.StartTime, e.EndTime = nil, nil
+	}
+
+	created, err := h.repo.CreateException(e)
+	if err != nil {
+		switch {
+		case errors.Is(err, repo.ErrNoAffiliation):
+			util.SendData(w, map[string]string{"error": "Doctor is not assigned to this hospital"}, http.StatusNotFound)
+		case errors.Is(err, repo.ErrDuplicateDate):
+			util.SendData(w, map[string]string{"error": "An exception already exists for this date"}, http.StatusConflict)
+		default:
+			log.Printf("Failed to create schedule exception: %v", err)
+			util.SendData(w, map[string]string{"error": "Failed to create schedule exception"}, http.StatusInternalServerError)
+		}
+		return
+	}
+	util.SendData(w, created, http.StatusCreated)
+}
+
+func (h *ScheduleHandler) DeleteException(w http.ResponseWriter, r *http.Request) {
+	id, err := strconv.Atoi(mux.Vars(r)["id"])
+	if err != nil {
+		util.SendData(w, map[string]string{"error": "Invalid exception ID format"}, http.StatusBadRequest)
+		return
+	}
+
+	e, err := h.repo.GetException(id)
+	if err == nil && !staffOwnsHospital(w, r, e.HospitalID) {
+		return
+	}
+	if err == nil {
+		err = h.repo.DeleteException(id)
+	}
+	if err != nil {
+		if errors.Is(err, repo.ErrExceptionNotFound) {
+			util.SendData(w, map[string]string{"error": "Schedule exception not found"}, http.StatusNotFound)
+			return
+		}
+		log.Printf("Failed to delete schedule exception ID %d: %v", id, err)
+		util.SendData(w, map[string]string{"error": "Failed to delete schedule exception"}, http.StatusInternalServerError)
+		return
+	}
+	util.SendData(w, map[string]string{"message": "Schedule exception deleted successfully"}, http.StatusOK)
+}
+
+// Weekly schedule of a doctor across all hospitals, with upcoming exceptions
+func (h *ScheduleHandler) GetDoctorSchedule(w http.ResponseWriter, r *http.Request) {
+	doctorID, err := strconv.Atoi(mux.Vars(r)["id"])
+	if err != nil {
+		util.SendData(w, map[string]string{"error": "Invalid doctor ID format"}, http.StatusBadRequest)
+		return
+	}
+
+	weekly, err := h.repo.ListByDoctor(doctorID)
+	if err != nil {
+		log.Printf("Failed to get schedule of doctor ID %d: %v", doctorID, err)
+		util.SendData(w, map[string]string{"error": "Failed to fetch doctor schedule"}, http.StatusInternalServerError)
+		return
+	}
+	exceptions, err := h.repo.ListDoctorExceptions(doctorID, util.TodayInDhaka())
+	if err != nil {
+		log.Printf("Failed to get schedule exceptions of doctor ID %d: %v", doctorID, err)
+		util.SendData(w, map[string]string{"error": "Failed to fetch doctor schedule"}, http.StatusInternalServerError)
+		return
+	}
+
+	response := map[string]interface{}{
+		"timezone":   "Asia/Dhaka",
+		"weekly":     weekly,
+		"exceptions": exceptions,
+	}
+	util.SendData(w, response, http.StatusOK)
+}
+
+// Sessions held at a hospital on a date (defaults to today in Asia/Dhaka)
+func (h *ScheduleHandler) GetHospitalSchedule(w http.ResponseWriter, r *http.Request) {
+	hospitalID, err := strconv.Atoi(mux.Vars(r)["id"])
+	if err != nil {
+		util.SendData(w, map[string]string{"error": "Invalid hospital ID format"}, http.StatusBadRequest)
+		return
+	}
+
+	day := r.URL.Query().Get("date")
+	if day == "" {
+		day = util.TodayInDhaka()
+	}
+	date, err := time.ParseInLocation("2006-01-02", day, util.Dhaka)
+	if err != nil {
+		util.SendData(w, map[string]string{"error": "date must be YYYY-MM-DD"}, http.StatusBadRequest)
+		return
+	}
+
+	sessions, err := h.repo.ListForHospitalOnDate(hospitalID, date)
+	if err != nil {
+		log.Printf("Failed to get schedule of hospital ID %d: %v", hospitalID, err)
+		util.SendData(w, map[string]string{"error": "Failed to fetch hospital schedule"}, http.StatusInternalServerError)
+		return
+	}
+
+	response := map[string]interface{}{
+		"date":     day,
+		"timezone": "Asia/Dhaka",
+		"sessions": sessions,
+	}
+	util.SendData(w, response, http.StatusOK)
+}
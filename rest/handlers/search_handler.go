@@ -1,6 +1,7 @@
 package handlers
 
 import (
+	"medidhaka/infra/storage"
 	"medidhaka/repo"
 	"medidhaka/util"
 	"net/http"
@@ -10,12 +11,14 @@ import (
 type SearchHandler struct {
 	doctorRepo   repo.DoctorRepo
 	hospitalRepo repo.HospitalRepo
+	store        storage.Storage
 }
 
-func NewSearchHandler(dRepo repo.DoctorRepo, hRepo repo.HospitalRepo) *SearchHandler {
+func NewSearchHandler(dRepo repo.DoctorRepo, hRepo repo.HospitalRepo, store storage.Storage) *SearchHandler {
 	return &SearchHandler{
 		doctorRepo:   dRepo,
 		hospitalRepo: hRepo,
+		store:        store,
 	}
 }
 
@@ -27,8 +30,8 @@ func (h *SearchHandler) Search(w http.ResponseWriter, r *http.Request) {
 	}
 
 	// Fetch up to 3 doctors and hospitals
-	doctors, _, err1 := h.doctorRepo.List(query, 0, 3)
-	hospitals, _, err2 := h.hospitalRepo.List(query, 0, 3)
+	doctors, _, err1 := h.doctorRepo.List(repo.DoctorFilter{Search: query, Status: repo.VerificationVerified}, 0, 3)
+	hospitals, _, err2 := h.hospitalRepo.List(repo.HospitalFilter{Search: query}, 0, 3)
 
 	if err1 != nil || err2 != nil {
 		util.SendData(w, map[string]string{"error": "Failed to fetch search results"}, http.StatusInternalServerError)
@@ -37,26 +40,29 @@ func (h *SearchHandler) Search(w http.ResponseWriter, r *http.Request) {
 
 	// Map results to simplified response
 	type item struct {
-		Name  string `json:"name"`
-		Image string `json:"image"`
-		ID    int    `json:"id"`
+		Name   string              `json:"name"`
+		Image  string              `json:"image"`
+		Images *repo.ImageVariants `json:"images,omitempty"`
+		ID     int                 `json:"id"`
 	}
 
 	var doctorList []item
 	for _, d := range doctors {
 		doctorList = append(doctorList, item{
-			Name:  d.Name,
-			Image: d.ImageURL,
-			ID:    d.DoctorID,
+			Name:   d.Name,
+			Image:  d.ImageURL,
+			Images: imageVariants(h.store, d.ImageURL),
+			ID:     d.DoctorID,
 		})
 	}
 
 	var hospitalList []item
 	for _, hsp := range hospitals {
 		hospitalList = append(hospitalList, item{
-			Name:  hsp.Name,
-			Image: hsp.ImageURL,
-			ID:    hsp.HospitalID,
+			Name:   hsp.Name,
+			Image:  hsp.ImageURL,
+			Images: imageVariants(h.store, hsp.ImageURL),
+			ID:     hsp.HospitalID,
 		})
 	}
 
@@ -0,0 +1,175 @@
+package handlers
+
+import (
+	"encoding/json"
+	"errors"
+	"log"
+	"medidhaka/repo"
+	"medidhaka/util"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/gorilla/mux"
+)
+
+type ServiceHandler struct {
+	repo repo.ServiceRepo
+}
+
+func NewServiceHandler(r repo.ServiceRepo) *ServiceHandler {
+	return &ServiceHandler{repo: r}
+}
+
+func (h *ServiceHandler) CreateService(w http.ResponseWriter, r *http.Request) {
+	var s repo.Service
+	if err := json.NewDecoder(r.Body).Decode(&s); err != nil {
+		util.SendData(w, map[string]string{"error": "Invalid input format"}, http.StatusBadRequest)
+		return
+	}
+	s.Code = strings.ToLower(strings.TrimSpace(s.Code))
+	if s.Code == "" || s.Name == "" {
+		util.SendData(w, map[string]string{"error": "Service code and name are required"}, http.StatusBadRequest)
+		return
+	}
+
+	created, err := h.repo.Create(s)
+	if err != nil {
+		if errors.Is(err, repo.ErrDuplicateService) {
+			util.SendData(w, map[string]string{"error": "Service code already exists"}, http.StatusConflict)
+			return
+		}
+		log.Printf("Failed to create service: %v", err)
+		util.SendData(w, map[string]string{"error": "Failed to create service"}, http.StatusInternalServerError)
+		return
+	}
+	util.SendData(w, created, http.StatusCreated)
+}
+
+func (h *ServiceHandler) ListServices(w http.ResponseWriter, r *http.Request) {
+	list, err := h.repo.List()
+	if err != nil {
+		log.Printf("Failed to list services: %v", err)
+		util.SendData(w, map[string]string{"error": "Failed to fetch services"}, http.StatusInternalServerError)
+		return
+	}
+	util.SendData(w, list, http.StatusOK)
+}
+
+func (h *ServiceHandler) UpdateService(w http.ResponseWriter, r *http.Request) {
+	id, err := strconv.Atoi(mux.Vars(r)["id"])
+	if err != nil {
+		util.SendData(w, map[string]string{"error": "Invalid service ID format"}, http.StatusBadRequest)
+		return
+	}
+
+	var s repo.Service
+	if err := json.NewDecoder(r.Body).Decode(&s); err != nil {
+		util.SendData(w, map[string]string{"error": "Invalid input format"}, http.StatusBadRequest)
+		return
+	}
+	s.ServiceID = id
+	s.Code = strings.ToLower(strings.TrimSpace(s.Code))
+	if s.Code == "" || s.Name == "" {
+		util.SendData(w, map[string]string{"error": "Service code and name are required"}, http.StatusBadRequest)
+		return
+	}
+
+	updated, err := h.repo.Update(s)
+	if err != nil {
+		switch {
+		case errors.Is(err, repo.ErrServiceNotFound):
+			util.SendData(w, map[string]string{"error": "Service not found"}, http.StatusNotFound)
+		case errors.Is(err, repo.ErrDuplicateService):
+			util.SendData(w, map[string]string{"error": "Service code already exists"}, http.StatusConflict)
+		default:
+			log.Printf("Failed to update service ID %d: %v", id, err)
+			util.SendData(w, map[string]string{"error": "Failed to update service"}, http.StatusInternalServerError)
+		}
+		return
+	}
+	util.SendData(w, updated, http.StatusOK)
+}
+
+func (h *ServiceHandler) DeleteService(w http.ResponseWriter, r *http.Request) {
+	id, err := strconv.Atoi(mux.Vars(r)["id"])
+	if err != nil {
+		util.SendData(w, map[string]string{"error": "Invalid service ID format"}, http.StatusBadRequest)
+		return
+	}
+
+	if err := h.repo.Delete(id); err != nil {
+		if errors.Is(err, repo.ErrServiceNotFound) {
+			util.SendData(w, map[string]string{"error": "Service not found"}, http.StatusNotFound)
+			return
+		}
+		log.Printf("Failed to delete service ID %d: %v", id, err)
+		util.SendData(w, map[string]string{"error": "Failed to delete service"}, http.StatusInternalServerError)
+		return
+	}
+	util.SendData(w, map[string]string{"message": "Service deleted successfully"}, http.StatusOK)
+}
+
+// List the services offered by a hospital
+func (h *ServiceHandler) ListHospitalServices(w http.ResponseWriter, r *http.Request) {
+	hospitalID, err := strconv.Atoi(mux.Vars(r)["id"])
+	if err != nil {
+		util.SendData(w, map[string]string{"error": "Invalid hospital ID format"}, http.StatusBadRequest)
+		return
+	}
+
+	list, err := h.repo.ListByHospital(hospitalID)
+	if err != nil {
+		log.Printf("Failed to list services of hospital ID %d: %v", hospitalID, err)
+		util.SendData(w, map[string]string{"error": "Failed to fetch services"}, http.StatusInternalServerError)
+		return
+	}
+	util.SendData(w, list, http.StatusOK)
+}
+
+// Add a service to a hospital, or update its notes
+func (h *ServiceHandler) AddHospitalService(w http.ResponseWriter, r *http.Request) {
+	hospitalID, err := strconv.Atoi(mux.Vars(r)["id"])
+	if err != nil {
+		util.SendData(w, map[string]string{"error": "Invalid hospital ID format"}, http.StatusBadRequest)
+		return
+	}
+
+	var body struct {
+		ServiceID int    `json:"service_id"`
+		Notes     string `json:"notes"`
+	}
+	if err := json.NewDecoder(r.Body).Decode(&body); err != nil || body.ServiceID <= 0 {
+		util.SendData(w, map[string]string{"error": "service_id is required"}, http.StatusBadRequest)
+		return
+	}
+
+	if err := h.repo.AddToHospital(hospitalID, body.ServiceID, body.Notes); err != nil {
+		log.Printf("Failed to add service %d to hospital %d: %v", body.ServiceID, hospitalID, err)
+		util.SendData(w, map[string]string{"error": "Failed to add service"}, http.StatusInternalServerError)
+		return
+	}
+	util.SendData(w, map[string]string{"message": "Service added successfully"}, http.StatusCreated)
+}
+
+// Remove a service from a hospital
+func (h *ServiceHandler) RemoveHospitalService(w http.ResponseWriter, r *http.Request) {
+	vars := mux.Vars(r)
+	hospitalID, errConv1 := strconv.Atoi(vars["id"])
+	serviceID, errConv2 := strconv.Atoi(vars["service_id"])
+	if errConv1 != nil || errConv2 != nil {
+		util.SendData(w, map[string]string{"error": "Invalid ID format"}, http.StatusBadRequest)
+		return
+	}
+
+	if err := h.repo.RemoveFromHospital(hospitalID, serviceID); err != nil {
+		if errors.Is(err, repo.ErrServiceNotFound) {
+			util.SendData(w, map[string]string{"error": "Hospital does not offer this service"}, http.StatusNotFound)
+			return
+		}
+		log.Printf("Failed to remove service %d from hospital %d: %v", serviceID, hospitalID, err)
+		util.SendData(w, map[string]string{"error": "Failed to remove service"}, http.StatusInternalServerError)
+		return
+	}
+	util.SendData(w, map[string]string{"message": "Service removed successfully"}, http.StatusOK)
+}
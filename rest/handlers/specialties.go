@@ -0,0 +1,203 @@
+package handlers
+
+import (
+	"encoding/json"
+	"errors"
+	"log"
+	"medidhaka/repo"
+	"medidhaka/util"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/gorilla/mux"
+)
+
+type SpecialtyHandler struct {
+	repo repo.SpecialtyRepo
+}
+
+func NewSpecialtyHandler(r repo.SpecialtyRepo) *SpecialtyHandler {
+	return &SpecialtyHandler{repo: r}
+}
+
+func (h *SpecialtyHandler) CreateSpecialty(w http.ResponseWriter, r *http.Request) {
+	var s repo.Specialty
+	if err := json.NewDecoder(r.Body).Decode(&s); err != nil {
+		util.SendData(w, map[string]string{"error": "Invalid input format"}, http.StatusBadRequest)
+		return
+	}
+	s.Code = strings.ToLower(strings.TrimSpace(s.Code))
+	if s.Code == "" || s.NameEn == "" {
+		util.SendData(w, map[string]string{"error": "Specialty code and name_en are required"}, http.StatusBadRequest)
+		return
+	}
+
+	created, err := h.repo.Create(s)
+	if err != nil {
+		if errors.Is(err, repo.ErrDuplicateSpecialty) {
+			util.SendData(w, map[string]string{"error": "Specialty code already exists"}, http.StatusConflict)
+			return
+		}
+		log.Printf("Failed to create specialty: %v", err)
+		util.SendData(w, map[string]string{"error": "Failed to create specialty"}, http.StatusInternalServerError)
+		return
+	}
+	util.SendData(w, created, http.StatusCreated)
+}
+
+func (h *SpecialtyHandler) ListSpecialties(w http.ResponseWriter, r *http.Request) {
+	query := r.URL.Query()
+	search := query.Get("search")
+	page, limit, offset := parsePagination(query, 50)
+
+	list, total, err := h.repo.List(search, offset, limit)
+	if err != nil {
+		log.Printf("Failed to list specialties: %v", err)
+		util.SendData(w, map[string]string{"error": "Failed to fetch specialties"}, http.StatusInternalServerError)
+		return
+	}
+
+	util.SendData(w, paginated(list, total, page, limit), http.StatusOK)
+}
+
+func (h *SpecialtyHandler) GetSpecialty(w http.ResponseWriter, r *http.Request) {
+	id, err := strconv.Atoi(mux.Vars(r)["id"])
+	if err != nil {
+		util.SendData(w, map[string]string{"error": "Invalid specialty ID format"}, http.StatusBadRequest)
+		return
+	}
+
+	s, err := h.repo.Get(id)
+	if err != nil {
+		if errors.Is(err, repo.ErrSpecialtyNotFound) {
+			util.SendData(w, map[string]string{"error": "Specialty not found"}, http.StatusNotFound)
+			return
+		}
+		log.Printf("Failed to get specialty ID %d: %v", id, err)
+		util.SendData(w, map[string]string{"error": "Server error"}, http.StatusInternalServerError)
+		return
+	}
+	util.SendData(w, s, http.StatusOK)
+}
+
+func (h *SpecialtyHandler) UpdateSpecialty(w http.ResponseWriter, r *http.Request) {
+	id, err := strconv.Atoi(mux.Vars(r)["id"])
+	if err != nil {
+		util.SendData(w, map[string]string{"error": "Invalid specialty ID format"}, http.StatusBadRequest)
+		return
+	}
+
+	var s repo.Specialty
+	if err := json.NewDecoder(r.Body).Decode(&s); err != nil {
+		util.SendData(w, map[string]string{"error": "Invalid input format"}, http.StatusBadRequest)
+		return
+	}
+	s.SpecialtyID = id
+	s.Code = strings.ToLower(strings.TrimSpace(s.Code))
+	if s.Code == "" || s.NameEn == "" {
+		util.SendData(w, map[string]string{"error": "Specialty code and name_en are required"}, http.StatusBadRequest)
+		return
+	}
+	if s.ParentID != nil && *s.ParentID == id {
+		util.SendData(w, map[string]string{"error": "A specialty cannot be its own parent"}, http.StatusBadRequest)
+		return
+	}
+
+	updated, err := h.repo.Update(s)
+	if err != nil {
+		switch {
+		case errors.Is(err, repo.ErrSpecialtyNotFound):
+			util.SendData(w, map[string]string{"error": "Specialty not found"}, http.StatusNotFound)
+		case errors.Is(err, repo.ErrDuplicateSpecialty):
+			util.SendData(w, map[string]string{"error": "Specialty code already exists"}, http.StatusConflict)
+		default:
+			log.Printf("Failed to update specialty ID %d: %v", id, err)
+			util.SendData(w, map[string]string{"error": "Failed to update specialty"}, http.StatusInternalServerError)
+		}
+		return
+	}
+	util.SendData(w, updated, http.StatusOK)
+}
+
+func (h *SpecialtyHandler) DeleteSpecialty(w http.ResponseWriter, r *http.Request) {
+	id, err := strconv.Atoi(mux.Vars(r)["id"])
+	if err != nil {
+		util.SendData(w, map[string]string{"error": "Invalid specialty ID format"}, http.StatusBadRequest)
+		return
+	}
+
+	if err := h.repo.Delete(id); err != nil {
+		if errors.Is(err, repo.ErrSpecialtyNotFound) {
+			util.SendData(w, map[string]string{"error": "Specialty not found"}, http.StatusNotFound)
+			return
+		}
+		log.Printf("Failed to delete specialty ID %d: %v", id, err)
+		util.SendData(w, map[string]string{"error": "Failed to delete specialty"}, http.StatusInternalServerError)
+		return
+	}
+	util.SendData(w, map[string]string{"message": "Specialty deleted successfully"}, http.StatusOK)
+}
+
+// List the specialties of a doctor
+func (h *SpecialtyHandler) ListDoctorSpecialties(w http.ResponseWriter, r *http.Request) {
+	doctorID, err := strconv.Atoi(mux.Vars(r)["id"])
+	if err != nil {
+		util.SendData(w, map[string]string{"error": "Invalid doctor ID format"}, http.StatusBadRequest)
+		return
+	}
+
+	list, err := h.repo.ListByDoctor(doctorID)
+	if err != nil {
+		log.Printf("Failed to list specialties of doctor ID %d: %v", doctorID, err)
+		util.SendData(w, map[string]string{"error": "Failed to fetch specialties"}, http.StatusInternalServerError)
+		return
+	}
+	util.SendData(w, list, http.StatusOK)
+}
+
+// Attach a specialty to a doctor
+func (h *SpecialtyHandler) AssignDoctorSpecialty(w http.ResponseWriter, r *http.Request) {
+	doctorID, err := strconv.Atoi(mux.Vars(r)["id"])
+	if err != nil {
+		util.SendData(w, map[string]string{"error": "Invalid doctor ID format"}, http.StatusBadRequest)
+		return
+	}
+
+	var body struct {
+		SpecialtyID int `json:"specialty_id"`
+	}
+	if err := json.NewDecoder(r.Body).Decode(&body); err != nil || body.SpecialtyID <= 0 {
+		util.SendData(w, map[string]string{"error": "specialty_id is required"}, http.StatusBadRequest)
+		return
+	}
+
+	if err := h.repo.AssignToDoctor(doctorID, body.SpecialtyID); err != nil {
+		log.Printf("Failed to assign specialty %d to doctor %d: %v", body.SpecialtyID, doctorID, err)
+		util.SendData(w, map[string]string{"error": "Failed to assign specialty"}, http.StatusInternalServerError)
+		return
+	}
+	util.SendData(w, map[string]string{"message": "Specialty assigned successfully"}, http.StatusCreated)
+}
+
+// Detach a specialty from a doctor
+func (h *SpecialtyHandler) RemoveDoctorSpecialty(w http.ResponseWriter, r *http.Request) {
+	vars := mux.Vars(r)
+	doctorID, errConv1 := strconv.Atoi(vars["id"])
+	specialtyID, errConv2 := strconv.Atoi(vars["specialty_id"])
+	if errConv1 != nil || errConv2 != nil {
+		util.SendData(w, map[string]string{"error": "Invalid ID format"}, http.StatusBadRequest)
+		return
+	}
+
+	if err := h.repo.RemoveFromDoctor(doctorID, specialtyID); err != nil {
+		if errors.Is(err, repo.ErrSpecialtyNotFound) {
+			util.SendData(w, map[string]string{"error": "Doctor does not have this specialty"}, http.StatusNotFound)
+			return
+		}
+		log.Printf("Failed to remove specialty %d from doctor %d: %v", specialtyID, doctorID, err)
+		util.SendData(w, map[string]string{"error": "Failed to remove specialty"}, http.StatusInternalServerError)
+		return
+	}
+	util.SendData(w, map[string]string{"message": "Specialty removed successfully"}, http.StatusOK)
+}
@@ -0,0 +1,102 @@
+package handlers
+
+import (
+	"encoding/json"
+	"errors"
+	"log"
+	"medidhaka/repo"
+	"medidhaka/util"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/gorilla/mux"
+)
+
+const staffKeyLength = 32
+
+type StaffKeyHandler struct {
+	repo repo.StaffKeyRepo
+}
+
+func NewStaffKeyHandler(r repo.StaffKeyRepo) *StaffKeyHandler {
+	return &StaffKeyHandler{repo: r}
+}
+
+// staffOwnsHospital checks that the request's staff key belongs to
+// hospitalID. On failure it writes the error response.
+func staffOwnsHospital(w http.ResponseWriter, r *http.Request, hospitalID int) bool {
+	staff, ok := util.Staff(r.Context())
+	if !ok || staff.HospitalID != hospitalID {
+		util.SendData(w, map[string]string{"error": "This key cannot update another hospital"}, http.StatusForbidden)
+		return false
+	}
+	return true
+}
+
+// Issue a staff key for a hospital. The key is only returned once.
+func (h *StaffKeyHandler) CreateStaffKey(w http.ResponseWriter, r *http.Request) {
+	hospitalID, err := strconv.Atoi(mux.Vars(r)["id"])
+	if err != nil {
+		util.SendData(w, map[string]string{"error": "Invalid hospital ID format"}, http.StatusBadRequest)
+		return
+	}
+	var body struct {
+		Label string `json:"label"`
+	}
+	if err := json.NewDecoder(r.Body).Decode(&body); err != nil {
+		util.SendData(w, map[string]string{"error": "Invalid input format"}, http.StatusBadRequest)
+		return
+	}
+
+	secret, err := util.RandomToken(staffKeyLength)
+	if err != nil {
+		log.Printf("Failed to generate staff key: %v", err)
+		util.SendData(w, map[string]string{"error": "Failed to create staff key"}, http.StatusInternalServerError)
+		return
+	}
+	key, err := h.repo.Create(hospitalID, strings.TrimSpace(body.Label), util.HashToken(secret))
+	if err != nil {
+		if errors.Is(err, repo.ErrNotFound) {
+			util.SendData(w, map[string]string{"error": "Hospital not found"}, http.StatusNotFound)
+			return
+		}
+		log.Printf("Failed to create staff key: %v", err)
+		util.SendData(w, map[string]string{"error": "Failed to create staff key"}, http.StatusInternalServerError)
+		return
+	}
+	util.SendData(w, map[string]interface{}{"key": secret, "staff_key": key}, http.StatusCreated)
+}
+
+func (h *StaffKeyHandler) ListStaffKeys(w http.ResponseWriter, r *http.Request) {
+	hospitalID, err := strconv.Atoi(mux.Vars(r)["id"])
+	if err != nil {
+		util.SendData(w, map[string]string{"error": "Invalid hospital ID format"}, http.StatusBadRequest)
+		return
+	}
+	list, err := h.repo.ListByHospital(hospitalID)
+	if err != nil {
+		log.Printf("Failed to list staff keys: %v", err)
+		util.SendData(w, map[string]string{"error": "Failed to fetch staff keys"}, http.StatusInternalServerError)
+		return
+	}
+	util.SendData(w, list, http.StatusOK)
+}
+
+func (h *StaffKeyHandler) RevokeStaffKey(w http.ResponseWriter, r *http.Request) {
+	id, err := strconv.Atoi(mux.Vars(r)["id"])
+	if err != nil {
+		util.SendData(w, map[string]string{"error": "Invalid staff key ID format"}, http.StatusBadRequest)
+		return
+	}
+	if err := h.repo.Revoke(id); err != nil {
+		if errors.Is(err, repo.ErrStaffKeyNotFound) {
+			util.SendData(w, map[string]string{"error": "Staff key not found or already revoked"}, http.StatusNotFound)
+			return
+		}
+		log.Printf("Failed to revoke staff key ID %d: %v", id, err)
+		util.SendData(w, map[string]string{"error": "Failed to revoke staff key"}, http.StatusInternalServerError)
+		return
+	}
+	util.SendData(w, map[string]string{"message": "Staff key revoked"}, http.StatusOK)
+}
@@ -0,0 +1,166 @@
+package handlers
+
+import (
+	"encoding/json"
+	"errors"
+	"log"
+	"medidhaka/repo"
+	"medidhaka/util"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/gorilla/mux"
+)
+
+type WaitlistHandler struct {
+	repo repo.WaitlistRepo
+}
+
+func NewWaitlistHandler(r repo.WaitlistRepo) *WaitlistHandler {
+	return &WaitlistHandler{repo: r}
+}
+
+// ownEntry loads the waitlist entry in the URL and checks that it belongs to
+// the logged-in patient. On failure it writes the error response.
+func (h *WaitlistHandler) ownEntry(w http.ResponseWriter, r *http.Request) (*repo.WaitlistEntry, bool) {
+	id, err := strconv.Atoi(mux.Vars(r)["id"])
+	if err != nil {
+		util.SendData(w, map[string]string{"error": "Invalid waitlist entry ID format"}, http.StatusBadRequest)
+		return nil, false
+	}
+	patientID, _ := util.PatientID(r.Context())
+
+	entry, err := h.repo.Get(id)
+	if err != nil || entry.PatientID != patientID {
+		if err == nil || errors.Is(err, repo.ErrWaitlistEntryNotFound) {
+			util.SendData(w, map[string]string{"error": "Waitlist entry not found"}, http.StatusNotFound)
+			return nil, false
+		}
+		log.Printf("Failed to get waitlist entry ID %d: %v", id, err)
+		util.SendData(w, map[string]string{"error": "Server error"}, http.StatusInternalServerError)
+		return nil, false
+	}
+	return entry, true
+}
+
+// Join the waitlist of a doctor at a hospital for a date
+func (h *WaitlistHandler) JoinWaitlist(w http.ResponseWriter, r *http.Request) {
+	var e repo.WaitlistEntry
+	if err := json.NewDecoder(r.Body).Decode(&e); err != nil {
+		util.SendData(w, map[string]string{"error": "Invalid input format"}, http.StatusBadRequest)
+		return
+	}
+	if e.HospitalID <= 0 || e.DoctorID <= 0 {
+		util.SendData(w, map[string]string{"error": "hospital_id and doctor_id are required"}, http.StatusBadRequest)
+		return
+	}
+	if _, err := time.Parse("2006-01-02", e.Date); err != nil || e.Date < util.TodayInDhaka() {
+		util.SendData(w, map[string]string{"error": "date must be today or later (YYYY-MM-DD)"}, http.StatusBadRequest)
+		return
+	}
+	e.PatientID, _ = util.PatientID(r.Context())
+
+	created, err := h.repo.Join(e)
+	if err != nil {
+		switch {
+		case errors.Is(err, repo.ErrAlreadyWaitlisted):
+			util.SendData(w, map[string]string{"error": "You are already on this waitlist"}, http.StatusConflict)
+		case errors.Is(err, repo.ErrNoAffiliation):
+			util.SendData(w, map[string]string{"error": "Doctor is not assigned to this hospital"}, http.StatusNotFound)
+		default:
+			log.Printf("Failed to join waitlist: %v", err)
+			util.SendData(w, map[string]string{"error": "Failed to join waitlist"}, http.StatusInternalServerError)
+		}
+		return
+	}
+	util.SendData(w, created, http.StatusCreated)
+}
+
+// Waitlist entries of the logged-in patient, with their queue positions
+func (h *WaitlistHandler) ListMyWaitlist(w http.ResponseWriter, r *http.Request) {
+	patientID, _ := util.PatientID(r.Context())
+	list, err := h.repo.ListByPatient(patientID)
+	if err != nil {
+		log.Printf("Failed to list waitlist of patient ID %d: %v", patientID, err)
+		util.SendData(w, map[string]string{"error": "Failed to fetch waitlist"}, http.StatusInternalServerError)
+		return
+	}
+	util.SendData(w, list, http.StatusOK)
+}
+
+func (h *WaitlistHandler) GetWaitlistEntry(w http.ResponseWriter, r *http.Request) {
+	entry, ok := h.ownEntry(w, r)
+	if !ok {
+		return
+	}
+	util.SendData(w, entry, http.StatusOK)
+}
+
+// Leave the waitlist; an outstanding offer moves on to the next patient
+func (h *WaitlistHandler) LeaveWaitlist(w http.ResponseWriter, r *http.Request) {
+	entry, ok := h.ownEntry(w, r)
+	if !ok {
+		return
+	}
+	if err := h.repo.Leave(entry.EntryID); err != nil {
+		if errors.Is(err, repo.ErrWaitlistEntryNotFound) {
+			util.SendData(w, map[string]string{"error": "Waitlist entry is no longer active"}, http.StatusConflict)
+			return
+		}
+		log.Printf("Failed to leave waitlist entry ID %d: %v", entry.EntryID, err)
+		util.SendData(w, map[string]string{"error": "Failed to leave waitlist"}, http.StatusInternalServerError)
+		return
+	}
+	util.SendData(w, map[string]string{"message": "Left the waitlist"}, http.StatusOK)
+}
+
+// Book the slot offered to a waitlist entry
+func (h *WaitlistHandler) ClaimOffer(w http.ResponseWriter, r *http.Request) {
+	entry, ok := h.ownEntry(w, r)
+	if !ok {
+		return
+	}
+	appointment, err := h.repo.Claim(entry.EntryID)
+	if err != nil {
+		switch {
+		case errors.Is(err, repo.ErrNoActiveOffer):
+			util.SendData(w, map[string]string{"error": "There is no active offer to claim"}, http.StatusConflict)
+		case errors.Is(err, repo.ErrSlotTaken):
+			util.SendData(w, map[string]string{"error": "Slot is already booked"}, http.StatusConflict)
+		default:
+			log.Printf("Failed to claim waitlist entry ID %d: %v", entry.EntryID, err)
+			util.SendData(w, map[string]string{"error": "Failed to claim offer"}, http.StatusInternalServerError)
+		}
+		return
+	}
+	util.SendData(w, appointment, http.StatusCreated)
+}
+
+// Waitlist of a hospital-doctor relation on a date, for the hospital's staff
+func (h *WaitlistHandler) ListRelationWaitlist(w http.ResponseWriter, r *http.Request) {
+	hospitalID, doctorID, ok := relationIDs(r)
+	if !ok {
+		util.SendData(w, map[string]string{"error": "Invalid ID format"}, http.StatusBadRequest)
+		return
+	}
+	if !staffOwnsHospital(w, r, hospitalID) {
+		return
+	}
+	day := r.URL.Query().Get("date")
+	if day == "" {
+		day = util.TodayInDhaka()
+	}
+	if _, err := time.Parse("2006-01-02", day); err != nil {
+		util.SendData(w, map[string]string{"error": "date must be YYYY-MM-DD"}, http.StatusBadRequest)
+		return
+	}
+
+	list, err := h.repo.ListByRelation(hospitalID, doctorID, day)
+	if err != nil {
+		log.Printf("Failed to list waitlist: %v", err)
+		util.SendData(w, map[string]string{"error": "Failed to fetch waitlist"}, http.StatusInternalServerError)
+		return
+	}
+	util.SendData(w, list, http.StatusOK)
+}
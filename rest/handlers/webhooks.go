@@ -0,0 +1,259 @@
+package handlers
+
+import (
+	"encoding/json"
+	"errors"
+	"log"
+	"medidhaka/repo"
+	"medidhaka/util"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+
+	"github.com/gorilla/mux"
+)
+
+const (
+	webhookSecretLength    = 32
+	minWebhookSecretLength = 16
+)
+
+type WebhookHandler struct {
+	repo repo.WebhookRepo
+}
+
+func NewWebhookHandler(r repo.WebhookRepo) *WebhookHandler {
+	return &WebhookHandler{repo: r}
+}
+
+// webhookBody is what an admin may set on a subscription. A nil Active keeps
+// the current state.
+type webhookBody struct {
+	URL         string   `json:"url"`
+	EventTypes  []string `json:"event_types"`
+	Description string   `json:"description"`
+	Secret      string   `json:"secret"`
+	Active      *bool    `json:"active"`
+}
+
+// validateWebhook copies body onto s and reports the first invalid field, if
+// any. Event types are lowercased and deduplicated.
+func validateWebhook(body webhookBody, s *repo.WebhookSubscription) string {
+	s.URL = strings.TrimSpace(body.URL)
+	u, err := url.Parse(s.URL)
+	if s.URL == "" || err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+		return "url must be an http or https URL"
+	}
+	s.EventTypes = nil
+	for _, t := range body.EventTypes {
+		t = strings.ToLower(strings.TrimSpace(t))
+		if !contains(repo.WebhookEventTypes, t) {
+			return "event_types must be from " + strings.Join(repo.WebhookEventTypes, ", ")
+		}
+		if !contains(s.EventTypes, t) {
+			s.EventTypes = append(s.EventTypes, t)
+		}
+	}
+	if len(s.EventTypes) == 0 {
+		return "event_types is required"
+	}
+	s.Description = strings.TrimSpace(body.Description)
+	s.Secret = body.Secret
+	if s.Secret != "" && len(s.Secret) < minWebhookSecretLength {
+		return "secret must be at least 16 characters"
+	}
+	if body.Active != nil {
+		s.Active = *body.Active
+	}
+	return ""
+}
+
+// Subscribe an endpoint to events. Without a secret one is generated; either
+// way it is only returned once.
+func (h *WebhookHandler) CreateWebhook(w http.ResponseWriter, r *http.Request) {
+	var body webhookBody
+	if err := json.NewDecoder(r.Body).Decode(&body); err != nil {
+		util.SendData(w, map[string]string{"error": "Invalid input format"}, http.StatusBadRequest)
+		return
+	}
+	var sub repo.WebhookSubscription
+	if msg := validateWebhook(body, &sub); msg != "" {
+		util.SendData(w, map[string]string{"error": msg}, http.StatusBadRequest)
+		return
+	}
+	if sub.Secret == "" {
+		secret, err := util.RandomToken(webhookSecretLength)
+		if err != nil {
+			log.Printf("Failed to generate webhook secret: %v", err)
+			util.SendData(w, map[string]string{"error": "Failed to create webhook"}, http.StatusInternalServerError)
+			return
+		}
+		sub.Secret = secret
+	}
+
+	created, err := h.repo.Create(sub)
+	if err != nil {
+		log.Printf("Failed to create webhook: %v", err)
+		util.SendData(w, map[string]string{"error": "Failed to create webhook"}, http.StatusInternalServerError)
+		return
+	}
+	util.SendData(w, map[string]interface{}{"secret": sub.Secret, "webhook": created}, http.StatusCreated)
+}
+
+func (h *WebhookHandler) ListWebhooks(w http.ResponseWriter, r *http.Request) {
+	page, limit, offset := parsePagination(r.URL.Query(), 20)
+	list, total, err := h.repo.List(offset, limit)
+	if err != nil {
+		log.Printf("Failed to list webhooks: %v", err)
+		util.SendData(w, map[string]string{"error": "Failed to fetch webhooks"}, http.StatusInternalServerError)
+		return
+	}
+	util.SendData(w, paginated(list, total, page, limit), http.StatusOK)
+}
+
+func (h *WebhookHandler) GetWebhook(w http.ResponseWriter, r *http.Request) {
+	id, err := strconv.Atoi(mux.Vars(r)["id"])
+	if err != nil {
+		util.SendData(w, map[string]string{"error": "Invalid webhook ID format"}, http.StatusBadRequest)
+		return
+	}
+	sub, err := h.repo.Get(id)
+	if err != nil {
+		if errors.Is(err, repo.ErrWebhookNotFound) {
+			util.SendData(w, map[string]string{"error": "Webhook not found"}, http.StatusNotFound)
+			return
+		}
+		log.Printf("Failed to get webhook ID %d: %v", id, err)
+		util.SendData(w, map[string]string{"error": "Failed to fetch webhook"}, http.StatusInternalServerError)
+		return
+	}
+	util.SendData(w, sub, http.StatusOK)
+}
+
+// Replace the URL, event types and description of a subscription, and its
+// secret if one is given. Setting active to true re-enables a disabled one.
+func (h *WebhookHandler) UpdateWebhook(w http.ResponseWriter, r *http.Request) {
+	id, err := strconv.Atoi(mux.Vars(r)["id"])
+	if err != nil {
+		util.SendData(w, map[string]string{"error": "Invalid webhook ID format"}, http.StatusBadRequest)
+		return
+	}
+	var body webhookBody
+	if err := json.NewDecoder(r.Body).Decode(&body); err != nil {
+		util.SendData(w, map[string]string{"error": "Invalid input format"}, http.StatusBadRequest)
+		return
+	}
+
+	sub, err := h.repo.Get(id)
+	if err == nil {
+		if msg := validateWebhook(body, sub); msg != "" {
+			util.SendData(w, map[string]string{"error": msg}, http.StatusBadRequest)
+			return
+		}
+		sub, err = h.repo.Update(*sub)
+	}
+	if err != nil {
+		if errors.Is(err, repo.ErrWebhookNotFound) {
+			util.SendData(w, map[string]string{"error": "Webhook not found"}, http.StatusNotFound)
+			return
+		}
+		log.Printf("Failed to update webhook ID %d: %v", id, err)
+		util.SendData(w, map[string]string{"error": "Failed to update webhook"}, http.StatusInternalServerError)
+		return
+	}
+	util.SendData(w, sub, http.StatusOK)
+}
+
+func (h *WebhookHandler) DeleteWebhook(w http.ResponseWriter, r *http.Request) {
+	id, err := strconv.Atoi(mux.Vars(r)["id"])
+	if err != nil {
+		util.SendData(w, map[string]string{"error": "Invalid webhook ID format"}, http.StatusBadRequest)
+		return
+	}
+	if err := h.repo.Delete(id); err != nil {
+		if errors.Is(err, repo.ErrWebhookNotFound) {
+			util.SendData(w, map[string]string{"error": "Webhook not found"}, http.StatusNotFound)
+			return
+		}
+		log.Printf("Failed to delete webhook ID %d: %v", id, err)
+		util.SendData(w, map[string]string{"error": "Failed to delete webhook"}, http.StatusInternalServerError)
+		return
+	}
+	util.SendData(w, map[string]string{"message": "Webhook deleted"}, http.StatusOK)
+}
+
+// The delivery log of a subscription, newest first.
+func (h *WebhookHandler) ListDeliveries(w http.ResponseWriter, r *http.Request) {
+	id, err := strconv.Atoi(mux.Vars(r)["id"])
+	if err != nil {
+		util.SendData(w, map[string]string{"error": "Invalid webhook ID format"}, http.StatusBadRequest)
+		return
+	}
+	query := r.URL.Query()
+	status := query.Get("status")
+	if status != "" && !contains(repo.DeliveryStatuses, status) {
+		util.SendData(w, map[string]string{"error": "status must be pending, succeeded or failed"}, http.StatusBadRequest)
+		return
+	}
+	page, limit, offset := parsePagination(query, 20)
+
+	if _, err := h.repo.Get(id); err != nil {
+		if errors.Is(err, repo.ErrWebhookNotFound) {
+			util.SendData(w, map[string]string{"error": "Webhook not found"}, http.StatusNotFound)
+			return
+		}
+		log.Printf("Failed to get webhook ID %d: %v", id, err)
+		util.SendData(w, map[string]string{"error": "Failed to fetch deliveries"}, http.StatusInternalServerError)
+		return
+	}
+	list, total, err := h.repo.ListDeliveries(id, status, offset, limit)
+	if err != nil {
+		log.Printf("Failed to list deliveries of webhook ID %d: %v", id, err)
+		util.SendData(w, map[string]string{"error": "Failed to fetch deliveries"}, http.StatusInternalServerError)
+		return
+	}
+	util.SendData(w, paginated(list, total, page, limit), http.StatusOK)
+}
+
+func (h *WebhookHandler) GetDelivery(w http.ResponseWriter, r *http.Request) {
+	id, err := strconv.ParseInt(mux.Vars(r)["id"], 10, 64)
+	if err != nil {
+		util.SendData(w, map[string]string{"error": "Invalid delivery ID format"}, http.StatusBadRequest)
+		return
+	}
+	d, err := h.repo.GetDelivery(id)
+	if err != nil {
+		if errors.Is(err, repo.ErrDeliveryNotFound) {
+			util.SendData(w, map[string]string{"error": "Delivery not found"}, http.StatusNotFound)
+			return
+		}
+		log.Printf("Failed to get webhook delivery ID %d: %v", id, err)
+		util.SendData(w, map[string]string{"error": "Failed to fetch delivery"}, http.StatusInternalServerError)
+		return
+	}
+	util.SendData(w, d, http.StatusOK)
+}
+
+// Send the event of a delivery again, as a new delivery due at once.
+func (h *WebhookHandler) ReplayDelivery(w http.ResponseWriter, r *http.Request) {
+	id, err := strconv.ParseInt(mux.Vars(r)["id"], 10, 64)
+	if err != nil {
+		util.SendData(w, map[string]string{"error": "Invalid delivery ID format"}, http.StatusBadRequest)
+		return
+	}
+	replay, err := h.repo.Replay(id)
+	if err != nil {
+		switch {
+		case errors.Is(err, repo.ErrDeliveryNotFound):
+			util.SendData(w, map[string]string{"error": "Delivery not found"}, http.StatusNotFound)
+		case errors.Is(err, repo.ErrWebhookDisabled):
+			util.SendData(w, map[string]string{"error": "Webhook is disabled; re-enable it before replaying"}, http.StatusConflict)
+		default:
+			log.Printf("Failed to replay webhook delivery ID %d: %v", id, err)
+			util.SendData(w, map[string]string{"error": "Failed to replay delivery"}, http.StatusInternalServerError)
+		}
+		return
+	}
+	util.SendData(w, replay, http.StatusCreated)
+}
@@ -0,0 +1,76 @@
+package middleware
+
+import (
+	"crypto/subtle"
+	"errors"
+	"log"
+	"medidhaka/repo"
+	"medidhaka/util"
+	"net/http"
+	"strings"
+)
+
+// PatientRole is the role claim carried by patient access tokens.
+const PatientRole = "patient"
+
+func bearerToken(r *http.Request) string {
+	header := r.Header.Get("Authorization")
+	if !strings.HasPrefix(header, "Bearer ") {
+		return ""
+	}
+	return strings.TrimSpace(strings.TrimPrefix(header, "Bearer "))
+}
+
+// RequirePatient rejects requests without a valid patient access token and
+// stores the patient's ID in the request context.
+func RequirePatient(secret string) Middleware {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			id, err := util.ParseAccessToken(secret, PatientRole, bearerToken(r))
+			if err != nil {
+				util.SendData(w, map[string]string{"error": "Unauthorized"}, http.StatusUnauthorized)
+				return
+			}
+			next.ServeHTTP(w, r.WithContext(util.WithPatientID(r.Context(), id)))
+		})
+	}
+}
+
+// RequireAdmin rejects requests whose X-Admin-Key header does not match key.
+// With an empty key every request is rejected.
+func RequireAdmin(key string) Middleware {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			given := r.Header.Get("X-Admin-Key")
+			if key == "" || subtle.ConstantTimeCompare([]byte(given), []byte(key)) != 1 {
+				util.SendData(w, map[string]string{"error": "Unauthorized"}, http.StatusUnauthorized)
+				return
+			}
+			next.ServeHTTP(w, r)
+		})
+	}
+}
+
+// RequireStaff rejects requests without an active hospital staff key in the
+// X-Staff-Key header and stores the key's identity in the request context.
+func RequireStaff(keys repo.StaffKeyRepo) Middleware {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			given := r.Header.Get("X-Staff-Key")
+			if given == "" {
+				util.SendData(w, map[string]string{"error": "Unauthorized"}, http.StatusUnauthorized)
+				return
+			}
+			key, err := keys.Authenticate(util.HashToken(given))
+			if err != nil {
+				if !errors.Is(err, repo.ErrStaffKeyNotFound) {
+					log.Printf("Failed to authenticate staff key: %v", err)
+				}
+				util.SendData(w, map[string]string{"error": "Unauthorized"}, http.StatusUnauthorized)
+				return
+			}
+			staff := util.StaffIdentity{KeyID: key.KeyID, HospitalID: key.HospitalID}
+			next.ServeHTTP(w, r.WithContext(util.WithStaff(r.Context(), staff)))
+		})
+	}
+}
@@ -6,7 +6,8 @@ func Cors(next http.Handler) http.Handler {
 	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
 		w.Header().Set("Access-Control-Allow-Origin", "*")
 		w.Header().Set("Access-Control-Allow-Methods", "GET, POST, PUT, PATCH, DELETE, OPTIONS")
-		w.Header().Set("Access-Control-Allow-Headers", "Content-Type, Authorization")
+		w.Header().Set("Access-Control-Allow-Headers", "Content-Type, Authorization, X-Admin-Key, X-Staff-Key")
+		w.Header().Set("Access-Control-Expose-Headers", "Deprecation, Sunset, Link")
 
 		if r.Method == http.MethodOptions {
 			w.WriteHeader(http.StatusNoContent)
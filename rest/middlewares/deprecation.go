@@ -0,0 +1,37 @@
+package middleware
+
+import (
+	"fmt"
+	"net/http"
+	"time"
+)
+
+// Deprecation describes a deprecated route: when it was deprecated, when it
+// stops working and what replaces it.
+type Deprecation struct {
+	Since     time.Time
+	Sunset    time.Time // zero when no removal date is set
+	Successor string    // path or URL of the replacement, if any
+}
+
+// SetHeaders announces the deprecation with the Deprecation (RFC 9745),
+// Sunset (RFC 8594) and successor-version Link headers.
+func (d Deprecation) SetHeaders(h http.Header) {
+	h.Set("Deprecation", fmt.Sprintf("@%d", d.Since.Unix()))
+	if !d.Sunset.IsZero() {
+		h.Set("Sunset", d.Sunset.UTC().Format(http.TimeFormat))
+	}
+	if d.Successor != "" {
+		h.Add("Link", fmt.Sprintf(`<%s>; rel="successor-version"`, d.Successor))
+	}
+}
+
+// Deprecated marks every response of a route as deprecated.
+func Deprecated(d Deprecation) Middleware {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			d.SetHeaders(w.Header())
+			next.ServeHTTP(w, r)
+		})
+	}
+}
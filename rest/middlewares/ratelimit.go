@@ -0,0 +1,33 @@
+package middleware
+
+import (
+	"medidhaka/infra/ratelimit"
+	"medidhaka/util"
+	"net"
+	"net/http"
+	"time"
+)
+
+// RateLimit rejects requests beyond the client's rate with 429. Clients are
+// told apart by their IP address, so that one client's connections share a
+// bucket.
+func RateLimit(l *ratelimit.Limiter) Middleware {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if !l.Allow(clientHost(r), time.Now()) {
+				w.Header().Set("Retry-After", "1")
+				util.SendData(w, map[string]string{"error": "Too many requests, please slow down"}, http.StatusTooManyRequests)
+				return
+			}
+			next.ServeHTTP(w, r)
+		})
+	}
+}
+
+func clientHost(r *http.Request) string {
+	host, _, err := net.SplitHostPort(r.RemoteAddr)
+	if err != nil {
+		return r.RemoteAddr
+	}
+	return host
+}
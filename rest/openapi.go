@@ -0,0 +1,571 @@
+package rest
+
+import (
+	"encoding/json"
+	"log"
+	"medidhaka/repo"
+	"net/http"
+	"reflect"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/gorilla/mux"
+)
+
+// apiOperation documents one route of initRoutes in the OpenAPI document.
+// Body and Response are sample values whose types are turned into schemas;
+// a nil Response documents a free-form JSON object.
+type apiOperation struct {
+	Method     string
+	Path       string
+	Tag        string
+	Summary    string
+	Auth       string // "", "admin", "staff" or "patient"
+	Query      []string
+	Body       interface{}
+	Response   interface{}
+	Status     int
+	Deprecated bool // the route sends Deprecation and Sunset headers
+}
+
+// Markers for responses that aren't a plain JSON value.
+type (
+	apiPage    struct{ item interface{} } // the paginated envelope around a list of item
+	apiMessage struct{}                   // {"message": "..."}
+	apiFile    string                     // a download with this content type
+	apiFHIR    string                     // a FHIR resource of this type
+)
+
+var listQuery = []string{"page", "limit"}
+
+// webhookBody is the request body of the webhook subscription routes.
+type webhookBody struct {
+	URL         string   `json:"url"`
+	EventTypes  []string `json:"event_types"`
+	Description string   `json:"description"`
+	Secret      string   `json:"secret,omitempty"`
+	Active      *bool    `json:"active,omitempty"`
+}
+
+// apiOperations must list every route registered in initRoutes;
+// reportUndocumentedRoutes logs the ones that are missing.
+var apiOperations = []apiOperation{
+	// Hospitals
+	{Method: "POST", Path: "/v1/hospitals", Tag: "Hospitals", Summary: "Create a hospital", Auth: "admin", Body: repo.Hospital{}, Response: repo.Hospital{}, Status: 201},
+	{Method: "GET", Path: "/v1/hospitals", Tag: "Hospitals", Summary: "List hospitals", Query: append([]string{"search", "service", "open_now", "emergency", "type", "licence", "min_beds", "accreditation", "sort"}, listQuery...), Response: apiPage{repo.Hospital{}}},
+	{Method: "GET", Path: "/v1/hospitals/export", Tag: "Hospitals", Summary: "Download every hospital matching the list filters", Query: []string{"search", "service", "open_now", "emergency", "type", "licence", "min_beds", "accreditation", "sort", "format", "compress"}, Response: apiFile("text/csv")},
+	{Method: "GET", Path: "/v1/hospitals/{id}", Tag: "Hospitals", Summary: "Get a hospital with its services, hours and insurance panels", Response: repo.Hospital{}},
+	{Method: "PUT", Path: "/v1/hospitals/{id}", Tag: "Hospitals", Summary: "Update a hospital", Auth: "admin", Body: repo.Hospital{}, Response: repo.Hospital{}},
+	{Method: "DELETE", Path: "/v1/hospitals/{id}", Tag: "Hospitals", Summary: "Delete a hospital", Auth: "admin", Response: apiMessage{}},
+	{Method: "POST", Path: "/v1/hospitals/{id}/image", Tag: "Hospitals", Summary: "Upload a hospital photo (multipart field image)", Auth: "staff", Body: apiFile("multipart/form-data"), Response: nil},
+	{Method: "DELETE", Path: "/v1/hospitals/{id}/image", Tag: "Hospitals", Summary: "Remove the hospital photo", Auth: "staff", Response: apiMessage{}},
+	{Method: "GET", Path: "/v1/hospitals/{id}/reviews", Tag: "Reviews", Summary: "Approved reviews of a hospital", Query: listQuery, Response: apiPage{repo.Review{}}},
+
+	// Services
+	{Method: "POST", Path: "/v1/services", Tag: "Services", Summary: "Create a service", Body: repo.Service{}, Response: repo.Service{}, Status: 201},
+	{Method: "GET", Path: "/v1/services", Tag: "Services", Summary: "List services", Response: []repo.Service{}},
+	{Method: "PUT", Path: "/v1/services/{id}", Tag: "Services", Summary: "Update a service", Body: repo.Service{}, Response: repo.Service{}},
+	{Method: "DELETE", Path: "/v1/services/{id}", Tag: "Services", Summary: "Delete a service", Response: apiMessage{}},
+	{Method: "GET", Path: "/v1/hospitals/{id}/services", Tag: "Services", Summary: "Services offered by a hospital", Response: []repo.HospitalService{}},
+	{Method: "POST", Path: "/v1/hospitals/{id}/services", Tag: "Services", Summary: "Add a service to a hospital", Body: struct {
+		ServiceID int    `json:"service_id"`
+		Notes     string `json:"notes"`
+	}{}, Response: apiMessage{}, Status: 201},
+	{Method: "DELETE", Path: "/v1/hospitals/{id}/services/{service_id}", Tag: "Services", Summary: "Remove a service from a hospital", Response: apiMessage{}},
+
+	// Insurance panels
+	{Method: "POST", Path: "/v1/insurance-panels", Tag: "Insurance", Summary: "Create an insurance panel", Auth: "admin", Body: repo.InsurancePanel{}, Response: repo.InsurancePanel{}, Status: 201},
+	{Method: "GET", Path: "/v1/insurance-panels", Tag: "Insurance", Summary: "List insurance panels", Response: []repo.InsurancePanel{}},
+	{Method: "DELETE", Path: "/v1/insurance-panels/{id}", Tag: "Insurance", Summary: "Delete an insurance panel", Auth: "admin", Response: apiMessage{}},
+	{Method: "GET", Path: "/v1/hospitals/{id}/insurance", Tag: "Insurance", Summary: "Panels accepted by a hospital", Response: []repo.HospitalInsurance{}},
+	{Method: "POST", Path: "/v1/hospitals/{id}/insurance", Tag: "Insurance", Summary: "Accept a panel at a hospital", Auth: "staff", Body: struct {
+		PanelID int    `json:"panel_id"`
+		Notes   string `json:"notes"`
+	}{}, Response: apiMessage{}, Status: 201},
+	{Method: "DELETE", Path: "/v1/hospitals/{id}/insurance/{panel_id}", Tag: "Insurance", Summary: "Stop accepting a panel", Auth: "staff", Response: apiMessage{}},
+
+	// Opening hours
+	{Method: "GET", Path: "/v1/hospitals/{id}/hours", Tag: "Opening Hours", Summary: "Weekly hours, upcoming overrides and whether the hospital is open now", Response: struct {
+		HospitalID  int                  `json:"hospital_id"`
+		Timezone    string               `json:"timezone"`
+		OpenNow     bool                 `json:"open_now"`
+		WeeklyHours []repo.OpeningHours  `json:"weekly_hours"`
+		Overrides   []repo.HoursOverride `json:"overrides"`
+	}{}},
+	{Method: "PUT", Path: "/v1/hospitals/{id}/hours", Tag: "Opening Hours", Summary: "Replace the weekly hours", Auth: "staff", Body: struct {
+		WeeklyHours []repo.OpeningHours `json:"weekly_hours"`
+	}{}, Response: []repo.OpeningHours{}},
+	{Method: "POST", Path: "/v1/hospitals/{id}/hour-overrides", Tag: "Opening Hours", Summary: "Override the hours on a date", Auth: "staff", Body: repo.HoursOverride{}, Response: repo.HoursOverride{}, Status: 201},
+	{Method: "DELETE", Path: "/v1/hour-overrides/{id}", Tag: "Opening Hours", Summary: "Delete an override", Auth: "staff", Response: apiMessage{}},
+
+	// Beds and blood
+	{Method: "GET", Path: "/v1/hospitals/availability", Tag: "Beds", Summary: "Hospitals with free beds in a ward", Query: append([]string{"ward"}, listQuery...), Response: apiPage{repo.BedAvailability{}}},
+	{Method: "GET", Path: "/v1/hospitals/{id}/beds", Tag: "Beds", Summary: "Bed inventory of a hospital", Response: []repo.BedInventory{}},
+	{Method: "PUT", Path: "/v1/hospitals/{id}/beds/{ward}", Tag: "Beds", Summary: "Update the bed count of a ward", Auth: "staff", Body: struct {
+		Total    *int `json:"total_beds"`
+		Occupied *int `json:"occupied_beds"`
+	}{}, Response: repo.BedInventory{}},
+	{Method: "GET", Path: "/v1/blood-banks", Tag: "Blood Banks", Summary: "Hospitals with blood in stock", Query: append([]string{"blood_group", "component"}, listQuery...), Response: apiPage{repo.BloodBank{}}},
+	{Method: "GET", Path: "/v1/hospitals/{id}/blood", Tag: "Blood Banks", Summary: "Blood stock of a hospital", Response: []repo.BloodStock{}},
+	{Method: "PUT", Path: "/v1/hospitals/{id}/blood", Tag: "Blood Banks", Summary: "Update the stock of a blood group and component", Auth: "staff", Body: struct {
+		BloodGroup     string `json:"blood_group"`
+		Component      string `json:"component"`
+		UnitsAvailable *int   `json:"units_available"`
+	}{}, Response: repo.BloodStock{}},
+
+	// Staff keys
+	{Method: "GET", Path: "/v1/admin/hospitals/{id}/staff-keys", Tag: "Staff Keys", Summary: "List a hospital's staff keys", Auth: "admin", Response: []repo.StaffKey{}},
+	{Method: "POST", Path: "/v1/admin/hospitals/{id}/staff-keys", Tag: "Staff Keys", Summary: "Issue a staff key; the key is only shown once", Auth: "admin", Body: struct {
+		Label string `json:"label"`
+	}{}, Response: nil, Status: 201},
+	{Method: "DELETE", Path: "/v1/admin/staff-keys/{id}", Tag: "Staff Keys", Summary: "Revoke a staff key", Auth: "admin", Response: apiMessage{}},
+
+	// Doctors
+	{Method: "POST", Path: "/v1/doctors", Tag: "Doctors", Summary: "Create a doctor", Auth: "admin", Body: repo.Doctor{}, Response: repo.Doctor{}, Status: 201},
+	{Method: "GET", Path: "/v1/doctors", Tag: "Doctors", Summary: "List verified doctors", Query: append([]string{"search", "max_fee", "insurance", "sort"}, listQuery...), Response: apiPage{repo.Doctor{}}},
+	{Method: "GET", Path: "/v1/doctors/export", Tag: "Doctors", Summary: "Download every verified doctor matching the list filters", Query: []string{"search", "max_fee", "insurance", "sort", "format", "compress"}, Response: apiFile("text/csv")},
+	{Method: "GET", Path: "/v1/doctors/nearby", Tag: "Doctors", Summary: "Verified doctors near a point with their closest hospital", Query: append([]string{"lat", "lng", "radius_km", "specialty"}, listQuery...), Response: apiPage{repo.NearbyDoctor{}}},
+	{Method: "GET", Path: "/v1/doctors/{id}", Tag: "Doctors", Summary: "Get a doctor with qualifications", Response: repo.Doctor{}},
+	{Method: "PUT", Path: "/v1/doctors/{id}", Tag: "Doctors", Summary: "Update a doctor", Auth: "admin", Body: repo.Doctor{}, Response: repo.Doctor{}},
+	{Method: "DELETE", Path: "/v1/doctors/{id}", Tag: "Doctors", Summary: "Delete a doctor", Auth: "admin", Response: apiMessage{}},
+	{Method: "POST", Path: "/v1/doctors/{id}/image", Tag: "Doctors", Summary: "Upload a doctor photo (multipart field image)", Auth: "admin", Body: apiFile("multipart/form-data"), Response: nil},
+	{Method: "DELETE", Path: "/v1/doctors/{id}/image", Tag: "Doctors", Summary: "Remove the doctor photo", Auth: "admin", Response: apiMessage{}},
+	{Method: "GET", Path: "/v1/doctors/{id}/reviews", Tag: "Reviews", Summary: "Approved reviews of a doctor", Query: listQuery, Response: apiPage{repo.Review{}}},
+
+	// Credentials
+	{Method: "GET", Path: "/v1/doctors/{id}/qualifications", Tag: "Doctor Credentials", Summary: "Qualifications of a doctor", Response: []repo.Qualification{}},
+	{Method: "POST", Path: "/v1/doctors/{id}/qualifications", Tag: "Doctor Credentials", Summary: "Add a qualification", Body: repo.Qualification{}, Response: repo.Qualification{}, Status: 201},
+	{Method: "DELETE", Path: "/v1/doctors/{id}/qualifications/{qualification_id}", Tag: "Doctor Credentials", Summary: "Delete a qualification", Response: apiMessage{}},
+	{Method: "POST", Path: "/v1/doctors/{id}/verification", Tag: "Doctor Credentials", Summary: "Submit the BMDC registration for verification", Response: repo.Doctor{}},
+	{Method: "GET", Path: "/v1/admin/doctors", Tag: "Doctor Credentials", Summary: "Doctors by verification status (pending by default)", Auth: "admin", Query: append([]string{"status", "search", "max_fee", "insurance", "sort"}, listQuery...), Response: apiPage{repo.Doctor{}}},
+	{Method: "PATCH", Path: "/v1/admin/doctors/{id}/verification", Tag: "Doctor Credentials", Summary: "Verify or reject a doctor", Auth: "admin", Body: struct {
+		Status string `json:"status"`
+		Note   string `json:"note"`
+	}{}, Response: repo.Doctor{}},
+
+	// Specialties
+	{Method: "POST", Path: "/v1/specialties", Tag: "Specialties", Summary: "Create a specialty", Body: repo.Specialty{}, Response: repo.Specialty{}, Status: 201},
+	{Method: "GET", Path: "/v1/specialties", Tag: "Specialties", Summary: "List specialties", Query: append([]string{"search"}, listQuery...), Response: apiPage{repo.Specialty{}}},
+	{Method: "GET", Path: "/v1/specialties/{id}", Tag: "Specialties", Summary: "Get a specialty", Response: repo.Specialty{}},
+	{Method: "PUT", Path: "/v1/specialties/{id}", Tag: "Specialties", Summary: "Update a specialty", Body: repo.Specialty{}, Response: repo.Specialty{}},
+	{Method: "DELETE", Path: "/v1/specialties/{id}", Tag: "Specialties", Summary: "Delete a specialty", Response: apiMessage{}},
+	{Method: "GET", Path: "/v1/doctors/{id}/specialties", Tag: "Specialties", Summary: "Specialties of a doctor", Response: []repo.Specialty{}},
+	{Method: "POST", Path: "/v1/doctors/{id}/specialties", Tag: "Specialties", Summary: "Assign a specialty to a doctor", Body: struct {
+		SpecialtyID int `json:"specialty_id"`
+	}{}, Response: apiMessage{}, Status: 201},
+	{Method: "DELETE", Path: "/v1/doctors/{id}/specialties/{specialty_id}", Tag: "Specialties", Summary: "Remove a specialty from a doctor", Response: apiMessage{}},
+
+	// Hospital-doctor relations
+	{Method: "POST", Path: "/v1/hospital-doctor", Tag: "Hospital-Doctor", Summary: "Assign a doctor to a hospital, optionally with fees", Auth: "admin", Body: repo.HospitalDoctor{}, Response: apiMessage{}, Status: 201},
+	{Method: "GET", Path: "/v1/hospital-doctor/{id}", Tag: "Hospital-Doctor", Summary: "Verified doctors assigned to a hospital (a bare array, not paginated)", Response: []repo.Doctor{}},
+	{Method: "DELETE", Path: "/v1/hospital-doctor/{hospital_id}/{doctor_id}", Tag: "Hospital-Doctor", Summary: "Remove a doctor from a hospital", Auth: "admin", Response: apiMessage{}},
+	{Method: "GET", Path: "/v1/hospital-doctor/{hospital_id}/{doctor_id}/fees", Tag: "Hospital-Doctor", Summary: "Consultation fees of a doctor at a hospital", Response: repo.HospitalDoctor{}},
+	{Method: "PUT", Path: "/v1/hospital-doctor/{hospital_id}/{doctor_id}/fees", Tag: "Hospital-Doctor", Summary: "Replace the fees", Auth: "staff", Body: repo.HospitalDoctor{}, Response: repo.HospitalDoctor{}},
+
+	// Schedules
+	{Method: "GET", Path: "/v1/hospital-doctor/{hospital_id}/{doctor_id}/schedules", Tag: "Schedules", Summary: "Weekly chamber sessions of an affiliation", Response: []repo.Schedule{}},
+	{Method: "POST", Path: "/v1/hospital-doctor/{hospital_id}/{doctor_id}/schedules", Tag: "Schedules", Summary: "Add a weekly session", Auth: "staff", Body: repo.Schedule{}, Response: repo.Schedule{}, Status: 201},
+	{Method: "GET", Path: "/v1/hospital-doctor/{hospital_id}/{doctor_id}/exceptions", Tag: "Schedules", Summary: "Dated exceptions of an affiliation", Response: []repo.ScheduleException{}},
+	{Method: "POST", Path: "/v1/hospital-doctor/{hospital_id}/{doctor_id}/exceptions", Tag: "Schedules", Summary: "Add a dated exception", Auth: "staff", Body: repo.ScheduleException{}, Response: repo.ScheduleException{}, Status: 201},
+	{Method: "PUT", Path: "/v1/schedules/{id}", Tag: "Schedules", Summary: "Update a weekly session", Auth: "staff", Body: repo.Schedule{}, Response: repo.Schedule{}},
+	{Method: "DELETE", Path: "/v1/schedules/{id}", Tag: "Schedules", Summary: "Delete a weekly session", Auth: "staff", Response: apiMessage{}},
+	{Method: "DELETE", Path: "/v1/schedule-exceptions/{id}", Tag: "Schedules", Summary: "Delete an exception", Auth: "staff", Response: apiMessage{}},
+	{Method: "GET", Path: "/v1/doctors/{id}/schedule", Tag: "Schedules", Summary: "A doctor's sessions across hospitals", Query: []string{"from", "days"}, Response: nil},
+	{Method: "GET", Path: "/v1/hospitals/{id}/schedule", Tag: "Schedules", Summary: "Sessions of all doctors at a hospital", Query: []string{"from", "days"}, Response: nil},
+
+	// Appointments
+	{Method: "GET", Path: "/v1/hospital-doctor/{hospital_id}/{doctor_id}/slots", Tag: "Appointments", Summary: "Bookable slots on a date", Query: []string{"date"}, Response: struct {
+		Date     string      `json:"date"`
+		Timezone string      `json:"timezone"`
+		Slots    []repo.Slot `json:"slots"`
+	}{}},
+	{Method: "POST", Path: "/v1/appointments", Tag: "Appointments", Summary: "Book an appointment", Auth: "patient", Body: repo.Appointment{}, Response: repo.Appointment{}, Status: 201},
+	{Method: "GET", Path: "/v1/appointments/{id}", Tag: "Appointments", Summary: "Get an appointment", Auth: "patient", Response: repo.Appointment{}},
+	{Method: "POST", Path: "/v1/appointments/{id}/cancel", Tag: "Appointments", Summary: "Cancel an appointment", Auth: "patient", Response: repo.Appointment{}},
+	{Method: "POST", Path: "/v1/appointments/{id}/reschedule", Tag: "Appointments", Summary: "Move an appointment to another slot", Auth: "patient", Body: struct {
+		Date      string `json:"date"`
+		StartTime string `json:"start_time"`
+	}{}, Response: repo.Appointment{}},
+	{Method: "PATCH", Path: "/v1/appointments/{id}/status", Tag: "Appointments", Summary: "Update the visit status", Auth: "staff", Body: struct {
+		Status string `json:"status"`
+	}{}, Response: repo.Appointment{}},
+	{Method: "GET", Path: "/v1/doctors/{id}/appointments", Tag: "Appointments", Summary: "Appointments of a doctor at the staff key's hospital", Auth: "staff", Query: append([]string{"date", "status"}, listQuery...), Response: apiPage{repo.Appointment{}}},
+	{Method: "GET", Path: "/v1/hospitals/{id}/appointments", Tag: "Appointments", Summary: "Appointments at a hospital", Auth: "staff", Query: append([]string{"date", "status"}, listQuery...), Response: apiPage{repo.Appointment{}}},
+	{Method: "GET", Path: "/v1/patients/me/appointments", Tag: "Appointments", Summary: "Current patient's appointments", Auth: "patient", Query: listQuery, Response: apiPage{repo.Appointment{}}},
+
+	// Waitlist
+	{Method: "GET", Path: "/v1/hospital-doctor/{hospital_id}/{doctor_id}/waitlist", Tag: "Waitlist", Summary: "Waitlist of an affiliation", Auth: "staff", Query: []string{"date"}, Response: []repo.WaitlistEntry{}},
+	{Method: "POST", Path: "/v1/waitlist", Tag: "Waitlist", Summary: "Join the waitlist for a full day", Auth: "patient", Body: repo.WaitlistEntry{}, Response: repo.WaitlistEntry{}, Status: 201},
+	{Method: "GET", Path: "/v1/waitlist/{id}", Tag: "Waitlist", Summary: "Get an own waitlist entry", Auth: "patient", Response: repo.WaitlistEntry{}},
+	{Method: "DELETE", Path: "/v1/waitlist/{id}", Tag: "Waitlist", Summary: "Leave the waitlist", Auth: "patient", Response: apiMessage{}},
+	{Method: "POST", Path: "/v1/waitlist/{id}/claim", Tag: "Waitlist", Summary: "Claim an offered slot", Auth: "patient", Response: repo.Appointment{}, Status: 201},
+	{Method: "GET", Path: "/v1/patients/me/waitlist", Tag: "Waitlist", Summary: "Current patient's waitlist entries", Auth: "patient", Response: []repo.WaitlistEntry{}},
+
+	// Queues
+	{Method: "GET", Path: "/v1/queues/{hospital_id}/{doctor_id}/today", Tag: "Queues", Summary: "Today's walk-in queue status", Response: repo.QueueStatus{}},
+	{Method: "GET", Path: "/v1/queues/{hospital_id}/{doctor_id}/today/events", Tag: "Queues", Summary: "Server-sent events with the queue status", Response: apiFile("text/event-stream")},
+	{Method: "GET", Path: "/v1/queues/{hospital_id}/{doctor_id}/today/serials", Tag: "Queues", Summary: "Serials issued today", Auth: "staff", Response: []repo.QueueSerial{}},
+	{Method: "POST", Path: "/v1/queues/{hospital_id}/{doctor_id}/today/serials", Tag: "Queues", Summary: "Issue a walk-in serial", Auth: "patient", Body: repo.QueueSerial{}, Response: repo.QueueSerial{}, Status: 201},
+	{Method: "POST", Path: "/v1/queues/{hospital_id}/{doctor_id}/today/next", Tag: "Queues", Summary: "Call the next waiting serial", Auth: "staff", Response: repo.QueueStatus{}},
+	{Method: "POST", Path: "/v1/queues/{hospital_id}/{doctor_id}/today/serials/{serial}/call", Tag: "Queues", Summary: "Call a serial", Auth: "staff", Response: repo.QueueStatus{}},
+	{Method: "POST", Path: "/v1/queues/{hospital_id}/{doctor_id}/today/serials/{serial}/recall", Tag: "Queues", Summary: "Call a serial again; deprecated, use call", Auth: "staff", Response: repo.QueueStatus{}, Deprecated: true},
+	{Method: "POST", Path: "/v1/queues/{hospital_id}/{doctor_id}/today/serials/{serial}/skip", Tag: "Queues", Summary: "Skip a serial", Auth: "staff", Response: repo.QueueStatus{}},
+
+	// Reviews
+	{Method: "POST", Path: "/v1/reviews", Tag: "Reviews", Summary: "Review a completed visit", Auth: "patient", Body: struct {
+		AppointmentID int    `json:"appointment_id"`
+		Rating        int    `json:"rating"`
+		Text          string `json:"text"`
+	}{}, Response: repo.Review{}, Status: 201},
+	{Method: "GET", Path: "/v1/patients/me/reviews", Tag: "Reviews", Summary: "Current patient's reviews", Auth: "patient", Query: listQuery, Response: apiPage{repo.Review{}}},
+	{Method: "GET", Path: "/v1/admin/reviews", Tag: "Reviews", Summary: "Moderation queue", Auth: "admin", Query: append([]string{"status"}, listQuery...), Response: apiPage{repo.Review{}}},
+	{Method: "PATCH", Path: "/v1/admin/reviews/{id}", Tag: "Reviews", Summary: "Approve or reject a review", Auth: "admin", Body: struct {
+		Status string `json:"status"`
+		Note   string `json:"note"`
+	}{}, Response: repo.Review{}},
+
+	// Patients
+	{Method: "POST", Path: "/v1/auth/otp/request", Tag: "Patients", Summary: "Send a login code", Body: struct {
+		PhoneNumber string `json:"phone_number"`
+	}{}, Response: apiMessage{}},
+	{Method: "POST", Path: "/v1/auth/otp/verify", Tag: "Patients", Summary: "Exchange a login code for tokens", Body: struct {
+		PhoneNumber string `json:"phone_number"`
+		Code        string `json:"code"`
+	}{}, Response: nil},
+	{Method: "POST", Path: "/v1/auth/refresh", Tag: "Patients", Summary: "Rotate a refresh token", Body: struct {
+		RefreshToken string `json:"refresh_token"`
+	}{}, Response: nil},
+	{Method: "POST", Path: "/v1/auth/logout", Tag: "Patients", Summary: "Revoke a refresh token", Body: struct {
+		RefreshToken string `json:"refresh_token"`
+	}{}, Response: apiMessage{}},
+	{Method: "GET", Path: "/v1/patients/me", Tag: "Patients", Summary: "Current patient's profile", Auth: "patient", Response: repo.Patient{}},
+	{Method: "PUT", Path: "/v1/patients/me", Tag: "Patients", Summary: "Update the profile", Auth: "patient", Body: repo.Patient{}, Response: repo.Patient{}},
+
+	// Search, import and media
+	{Method: "GET", Path: "/v1/search", Tag: "Search", Summary: "Search doctors and hospitals by name", Query: []string{"q"}, Response: nil},
+	{Method: "POST", Path: "/v1/import", Tag: "Import", Summary: "Import hospitals, doctors or affiliations from CSV", Auth: "admin", Query: []string{"kind", "dry_run", "atomic", "chunk_size"}, Body: apiFile("text/csv"), Response: repo.ImportReport{}},
+	{Method: "GET", Path: "/media/{key}", Tag: "Media", Summary: "An uploaded photo or one of its resized variants", Response: apiFile("image/*")},
+
+	// Webhooks
+	{Method: "GET", Path: "/v1/admin/webhooks", Tag: "Webhooks", Summary: "List webhook subscriptions", Auth: "admin", Query: listQuery, Response: apiPage{repo.WebhookSubscription{}}},
+	{Method: "POST", Path: "/v1/admin/webhooks", Tag: "Webhooks", Summary: "Subscribe an endpoint to events; the secret is only shown once", Auth: "admin", Body: webhookBody{}, Response: nil, Status: 201},
+	{Method: "GET", Path: "/v1/admin/webhooks/{id}", Tag: "Webhooks", Summary: "Get a webhook subscription", Auth: "admin", Response: repo.WebhookSubscription{}},
+	{Method: "PUT", Path: "/v1/admin/webhooks/{id}", Tag: "Webhooks", Summary: "Update a webhook subscription; active re-enables a disabled one", Auth: "admin", Body: webhookBody{}, Response: repo.WebhookSubscription{}},
+	{Method: "DELETE", Path: "/v1/admin/webhooks/{id}", Tag: "Webhooks", Summary: "Delete a webhook subscription and its delivery log", Auth: "admin", Response: apiMessage{}},
+	{Method: "GET", Path: "/v1/admin/webhooks/{id}/deliveries", Tag: "Webhooks", Summary: "Delivery log of a subscription, newest first", Auth: "admin", Query: append([]string{"status"}, listQuery...), Response: apiPage{repo.WebhookDelivery{}}},
+	{Method: "GET", Path: "/v1/admin/webhook-deliveries/{id}", Tag: "Webhooks", Summary: "Get a webhook delivery", Auth: "admin", Response: repo.WebhookDelivery{}},
+	{Method: "POST", Path: "/v1/admin/webhook-deliveries/{id}/replay", Tag: "Webhooks", Summary: "Send a delivery's event again as a new delivery", Auth: "admin", Response: repo.WebhookDelivery{}, Status: 201},
+
+	// FHIR R4
+	{Method: "GET", Path: "/fhir/R4/metadata", Tag: "FHIR R4", Summary: "CapabilityStatement", Response: apiFHIR("CapabilityStatement")},
+	{Method: "GET", Path: "/fhir/R4/Organization", Tag: "FHIR R4", Summary: "Search hospitals", Query: []string{"name", "_count", "_offset"}, Response: apiFHIR("Bundle")},
+	{Method: "GET", Path: "/fhir/R4/Organization/{id}", Tag: "FHIR R4", Summary: "Read a hospital", Response: apiFHIR("Organization")},
+	{Method: "GET", Path: "/fhir/R4/Location", Tag: "FHIR R4", Summary: "Search hospital locations", Query: []string{"name", "organization", "_count", "_offset"}, Response: apiFHIR("Bundle")},
+	{Method: "GET", Path: "/fhir/R4/Location/{id}", Tag: "FHIR R4", Summary: "Read a hospital location", Response: apiFHIR("Location")},
+	{Method: "GET", Path: "/fhir/R4/Practitioner", Tag: "FHIR R4", Summary: "Search doctors", Query: []string{"name", "specialty", "_count", "_offset"}, Response: apiFHIR("Bundle")},
+	{Method: "GET", Path: "/fhir/R4/Practitioner/{id}", Tag: "FHIR R4", Summary: "Read a doctor", Response: apiFHIR("Practitioner")},
+	{Method: "GET", Path: "/fhir/R4/PractitionerRole", Tag: "FHIR R4", Summary: "Search affiliations", Query: []string{"organization", "practitioner", "specialty", "_count", "_offset"}, Response: apiFHIR("Bundle")},
+	{Method: "GET", Path: "/fhir/R4/PractitionerRole/{id}", Tag: "FHIR R4", Summary: "Read an affiliation", Response: apiFHIR("PractitionerRole")},
+
+	// GraphQL
+	{Method: "GET", Path: "/graphql", Tag: "GraphQL", Summary: "Run a GraphQL query passed as the query, operationName and variables parameters", Query: []string{"query", "operationName", "variables"}, Response: nil},
+	{Method: "POST", Path: "/graphql", Tag: "GraphQL", Summary: "Run a GraphQL query", Body: struct {
+		Query         string                 `json:"query"`
+		OperationName string                 `json:"operationName,omitempty"`
+		Variables     map[string]interface{} `json:"variables,omitempty"`
+	}{}, Response: nil},
+
+	// Documentation
+	{Method: "GET", Path: "/openapi.json", Tag: "Documentation", Summary: "This OpenAPI document", Response: nil},
+	{Method: "GET", Path: "/docs", Tag: "Documentation", Summary: "Interactive API documentation", Response: apiFile("text/html")},
+}
+
+// specBuilder turns Go types into OpenAPI schemas, collecting named structs
+// as components.
+type specBuilder struct {
+	schemas map[string]interface{}
+}
+
+var timeType = reflect.TypeOf(time.Time{})
+
+func (b *specBuilder) schema(t reflect.Type) map[string]interface{} {
+	switch {
+	case t == timeType:
+		return map[string]interface{}{"type": "string", "format": "date-time"}
+	case t.Kind() == reflect.Pointer:
+		s := b.schema(t.Elem())
+		if _, ok := s["$ref"]; ok {
+			return map[string]interface{}{"allOf": []interface{}{s}, "nullable": true}
+		}
+		s["nullable"] = true
+		return s
+	}
+
+	switch t.Kind() {
+	case reflect.String:
+		return map[string]interface{}{"type": "string"}
+	case reflect.Bool:
+		return map[string]interface{}{"type": "boolean"}
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return map[string]interface{}{"type": "integer"}
+	case reflect.Float32, reflect.Float64:
+		return map[string]interface{}{"type": "number"}
+	case reflect.Slice, reflect.Array:
+		return map[string]interface{}{"type": "array", "items": b.schema(t.Elem())}
+	case reflect.Map:
+		return map[string]interface{}{"type": "object", "additionalProperties": b.schema(t.Elem())}
+	case reflect.Struct:
+		if t.Name() == "" {
+			return b.structSchema(t)
+		}
+		if _, ok := b.schemas[t.Name()]; !ok {
+			b.schemas[t.Name()] = nil // placeholder for recursive types
+			b.schemas[t.Name()] = b.structSchema(t)
+		}
+		return map[string]interface{}{"$ref": "#/components/schemas/" + t.Name()}
+	}
+	return map[string]interface{}{}
+}
+
+func (b *specBuilder) structSchema(t reflect.Type) map[string]interface{} {
+	properties := map[string]interface{}{}
+	required := []string{}
+	var addFields func(t reflect.Type)
+	addFields = func(t reflect.Type) {
+		for i := 0; i < t.NumField(); i++ {
+			f := t.Field(i)
+			tag := f.Tag.Get("json")
+			if f.Anonymous && tag == "" {
+				addFields(f.Type)
+				continue
+			}
+			name, opts, _ := strings.Cut(tag, ",")
+			if !f.IsExported() || name == "-" {
+				continue
+			}
+			if name == "" {
+				name = f.Name
+			}
+			properties[name] = b.schema(f.Type)
+			if !strings.Contains(opts, "omitempty") && f.Type.Kind() != reflect.Pointer {
+				required = append(required, name)
+			}
+		}
+	}
+	addFields(t)
+
+	s := map[string]interface{}{"type": "object", "properties": properties}
+	if len(required) > 0 {
+		s["required"] = required
+	}
+	return s
+}
+
+func jsonContent(schema interface{}) map[string]interface{} {
+	return map[string]interface{}{"application/json": map[string]interface{}{"schema": schema}}
+}
+
+// content describes the body of an operation's response or request.
+func (b *specBuilder) content(v interface{}) map[string]interface{} {
+	switch v := v.(type) {
+	case nil:
+		return jsonContent(map[string]interface{}{"type": "object"})
+	case apiPage:
+		return jsonContent(map[string]interface{}{"allOf": []interface{}{
+			map[string]interface{}{"$ref": "#/components/schemas/Page"},
+			map[string]interface{}{
+				"type":       "object",
+				"properties": map[string]interface{}{"data": map[string]interface{}{"type": "array", "items": b.schema(reflect.TypeOf(v.item))}},
+			},
+		}})
+	case apiMessage:
+		return jsonContent(map[string]interface{}{"$ref": "#/components/schemas/Message"})
+	case apiFile:
+		return map[string]interface{}{string(v): map[string]interface{}{"schema": map[string]interface{}{"type": "string", "format": "binary"}}}
+	case apiFHIR:
+		return map[string]interface{}{"application/fhir+json": map[string]interface{}{"schema": map[string]interface{}{
+			"type":        "object",
+			"description": "FHIR R4 " + string(v) + " resource",
+		}}}
+	}
+	return jsonContent(b.schema(reflect.TypeOf(v)))
+}
+
+var pathParamPattern = regexp.MustCompile(`\{([a-z_]+)\}`)
+
+// buildOpenAPI generates the OpenAPI 3 document from apiOperations.
+func buildOpenAPI(version string) map[string]interface{} {
+	b := &specBuilder{schemas: map[string]interface{}{
+		"Error":   map[string]interface{}{"type": "object", "properties": map[string]interface{}{"error": map[string]interface{}{"type": "string"}}, "required": []string{"error"}},
+		"Message": map[string]interface{}{"type": "object", "properties": map[string]interface{}{"message": map[string]interface{}{"type": "string"}}},
+		"Page": map[string]interface{}{
+			"type":        "object",
+			"description": "Envelope of the paginated listings",
+			"properties": map[string]interface{}{
+				"data":       map[string]interface{}{"type": "array", "items": map[string]interface{}{}},
+				"total":      map[string]interface{}{"type": "integer"},
+				"page":       map[string]interface{}{"type": "integer"},
+				"limit":      map[string]interface{}{"type": "integer"},
+				"totalPages": map[string]interface{}{"type": "integer"},
+			},
+			"required": []string{"data", "total", "page", "limit", "totalPages"},
+		},
+	}}
+	security := map[string][]interface{}{
+		"admin":   {map[string][]string{"adminKey": {}}},
+		"staff":   {map[string][]string{"staffKey": {}}},
+		"patient": {map[string][]string{"bearerAuth": {}}},
+	}
+
+	paths := map[string]map[string]interface{}{}
+	for _, op := range apiOperations {
+		var params []interface{}
+		for _, m := range pathParamPattern.FindAllStringSubmatch(op.Path, -1) {
+			schema := map[string]interface{}{"type": "string"}
+			if (m[1] == "id" || strings.HasSuffix(m[1], "_id") || m[1] == "serial") && !strings.HasPrefix(op.Path, "/fhir/") {
+				schema = map[string]interface{}{"type": "integer"}
+			}
+			params = append(params, map[string]interface{}{"name": m[1], "in": "path", "required": true, "schema": schema})
+		}
+		for _, q := range op.Query {
+			params = append(params, map[string]interface{}{"name": q, "in": "query", "schema": map[string]interface{}{"type": "string"}})
+		}
+
+		status := op.Status
+		if status == 0 {
+			status = http.StatusOK
+		}
+		operation := map[string]interface{}{
+			"tags":        []string{op.Tag},
+			"summary":     op.Summary,
+			"operationId": strings.ToLower(op.Method) + strings.NewReplacer("/", "_", "{", "", "}", "", "-", "_").Replace(op.Path),
+			"responses": map[string]interface{}{
+				strconv.Itoa(status): map[string]interface{}{
+					"description": http.StatusText(status),
+					"content":     b.content(op.Response),
+				},
+				"default": map[string]interface{}{
+					"description": "Error",
+					"content":     jsonContent(map[string]interface{}{"$ref": "#/components/schemas/Error"}),
+				},
+			},
+		}
+		if len(params) > 0 {
+			operation["parameters"] = params
+		}
+		if op.Body != nil {
+			operation["requestBody"] = map[string]interface{}{"required": true, "content": b.content(op.Body)}
+		}
+		if sec, ok := security[op.Auth]; ok {
+			operation["security"] = sec
+		}
+		if op.Deprecated {
+			operation["deprecated"] = true
+		}
+		if paths[op.Path] == nil {
+			paths[op.Path] = map[string]interface{}{}
+		}
+		paths[op.Path][strings.ToLower(op.Method)] = operation
+	}
+
+	return map[string]interface{}{
+		"openapi": "3.0.3",
+		"info": map[string]interface{}{
+			"title":       "MediDhaka API",
+			"version":     version,
+			"description": "Hospital and doctor directory, appointments and live hospital data for Dhaka.",
+		},
+		"paths": paths,
+		"components": map[string]interface{}{
+			"schemas": b.schemas,
+			"securitySchemes": map[string]interface{}{
+				"adminKey":   map[string]interface{}{"type": "apiKey", "in": "header", "name": "X-Admin-Key"},
+				"staffKey":   map[string]interface{}{"type": "apiKey", "in": "header", "name": "X-Staff-Key"},
+				"bearerAuth": map[string]interface{}{"type": "http", "scheme": "bearer", "bearerFormat": "JWT"},
+			},
+		},
+	}
+}
+
+// routeVariablePattern strips gorilla/mux patterns such as {key:.+}.
+var routeVariablePattern = regexp.MustCompile(`\{([a-z_]+):[^}]+\}`)
+
+// undocumentedRoutes lists the "METHOD path" of every route registered on r
+// or on the version routers mounted under /v1 and /v2 that apiOperations
+// doesn't describe.
+func undocumentedRoutes(r, v1, v2 *mux.Router) []string {
+	documented := map[string]bool{}
+	for _, op := range apiOperations {
+		documented[op.Method+" "+op.Path] = true
+	}
+	var missing []string
+	walk := func(router *mux.Router, prefix string) {
+		router.Walk(func(route *mux.Route, _ *mux.Router, _ []*mux.Route) error {
+			path, err := route.GetPathTemplate()
+			if err != nil {
+				return nil
+			}
+			path = prefix + routeVariablePattern.ReplaceAllString(path, "{$1}")
+			methods, _ := route.GetMethods()
+			for _, m := range methods {
+				if m == http.MethodOptions || m == http.MethodHead {
+					continue
+				}
+				if !documented[m+" "+path] {
+					missing = append(missing, m+" "+path)
+				}
+			}
+			return nil
+		})
+	}
+	walk(r, "")
+	walk(v1, "/v1")
+	walk(v2, "/v2")
+	sort.Strings(missing)
+	return missing
+}
+
+// reportUndocumentedRoutes logs the routes missing from the OpenAPI document
+// so that they are noticed as soon as the server starts.
+func reportUndocumentedRoutes(r, v1, v2 *mux.Router) {
+	for _, route := range undocumentedRoutes(r, v1, v2) {
+		log.Printf("OpenAPI: route %s is not documented in apiOperations", route)
+	}
+}
+
+// openAPIHandler serves the generated document.
+func openAPIHandler(version string) http.HandlerFunc {
+	spec, err := json.Marshal(buildOpenAPI(version))
+	if err != nil {
+		log.Fatalf("Failed to generate the OpenAPI document: %v", err)
+	}
+	return func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		w.Write(spec)
+	}
+}
+
+const docsPage = `<!DOCTYPE html>
+<html lang="en">
+<head>
+  <meta charset="utf-8">
+  <title>MediDhaka API</title>
+  <link rel="stylesheet" href="https://cdn.jsdelivr.net/npm/swagger-ui-dist@5/swagger-ui.css">
+</head>
+<body>
+  <div id="swagger-ui"></div>
+  <script src="https://cdn.jsdelivr.net/npm/swagger-ui-dist@5/swagger-ui-bundle.js"></script>
+  <script>SwaggerUIBundle({ url: "/openapi.json", dom_id: "#swagger-ui" });</script>
+</body>
+</html>
+`
+
+// serveDocs serves Swagger UI for /openapi.json.
+func serveDocs(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "text/html; charset=utf-8")
+	w.Write([]byte(docsPage))
+}
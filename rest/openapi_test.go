@@ -0,0 +1,20 @@
+package rest
+
+import (
+	"medidhaka/config"
+	middleware "medidhaka/rest/middlewares"
+	"testing"
+
+	"github.com/gorilla/mux"
+)
+
+// Every registered route must be described in apiOperations, including the
+// ones only on the /v2 router.
+func TestRoutesAreDocumented(t *testing.T) {
+	r := mux.NewRouter()
+	v1, v2 := initRoutes(r, middleware.NewManager(), config.Config{}, Deps{})
+
+	for _, route := range undocumentedRoutes(r, v1, v2) {
+		t.Errorf("route %s is not documented in apiOperations", route)
+	}
+}
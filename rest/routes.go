@@ -3,40 +3,246 @@ package rest
 import (
 	"net/http"
 
-	"medidhaka/repo"
+	"medidhaka/config"
+	"medidhaka/infra/pubsub"
+	"medidhaka/infra/ratelimit"
 	"medidhaka/rest/handlers"
 	middleware "medidhaka/rest/middlewares"
 
 	"github.com/gorilla/mux"
 )
 
-func initRoutes(r *mux.Router, manager *middleware.Manager, hospitalRepo repo.HospitalRepo, doctorRepo repo.DoctorRepo, hospitalDoctorRepo repo.HospitalDoctorRepo) {
+// initRoutes registers every route on r and returns the routers mounted under
+// /v1 and /v2.
+func initRoutes(r *mux.Router, manager *middleware.Manager, conf config.Config, deps Deps) (v1, v2 *mux.Router) {
 	// Initialize handlers
-	hospitalHandler := handlers.NewHospitalHandler(hospitalRepo)
-	doctorHandler := handlers.NewDoctorHandler(doctorRepo)
-	hospitalDoctorHandler := handlers.NewHospitalDoctorHandler(hospitalDoctorRepo)
-	searchHandler := handlers.NewSearchHandler(doctorRepo, hospitalRepo)
+	hospitalHandler := handlers.NewHospitalHandler(deps.HospitalRepo, deps.Store, deps.WebhookRepo)
+	doctorHandler := handlers.NewDoctorHandler(deps.DoctorRepo, deps.Store, deps.WebhookRepo)
+	hospitalDoctorHandler := handlers.NewHospitalDoctorHandler(deps.HospitalDoctorRepo, deps.WebhookRepo)
+	searchHandler := handlers.NewSearchHandler(deps.DoctorRepo, deps.HospitalRepo, deps.Store)
+	specialtyHandler := handlers.NewSpecialtyHandler(deps.SpecialtyRepo)
+	serviceHandler := handlers.NewServiceHandler(deps.ServiceRepo)
+	scheduleHandler := handlers.NewScheduleHandler(deps.ScheduleRepo)
+	appointmentHandler := handlers.NewAppointmentHandler(deps.AppointmentRepo, deps.ScheduleRepo)
+	authHandler := handlers.NewAuthHandler(deps.PatientRepo, deps.AuthRepo, deps.SmsSender, conf.JwtSecret)
+	patientHandler := handlers.NewPatientHandler(deps.PatientRepo)
+	waitlistHandler := handlers.NewWaitlistHandler(deps.WaitlistRepo)
+	queueHandler := handlers.NewQueueHandler(deps.QueueRepo, pubsub.NewBroker())
+	reviewHandler := handlers.NewReviewHandler(deps.ReviewRepo)
+	staffKeyHandler := handlers.NewStaffKeyHandler(deps.StaffKeyRepo)
+	bedHandler := handlers.NewBedHandler(deps.BedRepo)
+	hoursHandler := handlers.NewHoursHandler(deps.HoursRepo)
+	bloodHandler := handlers.NewBloodHandler(deps.BloodRepo)
+	insuranceHandler := handlers.NewInsuranceHandler(deps.InsuranceRepo)
+	credentialHandler := handlers.NewCredentialHandler(deps.CredentialRepo)
+	imageHandler := handlers.NewImageHandler(deps.HospitalRepo, deps.DoctorRepo, deps.Store)
+	importHandler := handlers.NewImportHandler(deps.ImportRepo)
+	fhirHandler := handlers.NewFHIRHandler(deps.HospitalRepo, deps.DoctorRepo, deps.HospitalDoctorRepo)
+	graphqlHandler := handlers.NewGraphQLHandler(deps.HospitalRepo, deps.DoctorRepo, deps.HospitalDoctorRepo, deps.CredentialRepo, deps.Store)
+	webhookHandler := handlers.NewWebhookHandler(deps.WebhookRepo)
+
+	requirePatient := middleware.RequirePatient(conf.JwtSecret)
+	requireAdmin := middleware.RequireAdmin(conf.AdminApiKey)
+	requireStaff := middleware.RequireStaff(deps.StaffKeyRepo)
+	// Writes share one rate limiter with each other and the same limits as
+	// the gRPC API.
+	rateLimit := middleware.RateLimit(ratelimit.New(ratelimit.Rate, ratelimit.Burst))
+	uploadLimit := middleware.RateLimit(ratelimit.New(ratelimit.UploadRate, ratelimit.UploadBurst))
+
+	// ---------- API v1 ----------
+	// The current REST API. /v2 falls back to it for every route it doesn't
+	// redefine, and the unversioned paths are deprecated aliases of it.
+	// Both are mounted at the end of this function and register their routes
+	// without the version prefix.
+	v1 = mux.NewRouter()
+	v2 = mux.NewRouter()
 
 	// ---------- Hospital Routes ----------
-	r.Handle("/hospitals", manager.With(http.HandlerFunc(hospitalHandler.CreateHospital))).Methods("POST", "OPTIONS")
-	r.Handle("/hospitals", manager.With(http.HandlerFunc(hospitalHandler.ListHospitals))).Methods("GET", "OPTIONS")
-	r.Handle("/hospitals/{id}", manager.With(http.HandlerFunc(hospitalHandler.GetHospital))).Methods("GET", "OPTIONS")
-	r.Handle("/hospitals/{id}", manager.With(http.HandlerFunc(hospitalHandler.UpdateHospital))).Methods("PUT", "OPTIONS")
-	r.Handle("/hospitals/{id}", manager.With(http.HandlerFunc(hospitalHandler.DeleteHospital))).Methods("DELETE", "OPTIONS")
+	v1.Handle("/hospitals", manager.With(http.HandlerFunc(hospitalHandler.CreateHospital), rateLimit, requireAdmin)).Methods("POST", "OPTIONS")
+	v1.Handle("/hospitals", manager.With(http.HandlerFunc(hospitalHandler.ListHospitals))).Methods("GET", "OPTIONS")
+	v1.Handle("/hospitals/export", manager.With(http.HandlerFunc(hospitalHandler.ExportHospitals))).Methods("GET", "OPTIONS")
+	v1.Handle("/hospitals/availability", manager.With(http.HandlerFunc(bedHandler.ListAvailability))).Methods("GET", "OPTIONS")
+	v1.Handle("/hospitals/{id}", manager.With(http.HandlerFunc(hospitalHandler.GetHospital))).Methods("GET", "OPTIONS")
+	v1.Handle("/hospitals/{id}", manager.With(http.HandlerFunc(hospitalHandler.UpdateHospital), rateLimit, requireAdmin)).Methods("PUT", "OPTIONS")
+	v1.Handle("/hospitals/{id}", manager.With(http.HandlerFunc(hospitalHandler.DeleteHospital), rateLimit, requireAdmin)).Methods("DELETE", "OPTIONS")
+	v1.Handle("/hospitals/{id}/image", manager.With(http.HandlerFunc(imageHandler.UploadHospitalImage), uploadLimit, requireStaff)).Methods("POST", "OPTIONS")
+	v1.Handle("/hospitals/{id}/image", manager.With(http.HandlerFunc(imageHandler.DeleteHospitalImage), rateLimit, requireStaff)).Methods("DELETE", "OPTIONS")
+	v1.Handle("/hospitals/{id}/services", manager.With(http.HandlerFunc(serviceHandler.ListHospitalServices))).Methods("GET", "OPTIONS")
+	v1.Handle("/hospitals/{id}/services", manager.With(http.HandlerFunc(serviceHandler.AddHospitalService))).Methods("POST", "OPTIONS")
+	v1.Handle("/hospitals/{id}/appointments", manager.With(http.HandlerFunc(appointmentHandler.ListHospitalAppointments), requireStaff)).Methods("GET", "OPTIONS")
+	v1.Handle("/hospitals/{id}/schedule", manager.With(http.HandlerFunc(scheduleHandler.GetHospitalSchedule))).Methods("GET", "OPTIONS")
+	v1.Handle("/hospitals/{id}/services/{service_id}", manager.With(http.HandlerFunc(serviceHandler.RemoveHospitalService))).Methods("DELETE", "OPTIONS")
+	v1.Handle("/hospitals/{id}/insurance", manager.With(http.HandlerFunc(insuranceHandler.ListHospitalPanels))).Methods("GET", "OPTIONS")
+	v1.Handle("/hospitals/{id}/insurance", manager.With(http.HandlerFunc(insuranceHandler.AddHospitalPanel), requireStaff)).Methods("POST", "OPTIONS")
+	v1.Handle("/hospitals/{id}/insurance/{panel_id}", manager.With(http.HandlerFunc(insuranceHandler.RemoveHospitalPanel), requireStaff)).Methods("DELETE", "OPTIONS")
+
+	// ---------- Opening Hours ----------
+	v1.Handle("/hospitals/{id}/hours", manager.With(http.HandlerFunc(hoursHandler.GetHospitalHours))).Methods("GET", "OPTIONS")
+	v1.Handle("/hospitals/{id}/hours", manager.With(http.HandlerFunc(hoursHandler.ReplaceHospitalHours), requireStaff)).Methods("PUT", "OPTIONS")
+	v1.Handle("/hospitals/{id}/hour-overrides", manager.With(http.HandlerFunc(hoursHandler.CreateHoursOverride), requireStaff)).Methods("POST", "OPTIONS")
+	v1.Handle("/hour-overrides/{id}", manager.With(http.HandlerFunc(hoursHandler.DeleteHoursOverride), requireStaff)).Methods("DELETE", "OPTIONS")
+
+	// ---------- Bed Availability ----------
+	v1.Handle("/hospitals/{id}/beds", manager.With(http.HandlerFunc(bedHandler.ListHospitalBeds))).Methods("GET", "OPTIONS")
+	v1.Handle("/hospitals/{id}/beds/{ward}", manager.With(http.HandlerFunc(bedHandler.UpdateHospitalBeds), requireStaff)).Methods("PUT", "OPTIONS")
+
+	// ---------- Blood Banks ----------
+	v1.Handle("/blood-banks", manager.With(http.HandlerFunc(bloodHandler.ListBloodBanks))).Methods("GET", "OPTIONS")
+	v1.Handle("/hospitals/{id}/blood", manager.With(http.HandlerFunc(bloodHandler.ListHospitalBlood))).Methods("GET", "OPTIONS")
+	v1.Handle("/hospitals/{id}/blood", manager.With(http.HandlerFunc(bloodHandler.UpdateHospitalBlood), requireStaff)).Methods("PUT", "OPTIONS")
+
+	// ---------- Hospital Staff Keys ----------
+	v1.Handle("/admin/hospitals/{id}/staff-keys", manager.With(http.HandlerFunc(staffKeyHandler.ListStaffKeys), requireAdmin)).Methods("GET", "OPTIONS")
+	v1.Handle("/admin/hospitals/{id}/staff-keys", manager.With(http.HandlerFunc(staffKeyHandler.CreateStaffKey), requireAdmin)).Methods("POST", "OPTIONS")
+	v1.Handle("/admin/staff-keys/{id}", manager.With(http.HandlerFunc(staffKeyHandler.RevokeStaffKey), requireAdmin)).Methods("DELETE", "OPTIONS")
+
+	// ---------- Service Catalog Routes ----------
+	v1.Handle("/services", manager.With(http.HandlerFunc(serviceHandler.CreateService))).Methods("POST", "OPTIONS")
+	v1.Handle("/services", manager.With(http.HandlerFunc(serviceHandler.ListServices))).Methods("GET", "OPTIONS")
+	v1.Handle("/services/{id}", manager.With(http.HandlerFunc(serviceHandler.UpdateService))).Methods("PUT", "OPTIONS")
+	v1.Handle("/services/{id}", manager.With(http.HandlerFunc(serviceHandler.DeleteService))).Methods("DELETE", "OPTIONS")
+
+	// ---------- Insurance Panels ----------
+	v1.Handle("/insurance-panels", manager.With(http.HandlerFunc(insuranceHandler.CreatePanel), requireAdmin)).Methods("POST", "OPTIONS")
+	v1.Handle("/insurance-panels", manager.With(http.HandlerFunc(insuranceHandler.ListPanels))).Methods("GET", "OPTIONS")
+	v1.Handle("/insurance-panels/{id}", manager.With(http.HandlerFunc(insuranceHandler.DeletePanel), requireAdmin)).Methods("DELETE", "OPTIONS")
 
 	// ---------- Doctor Routes ----------
-	r.Handle("/doctors", manager.With(http.HandlerFunc(doctorHandler.CreateDoctor))).Methods("POST", "OPTIONS")
-	r.Handle("/doctors", manager.With(http.HandlerFunc(doctorHandler.ListDoctors))).Methods("GET", "OPTIONS")
-	r.Handle("/doctors/{id}", manager.With(http.HandlerFunc(doctorHandler.GetDoctor))).Methods("GET", "OPTIONS")
-	r.Handle("/doctors/{id}", manager.With(http.HandlerFunc(doctorHandler.UpdateDoctor))).Methods("PUT", "OPTIONS")
-	r.Handle("/doctors/{id}", manager.With(http.HandlerFunc(doctorHandler.DeleteDoctor))).Methods("DELETE", "OPTIONS")
+	v1.Handle("/doctors", manager.With(http.HandlerFunc(doctorHandler.CreateDoctor), rateLimit, requireAdmin)).Methods("POST", "OPTIONS")
+	v1.Handle("/doctors", manager.With(http.HandlerFunc(doctorHandler.ListDoctors))).Methods("GET", "OPTIONS")
+	v1.Handle("/doctors/export", manager.With(http.HandlerFunc(doctorHandler.ExportDoctors))).Methods("GET", "OPTIONS")
+	v1.Handle("/doctors/nearby", manager.With(http.HandlerFunc(doctorHandler.ListNearbyDoctors))).Methods("GET", "OPTIONS")
+	v1.Handle("/doctors/{id}", manager.With(http.HandlerFunc(doctorHandler.GetDoctor))).Methods("GET", "OPTIONS")
+	v1.Handle("/doctors/{id}", manager.With(http.HandlerFunc(doctorHandler.UpdateDoctor), rateLimit, requireAdmin)).Methods("PUT", "OPTIONS")
+	v1.Handle("/doctors/{id}", manager.With(http.HandlerFunc(doctorHandler.DeleteDoctor), rateLimit, requireAdmin)).Methods("DELETE", "OPTIONS")
+
+	v1.Handle("/doctors/{id}/image", manager.With(http.HandlerFunc(imageHandler.UploadDoctorImage), uploadLimit, requireAdmin)).Methods("POST", "OPTIONS")
+	v1.Handle("/doctors/{id}/image", manager.With(http.HandlerFunc(imageHandler.DeleteDoctorImage), rateLimit, requireAdmin)).Methods("DELETE", "OPTIONS")
+	v1.Handle("/doctors/{id}/appointments", manager.With(http.HandlerFunc(appointmentHandler.ListDoctorAppointments), requireStaff)).Methods("GET", "OPTIONS")
+	v1.Handle("/doctors/{id}/schedule", manager.With(http.HandlerFunc(scheduleHandler.GetDoctorSchedule))).Methods("GET", "OPTIONS")
+	v1.Handle("/doctors/{id}/specialties", manager.With(http.HandlerFunc(specialtyHandler.ListDoctorSpecialties))).Methods("GET", "OPTIONS")
+	v1.Handle("/doctors/{id}/specialties", manager.With(http.HandlerFunc(specialtyHandler.AssignDoctorSpecialty))).Methods("POST", "OPTIONS")
+	v1.Handle("/doctors/{id}/specialties/{specialty_id}", manager.With(http.HandlerFunc(specialtyHandler.RemoveDoctorSpecialty))).Methods("DELETE", "OPTIONS")
+
+	// ---------- Doctor Credentials ----------
+	v1.Handle("/doctors/{id}/qualifications", manager.With(http.HandlerFunc(credentialHandler.ListQualifications))).Methods("GET", "OPTIONS")
+	v1.Handle("/doctors/{id}/qualifications", manager.With(http.HandlerFunc(credentialHandler.AddQualification))).Methods("POST", "OPTIONS")
+	v1.Handle("/doctors/{id}/qualifications/{qualification_id}", manager.With(http.HandlerFunc(credentialHandler.DeleteQualification))).Methods("DELETE", "OPTIONS")
+	v1.Handle("/doctors/{id}/verification", manager.With(http.HandlerFunc(credentialHandler.RequestVerification))).Methods("POST", "OPTIONS")
+	v1.Handle("/admin/doctors", manager.With(http.HandlerFunc(doctorHandler.ListDoctorsForReview), requireAdmin)).Methods("GET", "OPTIONS")
+	v1.Handle("/admin/doctors/{id}/verification", manager.With(http.HandlerFunc(credentialHandler.ReviewVerification), requireAdmin)).Methods("PATCH", "OPTIONS")
+
+	// ---------- Specialty Routes ----------
+	v1.Handle("/specialties", manager.With(http.HandlerFunc(specialtyHandler.CreateSpecialty))).Methods("POST", "OPTIONS")
+	v1.Handle("/specialties", manager.With(http.HandlerFunc(specialtyHandler.ListSpecialties))).Methods("GET", "OPTIONS")
+	v1.Handle("/specialties/{id}", manager.With(http.HandlerFunc(specialtyHandler.GetSpecialty))).Methods("GET", "OPTIONS")
+	v1.Handle("/specialties/{id}", manager.With(http.HandlerFunc(specialtyHandler.UpdateSpecialty))).Methods("PUT", "OPTIONS")
+	v1.Handle("/specialties/{id}", manager.With(http.HandlerFunc(specialtyHandler.DeleteSpecialty))).Methods("DELETE", "OPTIONS")
 
 	// ---------- Hospital–Doctor Relation ----------
-	r.Handle("/hospital-doctor", manager.With(http.HandlerFunc(hospitalDoctorHandler.AssignDoctor))).Methods("POST", "OPTIONS")
-	r.Handle("/hospital-doctor/{id}", manager.With(http.HandlerFunc(hospitalDoctorHandler.ListDoctorsByHospital))).Methods("GET", "OPTIONS")
-	r.Handle("/hospital-doctor/{hospital_id}/{doctor_id}", manager.With(http.HandlerFunc(hospitalDoctorHandler.DeleteDoctorRelation))).Methods("DELETE", "OPTIONS")
+	v1.Handle("/hospital-doctor", manager.With(http.HandlerFunc(hospitalDoctorHandler.AssignDoctor), rateLimit, requireAdmin)).Methods("POST", "OPTIONS")
+	v1.Handle("/hospital-doctor/{id}", manager.With(http.HandlerFunc(hospitalDoctorHandler.ListDoctorsByHospital))).Methods("GET", "OPTIONS")
+	v1.Handle("/hospital-doctor/{hospital_id}/{doctor_id}", manager.With(http.HandlerFunc(hospitalDoctorHandler.DeleteDoctorRelation), rateLimit, requireAdmin)).Methods("DELETE", "OPTIONS")
+	v1.Handle("/hospital-doctor/{hospital_id}/{doctor_id}/fees", manager.With(http.HandlerFunc(hospitalDoctorHandler.GetFees))).Methods("GET", "OPTIONS")
+	v1.Handle("/hospital-doctor/{hospital_id}/{doctor_id}/fees", manager.With(http.HandlerFunc(hospitalDoctorHandler.UpdateFees), requireStaff)).Methods("PUT", "OPTIONS")
+
+	// ---------- Chamber Schedules ----------
+	v1.Handle("/hospital-doctor/{hospital_id}/{doctor_id}/schedules", manager.With(http.HandlerFunc(scheduleHandler.ListRelationSchedules))).Methods("GET", "OPTIONS")
+	v1.Handle("/hospital-doctor/{hospital_id}/{doctor_id}/schedules", manager.With(http.HandlerFunc(scheduleHandler.CreateSchedule), requireStaff)).Methods("POST", "OPTIONS")
+	v1.Handle("/hospital-doctor/{hospital_id}/{doctor_id}/exceptions", manager.With(http.HandlerFunc(scheduleHandler.ListRelationExceptions))).Methods("GET", "OPTIONS")
+	v1.Handle("/hospital-doctor/{hospital_id}/{doctor_id}/exceptions", manager.With(http.HandlerFunc(scheduleHandler.CreateException), requireStaff)).Methods("POST", "OPTIONS")
+	v1.Handle("/schedules/{id}", manager.With(http.HandlerFunc(scheduleHandler.UpdateSchedule), requireStaff)).Methods("PUT", "OPTIONS")
+	v1.Handle("/schedules/{id}", manager.With(http.HandlerFunc(scheduleHandler.DeleteSchedule), requireStaff)).Methods("DELETE", "OPTIONS")
+	v1.Handle("/schedule-exceptions/{id}", manager.With(http.HandlerFunc(scheduleHandler.DeleteException), requireStaff)).Methods("DELETE", "OPTIONS")
+
+	// ---------- Appointments ----------
+	v1.Handle("/hospital-doctor/{hospital_id}/{doctor_id}/slots", manager.With(http.HandlerFunc(appointmentHandler.ListSlots))).Methods("GET", "OPTIONS")
+	v1.Handle("/appointments", manager.With(http.HandlerFunc(appointmentHandler.BookAppointment), requirePatient)).Methods("POST", "OPTIONS")
+	v1.Handle("/appointments/{id}", manager.With(http.HandlerFunc(appointmentHandler.GetAppointment), requirePatient)).Methods("GET", "OPTIONS")
+	v1.Handle("/appointments/{id}/cancel", manager.With(http.HandlerFunc(appointmentHandler.CancelAppointment), requirePatient)).Methods("POST", "OPTIONS")
+	v1.Handle("/appointments/{id}/reschedule", manager.With(http.HandlerFunc(appointmentHandler.RescheduleAppointment), requirePatient)).Methods("POST", "OPTIONS")
+	v1.Handle("/appointments/{id}/status", manager.With(http.HandlerFunc(appointmentHandler.UpdateAppointmentStatus), requireStaff)).Methods("PATCH", "OPTIONS")
+
+	// ---------- Waitlist ----------
+	v1.Handle("/hospital-doctor/{hospital_id}/{doctor_id}/waitlist", manager.With(http.HandlerFunc(waitlistHandler.ListRelationWaitlist), requireStaff)).Methods("GET", "OPTIONS")
+	v1.Handle("/waitlist", manager.With(http.HandlerFunc(waitlistHandler.JoinWaitlist), requirePatient)).Methods("POST", "OPTIONS")
+	v1.Handle("/waitlist/{id}", manager.With(http.HandlerFunc(waitlistHandler.GetWaitlistEntry), requirePatient)).Methods("GET", "OPTIONS")
+	v1.Handle("/waitlist/{id}", manager.With(http.HandlerFunc(waitlistHandler.LeaveWaitlist), requirePatient)).Methods("DELETE", "OPTIONS")
+	v1.Handle("/waitlist/{id}/claim", manager.With(http.HandlerFunc(waitlistHandler.ClaimOffer), requirePatient)).Methods("POST", "OPTIONS")
+	v1.Handle("/patients/me/waitlist", manager.With(http.HandlerFunc(waitlistHandler.ListMyWaitlist), requirePatient)).Methods("GET", "OPTIONS")
+
+	// ---------- Walk-in Serial Queues ----------
+	v1.Handle("/queues/{hospital_id}/{doctor_id}/today", manager.With(http.HandlerFunc(queueHandler.GetTodayQueue))).Methods("GET", "OPTIONS")
+	v1.Handle("/queues/{hospital_id}/{doctor_id}/today/events", manager.With(http.HandlerFunc(queueHandler.StreamTodayQueue))).Methods("GET", "OPTIONS")
+	v1.Handle("/queues/{hospital_id}/{doctor_id}/today/serials", manager.With(http.HandlerFunc(queueHandler.ListTodaySerials), requireStaff)).Methods("GET", "OPTIONS")
+	v1.Handle("/queues/{hospital_id}/{doctor_id}/today/serials", manager.With(http.HandlerFunc(queueHandler.IssueSerial), requirePatient)).Methods("POST", "OPTIONS")
+	v1.Handle("/queues/{hospital_id}/{doctor_id}/today/next", manager.With(http.HandlerFunc(queueHandler.CallNext), requireStaff)).Methods("POST", "OPTIONS")
+	v1.Handle("/queues/{hospital_id}/{doctor_id}/today/serials/{serial}/call", manager.With(http.HandlerFunc(queueHandler.CallSerial), requireStaff)).Methods("POST", "OPTIONS")
+	v1.Handle("/queues/{hospital_id}/{doctor_id}/today/serials/{serial}/recall", manager.With(http.HandlerFunc(queueHandler.CallSerial), middleware.Deprecated(recallDeprecation), requireStaff)).Methods("POST", "OPTIONS")
+	v1.Handle("/queues/{hospital_id}/{doctor_id}/today/serials/{serial}/skip", manager.With(http.HandlerFunc(queueHandler.SkipSerial), requireStaff)).Methods("POST", "OPTIONS")
+
+	// ---------- Reviews ----------
+	v1.Handle("/reviews", manager.With(http.HandlerFunc(reviewHandler.CreateReview), requirePatient)).Methods("POST", "OPTIONS")
+	v1.Handle("/doctors/{id}/reviews", manager.With(http.HandlerFunc(reviewHandler.ListDoctorReviews))).Methods("GET", "OPTIONS")
+	v1.Handle("/hospitals/{id}/reviews", manager.With(http.HandlerFunc(reviewHandler.ListHospitalReviews))).Methods("GET", "OPTIONS")
+	v1.Handle("/patients/me/reviews", manager.With(http.HandlerFunc(reviewHandler.ListMyReviews), requirePatient)).Methods("GET", "OPTIONS")
+	v1.Handle("/admin/reviews", manager.With(http.HandlerFunc(reviewHandler.ListModerationQueue), requireAdmin)).Methods("GET", "OPTIONS")
+	v1.Handle("/admin/reviews/{id}", manager.With(http.HandlerFunc(reviewHandler.ModerateReview), requireAdmin)).Methods("PATCH", "OPTIONS")
+
+	// ---------- Patient Auth & Profile ----------
+	v1.Handle("/auth/otp/request", manager.With(http.HandlerFunc(authHandler.RequestOTP))).Methods("POST", "OPTIONS")
+	v1.Handle("/auth/otp/verify", manager.With(http.HandlerFunc(authHandler.VerifyOTP))).Methods("POST", "OPTIONS")
+	v1.Handle("/auth/refresh", manager.With(http.HandlerFunc(authHandler.Refresh))).Methods("POST", "OPTIONS")
+	v1.Handle("/auth/logout", manager.With(http.HandlerFunc(authHandler.Logout))).Methods("POST", "OPTIONS")
+	v1.Handle("/patients/me", manager.With(http.HandlerFunc(patientHandler.GetProfile), requirePatient)).Methods("GET", "OPTIONS")
+	v1.Handle("/patients/me", manager.With(http.HandlerFunc(patientHandler.UpdateProfile), requirePatient)).Methods("PUT", "OPTIONS")
+	v1.Handle("/patients/me/appointments", manager.With(http.HandlerFunc(appointmentHandler.ListMyAppointments), requirePatient)).Methods("GET", "OPTIONS")
 
 	// ---------- Search Route ----------
-	r.Handle("/search", manager.With(http.HandlerFunc(searchHandler.Search))).Methods("GET", "OPTIONS")
+	v1.Handle("/search", manager.With(http.HandlerFunc(searchHandler.Search))).Methods("GET", "OPTIONS")
+
+	// ---------- Bulk Import ----------
+	v1.Handle("/import", manager.With(http.HandlerFunc(importHandler.Import), requireAdmin)).Methods("POST", "OPTIONS")
+
+	// ---------- Webhooks ----------
+	v1.Handle("/admin/webhooks", manager.With(http.HandlerFunc(webhookHandler.ListWebhooks), requireAdmin)).Methods("GET", "OPTIONS")
+	v1.Handle("/admin/webhooks", manager.With(http.HandlerFunc(webhookHandler.CreateWebhook), requireAdmin)).Methods("POST", "OPTIONS")
+	v1.Handle("/admin/webhooks/{id}", manager.With(http.HandlerFunc(webhookHandler.GetWebhook), requireAdmin)).Methods("GET", "OPTIONS")
+	v1.Handle("/admin/webhooks/{id}", manager.With(http.HandlerFunc(webhookHandler.UpdateWebhook), requireAdmin)).Methods("PUT", "OPTIONS")
+	v1.Handle("/admin/webhooks/{id}", manager.With(http.HandlerFunc(webhookHandler.DeleteWebhook), requireAdmin)).Methods("DELETE", "OPTIONS")
+	v1.Handle("/admin/webhooks/{id}/deliveries", manager.With(http.HandlerFunc(webhookHandler.ListDeliveries), requireAdmin)).Methods("GET", "OPTIONS")
+	v1.Handle("/admin/webhook-deliveries/{id}", manager.With(http.HandlerFunc(webhookHandler.GetDelivery), requireAdmin)).Methods("GET", "OPTIONS")
+	v1.Handle("/admin/webhook-deliveries/{id}/replay", manager.With(http.HandlerFunc(webhookHandler.ReplayDelivery), requireAdmin)).Methods("POST", "OPTIONS")
+
+	// ---------- FHIR R4 ----------
+	r.Handle("/fhir/R4/metadata", manager.With(http.HandlerFunc(fhirHandler.Metadata))).Methods("GET", "OPTIONS")
+	r.Handle("/fhir/R4/Organization", manager.With(http.HandlerFunc(fhirHandler.SearchOrganizations))).Methods("GET", "OPTIONS")
+	r.Handle("/fhir/R4/Organization/{id}", manager.With(http.HandlerFunc(fhirHandler.ReadOrganization))).Methods("GET", "OPTIONS")
+	r.Handle("/fhir/R4/Location", manager.With(http.HandlerFunc(fhirHandler.SearchLocations))).Methods("GET", "OPTIONS")
+	r.Handle("/fhir/R4/Location/{id}", manager.With(http.HandlerFunc(fhirHandler.ReadLocation))).Methods("GET", "OPTIONS")
+	r.Handle("/fhir/R4/Practitioner", manager.With(http.HandlerFunc(fhirHandler.SearchPractitioners))).Methods("GET", "OPTIONS")
+	r.Handle("/fhir/R4/Practitioner/{id}", manager.With(http.HandlerFunc(fhirHandler.ReadPractitioner))).Methods("GET", "OPTIONS")
+	r.Handle("/fhir/R4/PractitionerRole", manager.With(http.HandlerFunc(fhirHandler.SearchPractitionerRoles))).Methods("GET", "OPTIONS")
+	r.Handle("/fhir/R4/PractitionerRole/{id}", manager.With(http.HandlerFunc(fhirHandler.ReadPractitionerRole))).Methods("GET", "OPTIONS")
+
+	// ---------- GraphQL ----------
+	r.Handle("/graphql", manager.With(http.HandlerFunc(graphqlHandler.Serve))).Methods("GET", "POST", "OPTIONS")
+
+	// ---------- Uploaded Media ----------
+	r.Handle("/media/{key:.+}", manager.With(http.HandlerFunc(imageHandler.ServeMedia))).Methods("GET", "HEAD", "OPTIONS")
+
+	// ---------- API Documentation ----------
+	r.Handle("/openapi.json", manager.With(openAPIHandler(conf.Version))).Methods("GET", "OPTIONS")
+	r.Handle("/docs", manager.With(http.HandlerFunc(serveDocs))).Methods("GET", "OPTIONS")
+
+	// ---------- API v2 ----------
+	// Routes whose responses change incompatibly are registered on v2; every
+	// other /v2 path is served by its /v1 route.
+
+	r.PathPrefix("/v1/").Handler(http.StripPrefix("/v1", v1))
+	r.PathPrefix("/v2/").Handler(http.StripPrefix("/v2", withV1Fallback(v2, v1)))
+	r.NotFoundHandler = unversionedAlias(v1, unversionedDeprecation)
 
+	reportUndocumentedRoutes(r, v1, v2)
+	return v1, v2
 }
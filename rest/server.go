@@ -3,6 +3,8 @@ package rest
 import (
 	"fmt"
 	"medidhaka/config"
+	"medidhaka/infra/sms"
+	"medidhaka/infra/storage"
 	"medidhaka/repo"
 	middleware "medidhaka/rest/middlewares"
 	"net/http"
@@ -12,7 +14,34 @@ import (
 	"github.com/gorilla/mux"
 )
 
-func Start(conf config.Config, hospitalRepo repo.HospitalRepo, doctorRepo repo.DoctorRepo, hospitalDoctorRepo repo.HospitalDoctorRepo) {
+// Deps are the repositories and services the HTTP API is built on. Fields
+// left nil are only dereferenced by the routes that need them.
+type Deps struct {
+	HospitalRepo       repo.HospitalRepo
+	DoctorRepo         repo.DoctorRepo
+	HospitalDoctorRepo repo.HospitalDoctorRepo
+	SpecialtyRepo      repo.SpecialtyRepo
+	ServiceRepo        repo.ServiceRepo
+	ScheduleRepo       repo.ScheduleRepo
+	AppointmentRepo    repo.AppointmentRepo
+	PatientRepo        repo.PatientRepo
+	AuthRepo           repo.AuthRepo
+	WaitlistRepo       repo.WaitlistRepo
+	QueueRepo          repo.QueueRepo
+	ReviewRepo         repo.ReviewRepo
+	StaffKeyRepo       repo.StaffKeyRepo
+	BedRepo            repo.BedRepo
+	HoursRepo          repo.HoursRepo
+	BloodRepo          repo.BloodRepo
+	InsuranceRepo      repo.InsuranceRepo
+	CredentialRepo     repo.CredentialRepo
+	ImportRepo         repo.ImportRepo
+	WebhookRepo        repo.WebhookRepo
+	SmsSender          sms.Sender
+	Store              storage.Storage
+}
+
+func Start(conf config.Config, deps Deps) {
 	manager := middleware.NewManager()
 	manager.Use(
 		middleware.Cors,
@@ -21,7 +50,7 @@ func Start(conf config.Config, hospitalRepo repo.HospitalRepo, doctorRepo repo.D
 
 	r := mux.NewRouter()
 
-	initRoutes(r, manager, hospitalRepo, doctorRepo, hospitalDoctorRepo)
+	initRoutes(r, manager, conf, deps)
 
 	handler := manager.WrapMux(r)
 
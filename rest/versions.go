@@ -0,0 +1,51 @@
+package rest
+
+import (
+	middleware "medidhaka/rest/middlewares"
+	"net/http"
+	"time"
+
+	"github.com/gorilla/mux"
+)
+
+// unversionedDeprecation applies to the unversioned aliases of the /v1 routes,
+// kept until the mobile apps in the field have moved to /v1.
+var unversionedDeprecation = middleware.Deprecation{
+	Since:  time.Date(2026, time.October, 19, 0, 0, 0, 0, time.UTC),
+	Sunset: time.Date(2027, time.April, 30, 0, 0, 0, 0, time.UTC),
+}
+
+// recallDeprecation applies to POST .../serials/{serial}/recall, which does
+// the same as .../call. The successor is relative, so it resolves to the call
+// route of the same serial.
+var recallDeprecation = middleware.Deprecation{
+	Since:     time.Date(2026, time.October, 19, 0, 0, 0, 0, time.UTC),
+	Sunset:    time.Date(2027, time.April, 30, 0, 0, 0, 0, time.UTC),
+	Successor: "call",
+}
+
+// withV1Fallback serves the routes registered on v2 and answers every other
+// /v2 request with the v1 route for the same path.
+func withV1Fallback(v2, v1 *mux.Router) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var match mux.RouteMatch
+		if v2.Match(r, &match) {
+			v2.ServeHTTP(w, r)
+			return
+		}
+		v1.ServeHTTP(w, r)
+	})
+}
+
+// unversionedAlias answers requests that matched no route with the v1 route
+// for the same path, marking the response as deprecated when one exists.
+func unversionedAlias(v1 *mux.Router, d middleware.Deprecation) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var match mux.RouteMatch
+		if v1.Match(r, &match) || match.MatchErr == mux.ErrMethodMismatch {
+			d.Successor = "/v1" + r.URL.Path
+			d.SetHeaders(w.Header())
+		}
+		v1.ServeHTTP(w, r)
+	})
+}
@@ -0,0 +1,169 @@
+package rpc
+
+import (
+	"medidhaka/repo"
+	pb "medidhaka/rpc/medidhakav1"
+	"time"
+
+	"google.golang.org/protobuf/types/known/timestamppb"
+)
+
+func hospitalToProto(h *repo.Hospital) *pb.Hospital {
+	return &pb.Hospital{
+		HospitalId:       int64(h.HospitalID),
+		Name:             h.Name,
+		Address:          h.Address,
+		PhoneNumber:      h.PhoneNumber,
+		Email:            h.Email,
+		ImageUrl:         h.ImageURL,
+		Latitude:         h.Latitude,
+		Longitude:        h.Longitude,
+		RatingAvg:        h.RatingAvg,
+		RatingCount:      int32(h.RatingCount),
+		Open_24_7:        h.Open247,
+		HasEmergency:     h.HasEmergency,
+		EmergencyPhone:   h.EmergencyPhone,
+		AmbulanceNumbers: h.AmbulanceNumbers,
+		HospitalType:     h.HospitalType,
+		DghsLicenceNo:    h.LicenceNo,
+		LicenceExpiry:    h.LicenceExpiry,
+		LicenceExpired:   h.LicenceExpired,
+		BedCapacity:      int32Ptr(h.BedCapacity),
+		Accreditations:   h.Accreditations,
+		Website:          h.Website,
+		CreatedAt:        timestamppb.New(h.CreatedAt),
+		UpdatedAt:        timestamppb.New(h.UpdatedAt),
+	}
+}
+
+// hospitalFromProto copies the fields a client may set; the rating, licence
+// status and timestamps are kept by the database.
+func hospitalFromProto(h *pb.Hospital) repo.Hospital {
+	return repo.Hospital{
+		HospitalID:       int(h.GetHospitalId()),
+		Name:             h.GetName(),
+		Address:          h.GetAddress(),
+		PhoneNumber:      h.GetPhoneNumber(),
+		Email:            h.GetEmail(),
+		ImageURL:         h.GetImageUrl(),
+		Latitude:         h.Latitude,
+		Longitude:        h.Longitude,
+		Open247:          h.GetOpen_24_7(),
+		HasEmergency:     h.GetHasEmergency(),
+		EmergencyPhone:   h.GetEmergencyPhone(),
+		AmbulanceNumbers: h.GetAmbulanceNumbers(),
+		HospitalType:     h.GetHospitalType(),
+		LicenceNo:        h.GetDghsLicenceNo(),
+		LicenceExpiry:    h.LicenceExpiry,
+		BedCapacity:      intPtr(h.BedCapacity),
+		Accreditations:   h.GetAccreditations(),
+		Website:          h.GetWebsite(),
+	}
+}
+
+func doctorToProto(d *repo.Doctor) *pb.Doctor {
+	doc := &pb.Doctor{
+		DoctorId:                int64(d.DoctorID),
+		Name:                    d.Name,
+		Specialty:               d.Specialty,
+		YearsExperience:         int32(d.YearsExperience),
+		PhoneNumber:             d.PhoneNumber,
+		Email:                   d.Email,
+		ImageUrl:                d.ImageURL,
+		RatingAvg:               d.RatingAvg,
+		RatingCount:             int32(d.RatingCount),
+		BmdcRegNo:               d.BMDCRegNo,
+		Gender:                  d.Gender,
+		Languages:               d.Languages,
+		VerificationStatus:      d.VerificationStatus,
+		VerificationNote:        d.VerificationNote,
+		VerificationRequestedAt: timestamp(d.VerificationRequestedAt),
+		VerifiedAt:              timestamp(d.VerifiedAt),
+		MinFee:                  d.MinFee,
+		CreatedAt:               timestamppb.New(d.CreatedAt),
+		UpdatedAt:               timestamppb.New(d.UpdatedAt),
+	}
+	for _, q := range d.Qualifications {
+		doc.Qualifications = append(doc.Qualifications, &pb.Qualification{
+			QualificationId: int64(q.QualificationID),
+			Degree:          q.Degree,
+			Institution:     q.Institution,
+			Year:            int32Ptr(q.Year),
+		})
+	}
+	return doc
+}
+
+func doctorsToProto(list []repo.Doctor) []*pb.Doctor {
+	doctors := make([]*pb.Doctor, len(list))
+	for i := range list {
+		doctors[i] = doctorToProto(&list[i])
+	}
+	return doctors
+}
+
+// doctorFromProto copies the profile fields a client may set; verification,
+// ratings and qualifications have their own workflows.
+func doctorFromProto(d *pb.Doctor) repo.Doctor {
+	return repo.Doctor{
+		DoctorID:        int(d.GetDoctorId()),
+		Name:            d.GetName(),
+		Specialty:       d.GetSpecialty(),
+		YearsExperience: int(d.GetYearsExperience()),
+		PhoneNumber:     d.GetPhoneNumber(),
+		Email:           d.GetEmail(),
+		ImageURL:        d.GetImageUrl(),
+		BMDCRegNo:       d.BmdcRegNo,
+		Gender:          d.GetGender(),
+		Languages:       d.GetLanguages(),
+	}
+}
+
+func hospitalDoctorToProto(rel *repo.HospitalDoctor) *pb.HospitalDoctor {
+	return &pb.HospitalDoctor{
+		HospitalId:      int64(rel.HospitalID),
+		DoctorId:        int64(rel.DoctorID),
+		Role:            rel.Role,
+		FeeNewPatient:   rel.FeeNewPatient,
+		FeeFollowUp:     rel.FeeFollowUp,
+		FeeReportReview: rel.FeeReportReview,
+		FeeCurrency:     rel.FeeCurrency,
+		CreatedAt:       timestamppb.New(rel.CreatedAt),
+		UpdatedAt:       timestamppb.New(rel.UpdatedAt),
+	}
+}
+
+func hospitalDoctorFromProto(rel *pb.HospitalDoctor) repo.HospitalDoctor {
+	return repo.HospitalDoctor{
+		HospitalID:      int(rel.GetHospitalId()),
+		DoctorID:        int(rel.GetDoctorId()),
+		Role:            rel.GetRole(),
+		FeeNewPatient:   rel.FeeNewPatient,
+		FeeFollowUp:     rel.FeeFollowUp,
+		FeeReportReview: rel.FeeReportReview,
+		FeeCurrency:     rel.GetFeeCurrency(),
+	}
+}
+
+func timestamp(t *time.Time) *timestamppb.Timestamp {
+	if t == nil {
+		return nil
+	}
+	return timestamppb.New(*t)
+}
+
+func intPtr(v *int32) *int {
+	if v == nil {
+		return nil
+	}
+	n := int(*v)
+	return &n
+}
+
+func int32Ptr(v *int) *int32 {
+	if v == nil {
+		return nil
+	}
+	n := int32(*v)
+	return &n
+}
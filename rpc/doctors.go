@@ -0,0 +1,126 @@
+package rpc
+
+import (
+	"context"
+	"errors"
+	"medidhaka/directory"
+	"medidhaka/infra/storage"
+	"medidhaka/repo"
+	pb "medidhaka/rpc/medidhakav1"
+	"net/url"
+	"strconv"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+type doctorServer struct {
+	pb.UnimplementedDoctorServiceServer
+	repo     repo.DoctorRepo
+	store    storage.Storage
+	webhooks repo.WebhookRepo
+}
+
+func (s *doctorServer) CreateDoctor(ctx context.Context, req *pb.CreateDoctorRequest) (*pb.Doctor, error) {
+	if req.Doctor == nil {
+		return nil, status.Error(codes.InvalidArgument, "doctor is required")
+	}
+	doc := doctorFromProto(req.Doctor)
+	if msg := directory.NormalizeCredentials(&doc); msg != "" {
+		return nil, status.Error(codes.InvalidArgument, msg)
+	}
+
+	created, err := s.repo.Create(doc)
+	if err != nil {
+		if errors.Is(err, repo.ErrDuplicateBMDC) {
+			return nil, status.Error(codes.AlreadyExists, "BMDC registration number already exists")
+		}
+		return nil, internalError("Failed to create doctor", err)
+	}
+	directory.PublishEvent(s.webhooks, repo.EventDoctorCreated, created)
+	return doctorToProto(created), nil
+}
+
+func (s *doctorServer) GetDoctor(ctx context.Context, req *pb.GetDoctorRequest) (*pb.Doctor, error) {
+	doctor, err := s.repo.Get(int(req.DoctorId))
+	if err != nil {
+		if errors.Is(err, repo.ErrDoctorNotFound) {
+			return nil, status.Error(codes.NotFound, "Doctor not found")
+		}
+		return nil, internalError("Server error", err)
+	}
+	return doctorToProto(doctor), nil
+}
+
+func (s *doctorServer) UpdateDoctor(ctx context.Context, req *pb.UpdateDoctorRequest) (*pb.Doctor, error) {
+	if req.Doctor == nil {
+		return nil, status.Error(codes.InvalidArgument, "doctor is required")
+	}
+	doc := doctorFromProto(req.Doctor)
+	if msg := directory.NormalizeCredentials(&doc); msg != "" {
+		return nil, status.Error(codes.InvalidArgument, msg)
+	}
+
+	updated, err := s.repo.Update(doc)
+	if err != nil {
+		if errors.Is(err, repo.ErrFailedToUpdate) {
+			return nil, status.Error(codes.NotFound, "Doctor not found")
+		}
+		if errors.Is(err, repo.ErrDuplicateBMDC) {
+			return nil, status.Error(codes.AlreadyExists, "BMDC registration number already exists")
+		}
+		return nil, internalError("Failed to update", err)
+	}
+	directory.PublishEvent(s.webhooks, repo.EventDoctorUpdated, updated)
+	return doctorToProto(updated), nil
+}
+
+func (s *doctorServer) DeleteDoctor(ctx context.Context, req *pb.DeleteDoctorRequest) (*pb.DeleteDoctorResponse, error) {
+	id := int(req.DoctorId)
+
+	// Remember the photo so the stored file can go with the record.
+	var imageURL string
+	if existing, err := s.repo.Get(id); err == nil {
+		imageURL = existing.ImageURL
+	}
+
+	if err := s.repo.Delete(id); err != nil {
+		if errors.Is(err, repo.ErrFailedToDelete) {
+			return nil, status.Error(codes.NotFound, "Doctor not found")
+		}
+		return nil, internalError("Failed to delete", err)
+	}
+	if imageURL != "" {
+		directory.RemoveStoredImage(s.store, imageURL, "doctors", id)
+	}
+	directory.PublishEvent(s.webhooks, repo.EventDoctorDeleted, map[string]int{"doctor_id": id})
+	return &pb.DeleteDoctorResponse{}, nil
+}
+
+func (s *doctorServer) ListDoctors(ctx context.Context, req *pb.ListDoctorsRequest) (*pb.ListDoctorsResponse, error) {
+	query := url.Values{}
+	setQuery(query, "search", req.Search)
+	setQuery(query, "sort", req.Sort)
+	setQuery(query, "insurance", req.Insurance)
+	if req.MaxFee != nil {
+		query.Set("max_fee", strconv.FormatFloat(*req.MaxFee, 'f', -1, 64))
+	}
+	filter, msg := directory.ParseDoctorFilter(query, repo.VerificationVerified)
+	if msg != "" {
+		return nil, status.Error(codes.InvalidArgument, msg)
+	}
+
+	page, limit, offset := pagination(req.Page, req.Limit)
+	list, total, err := s.repo.List(filter, offset, limit)
+	if err != nil {
+		return nil, internalError("Failed to fetch doctors", err)
+	}
+
+	return &pb.ListDoctorsResponse{
+		Doctors:    doctorsToProto(list),
+		Total:      int32(total),
+		Page:       int32(page),
+		Limit:      int32(limit),
+		TotalPages: int32((total + limit - 1) / limit),
+	}, nil
+}
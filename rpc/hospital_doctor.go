@@ -0,0 +1,91 @@
+package rpc
+
+import (
+	"context"
+	"errors"
+	"medidhaka/directory"
+	"medidhaka/repo"
+	pb "medidhaka/rpc/medidhakav1"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+type hospitalDoctorServer struct {
+	pb.UnimplementedHospitalDoctorServiceServer
+	repo     repo.HospitalDoctorRepo
+	webhooks repo.WebhookRepo
+}
+
+func (s *hospitalDoctorServer) AssignDoctor(ctx context.Context, req *pb.AssignDoctorRequest) (*pb.HospitalDoctor, error) {
+	if req.Affiliation == nil {
+		return nil, status.Error(codes.InvalidArgument, "affiliation is required")
+	}
+	rel := hospitalDoctorFromProto(req.Affiliation)
+	if msg := directory.ValidateFees(&rel); msg != "" {
+		return nil, status.Error(codes.InvalidArgument, msg)
+	}
+
+	if err := s.repo.AssignDoctor(rel); err != nil {
+		return nil, internalError("Failed to assign doctor", err)
+	}
+	assigned, err := s.repo.Get(rel.HospitalID, rel.DoctorID)
+	if err != nil {
+		return nil, internalError("Failed to fetch fees", err)
+	}
+	directory.PublishEvent(s.webhooks, repo.EventHospitalDoctorCreated, assigned)
+	return hospitalDoctorToProto(assigned), nil
+}
+
+func (s *hospitalDoctorServer) GetHospitalDoctor(ctx context.Context, req *pb.GetHospitalDoctorRequest) (*pb.HospitalDoctor, error) {
+	rel, err := s.repo.Get(int(req.HospitalId), int(req.DoctorId))
+	if err != nil {
+		if errors.Is(err, repo.ErrNoAffiliation) {
+			return nil, status.Error(codes.NotFound, "Doctor is not assigned to this hospital")
+		}
+		return nil, internalError("Failed to fetch fees", err)
+	}
+	return hospitalDoctorToProto(rel), nil
+}
+
+func (s *hospitalDoctorServer) UpdateFees(ctx context.Context, req *pb.UpdateFeesRequest) (*pb.HospitalDoctor, error) {
+	if req.Affiliation == nil {
+		return nil, status.Error(codes.InvalidArgument, "affiliation is required")
+	}
+	rel := hospitalDoctorFromProto(req.Affiliation)
+	if msg := directory.ValidateFees(&rel); msg != "" {
+		return nil, status.Error(codes.InvalidArgument, msg)
+	}
+
+	updated, err := s.repo.UpdateFees(rel)
+	if err != nil {
+		if errors.Is(err, repo.ErrNoAffiliation) {
+			return nil, status.Error(codes.NotFound, "Doctor is not assigned to this hospital")
+		}
+		return nil, internalError("Failed to update fees", err)
+	}
+	directory.PublishEvent(s.webhooks, repo.EventHospitalDoctorUpdated, updated)
+	return hospitalDoctorToProto(updated), nil
+}
+
+func (s *hospitalDoctorServer) RemoveDoctor(ctx context.Context, req *pb.RemoveDoctorRequest) (*pb.RemoveDoctorResponse, error) {
+	hospitalID, doctorID := int(req.HospitalId), int(req.DoctorId)
+
+	// Only announce relations that existed; removing a missing one succeeds.
+	_, errExisting := s.repo.Get(hospitalID, doctorID)
+	if err := s.repo.DeleteDoctorRelation(hospitalID, doctorID); err != nil {
+		return nil, internalError("Failed to delete relation", err)
+	}
+	if errExisting == nil {
+		directory.PublishEvent(s.webhooks, repo.EventHospitalDoctorDeleted, map[string]int{"hospital_id": hospitalID, "doctor_id": doctorID})
+	}
+	return &pb.RemoveDoctorResponse{}, nil
+}
+
+func (s *hospitalDoctorServer) ListHospitalDoctors(ctx context.Context, req *pb.ListHospitalDoctorsRequest) (*pb.ListHospitalDoctorsResponse, error) {
+	doctors, err := s.repo.ListDoctorsByHospital(int(req.HospitalId))
+	if err != nil {
+		return nil, internalError("Failed to fetch doctors", err)
+	}
+	return &pb.ListHospitalDoctorsResponse{Doctors: doctorsToProto(doctors)}, nil
+}
@@ -0,0 +1,153 @@
+package rpc
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"medidhaka/directory"
+	"medidhaka/infra/storage"
+	"medidhaka/repo"
+	pb "medidhaka/rpc/medidhakav1"
+	"net/url"
+	"strconv"
+	"strings"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+type hospitalServer struct {
+	pb.UnimplementedHospitalServiceServer
+	repo     repo.HospitalRepo
+	store    storage.Storage
+	webhooks repo.WebhookRepo
+}
+
+func (s *hospitalServer) CreateHospital(ctx context.Context, req *pb.CreateHospitalRequest) (*pb.Hospital, error) {
+	if req.Hospital == nil {
+		return nil, status.Error(codes.InvalidArgument, "hospital is required")
+	}
+	hospital := hospitalFromProto(req.Hospital)
+	if hospital.Name == "" {
+		return nil, status.Error(codes.InvalidArgument, "Hospital name is required")
+	}
+	if msg := directory.ValidateHospitalDetails(&hospital); msg != "" {
+		return nil, status.Error(codes.InvalidArgument, msg)
+	}
+
+	created, err := s.repo.Create(hospital)
+	if err != nil {
+		if errors.Is(err, repo.ErrDuplicateLicence) {
+			return nil, status.Error(codes.AlreadyExists, "DGHS licence number already exists")
+		}
+		return nil, internalError("Failed to create hospital record", err)
+	}
+	directory.PublishEvent(s.webhooks, repo.EventHospitalCreated, created)
+	log.Printf("Hospital created: %s (ID: %d)", created.Name, created.HospitalID)
+	return hospitalToProto(created), nil
+}
+
+func (s *hospitalServer) GetHospital(ctx context.Context, req *pb.GetHospitalRequest) (*pb.Hospital, error) {
+	hospital, err := s.repo.Get(int(req.HospitalId))
+	if err != nil {
+		if errors.Is(err, repo.ErrNotFound) {
+			return nil, status.Errorf(codes.NotFound, "Hospital with ID %d not found", req.HospitalId)
+		}
+		return nil, internalError("Internal server error fetching hospital", err)
+	}
+	return hospitalToProto(hospital), nil
+}
+
+func (s *hospitalServer) UpdateHospital(ctx context.Context, req *pb.UpdateHospitalRequest) (*pb.Hospital, error) {
+	if req.Hospital == nil {
+		return nil, status.Error(codes.InvalidArgument, "hospital is required")
+	}
+	hospital := hospitalFromProto(req.Hospital)
+	if msg := directory.ValidateHospitalDetails(&hospital); msg != "" {
+		return nil, status.Error(codes.InvalidArgument, msg)
+	}
+
+	updated, err := s.repo.Update(hospital)
+	if err != nil {
+		if errors.Is(err, repo.ErrFailedUpdate) {
+			return nil, status.Errorf(codes.NotFound, "Hospital with ID %d not found for update", hospital.HospitalID)
+		}
+		if errors.Is(err, repo.ErrDuplicateLicence) {
+			return nil, status.Error(codes.AlreadyExists, "DGHS licence number already exists")
+		}
+		return nil, internalError("Internal server error updating hospital", err)
+	}
+	directory.PublishEvent(s.webhooks, repo.EventHospitalUpdated, updated)
+	log.Printf("Hospital updated: %s (ID: %d)", updated.Name, updated.HospitalID)
+	return hospitalToProto(updated), nil
+}
+
+func (s *hospitalServer) DeleteHospital(ctx context.Context, req *pb.DeleteHospitalRequest) (*pb.DeleteHospitalResponse, error) {
+	id := int(req.HospitalId)
+
+	// Remember the photo so the stored file can go with the record.
+	var imageURL string
+	if existing, err := s.repo.Get(id); err == nil {
+		imageURL = existing.ImageURL
+	}
+
+	if err := s.repo.Delete(id); err != nil {
+		if errors.Is(err, repo.ErrNotFound) {
+			return nil, status.Errorf(codes.NotFound, "Hospital with ID %d not found for deletion", id)
+		}
+		return nil, internalError("Internal server error deleting hospital", err)
+	}
+	if imageURL != "" {
+		directory.RemoveStoredImage(s.store, imageURL, "hospitals", id)
+	}
+	directory.PublishEvent(s.webhooks, repo.EventHospitalDeleted, map[string]int{"hospital_id": id})
+	log.Printf("🗑️ Hospital deleted: ID %d", id)
+	return &pb.DeleteHospitalResponse{}, nil
+}
+
+func (s *hospitalServer) ListHospitals(ctx context.Context, req *pb.ListHospitalsRequest) (*pb.ListHospitalsResponse, error) {
+	// Going through the HTTP query parser keeps the two APIs' filters in step.
+	query := url.Values{}
+	setQuery(query, "search", req.Search)
+	setQuery(query, "service", strings.Join(req.Services, ","))
+	setQuery(query, "open_now", strconv.FormatBool(req.OpenNow))
+	setQuery(query, "emergency", strconv.FormatBool(req.Emergency))
+	setQuery(query, "type", req.HospitalType)
+	setQuery(query, "licence", req.Licence)
+	setQuery(query, "accreditation", req.Accreditation)
+	setQuery(query, "sort", req.Sort)
+	if req.MinBeds != 0 {
+		query.Set("min_beds", fmt.Sprint(req.MinBeds))
+	}
+	filter, msg := directory.ParseHospitalFilter(query)
+	if msg != "" {
+		return nil, status.Error(codes.InvalidArgument, msg)
+	}
+
+	page, limit, offset := pagination(req.Page, req.Limit)
+	hospitals, total, err := s.repo.List(filter, offset, limit)
+	if err != nil {
+		return nil, internalError("Internal server error listing hospitals", err)
+	}
+
+	resp := &pb.ListHospitalsResponse{
+		Hospitals:  make([]*pb.Hospital, len(hospitals)),
+		Total:      int32(total),
+		Page:       int32(page),
+		Limit:      int32(limit),
+		TotalPages: int32((total + limit - 1) / limit),
+	}
+	for i, h := range hospitals {
+		resp.Hospitals[i] = hospitalToProto(h)
+	}
+	return resp, nil
+}
+
+// setQuery sets key unless value is empty or false, which the HTTP parsers
+// treat as absent anyway.
+func setQuery(query url.Values, key, value string) {
+	if value != "" && value != "false" {
+		query.Set(key, value)
+	}
+}
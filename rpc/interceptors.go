@@ -0,0 +1,94 @@
+package rpc
+
+import (
+	"context"
+	"crypto/subtle"
+	"log"
+	"medidhaka/infra/ratelimit"
+	pb "medidhaka/rpc/medidhakav1"
+	"net"
+	"runtime/debug"
+	"time"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/peer"
+	"google.golang.org/grpc/status"
+)
+
+// logger logs every call like the HTTP Logger middleware does requests.
+func logger(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (interface{}, error) {
+	start := time.Now()
+	resp, err := handler(ctx, req)
+	log.Printf("GRPC %s %s %s", info.FullMethod, status.Code(err), time.Since(start))
+	return resp, err
+}
+
+// recoverer turns a panic into an Internal error so one bad call does not
+// take the server down, as net/http does for handlers.
+func recoverer(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (resp interface{}, err error) {
+	defer func() {
+		if p := recover(); p != nil {
+			log.Printf("panic serving %s: %v\n%s", info.FullMethod, p, debug.Stack())
+			err = status.Error(codes.Internal, "Internal server error")
+		}
+	}()
+	return handler(ctx, req)
+}
+
+// adminMethods are the calls that change data. They need the admin key, like
+// the /admin HTTP routes.
+var adminMethods = map[string]bool{
+	pb.HospitalService_CreateHospital_FullMethodName:     true,
+	pb.HospitalService_UpdateHospital_FullMethodName:     true,
+	pb.HospitalService_DeleteHospital_FullMethodName:     true,
+	pb.DoctorService_CreateDoctor_FullMethodName:         true,
+	pb.DoctorService_UpdateDoctor_FullMethodName:         true,
+	pb.DoctorService_DeleteDoctor_FullMethodName:         true,
+	pb.HospitalDoctorService_AssignDoctor_FullMethodName: true,
+	pb.HospitalDoctorService_UpdateFees_FullMethodName:   true,
+	pb.HospitalDoctorService_RemoveDoctor_FullMethodName: true,
+}
+
+// requireAdmin rejects calls to adminMethods whose x-admin-key metadata does
+// not match key. With an empty key every such call is rejected.
+func requireAdmin(key string) grpc.UnaryServerInterceptor {
+	return func(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (interface{}, error) {
+		if !adminMethods[info.FullMethod] {
+			return handler(ctx, req)
+		}
+		var given string
+		if values := metadata.ValueFromIncomingContext(ctx, "x-admin-key"); len(values) == 1 {
+			given = values[0]
+		}
+		if key == "" || subtle.ConstantTimeCompare([]byte(given), []byte(key)) != 1 {
+			return nil, status.Error(codes.Unauthenticated, "Unauthorized")
+		}
+		return handler(ctx, req)
+	}
+}
+
+// rateLimit rejects calls beyond the peer's rate with ResourceExhausted.
+func rateLimit(l *ratelimit.Limiter) grpc.UnaryServerInterceptor {
+	return func(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (interface{}, error) {
+		if !l.Allow(peerHost(ctx), time.Now()) {
+			return nil, status.Error(codes.ResourceExhausted, "Too many requests, please slow down")
+		}
+		return handler(ctx, req)
+	}
+}
+
+// peerHost is the IP address of the caller, without the port, so that one
+// client's connections share a bucket.
+func peerHost(ctx context.Context) string {
+	p, ok := peer.FromContext(ctx)
+	if !ok || p.Addr == nil {
+		return ""
+	}
+	host, _, err := net.SplitHostPort(p.Addr.String())
+	if err != nil {
+		return p.Addr.String()
+	}
+	return host
+}
@@ -0,0 +1,822 @@
+// Code generated by protoc-gen-go. DO NOT EDIT.
+// versions:
+// 	protoc-gen-go v1.36.11
+// 	protoc        (unknown)
+// source: medidhaka/v1/doctor.proto
+
+package medidhakav1
+
+import (
+	protoreflect "google.golang.org/protobuf/reflect/protoreflect"
+	protoimpl "google.golang.org/protobuf/runtime/protoimpl"
+	timestamppb "google.golang.org/protobuf/types/known/timestamppb"
+	reflect "reflect"
+	sync "sync"
+	unsafe "unsafe"
+)
+
+const (
+	// Verify that this generated code is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(20 - protoimpl.MinVersion)
+	// Verify that runtime/protoimpl is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(protoimpl.MaxVersion - 20)
+)
+
+type Doctor struct {
+	state           protoimpl.MessageState `protogen:"open.v1"`
+	DoctorId        int64                  `protobuf:"varint,1,opt,name=doctor_id,json=doctorId,proto3" json:"doctor_id,omitempty"`
+	Name            string                 `protobuf:"bytes,2,opt,name=name,proto3" json:"name,omitempty"`
+	Specialty       string                 `protobuf:"bytes,3,opt,name=specialty,proto3" json:"specialty,omitempty"`
+	YearsExperience int32                  `protobuf:"varint,4,opt,name=years_experience,json=yearsExperience,proto3" json:"years_experience,omitempty"`
+	PhoneNumber     string                 `protobuf:"bytes,5,opt,name=phone_number,json=phoneNumber,proto3" json:"phone_number,omitempty"`
+	Email           string                 `protobuf:"bytes,6,opt,name=email,proto3" json:"email,omitempty"`
+	ImageUrl        string                 `protobuf:"bytes,7,opt,name=image_url,json=imageUrl,proto3" json:"image_url,omitempty"`
+	RatingAvg       float64                `protobuf:"fixed64,8,opt,name=rating_avg,json=ratingAvg,proto3" json:"rating_avg,omitempty"`
+	RatingCount     int32                  `protobuf:"varint,9,opt,name=rating_count,json=ratingCount,proto3" json:"rating_count,omitempty"`
+	// BMDC registration number such as A-12345.
+	BmdcRegNo *string `protobuf:"bytes,10,opt,name=bmdc_reg_no,json=bmdcRegNo,proto3,oneof" json:"bmdc_reg_no,omitempty"`
+	// male, female, other or empty.
+	Gender    string   `protobuf:"bytes,11,opt,name=gender,proto3" json:"gender,omitempty"`
+	Languages []string `protobuf:"bytes,12,rep,name=languages,proto3" json:"languages,omitempty"`
+	// Only changed through the verification workflow.
+	VerificationStatus      string                 `protobuf:"bytes,13,opt,name=verification_status,json=verificationStatus,proto3" json:"verification_status,omitempty"`
+	VerificationNote        string                 `protobuf:"bytes,14,opt,name=verification_note,json=verificationNote,proto3" json:"verification_note,omitempty"`
+	VerificationRequestedAt *timestamppb.Timestamp `protobuf:"bytes,15,opt,name=verification_requested_at,json=verificationRequestedAt,proto3" json:"verification_requested_at,omitempty"`
+	VerifiedAt              *timestamppb.Timestamp `protobuf:"bytes,16,opt,name=verified_at,json=verifiedAt,proto3" json:"verified_at,omitempty"`
+	// Only set by GetDoctor.
+	Qualifications []*Qualification `protobuf:"bytes,17,rep,name=qualifications,proto3" json:"qualifications,omitempty"`
+	// Lowest known new-patient fee among the affiliations matching the listing
+	// filter. Only set by ListDoctors.
+	MinFee        *float64               `protobuf:"fixed64,18,opt,name=min_fee,json=minFee,proto3,oneof" json:"min_fee,omitempty"`
+	CreatedAt     *timestamppb.Timestamp `protobuf:"bytes,19,opt,name=created_at,json=createdAt,proto3" json:"created_at,omitempty"`
+	UpdatedAt     *timestamppb.Timestamp `protobuf:"bytes,20,opt,name=updated_at,json=updatedAt,proto3" json:"updated_at,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *Doctor) Reset() {
+	*x = Doctor{}
+	mi := &file_medidhaka_v1_doctor_proto_msgTypes[0]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *Doctor) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Doctor) ProtoMessage() {}
+
+func (x *Doctor) ProtoReflect() protoreflect.Message {
+	mi := &file_medidhaka_v1_doctor_proto_msgTypes[0]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Doctor.ProtoReflect.Descriptor instead.
+func (*Doctor) Descriptor() ([]byte, []int) {
+	return file_medidhaka_v1_doctor_proto_rawDescGZIP(), []int{0}
+}
+
+func (x *Doctor) GetDoctorId() int64 {
+	if x != nil {
+		return x.DoctorId
+	}
+	return 0
+}
+
+func (x *Doctor) GetName() string {
+	if x != nil {
+		return x.Name
+	}
+	return ""
+}
+
+func (x *Doctor) GetSpecialty() string {
+	if x != nil {
+		return x.Specialty
+	}
+	return ""
+}
+
+func (x *Doctor) GetYearsExperience() int32 {
+	if x != nil {
+		return x.YearsExperience
+	}
+	return 0
+}
+
+func (x *Doctor) GetPhoneNumber() string {
+	if x != nil {
+		return x.PhoneNumber
+	}
+	return ""
+}
+
+func (x *Doctor) GetEmail() string {
+	if x != nil {
+		return x.Email
+	}
+	return ""
+}
+
+func (x *Doctor) GetImageUrl() string {
+	if x != nil {
+		return x.ImageUrl
+	}
+	return ""
+}
+
+func (x *Doctor) GetRatingAvg() float64 {
+	if x != nil {
+		return x.RatingAvg
+	}
+	return 0
+}
+
+func (x *Doctor) GetRatingCount() int32 {
+	if x != nil {
+		return x.RatingCount
+	}
+	return 0
+}
+
+func (x *Doctor) GetBmdcRegNo() string {
+	if x != nil && x.BmdcRegNo != nil {
+		return *x.BmdcRegNo
+	}
+	return ""
+}
+
+func (x *Doctor) GetGender() string {
+	if x != nil {
+		return x.Gender
+	}
+	return ""
+}
+
+func (x *Doctor) GetLanguages() []string {
+	if x != nil {
+		return x.Languages
+	}
+	return nil
+}
+
+func (x *Doctor) GetVerificationStatus() string {
+	if x != nil {
+		return x.VerificationStatus
+	}
+	return ""
+}
+
+func (x *Doctor) GetVerificationNote() string {
+	if x != nil {
+		return x.VerificationNote
+	}
+	return ""
+}
+
+func (x *Doctor) GetVerificationRequestedAt() *timestamppb.Timestamp {
+	if x != nil {
+		return x.VerificationRequestedAt
+	}
+	return nil
+}
+
+func (x *Doctor) GetVerifiedAt() *timestamppb.Timestamp {
+	if x != nil {
+		return x.VerifiedAt
+	}
+	return nil
+}
+
+func (x *Doctor) GetQualifications() []*Qualification {
+	if x != nil {
+		return x.Qualifications
+	}
+	return nil
+}
+
+func (x *Doctor) GetMinFee() float64 {
+	if x != nil && x.MinFee != nil {
+		return *x.MinFee
+	}
+	return 0
+}
+
+func (x *Doctor) GetCreatedAt() *timestamppb.Timestamp {
+	if x != nil {
+		return x.CreatedAt
+	}
+	return nil
+}
+
+func (x *Doctor) GetUpdatedAt() *timestamppb.Timestamp {
+	if x != nil {
+		return x.UpdatedAt
+	}
+	return nil
+}
+
+type Qualification struct {
+	state           protoimpl.MessageState `protogen:"open.v1"`
+	QualificationId int64                  `protobuf:"varint,1,opt,name=qualification_id,json=qualificationId,proto3" json:"qualification_id,omitempty"`
+	Degree          string                 `protobuf:"bytes,2,opt,name=degree,proto3" json:"degree,omitempty"`
+	Institution     string                 `protobuf:"bytes,3,opt,name=institution,proto3" json:"institution,omitempty"`
+	Year            *int32                 `protobuf:"varint,4,opt,name=year,proto3,oneof" json:"year,omitempty"`
+	unknownFields   protoimpl.UnknownFields
+	sizeCache       protoimpl.SizeCache
+}
+
+func (x *Qualification) Reset() {
+	*x = Qualification{}
+	mi := &file_medidhaka_v1_doctor_proto_msgTypes[1]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *Qualification) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Qualification) ProtoMessage() {}
+
+func (x *Qualification) ProtoReflect() protoreflect.Message {
+	mi := &file_medidhaka_v1_doctor_proto_msgTypes[1]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Qualification.ProtoReflect.Descriptor instead.
+func (*Qualification) Descriptor() ([]byte, []int) {
+	return file_medidhaka_v1_doctor_proto_rawDescGZIP(), []int{1}
+}
+
+func (x *Qualification) GetQualificationId() int64 {
+	if x != nil {
+		return x.QualificationId
+	}
+	return 0
+}
+
+func (x *Qualification) GetDegree() string {
+	if x != nil {
+		return x.Degree
+	}
+	return ""
+}
+
+func (x *Qualification) GetInstitution() string {
+	if x != nil {
+		return x.Institution
+	}
+	return ""
+}
+
+func (x *Qualification) GetYear() int32 {
+	if x != nil && x.Year != nil {
+		return *x.Year
+	}
+	return 0
+}
+
+type CreateDoctorRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Doctor        *Doctor                `protobuf:"bytes,1,opt,name=doctor,proto3" json:"doctor,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *CreateDoctorRequest) Reset() {
+	*x = CreateDoctorRequest{}
+	mi := &file_medidhaka_v1_doctor_proto_msgTypes[2]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *CreateDoctorRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*CreateDoctorRequest) ProtoMessage() {}
+
+func (x *CreateDoctorRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_medidhaka_v1_doctor_proto_msgTypes[2]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use CreateDoctorRequest.ProtoReflect.Descriptor instead.
+func (*CreateDoctorRequest) Descriptor() ([]byte, []int) {
+	return file_medidhaka_v1_doctor_proto_rawDescGZIP(), []int{2}
+}
+
+func (x *CreateDoctorRequest) GetDoctor() *Doctor {
+	if x != nil {
+		return x.Doctor
+	}
+	return nil
+}
+
+type GetDoctorRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	DoctorId      int64                  `protobuf:"varint,1,opt,name=doctor_id,json=doctorId,proto3" json:"doctor_id,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GetDoctorRequest) Reset() {
+	*x = GetDoctorRequest{}
+	mi := &file_medidhaka_v1_doctor_proto_msgTypes[3]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetDoctorRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetDoctorRequest) ProtoMessage() {}
+
+func (x *GetDoctorRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_medidhaka_v1_doctor_proto_msgTypes[3]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetDoctorRequest.ProtoReflect.Descriptor instead.
+func (*GetDoctorRequest) Descriptor() ([]byte, []int) {
+	return file_medidhaka_v1_doctor_proto_rawDescGZIP(), []int{3}
+}
+
+func (x *GetDoctorRequest) GetDoctorId() int64 {
+	if x != nil {
+		return x.DoctorId
+	}
+	return 0
+}
+
+type UpdateDoctorRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Doctor        *Doctor                `protobuf:"bytes,1,opt,name=doctor,proto3" json:"doctor,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *UpdateDoctorRequest) Reset() {
+	*x = UpdateDoctorRequest{}
+	mi := &file_medidhaka_v1_doctor_proto_msgTypes[4]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *UpdateDoctorRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*UpdateDoctorRequest) ProtoMessage() {}
+
+func (x *UpdateDoctorRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_medidhaka_v1_doctor_proto_msgTypes[4]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use UpdateDoctorRequest.ProtoReflect.Descriptor instead.
+func (*UpdateDoctorRequest) Descriptor() ([]byte, []int) {
+	return file_medidhaka_v1_doctor_proto_rawDescGZIP(), []int{4}
+}
+
+func (x *UpdateDoctorRequest) GetDoctor() *Doctor {
+	if x != nil {
+		return x.Doctor
+	}
+	return nil
+}
+
+type DeleteDoctorRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	DoctorId      int64                  `protobuf:"varint,1,opt,name=doctor_id,json=doctorId,proto3" json:"doctor_id,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *DeleteDoctorRequest) Reset() {
+	*x = DeleteDoctorRequest{}
+	mi := &file_medidhaka_v1_doctor_proto_msgTypes[5]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *DeleteDoctorRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*DeleteDoctorRequest) ProtoMessage() {}
+
+func (x *DeleteDoctorRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_medidhaka_v1_doctor_proto_msgTypes[5]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use DeleteDoctorRequest.ProtoReflect.Descriptor instead.
+func (*DeleteDoctorRequest) Descriptor() ([]byte, []int) {
+	return file_medidhaka_v1_doctor_proto_rawDescGZIP(), []int{5}
+}
+
+func (x *DeleteDoctorRequest) GetDoctorId() int64 {
+	if x != nil {
+		return x.DoctorId
+	}
+	return 0
+}
+
+type DeleteDoctorResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *DeleteDoctorResponse) Reset() {
+	*x = DeleteDoctorResponse{}
+	mi := &file_medidhaka_v1_doctor_proto_msgTypes[6]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *DeleteDoctorResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*DeleteDoctorResponse) ProtoMessage() {}
+
+func (x *DeleteDoctorResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_medidhaka_v1_doctor_proto_msgTypes[6]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use DeleteDoctorResponse.ProtoReflect.Descriptor instead.
+func (*DeleteDoctorResponse) Descriptor() ([]byte, []int) {
+	return file_medidhaka_v1_doctor_proto_rawDescGZIP(), []int{6}
+}
+
+// The filters of GET /v1/doctors.
+type ListDoctorsRequest struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// Name or specialty.
+	Search string `protobuf:"bytes,1,opt,name=search,proto3" json:"search,omitempty"`
+	// newest (default) or rating.
+	Sort string `protobuf:"bytes,2,opt,name=sort,proto3" json:"sort,omitempty"`
+	// New-patient fee at some affiliation, at most this.
+	MaxFee *float64 `protobuf:"fixed64,3,opt,name=max_fee,json=maxFee,proto3,oneof" json:"max_fee,omitempty"`
+	// Insurance panel code accepted by that affiliation's hospital.
+	Insurance string `protobuf:"bytes,4,opt,name=insurance,proto3" json:"insurance,omitempty"`
+	// Defaults to 1.
+	Page int32 `protobuf:"varint,5,opt,name=page,proto3" json:"page,omitempty"`
+	// Defaults to 10, at most 100.
+	Limit         int32 `protobuf:"varint,6,opt,name=limit,proto3" json:"limit,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ListDoctorsRequest) Reset() {
+	*x = ListDoctorsRequest{}
+	mi := &file_medidhaka_v1_doctor_proto_msgTypes[7]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ListDoctorsRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListDoctorsRequest) ProtoMessage() {}
+
+func (x *ListDoctorsRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_medidhaka_v1_doctor_proto_msgTypes[7]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListDoctorsRequest.ProtoReflect.Descriptor instead.
+func (*ListDoctorsRequest) Descriptor() ([]byte, []int) {
+	return file_medidhaka_v1_doctor_proto_rawDescGZIP(), []int{7}
+}
+
+func (x *ListDoctorsRequest) GetSearch() string {
+	if x != nil {
+		return x.Search
+	}
+	return ""
+}
+
+func (x *ListDoctorsRequest) GetSort() string {
+	if x != nil {
+		return x.Sort
+	}
+	return ""
+}
+
+func (x *ListDoctorsRequest) GetMaxFee() float64 {
+	if x != nil && x.MaxFee != nil {
+		return *x.MaxFee
+	}
+	return 0
+}
+
+func (x *ListDoctorsRequest) GetInsurance() string {
+	if x != nil {
+		return x.Insurance
+	}
+	return ""
+}
+
+func (x *ListDoctorsRequest) GetPage() int32 {
+	if x != nil {
+		return x.Page
+	}
+	return 0
+}
+
+func (x *ListDoctorsRequest) GetLimit() int32 {
+	if x != nil {
+		return x.Limit
+	}
+	return 0
+}
+
+type ListDoctorsResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Doctors       []*Doctor              `protobuf:"bytes,1,rep,name=doctors,proto3" json:"doctors,omitempty"`
+	Total         int32                  `protobuf:"varint,2,opt,name=total,proto3" json:"total,omitempty"`
+	Page          int32                  `protobuf:"varint,3,opt,name=page,proto3" json:"page,omitempty"`
+	Limit         int32                  `protobuf:"varint,4,opt,name=limit,proto3" json:"limit,omitempty"`
+	TotalPages    int32                  `protobuf:"varint,5,opt,name=total_pages,json=totalPages,proto3" json:"total_pages,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ListDoctorsResponse) Reset() {
+	*x = ListDoctorsResponse{}
+	mi := &file_medidhaka_v1_doctor_proto_msgTypes[8]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ListDoctorsResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListDoctorsResponse) ProtoMessage() {}
+
+func (x *ListDoctorsResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_medidhaka_v1_doctor_proto_msgTypes[8]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListDoctorsResponse.ProtoReflect.Descriptor instead.
+func (*ListDoctorsResponse) Descriptor() ([]byte, []int) {
+	return file_medidhaka_v1_doctor_proto_rawDescGZIP(), []int{8}
+}
+
+func (x *ListDoctorsResponse) GetDoctors() []*Doctor {
+	if x != nil {
+		return x.Doctors
+	}
+	return nil
+}
+
+func (x *ListDoctorsResponse) GetTotal() int32 {
+	if x != nil {
+		return x.Total
+	}
+	return 0
+}
+
+func (x *ListDoctorsResponse) GetPage() int32 {
+	if x != nil {
+		return x.Page
+	}
+	return 0
+}
+
+func (x *ListDoctorsResponse) GetLimit() int32 {
+	if x != nil {
+		return x.Limit
+	}
+	return 0
+}
+
+func (x *ListDoctorsResponse) GetTotalPages() int32 {
+	if x != nil {
+		return x.TotalPages
+	}
+	return 0
+}
+
+var File_medidhaka_v1_doctor_proto protoreflect.FileDescriptor
+
+const file_medidhaka_v1_doctor_proto_rawDesc = "" +
+	"\n" +
+	"\x19medidhaka/v1/doctor.proto\x12\fmedidhaka.v1\x1a\x1fgoogle/protobuf/timestamp.proto\"\xdd\x06\n" +
+	"\x06Doctor\x12\x1b\n" +
+	"\tdoctor_id\x18\x01 \x01(\x03R\bdoctorId\x12\x12\n" +
+	"\x04name\x18\x02 \x01(\tR\x04name\x12\x1c\n" +
+	"\tspecialty\x18\x03 \x01(\tR\tspecialty\x12)\n" +
+	"\x10years_experience\x18\x04 \x01(\x05R\x0fyearsExperience\x12!\n" +
+	"\fphone_number\x18\x05 \x01(\tR\vphoneNumber\x12\x14\n" +
+	"\x05email\x18\x06 \x01(\tR\x05email\x12\x1b\n" +
+	"\timage_url\x18\a \x01(\tR\bimageUrl\x12\x1d\n" +
+	"\n" +
+	"rating_avg\x18\b \x01(\x01R\tratingAvg\x12!\n" +
+	"\frating_count\x18\t \x01(\x05R\vratingCount\x12#\n" +
+	"\vbmdc_reg_no\x18\n" +
+	" \x01(\tH\x00R\tbmdcRegNo\x88\x01\x01\x12\x16\n" +
+	"\x06gender\x18\v \x01(\tR\x06gender\x12\x1c\n" +
+	"\tlanguages\x18\f \x03(\tR\tlanguages\x12/\n" +
+	"\x13verification_status\x18\r \x01(\tR\x12verificationStatus\x12+\n" +
+	"\x11verification_note\x18\x0e \x01(\tR\x10verificationNote\x12V\n" +
+	"\x19verification_requested_at\x18\x0f \x01(\v2\x1a.google.protobuf.TimestampR\x17verificationRequestedAt\x12;\n" +
+	"\vverified_at\x18\x10 \x01(\v2\x1a.google.protobuf.TimestampR\n" +
+	"verifiedAt\x12C\n" +
+	"\x0equalifications\x18\x11 \x03(\v2\x1b.medidhaka.v1.QualificationR\x0equalifications\x12\x1c\n" +
+	"\amin_fee\x18\x12 \x01(\x01H\x01R\x06minFee\x88\x01\x01\x129\n" +
+	"\n" +
+	"created_at\x18\x13 \x01(\v2\x1a.google.protobuf.TimestampR\tcreatedAt\x129\n" +
+	"\n" +
+	"updated_at\x18\x14 \x01(\v2\x1a.google.protobuf.TimestampR\tupdatedAtB\x0e\n" +
+	"\f_bmdc_reg_noB\n" +
+	"\n" +
+	"\b_min_fee\"\x96\x01\n" +
+	"\rQualification\x12)\n" +
+	"\x10qualification_id\x18\x01 \x01(\x03R\x0fqualificationId\x12\x16\n" +
+	"\x06degree\x18\x02 \x01(\tR\x06degree\x12 \n" +
+	"\vinstitution\x18\x03 \x01(\tR\vinstitution\x12\x17\n" +
+	"\x04year\x18\x04 \x01(\x05H\x00R\x04year\x88\x01\x01B\a\n" +
+	"\x05_year\"C\n" +
+	"\x13CreateDoctorRequest\x12,\n" +
+	"\x06doctor\x18\x01 \x01(\v2\x14.medidhaka.v1.DoctorR\x06doctor\"/\n" +
+	"\x10GetDoctorRequest\x12\x1b\n" +
+	"\tdoctor_id\x18\x01 \x01(\x03R\bdoctorId\"C\n" +
+	"\x13UpdateDoctorRequest\x12,\n" +
+	"\x06doctor\x18\x01 \x01(\v2\x14.medidhaka.v1.DoctorR\x06doctor\"2\n" +
+	"\x13DeleteDoctorRequest\x12\x1b\n" +
+	"\tdoctor_id\x18\x01 \x01(\x03R\bdoctorId\"\x16\n" +
+	"\x14DeleteDoctorResponse\"\xb2\x01\n" +
+	"\x12ListDoctorsRequest\x12\x16\n" +
+	"\x06search\x18\x01 \x01(\tR\x06search\x12\x12\n" +
+	"\x04sort\x18\x02 \x01(\tR\x04sort\x12\x1c\n" +
+	"\amax_fee\x18\x03 \x01(\x01H\x00R\x06maxFee\x88\x01\x01\x12\x1c\n" +
+	"\tinsurance\x18\x04 \x01(\tR\tinsurance\x12\x12\n" +
+	"\x04page\x18\x05 \x01(\x05R\x04page\x12\x14\n" +
+	"\x05limit\x18\x06 \x01(\x05R\x05limitB\n" +
+	"\n" +
+	"\b_max_fee\"\xa6\x01\n" +
+	"\x13ListDoctorsResponse\x12.\n" +
+	"\adoctors\x18\x01 \x03(\v2\x14.medidhaka.v1.DoctorR\adoctors\x12\x14\n" +
+	"\x05total\x18\x02 \x01(\x05R\x05total\x12\x12\n" +
+	"\x04page\x18\x03 \x01(\x05R\x04page\x12\x14\n" +
+	"\x05limit\x18\x04 \x01(\x05R\x05limit\x12\x1f\n" +
+	"\vtotal_pages\x18\x05 \x01(\x05R\n" +
+	"totalPages2\x8f\x03\n" +
+	"\rDoctorService\x12G\n" +
+	"\fCreateDoctor\x12!.medidhaka.v1.CreateDoctorRequest\x1a\x14.medidhaka.v1.Doctor\x12A\n" +
+	"\tGetDoctor\x12\x1e.medidhaka.v1.GetDoctorRequest\x1a\x14.medidhaka.v1.Doctor\x12G\n" +
+	"\fUpdateDoctor\x12!.medidhaka.v1.UpdateDoctorRequest\x1a\x14.medidhaka.v1.Doctor\x12U\n" +
+	"\fDeleteDoctor\x12!.medidhaka.v1.DeleteDoctorRequest\x1a\".medidhaka.v1.DeleteDoctorResponse\x12R\n" +
+	"\vListDoctors\x12 .medidhaka.v1.ListDoctorsRequest\x1a!.medidhaka.v1.ListDoctorsResponseB'Z%medidhaka/rpc/medidhakav1;medidhakav1b\x06proto3"
+
+var (
+	file_medidhaka_v1_doctor_proto_rawDescOnce sync.Once
+	file_medidhaka_v1_doctor_proto_rawDescData []byte
+)
+
+func file_medidhaka_v1_doctor_proto_rawDescGZIP() []byte {
+	file_medidhaka_v1_doctor_proto_rawDescOnce.Do(func() {
+		file_medidhaka_v1_doctor_proto_rawDescData = protoimpl.X.CompressGZIP(unsafe.Slice(unsafe.StringData(file_medidhaka_v1_doctor_proto_rawDesc), len(file_medidhaka_v1_doctor_proto_rawDesc)))
+	})
+	return file_medidhaka_v1_doctor_proto_rawDescData
+}
+
+var file_medidhaka_v1_doctor_proto_msgTypes = make([]protoimpl.MessageInfo, 9)
+var file_medidhaka_v1_doctor_proto_goTypes = []any{
+	(*Doctor)(nil),                // 0: medidhaka.v1.Doctor
+	(*Qualification)(nil),         // 1: medidhaka.v1.Qualification
+	(*CreateDoctorRequest)(nil),   // 2: medidhaka.v1.CreateDoctorRequest
+	(*GetDoctorRequest)(nil),      // 3: medidhaka.v1.GetDoctorRequest
+	(*UpdateDoctorRequest)(nil),   // 4: medidhaka.v1.UpdateDoctorRequest
+	(*DeleteDoctorRequest)(nil),   // 5: medidhaka.v1.DeleteDoctorRequest
+	(*DeleteDoctorResponse)(nil),  // 6: medidhaka.v1.DeleteDoctorResponse
+	(*ListDoctorsRequest)(nil),    // 7: medidhaka.v1.ListDoctorsRequest
+	(*ListDoctorsResponse)(nil),   // 8: medidhaka.v1.ListDoctorsResponse
+	(*timestamppb.Timestamp)(nil), // 9: google.protobuf.Timestamp
+}
+var file_medidhaka_v1_doctor_proto_depIdxs = []int32{
+	9,  // 0: medidhaka.v1.Doctor.verification_requested_at:type_name -> google.protobuf.Timestamp
+	9,  // 1: medidhaka.v1.Doctor.verified_at:type_name -> google.protobuf.Timestamp
+	1,  // 2: medidhaka.v1.Doctor.qualifications:type_name -> medidhaka.v1.Qualification
+	9,  // 3: medidhaka.v1.Doctor.created_at:type_name -> google.protobuf.Timestamp
+	9,  // 4: medidhaka.v1.Doctor.updated_at:type_name -> google.protobuf.Timestamp
+	0,  // 5: medidhaka.v1.CreateDoctorRequest.doctor:type_name -> medidhaka.v1.Doctor
+	0,  // 6: medidhaka.v1.UpdateDoctorRequest.doctor:type_name -> medidhaka.v1.Doctor
+	0,  // 7: medidhaka.v1.ListDoctorsResponse.doctors:type_name -> medidhaka.v1.Doctor
+	2,  // 8: medidhaka.v1.DoctorService.CreateDoctor:input_type -> medidhaka.v1.CreateDoctorRequest
+	3,  // 9: medidhaka.v1.DoctorService.GetDoctor:input_type -> medidhaka.v1.GetDoctorRequest
+	4,  // 10: medidhaka.v1.DoctorService.UpdateDoctor:input_type -> medidhaka.v1.UpdateDoctorRequest
+	5,  // 11: medidhaka.v1.DoctorService.DeleteDoctor:input_type -> medidhaka.v1.DeleteDoctorRequest
+	7,  // 12: medidhaka.v1.DoctorService.ListDoctors:input_type -> medidhaka.v1.ListDoctorsRequest
+	0,  // 13: medidhaka.v1.DoctorService.CreateDoctor:output_type -> medidhaka.v1.Doctor
+	0,  // 14: medidhaka.v1.DoctorService.GetDoctor:output_type -> medidhaka.v1.Doctor
+	0,  // 15: medidhaka.v1.DoctorService.UpdateDoctor:output_type -> medidhaka.v1.Doctor
+	6,  // 16: medidhaka.v1.DoctorService.DeleteDoctor:output_type -> medidhaka.v1.DeleteDoctorResponse
+	8,  // 17: medidhaka.v1.DoctorService.ListDoctors:output_type -> medidhaka.v1.ListDoctorsResponse
+	13, // [13:18] is the sub-list for method output_type
+	8,  // [8:13] is the sub-list for method input_type
+	8,  // [8:8] is the sub-list for extension type_name
+	8,  // [8:8] is the sub-list for extension extendee
+	0,  // [0:8] is the sub-list for field type_name
+}
+
+func init() { file_medidhaka_v1_doctor_proto_init() }
+func file_medidhaka_v1_doctor_proto_init() {
+	if File_medidhaka_v1_doctor_proto != nil {
+		return
+	}
+	file_medidhaka_v1_doctor_proto_msgTypes[0].OneofWrappers = []any{}
+	file_medidhaka_v1_doctor_proto_msgTypes[1].OneofWrappers = []any{}
+	file_medidhaka_v1_doctor_proto_msgTypes[7].OneofWrappers = []any{}
+	type x struct{}
+	out := protoimpl.TypeBuilder{
+		File: protoimpl.DescBuilder{
+			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
+			RawDescriptor: unsafe.Slice(unsafe.StringData(file_medidhaka_v1_doctor_proto_rawDesc), len(file_medidhaka_v1_doctor_proto_rawDesc)),
+			NumEnums:      0,
+			NumMessages:   9,
+			NumExtensions: 0,
+			NumServices:   1,
+		},
+		GoTypes:           file_medidhaka_v1_doctor_proto_goTypes,
+		DependencyIndexes: file_medidhaka_v1_doctor_proto_depIdxs,
+		MessageInfos:      file_medidhaka_v1_doctor_proto_msgTypes,
+	}.Build()
+	File_medidhaka_v1_doctor_proto = out.File
+	file_medidhaka_v1_doctor_proto_goTypes = nil
+	file_medidhaka_v1_doctor_proto_depIdxs = nil
+}
@@ -0,0 +1,812 @@
+// Code generated by protoc-gen-go. DO NOT EDIT.
+// versions:
+// 	protoc-gen-go v1.36.11
+// 	protoc        (unknown)
+// source: medidhaka/v1/hospital.proto
+
+package medidhakav1
+
+import (
+	protoreflect "google.golang.org/protobuf/reflect/protoreflect"
+	protoimpl "google.golang.org/protobuf/runtime/protoimpl"
+	timestamppb "google.golang.org/protobuf/types/known/timestamppb"
+	reflect "reflect"
+	sync "sync"
+	unsafe "unsafe"
+)
+
+const (
+	// Verify that this generated code is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(20 - protoimpl.MinVersion)
+	// Verify that runtime/protoimpl is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(protoimpl.MaxVersion - 20)
+)
+
+type Hospital struct {
+	state            protoimpl.MessageState `protogen:"open.v1"`
+	HospitalId       int64                  `protobuf:"varint,1,opt,name=hospital_id,json=hospitalId,proto3" json:"hospital_id,omitempty"`
+	Name             string                 `protobuf:"bytes,2,opt,name=name,proto3" json:"name,omitempty"`
+	Address          string                 `protobuf:"bytes,3,opt,name=address,proto3" json:"address,omitempty"`
+	PhoneNumber      string                 `protobuf:"bytes,4,opt,name=phone_number,json=phoneNumber,proto3" json:"phone_number,omitempty"`
+	Email            string                 `protobuf:"bytes,5,opt,name=email,proto3" json:"email,omitempty"`
+	ImageUrl         string                 `protobuf:"bytes,6,opt,name=image_url,json=imageUrl,proto3" json:"image_url,omitempty"`
+	Latitude         *float64               `protobuf:"fixed64,7,opt,name=latitude,proto3,oneof" json:"latitude,omitempty"`
+	Longitude        *float64               `protobuf:"fixed64,8,opt,name=longitude,proto3,oneof" json:"longitude,omitempty"`
+	RatingAvg        float64                `protobuf:"fixed64,9,opt,name=rating_avg,json=ratingAvg,proto3" json:"rating_avg,omitempty"`
+	RatingCount      int32                  `protobuf:"varint,10,opt,name=rating_count,json=ratingCount,proto3" json:"rating_count,omitempty"`
+	Open_24_7        bool                   `protobuf:"varint,11,opt,name=open_24_7,json=open247,proto3" json:"open_24_7,omitempty"`
+	HasEmergency     bool                   `protobuf:"varint,12,opt,name=has_emergency,json=hasEmergency,proto3" json:"has_emergency,omitempty"`
+	EmergencyPhone   string                 `protobuf:"bytes,13,opt,name=emergency_phone,json=emergencyPhone,proto3" json:"emergency_phone,omitempty"`
+	AmbulanceNumbers []string               `protobuf:"bytes,14,rep,name=ambulance_numbers,json=ambulanceNumbers,proto3" json:"ambulance_numbers,omitempty"`
+	// One of government, private or ngo.
+	HospitalType  string `protobuf:"bytes,15,opt,name=hospital_type,json=hospitalType,proto3" json:"hospital_type,omitempty"`
+	DghsLicenceNo string `protobuf:"bytes,16,opt,name=dghs_licence_no,json=dghsLicenceNo,proto3" json:"dghs_licence_no,omitempty"`
+	// YYYY-MM-DD.
+	LicenceExpiry  *string                `protobuf:"bytes,17,opt,name=licence_expiry,json=licenceExpiry,proto3,oneof" json:"licence_expiry,omitempty"`
+	LicenceExpired bool                   `protobuf:"varint,18,opt,name=licence_expired,json=licenceExpired,proto3" json:"licence_expired,omitempty"`
+	BedCapacity    *int32                 `protobuf:"varint,19,opt,name=bed_capacity,json=bedCapacity,proto3,oneof" json:"bed_capacity,omitempty"`
+	Accreditations []string               `protobuf:"bytes,20,rep,name=accreditations,proto3" json:"accreditations,omitempty"`
+	Website        string                 `protobuf:"bytes,21,opt,name=website,proto3" json:"website,omitempty"`
+	CreatedAt      *timestamppb.Timestamp `protobuf:"bytes,22,opt,name=created_at,json=createdAt,proto3" json:"created_at,omitempty"`
+	UpdatedAt      *timestamppb.Timestamp `protobuf:"bytes,23,opt,name=updated_at,json=updatedAt,proto3" json:"updated_at,omitempty"`
+	unknownFields  protoimpl.UnknownFields
+	sizeCache      protoimpl.SizeCache
+}
+
+func (x *Hospital) Reset() {
+	*x = Hospital{}
+	mi := &file_medidhaka_v1_hospital_proto_msgTypes[0]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *Hospital) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Hospital) ProtoMessage() {}
+
+func (x *Hospital) ProtoReflect() protoreflect.Message {
+	mi := &file_medidhaka_v1_hospital_proto_msgTypes[0]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Hospital.ProtoReflect.Descriptor instead.
+func (*Hospital) Descriptor() ([]byte, []int) {
+	return file_medidhaka_v1_hospital_proto_rawDescGZIP(), []int{0}
+}
+
+func (x *Hospital) GetHospitalId() int64 {
+	if x != nil {
+		return x.HospitalId
+	}
+	return 0
+}
+
+func (x *Hospital) GetName() string {
+	if x != nil {
+		return x.Name
+	}
+	return ""
+}
+
+func (x *Hospital) GetAddress() string {
+	if x != nil {
+		return x.Address
+	}
+	return ""
+}
+
+func (x *Hospital) GetPhoneNumber() string {
+	if x != nil {
+		return x.PhoneNumber
+	}
+	return ""
+}
+
+func (x *Hospital) GetEmail() string {
+	if x != nil {
+		return x.Email
+	}
+	return ""
+}
+
+func (x *Hospital) GetImageUrl() string {
+	if x != nil {
+		return x.ImageUrl
+	}
+	return ""
+}
+
+func (x *Hospital) GetLatitude() float64 {
+	if x != nil && x.Latitude != nil {
+		return *x.Latitude
+	}
+	return 0
+}
+
+func (x *Hospital) GetLongitude() float64 {
+	if x != nil && x.Longitude != nil {
+		return *x.Longitude
+	}
+	return 0
+}
+
+func (x *Hospital) GetRatingAvg() float64 {
+	if x != nil {
+		return x.RatingAvg
+	}
+	return 0
+}
+
+func (x *Hospital) GetRatingCount() int32 {
+	if x != nil {
+		return x.RatingCount
+	}
+	return 0
+}
+
+func (x *Hospital) GetOpen_24_7() bool {
+	if x != nil {
+		return x.Open_24_7
+	}
+	return false
+}
+
+func (x *Hospital) GetHasEmergency() bool {
+	if x != nil {
+		return x.HasEmergency
+	}
+	return false
+}
+
+func (x *Hospital) GetEmergencyPhone() string {
+	if x != nil {
+		return x.EmergencyPhone
+	}
+	return ""
+}
+
+func (x *Hospital) GetAmbulanceNumbers() []string {
+	if x != nil {
+		return x.AmbulanceNumbers
+	}
+	return nil
+}
+
+func (x *Hospital) GetHospitalType() string {
+	if x != nil {
+		return x.HospitalType
+	}
+	return ""
+}
+
+func (x *Hospital) GetDghsLicenceNo() string {
+	if x != nil {
+		return x.DghsLicenceNo
+	}
+	return ""
+}
+
+func (x *Hospital) GetLicenceExpiry() string {
+	if x != nil && x.LicenceExpiry != nil {
+		return *x.LicenceExpiry
+	}
+	return ""
+}
+
+func (x *Hospital) GetLicenceExpired() bool {
+	if x != nil {
+		return x.LicenceExpired
+	}
+	return false
+}
+
+func (x *Hospital) GetBedCapacity() int32 {
+	if x != nil && x.BedCapacity != nil {
+		return *x.BedCapacity
+	}
+	return 0
+}
+
+func (x *Hospital) GetAccreditations() []string {
+	if x != nil {
+		return x.Accreditations
+	}
+	return nil
+}
+
+func (x *Hospital) GetWebsite() string {
+	if x != nil {
+		return x.Website
+	}
+	return ""
+}
+
+func (x *Hospital) GetCreatedAt() *timestamppb.Timestamp {
+	if x != nil {
+		return x.CreatedAt
+	}
+	return nil
+}
+
+func (x *Hospital) GetUpdatedAt() *timestamppb.Timestamp {
+	if x != nil {
+		return x.UpdatedAt
+	}
+	return nil
+}
+
+type CreateHospitalRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Hospital      *Hospital              `protobuf:"bytes,1,opt,name=hospital,proto3" json:"hospital,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *CreateHospitalRequest) Reset() {
+	*x = CreateHospitalRequest{}
+	mi := &file_medidhaka_v1_hospital_proto_msgTypes[1]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *CreateHospitalRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*CreateHospitalRequest) ProtoMessage() {}
+
+func (x *CreateHospitalRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_medidhaka_v1_hospital_proto_msgTypes[1]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use CreateHospitalRequest.ProtoReflect.Descriptor instead.
+func (*CreateHospitalRequest) Descriptor() ([]byte, []int) {
+	return file_medidhaka_v1_hospital_proto_rawDescGZIP(), []int{1}
+}
+
+func (x *CreateHospitalRequest) GetHospital() *Hospital {
+	if x != nil {
+		return x.Hospital
+	}
+	return nil
+}
+
+type GetHospitalRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	HospitalId    int64                  `protobuf:"varint,1,opt,name=hospital_id,json=hospitalId,proto3" json:"hospital_id,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GetHospitalRequest) Reset() {
+	*x = GetHospitalRequest{}
+	mi := &file_medidhaka_v1_hospital_proto_msgTypes[2]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetHospitalRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetHospitalRequest) ProtoMessage() {}
+
+func (x *GetHospitalRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_medidhaka_v1_hospital_proto_msgTypes[2]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetHospitalRequest.ProtoReflect.Descriptor instead.
+func (*GetHospitalRequest) Descriptor() ([]byte, []int) {
+	return file_medidhaka_v1_hospital_proto_rawDescGZIP(), []int{2}
+}
+
+func (x *GetHospitalRequest) GetHospitalId() int64 {
+	if x != nil {
+		return x.HospitalId
+	}
+	return 0
+}
+
+type UpdateHospitalRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Hospital      *Hospital              `protobuf:"bytes,1,opt,name=hospital,proto3" json:"hospital,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *UpdateHospitalRequest) Reset() {
+	*x = UpdateHospitalRequest{}
+	mi := &file_medidhaka_v1_hospital_proto_msgTypes[3]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *UpdateHospitalRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*UpdateHospitalRequest) ProtoMessage() {}
+
+func (x *UpdateHospitalRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_medidhaka_v1_hospital_proto_msgTypes[3]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use UpdateHospitalRequest.ProtoReflect.Descriptor instead.
+func (*UpdateHospitalRequest) Descriptor() ([]byte, []int) {
+	return file_medidhaka_v1_hospital_proto_rawDescGZIP(), []int{3}
+}
+
+func (x *UpdateHospitalRequest) GetHospital() *Hospital {
+	if x != nil {
+		return x.Hospital
+	}
+	return nil
+}
+
+type DeleteHospitalRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	HospitalId    int64                  `protobuf:"varint,1,opt,name=hospital_id,json=hospitalId,proto3" json:"hospital_id,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *DeleteHospitalRequest) Reset() {
+	*x = DeleteHospitalRequest{}
+	mi := &file_medidhaka_v1_hospital_proto_msgTypes[4]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *DeleteHospitalRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*DeleteHospitalRequest) ProtoMessage() {}
+
+func (x *DeleteHospitalRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_medidhaka_v1_hospital_proto_msgTypes[4]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use DeleteHospitalRequest.ProtoReflect.Descriptor instead.
+func (*DeleteHospitalRequest) Descriptor() ([]byte, []int) {
+	return file_medidhaka_v1_hospital_proto_rawDescGZIP(), []int{4}
+}
+
+func (x *DeleteHospitalRequest) GetHospitalId() int64 {
+	if x != nil {
+		return x.HospitalId
+	}
+	return 0
+}
+
+type DeleteHospitalResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *DeleteHospitalResponse) Reset() {
+	*x = DeleteHospitalResponse{}
+	mi := &file_medidhaka_v1_hospital_proto_msgTypes[5]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *DeleteHospitalResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*DeleteHospitalResponse) ProtoMessage() {}
+
+func (x *DeleteHospitalResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_medidhaka_v1_hospital_proto_msgTypes[5]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use DeleteHospitalResponse.ProtoReflect.Descriptor instead.
+func (*DeleteHospitalResponse) Descriptor() ([]byte, []int) {
+	return file_medidhaka_v1_hospital_proto_rawDescGZIP(), []int{5}
+}
+
+// The filters of GET /v1/hospitals.
+type ListHospitalsRequest struct {
+	state  protoimpl.MessageState `protogen:"open.v1"`
+	Search string                 `protobuf:"bytes,1,opt,name=search,proto3" json:"search,omitempty"`
+	// Service codes; a hospital must offer all of them.
+	Services     []string `protobuf:"bytes,2,rep,name=services,proto3" json:"services,omitempty"`
+	OpenNow      bool     `protobuf:"varint,3,opt,name=open_now,json=openNow,proto3" json:"open_now,omitempty"`
+	Emergency    bool     `protobuf:"varint,4,opt,name=emergency,proto3" json:"emergency,omitempty"`
+	HospitalType string   `protobuf:"bytes,5,opt,name=hospital_type,json=hospitalType,proto3" json:"hospital_type,omitempty"`
+	// valid or expired.
+	Licence       string `protobuf:"bytes,6,opt,name=licence,proto3" json:"licence,omitempty"`
+	MinBeds       int32  `protobuf:"varint,7,opt,name=min_beds,json=minBeds,proto3" json:"min_beds,omitempty"`
+	Accreditation string `protobuf:"bytes,8,opt,name=accreditation,proto3" json:"accreditation,omitempty"`
+	// newest (default) or rating.
+	Sort string `protobuf:"bytes,9,opt,name=sort,proto3" json:"sort,omitempty"`
+	// Defaults to 1.
+	Page int32 `protobuf:"varint,10,opt,name=page,proto3" json:"page,omitempty"`
+	// Defaults to 10, at most 100.
+	Limit         int32 `protobuf:"varint,11,opt,name=limit,proto3" json:"limit,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ListHospitalsRequest) Reset() {
+	*x = ListHospitalsRequest{}
+	mi := &file_medidhaka_v1_hospital_proto_msgTypes[6]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ListHospitalsRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListHospitalsRequest) ProtoMessage() {}
+
+func (x *ListHospitalsRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_medidhaka_v1_hospital_proto_msgTypes[6]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListHospitalsRequest.ProtoReflect.Descriptor instead.
+func (*ListHospitalsRequest) Descriptor() ([]byte, []int) {
+	return file_medidhaka_v1_hospital_proto_rawDescGZIP(), []int{6}
+}
+
+func (x *ListHospitalsRequest) GetSearch() string {
+	if x != nil {
+		return x.Search
+	}
+	return ""
+}
+
+func (x *ListHospitalsRequest) GetServices() []string {
+	if x != nil {
+		return x.Services
+	}
+	return nil
+}
+
+func (x *ListHospitalsRequest) GetOpenNow() bool {
+	if x != nil {
+		return x.OpenNow
+	}
+	return false
+}
+
+func (x *ListHospitalsRequest) GetEmergency() bool {
+	if x != nil {
+		return x.Emergency
+	}
+	return false
+}
+
+func (x *ListHospitalsRequest) GetHospitalType() string {
+	if x != nil {
+		return x.HospitalType
+	}
+	return ""
+}
+
+func (x *ListHospitalsRequest) GetLicence() string {
+	if x != nil {
+		return x.Licence
+	}
+	return ""
+}
+
+func (x *ListHospitalsRequest) GetMinBeds() int32 {
+	if x != nil {
+		return x.MinBeds
+	}
+	return 0
+}
+
+func (x *ListHospitalsRequest) GetAccreditation() string {
+	if x != nil {
+		return x.Accreditation
+	}
+	return ""
+}
+
+func (x *ListHospitalsRequest) GetSort() string {
+	if x != nil {
+		return x.Sort
+	}
+	return ""
+}
+
+func (x *ListHospitalsRequest) GetPage() int32 {
+	if x != nil {
+		return x.Page
+	}
+	return 0
+}
+
+func (x *ListHospitalsRequest) GetLimit() int32 {
+	if x != nil {
+		return x.Limit
+	}
+	return 0
+}
+
+type ListHospitalsResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Hospitals     []*Hospital            `protobuf:"bytes,1,rep,name=hospitals,proto3" json:"hospitals,omitempty"`
+	Total         int32                  `protobuf:"varint,2,opt,name=total,proto3" json:"total,omitempty"`
+	Page          int32                  `protobuf:"varint,3,opt,name=page,proto3" json:"page,omitempty"`
+	Limit         int32                  `protobuf:"varint,4,opt,name=limit,proto3" json:"limit,omitempty"`
+	TotalPages    int32                  `protobuf:"varint,5,opt,name=total_pages,json=totalPages,proto3" json:"total_pages,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ListHospitalsResponse) Reset() {
+	*x = ListHospitalsResponse{}
+	mi := &file_medidhaka_v1_hospital_proto_msgTypes[7]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ListHospitalsResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListHospitalsResponse) ProtoMessage() {}
+
+func (x *ListHospitalsResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_medidhaka_v1_hospital_proto_msgTypes[7]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListHospitalsResponse.ProtoReflect.Descriptor instead.
+func (*ListHospitalsResponse) Descriptor() ([]byte, []int) {
+	return file_medidhaka_v1_hospital_proto_rawDescGZIP(), []int{7}
+}
+
+func (x *ListHospitalsResponse) GetHospitals() []*Hospital {
+	if x != nil {
+		return x.Hospitals
+	}
+	return nil
+}
+
+func (x *ListHospitalsResponse) GetTotal() int32 {
+	if x != nil {
+		return x.Total
+	}
+	return 0
+}
+
+func (x *ListHospitalsResponse) GetPage() int32 {
+	if x != nil {
+		return x.Page
+	}
+	return 0
+}
+
+func (x *ListHospitalsResponse) GetLimit() int32 {
+	if x != nil {
+		return x.Limit
+	}
+	return 0
+}
+
+func (x *ListHospitalsResponse) GetTotalPages() int32 {
+	if x != nil {
+		return x.TotalPages
+	}
+	return 0
+}
+
+var File_medidhaka_v1_hospital_proto protoreflect.FileDescriptor
+
+const file_medidhaka_v1_hospital_proto_rawDesc = "" +
+	"\n" +
+	"\x1bmedidhaka/v1/hospital.proto\x12\fmedidhaka.v1\x1a\x1fgoogle/protobuf/timestamp.proto\"\x8d\a\n" +
+	"\bHospital\x12\x1f\n" +
+	"\vhospital_id\x18\x01 \x01(\x03R\n" +
+	"hospitalId\x12\x12\n" +
+	"\x04name\x18\x02 \x01(\tR\x04name\x12\x18\n" +
+	"\aaddress\x18\x03 \x01(\tR\aaddress\x12!\n" +
+	"\fphone_number\x18\x04 \x01(\tR\vphoneNumber\x12\x14\n" +
+	"\x05email\x18\x05 \x01(\tR\x05email\x12\x1b\n" +
+	"\timage_url\x18\x06 \x01(\tR\bimageUrl\x12\x1f\n" +
+	"\blatitude\x18\a \x01(\x01H\x00R\blatitude\x88\x01\x01\x12!\n" +
+	"\tlongitude\x18\b \x01(\x01H\x01R\tlongitude\x88\x01\x01\x12\x1d\n" +
+	"\n" +
+	"rating_avg\x18\t \x01(\x01R\tratingAvg\x12!\n" +
+	"\frating_count\x18\n" +
+	" \x01(\x05R\vratingCount\x12\x1a\n" +
+	"\topen_24_7\x18\v \x01(\bR\aopen247\x12#\n" +
+	"\rhas_emergency\x18\f \x01(\bR\fhasEmergency\x12'\n" +
+	"\x0femergency_phone\x18\r \x01(\tR\x0eemergencyPhone\x12+\n" +
+	"\x11ambulance_numbers\x18\x0e \x03(\tR\x10ambulanceNumbers\x12#\n" +
+	"\rhospital_type\x18\x0f \x01(\tR\fhospitalType\x12&\n" +
+	"\x0fdghs_licence_no\x18\x10 \x01(\tR\rdghsLicenceNo\x12*\n" +
+	"\x0elicence_expiry\x18\x11 \x01(\tH\x02R\rlicenceExpiry\x88\x01\x01\x12'\n" +
+	"\x0flicence_expired\x18\x12 \x01(\bR\x0elicenceExpired\x12&\n" +
+	"\fbed_capacity\x18\x13 \x01(\x05H\x03R\vbedCapacity\x88\x01\x01\x12&\n" +
+	"\x0eaccreditations\x18\x14 \x03(\tR\x0eaccreditations\x12\x18\n" +
+	"\awebsite\x18\x15 \x01(\tR\awebsite\x129\n" +
+	"\n" +
+	"created_at\x18\x16 \x01(\v2\x1a.google.protobuf.TimestampR\tcreatedAt\x129\n" +
+	"\n" +
+	"updated_at\x18\x17 \x01(\v2\x1a.google.protobuf.TimestampR\tupdatedAtB\v\n" +
+	"\t_latitudeB\f\n" +
+	"\n" +
+	"_longitudeB\x11\n" +
+	"\x0f_licence_expiryB\x0f\n" +
+	"\r_bed_capacity\"K\n" +
+	"\x15CreateHospitalRequest\x122\n" +
+	"\bhospital\x18\x01 \x01(\v2\x16.medidhaka.v1.HospitalR\bhospital\"5\n" +
+	"\x12GetHospitalRequest\x12\x1f\n" +
+	"\vhospital_id\x18\x01 \x01(\x03R\n" +
+	"hospitalId\"K\n" +
+	"\x15UpdateHospitalRequest\x122\n" +
+	"\bhospital\x18\x01 \x01(\v2\x16.medidhaka.v1.HospitalR\bhospital\"8\n" +
+	"\x15DeleteHospitalRequest\x12\x1f\n" +
+	"\vhospital_id\x18\x01 \x01(\x03R\n" +
+	"hospitalId\"\x18\n" +
+	"\x16DeleteHospitalResponse\"\xc1\x02\n" +
+	"\x14ListHospitalsRequest\x12\x16\n" +
+	"\x06search\x18\x01 \x01(\tR\x06search\x12\x1a\n" +
+	"\bservices\x18\x02 \x03(\tR\bservices\x12\x19\n" +
+	"\bopen_now\x18\x03 \x01(\bR\aopenNow\x12\x1c\n" +
+	"\temergency\x18\x04 \x01(\bR\temergency\x12#\n" +
+	"\rhospital_type\x18\x05 \x01(\tR\fhospitalType\x12\x18\n" +
+	"\alicence\x18\x06 \x01(\tR\alicence\x12\x19\n" +
+	"\bmin_beds\x18\a \x01(\x05R\aminBeds\x12$\n" +
+	"\raccreditation\x18\b \x01(\tR\raccreditation\x12\x12\n" +
+	"\x04sort\x18\t \x01(\tR\x04sort\x12\x12\n" +
+	"\x04page\x18\n" +
+	" \x01(\x05R\x04page\x12\x14\n" +
+	"\x05limit\x18\v \x01(\x05R\x05limit\"\xae\x01\n" +
+	"\x15ListHospitalsResponse\x124\n" +
+	"\thospitals\x18\x01 \x03(\v2\x16.medidhaka.v1.HospitalR\thospitals\x12\x14\n" +
+	"\x05total\x18\x02 \x01(\x05R\x05total\x12\x12\n" +
+	"\x04page\x18\x03 \x01(\x05R\x04page\x12\x14\n" +
+	"\x05limit\x18\x04 \x01(\x05R\x05limit\x12\x1f\n" +
+	"\vtotal_pages\x18\x05 \x01(\x05R\n" +
+	"totalPages2\xaf\x03\n" +
+	"\x0fHospitalService\x12M\n" +
+	"\x0eCreateHospital\x12#.medidhaka.v1.CreateHospitalRequest\x1a\x16.medidhaka.v1.Hospital\x12G\n" +
+	"\vGetHospital\x12 .medidhaka.v1.GetHospitalRequest\x1a\x16.medidhaka.v1.Hospital\x12M\n" +
+	"\x0eUpdateHospital\x12#.medidhaka.v1.UpdateHospitalRequest\x1a\x16.medidhaka.v1.Hospital\x12[\n" +
+	"\x0eDeleteHospital\x12#.medidhaka.v1.DeleteHospitalRequest\x1a$.medidhaka.v1.DeleteHospitalResponse\x12X\n" +
+	"\rListHospitals\x12\".medidhaka.v1.ListHospitalsRequest\x1a#.medidhaka.v1.ListHospitalsResponseB'Z%medidhaka/rpc/medidhakav1;medidhakav1b\x06proto3"
+
+var (
+	file_medidhaka_v1_hospital_proto_rawDescOnce sync.Once
+	file_medidhaka_v1_hospital_proto_rawDescData []byte
+)
+
+func file_medidhaka_v1_hospital_proto_rawDescGZIP() []byte {
+	file_medidhaka_v1_hospital_proto_rawDescOnce.Do(func() {
+		file_medidhaka_v1_hospital_proto_rawDescData = protoimpl.X.CompressGZIP(unsafe.Slice(unsafe.StringData(file_medidhaka_v1_hospital_proto_rawDesc), len(file_medidhaka_v1_hospital_proto_rawDesc)))
+	})
+	return file_medidhaka_v1_hospital_proto_rawDescData
+}
+
+var file_medidhaka_v1_hospital_proto_msgTypes = make([]protoimpl.MessageInfo, 8)
+var file_medidhaka_v1_hospital_proto_goTypes = []any{
+	(*Hospital)(nil),               // 0: medidhaka.v1.Hospital
+	(*CreateHospitalRequest)(nil),  // 1: medidhaka.v1.CreateHospitalRequest
+	(*GetHospitalRequest)(nil),     // 2: medidhaka.v1.GetHospitalRequest
+	(*UpdateHospitalRequest)(nil),  // 3: medidhaka.v1.UpdateHospitalRequest
+	(*DeleteHospitalRequest)(nil),  // 4: medidhaka.v1.DeleteHospitalRequest
+	(*DeleteHospitalResponse)(nil), // 5: medidhaka.v1.DeleteHospitalResponse
+	(*ListHospitalsRequest)(nil),   // 6: medidhaka.v1.ListHospitalsRequest
+	(*ListHospitalsResponse)(nil),  // 7: medidhaka.v1.ListHospitalsResponse
+	(*timestamppb.Timestamp)(nil),  // 8: google.protobuf.Timestamp
+}
+var file_medidhaka_v1_hospital_proto_depIdxs = []int32{
+	8,  // 0: medidhaka.v1.Hospital.created_at:type_name -> google.protobuf.Timestamp
+	8,  // 1: medidhaka.v1.Hospital.updated_at:type_name -> google.protobuf.Timestamp
+	0,  // 2: medidhaka.v1.CreateHospitalRequest.hospital:type_name -> medidhaka.v1.Hospital
+	0,  // 3: medidhaka.v1.UpdateHospitalRequest.hospital:type_name -> medidhaka.v1.Hospital
+	0,  // 4: medidhaka.v1.ListHospitalsResponse.hospitals:type_name -> medidhaka.v1.Hospital
+	1,  // 5: medidhaka.v1.HospitalService.CreateHospital:input_type -> medidhaka.v1.CreateHospitalRequest
+	2,  // 6: medidhaka.v1.HospitalService.GetHospital:input_type -> medidhaka.v1.GetHospitalRequest
+	3,  // 7: medidhaka.v1.HospitalService.UpdateHospital:input_type -> medidhaka.v1.UpdateHospitalRequest
+	4,  // 8: medidhaka.v1.HospitalService.DeleteHospital:input_type -> medidhaka.v1.DeleteHospitalRequest
+	6,  // 9: medidhaka.v1.HospitalService.ListHospitals:input_type -> medidhaka.v1.ListHospitalsRequest
+	0,  // 10: medidhaka.v1.HospitalService.CreateHospital:output_type -> medidhaka.v1.Hospital
+	0,  // 11: medidhaka.v1.HospitalService.GetHospital:output_type -> medidhaka.v1.Hospital
+	0,  // 12: medidhaka.v1.HospitalService.UpdateHospital:output_type -> medidhaka.v1.Hospital
+	5,  // 13: medidhaka.v1.HospitalService.DeleteHospital:output_type -> medidhaka.v1.DeleteHospitalResponse
+	7,  // 14: medidhaka.v1.HospitalService.ListHospitals:output_type -> medidhaka.v1.ListHospitalsResponse
+	10, // [10:15] is the sub-list for method output_type
+	5,  // [5:10] is the sub-list for method input_type
+	5,  // [5:5] is the sub-list for extension type_name
+	5,  // [5:5] is the sub-list for extension extendee
+	0,  // [0:5] is the sub-list for field type_name
+}
+
+func init() { file_medidhaka_v1_hospital_proto_init() }
+func file_medidhaka_v1_hospital_proto_init() {
+	if File_medidhaka_v1_hospital_proto != nil {
+		return
+	}
+	file_medidhaka_v1_hospital_proto_msgTypes[0].OneofWrappers = []any{}
+	type x struct{}
+	out := protoimpl.TypeBuilder{
+		File: protoimpl.DescBuilder{
+			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
+			RawDescriptor: unsafe.Slice(unsafe.StringData(file_medidhaka_v1_hospital_proto_rawDesc), len(file_medidhaka_v1_hospital_proto_rawDesc)),
+			NumEnums:      0,
+			NumMessages:   8,
+			NumExtensions: 0,
+			NumServices:   1,
+		},
+		GoTypes:           file_medidhaka_v1_hospital_proto_goTypes,
+		DependencyIndexes: file_medidhaka_v1_hospital_proto_depIdxs,
+		MessageInfos:      file_medidhaka_v1_hospital_proto_msgTypes,
+	}.Build()
+	File_medidhaka_v1_hospital_proto = out.File
+	file_medidhaka_v1_hospital_proto_goTypes = nil
+	file_medidhaka_v1_hospital_proto_depIdxs = nil
+}
@@ -0,0 +1,571 @@
+// Code generated by protoc-gen-go. DO NOT EDIT.
+// versions:
+// 	protoc-gen-go v1.36.11
+// 	protoc        (unknown)
+// source: medidhaka/v1/hospital_doctor.proto
+
+package medidhakav1
+
+import (
+	protoreflect "google.golang.org/protobuf/reflect/protoreflect"
+	protoimpl "google.golang.org/protobuf/runtime/protoimpl"
+	timestamppb "google.golang.org/protobuf/types/known/timestamppb"
+	reflect "reflect"
+	sync "sync"
+	unsafe "unsafe"
+)
+
+const (
+	// Verify that this generated code is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(20 - protoimpl.MinVersion)
+	// Verify that runtime/protoimpl is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(protoimpl.MaxVersion - 20)
+)
+
+// HospitalDoctor is a doctor's affiliation with a hospital. Fees are what the
+// doctor charges at that hospital; unset means unknown.
+type HospitalDoctor struct {
+	state           protoimpl.MessageState `protogen:"open.v1"`
+	HospitalId      int64                  `protobuf:"varint,1,opt,name=hospital_id,json=hospitalId,proto3" json:"hospital_id,omitempty"`
+	DoctorId        int64                  `protobuf:"varint,2,opt,name=doctor_id,json=doctorId,proto3" json:"doctor_id,omitempty"`
+	Role            string                 `protobuf:"bytes,3,opt,name=role,proto3" json:"role,omitempty"`
+	FeeNewPatient   *float64               `protobuf:"fixed64,4,opt,name=fee_new_patient,json=feeNewPatient,proto3,oneof" json:"fee_new_patient,omitempty"`
+	FeeFollowUp     *float64               `protobuf:"fixed64,5,opt,name=fee_follow_up,json=feeFollowUp,proto3,oneof" json:"fee_follow_up,omitempty"`
+	FeeReportReview *float64               `protobuf:"fixed64,6,opt,name=fee_report_review,json=feeReportReview,proto3,oneof" json:"fee_report_review,omitempty"`
+	// ISO 4217 code, BDT by default.
+	FeeCurrency   string                 `protobuf:"bytes,7,opt,name=fee_currency,json=feeCurrency,proto3" json:"fee_currency,omitempty"`
+	CreatedAt     *timestamppb.Timestamp `protobuf:"bytes,8,opt,name=created_at,json=createdAt,proto3" json:"created_at,omitempty"`
+	UpdatedAt     *timestamppb.Timestamp `protobuf:"bytes,9,opt,name=updated_at,json=updatedAt,proto3" json:"updated_at,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *HospitalDoctor) Reset() {
+	*x = HospitalDoctor{}
+	mi := &file_medidhaka_v1_hospital_doctor_proto_msgTypes[0]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *HospitalDoctor) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*HospitalDoctor) ProtoMessage() {}
+
+func (x *HospitalDoctor) ProtoReflect() protoreflect.Message {
+	mi := &file_medidhaka_v1_hospital_doctor_proto_msgTypes[0]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use HospitalDoctor.ProtoReflect.Descriptor instead.
+func (*HospitalDoctor) Descriptor() ([]byte, []int) {
+	return file_medidhaka_v1_hospital_doctor_proto_rawDescGZIP(), []int{0}
+}
+
+func (x *HospitalDoctor) GetHospitalId() int64 {
+	if x != nil {
+		return x.HospitalId
+	}
+	return 0
+}
+
+func (x *HospitalDoctor) GetDoctorId() int64 {
+	if x != nil {
+		return x.DoctorId
+	}
+	return 0
+}
+
+func (x *HospitalDoctor) GetRole() string {
+	if x != nil {
+		return x.Role
+	}
+	return ""
+}
+
+func (x *HospitalDoctor) GetFeeNewPatient() float64 {
+	if x != nil && x.FeeNewPatient != nil {
+		return *x.FeeNewPatient
+	}
+	return 0
+}
+
+func (x *HospitalDoctor) GetFeeFollowUp() float64 {
+	if x != nil && x.FeeFollowUp != nil {
+		return *x.FeeFollowUp
+	}
+	return 0
+}
+
+func (x *HospitalDoctor) GetFeeReportReview() float64 {
+	if x != nil && x.FeeReportReview != nil {
+		return *x.FeeReportReview
+	}
+	return 0
+}
+
+func (x *HospitalDoctor) GetFeeCurrency() string {
+	if x != nil {
+		return x.FeeCurrency
+	}
+	return ""
+}
+
+func (x *HospitalDoctor) GetCreatedAt() *timestamppb.Timestamp {
+	if x != nil {
+		return x.CreatedAt
+	}
+	return nil
+}
+
+func (x *HospitalDoctor) GetUpdatedAt() *timestamppb.Timestamp {
+	if x != nil {
+		return x.UpdatedAt
+	}
+	return nil
+}
+
+type AssignDoctorRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Affiliation   *HospitalDoctor        `protobuf:"bytes,1,opt,name=affiliation,proto3" json:"affiliation,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *AssignDoctorRequest) Reset() {
+	*x = AssignDoctorRequest{}
+	mi := &file_medidhaka_v1_hospital_doctor_proto_msgTypes[1]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *AssignDoctorRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*AssignDoctorRequest) ProtoMessage() {}
+
+func (x *AssignDoctorRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_medidhaka_v1_hospital_doctor_proto_msgTypes[1]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use AssignDoctorRequest.ProtoReflect.Descriptor instead.
+func (*AssignDoctorRequest) Descriptor() ([]byte, []int) {
+	return file_medidhaka_v1_hospital_doctor_proto_rawDescGZIP(), []int{1}
+}
+
+func (x *AssignDoctorRequest) GetAffiliation() *HospitalDoctor {
+	if x != nil {
+		return x.Affiliation
+	}
+	return nil
+}
+
+type GetHospitalDoctorRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	HospitalId    int64                  `protobuf:"varint,1,opt,name=hospital_id,json=hospitalId,proto3" json:"hospital_id,omitempty"`
+	DoctorId      int64                  `protobuf:"varint,2,opt,name=doctor_id,json=doctorId,proto3" json:"doctor_id,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GetHospitalDoctorRequest) Reset() {
+	*x = GetHospitalDoctorRequest{}
+	mi := &file_medidhaka_v1_hospital_doctor_proto_msgTypes[2]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetHospitalDoctorRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetHospitalDoctorRequest) ProtoMessage() {}
+
+func (x *GetHospitalDoctorRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_medidhaka_v1_hospital_doctor_proto_msgTypes[2]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetHospitalDoctorRequest.ProtoReflect.Descriptor instead.
+func (*GetHospitalDoctorRequest) Descriptor() ([]byte, []int) {
+	return file_medidhaka_v1_hospital_doctor_proto_rawDescGZIP(), []int{2}
+}
+
+func (x *GetHospitalDoctorRequest) GetHospitalId() int64 {
+	if x != nil {
+		return x.HospitalId
+	}
+	return 0
+}
+
+func (x *GetHospitalDoctorRequest) GetDoctorId() int64 {
+	if x != nil {
+		return x.DoctorId
+	}
+	return 0
+}
+
+type UpdateFeesRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Affiliation   *HospitalDoctor        `protobuf:"bytes,1,opt,name=affiliation,proto3" json:"affiliation,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *UpdateFeesRequest) Reset() {
+	*x = UpdateFeesRequest{}
+	mi := &file_medidhaka_v1_hospital_doctor_proto_msgTypes[3]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *UpdateFeesRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*UpdateFeesRequest) ProtoMessage() {}
+
+func (x *UpdateFeesRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_medidhaka_v1_hospital_doctor_proto_msgTypes[3]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use UpdateFeesRequest.ProtoReflect.Descriptor instead.
+func (*UpdateFeesRequest) Descriptor() ([]byte, []int) {
+	return file_medidhaka_v1_hospital_doctor_proto_rawDescGZIP(), []int{3}
+}
+
+func (x *UpdateFeesRequest) GetAffiliation() *HospitalDoctor {
+	if x != nil {
+		return x.Affiliation
+	}
+	return nil
+}
+
+type RemoveDoctorRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	HospitalId    int64                  `protobuf:"varint,1,opt,name=hospital_id,json=hospitalId,proto3" json:"hospital_id,omitempty"`
+	DoctorId      int64                  `protobuf:"varint,2,opt,name=doctor_id,json=doctorId,proto3" json:"doctor_id,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *RemoveDoctorRequest) Reset() {
+	*x = RemoveDoctorRequest{}
+	mi := &file_medidhaka_v1_hospital_doctor_proto_msgTypes[4]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *RemoveDoctorRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*RemoveDoctorRequest) ProtoMessage() {}
+
+func (x *RemoveDoctorRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_medidhaka_v1_hospital_doctor_proto_msgTypes[4]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use RemoveDoctorRequest.ProtoReflect.Descriptor instead.
+func (*RemoveDoctorRequest) Descriptor() ([]byte, []int) {
+	return file_medidhaka_v1_hospital_doctor_proto_rawDescGZIP(), []int{4}
+}
+
+func (x *RemoveDoctorRequest) GetHospitalId() int64 {
+	if x != nil {
+		return x.HospitalId
+	}
+	return 0
+}
+
+func (x *RemoveDoctorRequest) GetDoctorId() int64 {
+	if x != nil {
+		return x.DoctorId
+	}
+	return 0
+}
+
+type RemoveDoctorResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *RemoveDoctorResponse) Reset() {
+	*x = RemoveDoctorResponse{}
+	mi := &file_medidhaka_v1_hospital_doctor_proto_msgTypes[5]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *RemoveDoctorResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*RemoveDoctorResponse) ProtoMessage() {}
+
+func (x *RemoveDoctorResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_medidhaka_v1_hospital_doctor_proto_msgTypes[5]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use RemoveDoctorResponse.ProtoReflect.Descriptor instead.
+func (*RemoveDoctorResponse) Descriptor() ([]byte, []int) {
+	return file_medidhaka_v1_hospital_doctor_proto_rawDescGZIP(), []int{5}
+}
+
+type ListHospitalDoctorsRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	HospitalId    int64                  `protobuf:"varint,1,opt,name=hospital_id,json=hospitalId,proto3" json:"hospital_id,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ListHospitalDoctorsRequest) Reset() {
+	*x = ListHospitalDoctorsRequest{}
+	mi := &file_medidhaka_v1_hospital_doctor_proto_msgTypes[6]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ListHospitalDoctorsRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListHospitalDoctorsRequest) ProtoMessage() {}
+
+func (x *ListHospitalDoctorsRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_medidhaka_v1_hospital_doctor_proto_msgTypes[6]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListHospitalDoctorsRequest.ProtoReflect.Descriptor instead.
+func (*ListHospitalDoctorsRequest) Descriptor() ([]byte, []int) {
+	return file_medidhaka_v1_hospital_doctor_proto_rawDescGZIP(), []int{6}
+}
+
+func (x *ListHospitalDoctorsRequest) GetHospitalId() int64 {
+	if x != nil {
+		return x.HospitalId
+	}
+	return 0
+}
+
+type ListHospitalDoctorsResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Doctors       []*Doctor              `protobuf:"bytes,1,rep,name=doctors,proto3" json:"doctors,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ListHospitalDoctorsResponse) Reset() {
+	*x = ListHospitalDoctorsResponse{}
+	mi := &file_medidhaka_v1_hospital_doctor_proto_msgTypes[7]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ListHospitalDoctorsResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListHospitalDoctorsResponse) ProtoMessage() {}
+
+func (x *ListHospitalDoctorsResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_medidhaka_v1_hospital_doctor_proto_msgTypes[7]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListHospitalDoctorsResponse.ProtoReflect.Descriptor instead.
+func (*ListHospitalDoctorsResponse) Descriptor() ([]byte, []int) {
+	return file_medidhaka_v1_hospital_doctor_proto_rawDescGZIP(), []int{7}
+}
+
+func (x *ListHospitalDoctorsResponse) GetDoctors() []*Doctor {
+	if x != nil {
+		return x.Doctors
+	}
+	return nil
+}
+
+var File_medidhaka_v1_hospital_doctor_proto protoreflect.FileDescriptor
+
+const file_medidhaka_v1_hospital_doctor_proto_rawDesc = "" +
+	"\n" +
+	"\"medidhaka/v1/hospital_doctor.proto\x12\fmedidhaka.v1\x1a\x1fgoogle/protobuf/timestamp.proto\x1a\x19medidhaka/v1/doctor.proto\"\xbe\x03\n" +
+	"\x0eHospitalDoctor\x12\x1f\n" +
+	"\vhospital_id\x18\x01 \x01(\x03R\n" +
+	"hospitalId\x12\x1b\n" +
+	"\tdoctor_id\x18\x02 \x01(\x03R\bdoctorId\x12\x12\n" +
+	"\x04role\x18\x03 \x01(\tR\x04role\x12+\n" +
+	"\x0ffee_new_patient\x18\x04 \x01(\x01H\x00R\rfeeNewPatient\x88\x01\x01\x12'\n" +
+	"\rfee_follow_up\x18\x05 \x01(\x01H\x01R\vfeeFollowUp\x88\x01\x01\x12/\n" +
+	"\x11fee_report_review\x18\x06 \x01(\x01H\x02R\x0ffeeReportReview\x88\x01\x01\x12!\n" +
+	"\ffee_currency\x18\a \x01(\tR\vfeeCurrency\x129\n" +
+	"\n" +
+	"created_at\x18\b \x01(\v2\x1a.google.protobuf.TimestampR\tcreatedAt\x129\n" +
+	"\n" +
+	"updated_at\x18\t \x01(\v2\x1a.google.protobuf.TimestampR\tupdatedAtB\x12\n" +
+	"\x10_fee_new_patientB\x10\n" +
+	"\x0e_fee_follow_upB\x14\n" +
+	"\x12_fee_report_review\"U\n" +
+	"\x13AssignDoctorRequest\x12>\n" +
+	"\vaffiliation\x18\x01 \x01(\v2\x1c.medidhaka.v1.HospitalDoctorR\vaffiliation\"X\n" +
+	"\x18GetHospitalDoctorRequest\x12\x1f\n" +
+	"\vhospital_id\x18\x01 \x01(\x03R\n" +
+	"hospitalId\x12\x1b\n" +
+	"\tdoctor_id\x18\x02 \x01(\x03R\bdoctorId\"S\n" +
+	"\x11UpdateFeesRequest\x12>\n" +
+	"\vaffiliation\x18\x01 \x01(\v2\x1c.medidhaka.v1.HospitalDoctorR\vaffiliation\"S\n" +
+	"\x13RemoveDoctorRequest\x12\x1f\n" +
+	"\vhospital_id\x18\x01 \x01(\x03R\n" +
+	"hospitalId\x12\x1b\n" +
+	"\tdoctor_id\x18\x02 \x01(\x03R\bdoctorId\"\x16\n" +
+	"\x14RemoveDoctorResponse\"=\n" +
+	"\x1aListHospitalDoctorsRequest\x12\x1f\n" +
+	"\vhospital_id\x18\x01 \x01(\x03R\n" +
+	"hospitalId\"M\n" +
+	"\x1bListHospitalDoctorsResponse\x12.\n" +
+	"\adoctors\x18\x01 \x03(\v2\x14.medidhaka.v1.DoctorR\adoctors2\xd3\x03\n" +
+	"\x15HospitalDoctorService\x12O\n" +
+	"\fAssignDoctor\x12!.medidhaka.v1.AssignDoctorRequest\x1a\x1c.medidhaka.v1.HospitalDoctor\x12Y\n" +
+	"\x11GetHospitalDoctor\x12&.medidhaka.v1.GetHospitalDoctorRequest\x1a\x1c.medidhaka.v1.HospitalDoctor\x12K\n" +
+	"\n" +
+	"UpdateFees\x12\x1f.medidhaka.v1.UpdateFeesRequest\x1a\x1c.medidhaka.v1.HospitalDoctor\x12U\n" +
+	"\fRemoveDoctor\x12!.medidhaka.v1.RemoveDoctorRequest\x1a\".medidhaka.v1.RemoveDoctorResponse\x12j\n" +
+	"\x13ListHospitalDoctors\x12(.medidhaka.v1.ListHospitalDoctorsRequest\x1a).medidhaka.v1.ListHospitalDoctorsResponseB'Z%medidhaka/rpc/medidhakav1;medidhakav1b\x06proto3"
+
+var (
+	file_medidhaka_v1_hospital_doctor_proto_rawDescOnce sync.Once
+	file_medidhaka_v1_hospital_doctor_proto_rawDescData []byte
+)
+
+func file_medidhaka_v1_hospital_doctor_proto_rawDescGZIP() []byte {
+	file_medidhaka_v1_hospital_doctor_proto_rawDescOnce.Do(func() {
+		file_medidhaka_v1_hospital_doctor_proto_rawDescData = protoimpl.X.CompressGZIP(unsafe.Slice(unsafe.StringData(file_medidhaka_v1_hospital_doctor_proto_rawDesc), len(file_medidhaka_v1_hospital_doctor_proto_rawDesc)))
+	})
+	return file_medidhaka_v1_hospital_doctor_proto_rawDescData
+}
+
+var file_medidhaka_v1_hospital_doctor_proto_msgTypes = make([]protoimpl.MessageInfo, 8)
+var file_medidhaka_v1_hospital_doctor_proto_goTypes = []any{
+	(*HospitalDoctor)(nil),              // 0: medidhaka.v1.HospitalDoctor
+	(*AssignDoctorRequest)(nil),         // 1: medidhaka.v1.AssignDoctorRequest
+	(*GetHospitalDoctorRequest)(nil),    // 2: medidhaka.v1.GetHospitalDoctorRequest
+	(*UpdateFeesRequest)(nil),           // 3: medidhaka.v1.UpdateFeesRequest
+	(*RemoveDoctorRequest)(nil),         // 4: medidhaka.v1.RemoveDoctorRequest
+	(*RemoveDoctorResponse)(nil),        // 5: medidhaka.v1.RemoveDoctorResponse
+	(*ListHospitalDoctorsRequest)(nil),  // 6: medidhaka.v1.ListHospitalDoctorsRequest
+	(*ListHospitalDoctorsResponse)(nil), // 7: medidhaka.v1.ListHospitalDoctorsResponse
+	(*timestamppb.Timestamp)(nil),       // 8: google.protobuf.Timestamp
+	(*Doctor)(nil),                      // 9: medidhaka.v1.Doctor
+}
+var file_medidhaka_v1_hospital_doctor_proto_depIdxs = []int32{
+	8,  // 0: medidhaka.v1.HospitalDoctor.created_at:type_name -> google.protobuf.Timestamp
+	8,  // 1: medidhaka.v1.HospitalDoctor.updated_at:type_name -> google.protobuf.Timestamp
+	0,  // 2: medidhaka.v1.AssignDoctorRequest.affiliation:type_name -> medidhaka.v1.HospitalDoctor
+	0,  // 3: medidhaka.v1.UpdateFeesRequest.affiliation:type_name -> medidhaka.v1.HospitalDoctor
+	9,  // 4: medidhaka.v1.ListHospitalDoctorsResponse.doctors:type_name -> medidhaka.v1.Doctor
+	1,  // 5: medidhaka.v1.HospitalDoctorService.AssignDoctor:input_type -> medidhaka.v1.AssignDoctorRequest
+	2,  // 6: medidhaka.v1.HospitalDoctorService.GetHospitalDoctor:input_type -> medidhaka.v1.GetHospitalDoctorRequest
+	3,  // 7: medidhaka.v1.HospitalDoctorService.UpdateFees:input_type -> medidhaka.v1.UpdateFeesRequest
+	4,  // 8: medidhaka.v1.HospitalDoctorService.RemoveDoctor:input_type -> medidhaka.v1.RemoveDoctorRequest
+	6,  // 9: medidhaka.v1.HospitalDoctorService.ListHospitalDoctors:input_type -> medidhaka.v1.ListHospitalDoctorsRequest
+	0,  // 10: medidhaka.v1.HospitalDoctorService.AssignDoctor:output_type -> medidhaka.v1.HospitalDoctor
+	0,  // 11: medidhaka.v1.HospitalDoctorService.GetHospitalDoctor:output_type -> medidhaka.v1.HospitalDoctor
+	0,  // 12: medidhaka.v1.HospitalDoctorService.UpdateFees:output_type -> medidhaka.v1.HospitalDoctor
+	5,  // 13: medidhaka.v1.HospitalDoctorService.RemoveDoctor:output_type -> medidhaka.v1.RemoveDoctorResponse
+	7,  // 14: medidhaka.v1.HospitalDoctorService.ListHospitalDoctors:output_type -> medidhaka.v1.ListHospitalDoctorsResponse
+	10, // [10:15] is the sub-list for method output_type
+	5,  // [5:10] is the sub-list for method input_type
+	5,  // [5:5] is the sub-list for extension type_name
+	5,  // [5:5] is the sub-list for extension extendee
+	0,  // [0:5] is the sub-list for field type_name
+}
+
+func init() { file_medidhaka_v1_hospital_doctor_proto_init() }
+func file_medidhaka_v1_hospital_doctor_proto_init() {
+	if File_medidhaka_v1_hospital_doctor_proto != nil {
+		return
+	}
+	file_medidhaka_v1_doctor_proto_init()
+	file_medidhaka_v1_hospital_doctor_proto_msgTypes[0].OneofWrappers = []any{}
+	type x struct{}
+	out := protoimpl.TypeBuilder{
+		File: protoimpl.DescBuilder{
+			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
+			RawDescriptor: unsafe.Slice(unsafe.StringData(file_medidhaka_v1_hospital_doctor_proto_rawDesc), len(file_medidhaka_v1_hospital_doctor_proto_rawDesc)),
+			NumEnums:      0,
+			NumMessages:   8,
+			NumExtensions: 0,
+			NumServices:   1,
+		},
+		GoTypes:           file_medidhaka_v1_hospital_doctor_proto_goTypes,
+		DependencyIndexes: file_medidhaka_v1_hospital_doctor_proto_depIdxs,
+		MessageInfos:      file_medidhaka_v1_hospital_doctor_proto_msgTypes,
+	}.Build()
+	File_medidhaka_v1_hospital_doctor_proto = out.File
+	file_medidhaka_v1_hospital_doctor_proto_goTypes = nil
+	file_medidhaka_v1_hospital_doctor_proto_depIdxs = nil
+}
@@ -0,0 +1,285 @@
+// Code generated by protoc-gen-go-grpc. DO NOT EDIT.
+// versions:
+// - protoc-gen-go-grpc v1.5.1
+// - protoc             (unknown)
+// source: medidhaka/v1/hospital_doctor.proto
+
+package medidhakav1
+
+import (
+	context "context"
+	grpc "google.golang.org/grpc"
+	codes "google.golang.org/grpc/codes"
+	status "google.golang.org/grpc/status"
+)
+
+// This is a compile-time assertion to ensure that this generated file
+// is compatible with the grpc package it is being compiled against.
+// Requires gRPC-Go v1.64.0 or later.
+const _ = grpc.SupportPackageIsVersion9
+
+const (
+	HospitalDoctorService_AssignDoctor_FullMethodName        = "/medidhaka.v1.HospitalDoctorService/AssignDoctor"
+	HospitalDoctorService_GetHospitalDoctor_FullMethodName   = "/medidhaka.v1.HospitalDoctorService/GetHospitalDoctor"
+	HospitalDoctorService_UpdateFees_FullMethodName          = "/medidhaka.v1.HospitalDoctorService/UpdateFees"
+	HospitalDoctorService_RemoveDoctor_FullMethodName        = "/medidhaka.v1.HospitalDoctorService/RemoveDoctor"
+	HospitalDoctorService_ListHospitalDoctors_FullMethodName = "/medidhaka.v1.HospitalDoctorService/ListHospitalDoctors"
+)
+
+// HospitalDoctorServiceClient is the client API for HospitalDoctorService service.
+//
+// For semantics around ctx use and closing/ending streaming RPCs, please refer to https://pkg.go.dev/google.golang.org/grpc/?tab=doc#ClientConn.NewStream.
+//
+// HospitalDoctorService manages the affiliations of doctors with hospitals,
+// like the /v1/hospital-doctor routes.
+type HospitalDoctorServiceClient interface {
+	// AssignDoctor adds a doctor to a hospital and returns the affiliation.
+	AssignDoctor(ctx context.Context, in *AssignDoctorRequest, opts ...grpc.CallOption) (*HospitalDoctor, error)
+	GetHospitalDoctor(ctx context.Context, in *GetHospitalDoctorRequest, opts ...grpc.CallOption) (*HospitalDoctor, error)
+	// UpdateFees replaces the fees; unset fees become unknown.
+	UpdateFees(ctx context.Context, in *UpdateFeesRequest, opts ...grpc.CallOption) (*HospitalDoctor, error)
+	RemoveDoctor(ctx context.Context, in *RemoveDoctorRequest, opts ...grpc.CallOption) (*RemoveDoctorResponse, error)
+	// ListHospitalDoctors returns the verified doctors assigned to a hospital.
+	ListHospitalDoctors(ctx context.Context, in *ListHospitalDoctorsRequest, opts ...grpc.CallOption) (*ListHospitalDoctorsResponse, error)
+}
+
+type hospitalDoctorServiceClient struct {
+	cc grpc.ClientConnInterface
+}
+
+func NewHospitalDoctorServiceClient(cc grpc.ClientConnInterface) HospitalDoctorServiceClient {
+	return &hospitalDoctorServiceClient{cc}
+}
+
+func (c *hospitalDoctorServiceClient) AssignDoctor(ctx context.Context, in *AssignDoctorRequest, opts ...grpc.CallOption) (*HospitalDoctor, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(HospitalDoctor)
+	err := c.cc.Invoke(ctx, HospitalDoctorService_AssignDoctor_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *hospitalDoctorServiceClient) GetHospitalDoctor(ctx context.Context, in *GetHospitalDoctorRequest, opts ...grpc.CallOption) (*HospitalDoctor, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(HospitalDoctor)
+	err := c.cc.Invoke(ctx, HospitalDoctorService_GetHospitalDoctor_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *hospitalDoctorServiceClient) UpdateFees(ctx context.Context, in *UpdateFeesRequest, opts ...grpc.CallOption) (*HospitalDoctor, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(HospitalDoctor)
+	err := c.cc.Invoke(ctx, HospitalDoctorService_UpdateFees_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *hospitalDoctorServiceClient) RemoveDoctor(ctx context.Context, in *RemoveDoctorRequest, opts ...grpc.CallOption) (*RemoveDoctorResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(RemoveDoctorResponse)
+	err := c.cc.Invoke(ctx, HospitalDoctorService_RemoveDoctor_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *hospitalDoctorServiceClient) ListHospitalDoctors(ctx context.Context, in *ListHospitalDoctorsRequest, opts ...grpc.CallOption) (*ListHospitalDoctorsResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(ListHospitalDoctorsResponse)
+	err := c.cc.Invoke(ctx, HospitalDoctorService_ListHospitalDoctors_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+// HospitalDoctorServiceServer is the server API for HospitalDoctorService service.
+// All implementations must embed UnimplementedHospitalDoctorServiceServer
+// for forward compatibility.
+//
+// HospitalDoctorService manages the affiliations of doctors with hospitals,
+// like the /v1/hospital-doctor routes.
+type HospitalDoctorServiceServer interface {
+	// AssignDoctor adds a doctor to a hospital and returns the affiliation.
+	AssignDoctor(context.Context, *AssignDoctorRequest) (*HospitalDoctor, error)
+	GetHospitalDoctor(context.Context, *GetHospitalDoctorRequest) (*HospitalDoctor, error)
+	// UpdateFees replaces the fees; unset fees become unknown.
+	UpdateFees(context.Context, *UpdateFeesRequest) (*HospitalDoctor, error)
+	RemoveDoctor(context.Context, *RemoveDoctorRequest) (*RemoveDoctorResponse, error)
+	// ListHospitalDoctors returns the verified doctors assigned to a hospital.
+	ListHospitalDoctors(context.Context, *ListHospitalDoctorsRequest) (*ListHospitalDoctorsResponse, error)
+	mustEmbedUnimplementedHospitalDoctorServiceServer()
+}
+
+// UnimplementedHospitalDoctorServiceServer must be embedded to have
+// forward compatible implementations.
+//
+// NOTE: this should be embedded by value instead of pointer to avoid a nil
+// pointer dereference when methods are called.
+type UnimplementedHospitalDoctorServiceServer struct{}
+
+func (UnimplementedHospitalDoctorServiceServer) AssignDoctor(context.Context, *AssignDoctorRequest) (*HospitalDoctor, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method AssignDoctor not implemented")
+}
+func (UnimplementedHospitalDoctorServiceServer) GetHospitalDoctor(context.Context, *GetHospitalDoctorRequest) (*HospitalDoctor, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetHospitalDoctor not implemented")
+}
+func (UnimplementedHospitalDoctorServiceServer) UpdateFees(context.Context, *UpdateFeesRequest) (*HospitalDoctor, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method UpdateFees not implemented")
+}
+func (UnimplementedHospitalDoctorServiceServer) RemoveDoctor(context.Context, *RemoveDoctorRequest) (*RemoveDoctorResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method RemoveDoctor not implemented")
+}
+func (UnimplementedHospitalDoctorServiceServer) ListHospitalDoctors(context.Context, *ListHospitalDoctorsRequest) (*ListHospitalDoctorsResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ListHospitalDoctors not implemented")
+}
+func (UnimplementedHospitalDoctorServiceServer) mustEmbedUnimplementedHospitalDoctorServiceServer() {}
+func (UnimplementedHospitalDoctorServiceServer) testEmbeddedByValue()                               {}
+
+// UnsafeHospitalDoctorServiceServer may be embedded to opt out of forward compatibility for this service.
+// Use of this interface is not recommended, as added methods to HospitalDoctorServiceServer will
+// result in compilation errors.
+type UnsafeHospitalDoctorServiceServer interface {
+	mustEmbedUnimplementedHospitalDoctorServiceServer()
+}
+
+func RegisterHospitalDoctorServiceServer(s grpc.ServiceRegistrar, srv HospitalDoctorServiceServer) {
+	// If the following call pancis, it indicates UnimplementedHospitalDoctorServiceServer was
+	// embedded by pointer and is nil.  This will cause panics if an
+	// unimplemented method is ever invoked, so we test this at initialization
+	// time to prevent it from happening at runtime later due to I/O.
+	if t, ok := srv.(interface{ testEmbeddedByValue() }); ok {
+		t.testEmbeddedByValue()
+	}
+	s.RegisterService(&HospitalDoctorService_ServiceDesc, srv)
+}
+
+func _HospitalDoctorService_AssignDoctor_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(AssignDoctorRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(HospitalDoctorServiceServer).AssignDoctor(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: HospitalDoctorService_AssignDoctor_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(HospitalDoctorServiceServer).AssignDoctor(ctx, req.(*AssignDoctorRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _HospitalDoctorService_GetHospitalDoctor_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetHospitalDoctorRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(HospitalDoctorServiceServer).GetHospitalDoctor(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: HospitalDoctorService_GetHospitalDoctor_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(HospitalDoctorServiceServer).GetHospitalDoctor(ctx, req.(*GetHospitalDoctorRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _HospitalDoctorService_UpdateFees_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(UpdateFeesRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(HospitalDoctorServiceServer).UpdateFees(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: HospitalDoctorService_UpdateFees_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(HospitalDoctorServiceServer).UpdateFees(ctx, req.(*UpdateFeesRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _HospitalDoctorService_RemoveDoctor_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(RemoveDoctorRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(HospitalDoctorServiceServer).RemoveDoctor(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: HospitalDoctorService_RemoveDoctor_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(HospitalDoctorServiceServer).RemoveDoctor(ctx, req.(*RemoveDoctorRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _HospitalDoctorService_ListHospitalDoctors_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ListHospitalDoctorsRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(HospitalDoctorServiceServer).ListHospitalDoctors(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: HospitalDoctorService_ListHospitalDoctors_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(HospitalDoctorServiceServer).ListHospitalDoctors(ctx, req.(*ListHospitalDoctorsRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+// HospitalDoctorService_ServiceDesc is the grpc.ServiceDesc for HospitalDoctorService service.
+// It's only intended for direct use with grpc.RegisterService,
+// and not to be introspected or modified (even as a copy)
+var HospitalDoctorService_ServiceDesc = grpc.ServiceDesc{
+	ServiceName: "medidhaka.v1.HospitalDoctorService",
+	HandlerType: (*HospitalDoctorServiceServer)(nil),
+	Methods: []grpc.MethodDesc{
+		{
+			MethodName: "AssignDoctor",
+			Handler:    _HospitalDoctorService_AssignDoctor_Handler,
+		},
+		{
+			MethodName: "GetHospitalDoctor",
+			Handler:    _HospitalDoctorService_GetHospitalDoctor_Handler,
+		},
+		{
+			MethodName: "UpdateFees",
+			Handler:    _HospitalDoctorService_UpdateFees_Handler,
+		},
+		{
+			MethodName: "RemoveDoctor",
+			Handler:    _HospitalDoctorService_RemoveDoctor_Handler,
+		},
+		{
+			MethodName: "ListHospitalDoctors",
+			Handler:    _HospitalDoctorService_ListHospitalDoctors_Handler,
+		},
+	},
+	Streams:  []grpc.StreamDesc{},
+	Metadata: "medidhaka/v1/hospital_doctor.proto",
+}
@@ -0,0 +1,199 @@
+// Code generated by protoc-gen-go. DO NOT EDIT.
+// versions:
+// 	protoc-gen-go v1.36.11
+// 	protoc        (unknown)
+// source: medidhaka/v1/search.proto
+
+package medidhakav1
+
+import (
+	protoreflect "google.golang.org/protobuf/reflect/protoreflect"
+	protoimpl "google.golang.org/protobuf/runtime/protoimpl"
+	reflect "reflect"
+	sync "sync"
+	unsafe "unsafe"
+)
+
+const (
+	// Verify that this generated code is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(20 - protoimpl.MinVersion)
+	// Verify that runtime/protoimpl is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(protoimpl.MaxVersion - 20)
+)
+
+type SearchRequest struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	Query string                 `protobuf:"bytes,1,opt,name=query,proto3" json:"query,omitempty"`
+	// Results per kind; defaults to 3, at most 20.
+	Limit         int32 `protobuf:"varint,2,opt,name=limit,proto3" json:"limit,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *SearchRequest) Reset() {
+	*x = SearchRequest{}
+	mi := &file_medidhaka_v1_search_proto_msgTypes[0]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *SearchRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*SearchRequest) ProtoMessage() {}
+
+func (x *SearchRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_medidhaka_v1_search_proto_msgTypes[0]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use SearchRequest.ProtoReflect.Descriptor instead.
+func (*SearchRequest) Descriptor() ([]byte, []int) {
+	return file_medidhaka_v1_search_proto_rawDescGZIP(), []int{0}
+}
+
+func (x *SearchRequest) GetQuery() string {
+	if x != nil {
+		return x.Query
+	}
+	return ""
+}
+
+func (x *SearchRequest) GetLimit() int32 {
+	if x != nil {
+		return x.Limit
+	}
+	return 0
+}
+
+type SearchResponse struct {
+	state     protoimpl.MessageState `protogen:"open.v1"`
+	Hospitals []*Hospital            `protobuf:"bytes,1,rep,name=hospitals,proto3" json:"hospitals,omitempty"`
+	// Verified doctors only.
+	Doctors       []*Doctor `protobuf:"bytes,2,rep,name=doctors,proto3" json:"doctors,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *SearchResponse) Reset() {
+	*x = SearchResponse{}
+	mi := &file_medidhaka_v1_search_proto_msgTypes[1]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *SearchResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*SearchResponse) ProtoMessage() {}
+
+func (x *SearchResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_medidhaka_v1_search_proto_msgTypes[1]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use SearchResponse.ProtoReflect.Descriptor instead.
+func (*SearchResponse) Descriptor() ([]byte, []int) {
+	return file_medidhaka_v1_search_proto_rawDescGZIP(), []int{1}
+}
+
+func (x *SearchResponse) GetHospitals() []*Hospital {
+	if x != nil {
+		return x.Hospitals
+	}
+	return nil
+}
+
+func (x *SearchResponse) GetDoctors() []*Doctor {
+	if x != nil {
+		return x.Doctors
+	}
+	return nil
+}
+
+var File_medidhaka_v1_search_proto protoreflect.FileDescriptor
+
+const file_medidhaka_v1_search_proto_rawDesc = "" +
+	"\n" +
+	"\x19medidhaka/v1/search.proto\x12\fmedidhaka.v1\x1a\x19medidhaka/v1/doctor.proto\x1a\x1bmedidhaka/v1/hospital.proto\";\n" +
+	"\rSearchRequest\x12\x14\n" +
+	"\x05query\x18\x01 \x01(\tR\x05query\x12\x14\n" +
+	"\x05limit\x18\x02 \x01(\x05R\x05limit\"v\n" +
+	"\x0eSearchResponse\x124\n" +
+	"\thospitals\x18\x01 \x03(\v2\x16.medidhaka.v1.HospitalR\thospitals\x12.\n" +
+	"\adoctors\x18\x02 \x03(\v2\x14.medidhaka.v1.DoctorR\adoctors2T\n" +
+	"\rSearchService\x12C\n" +
+	"\x06Search\x12\x1b.medidhaka.v1.SearchRequest\x1a\x1c.medidhaka.v1.SearchResponseB'Z%medidhaka/rpc/medidhakav1;medidhakav1b\x06proto3"
+
+var (
+	file_medidhaka_v1_search_proto_rawDescOnce sync.Once
+	file_medidhaka_v1_search_proto_rawDescData []byte
+)
+
+func file_medidhaka_v1_search_proto_rawDescGZIP() []byte {
+	file_medidhaka_v1_search_proto_rawDescOnce.Do(func() {
+		file_medidhaka_v1_search_proto_rawDescData = protoimpl.X.CompressGZIP(unsafe.Slice(unsafe.StringData(file_medidhaka_v1_search_proto_rawDesc), len(file_medidhaka_v1_search_proto_rawDesc)))
+	})
+	return file_medidhaka_v1_search_proto_rawDescData
+}
+
+var file_medidhaka_v1_search_proto_msgTypes = make([]protoimpl.MessageInfo, 2)
+var file_medidhaka_v1_search_proto_goTypes = []any{
+	(*SearchRequest)(nil),  // 0: medidhaka.v1.SearchRequest
+	(*SearchResponse)(nil), // 1: medidhaka.v1.SearchResponse
+	(*Hospital)(nil),       // 2: medidhaka.v1.Hospital
+	(*Doctor)(nil),         // 3: medidhaka.v1.Doctor
+}
+var file_medidhaka_v1_search_proto_depIdxs = []int32{
+	2, // 0: medidhaka.v1.SearchResponse.hospitals:type_name -> medidhaka.v1.Hospital
+	3, // 1: medidhaka.v1.SearchResponse.doctors:type_name -> medidhaka.v1.Doctor
+	0, // 2: medidhaka.v1.SearchService.Search:input_type -> medidhaka.v1.SearchRequest
+	1, // 3: medidhaka.v1.SearchService.Search:output_type -> medidhaka.v1.SearchResponse
+	3, // [3:4] is the sub-list for method output_type
+	2, // [2:3] is the sub-list for method input_type
+	2, // [2:2] is the sub-list for extension type_name
+	2, // [2:2] is the sub-list for extension extendee
+	0, // [0:2] is the sub-list for field type_name
+}
+
+func init() { file_medidhaka_v1_search_proto_init() }
+func file_medidhaka_v1_search_proto_init() {
+	if File_medidhaka_v1_search_proto != nil {
+		return
+	}
+	file_medidhaka_v1_doctor_proto_init()
+	file_medidhaka_v1_hospital_proto_init()
+	type x struct{}
+	out := protoimpl.TypeBuilder{
+		File: protoimpl.DescBuilder{
+			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
+			RawDescriptor: unsafe.Slice(unsafe.StringData(file_medidhaka_v1_search_proto_rawDesc), len(file_medidhaka_v1_search_proto_rawDesc)),
+			NumEnums:      0,
+			NumMessages:   2,
+			NumExtensions: 0,
+			NumServices:   1,
+		},
+		GoTypes:           file_medidhaka_v1_search_proto_goTypes,
+		DependencyIndexes: file_medidhaka_v1_search_proto_depIdxs,
+		MessageInfos:      file_medidhaka_v1_search_proto_msgTypes,
+	}.Build()
+	File_medidhaka_v1_search_proto = out.File
+	file_medidhaka_v1_search_proto_goTypes = nil
+	file_medidhaka_v1_search_proto_depIdxs = nil
+}
@@ -0,0 +1,47 @@
+package rpc
+
+import (
+	"context"
+	"errors"
+	"medidhaka/repo"
+	pb "medidhaka/rpc/medidhakav1"
+	"strings"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// maxSearchLimit caps the results per kind of a search.
+const maxSearchLimit = 20
+
+type searchServer struct {
+	pb.UnimplementedSearchServiceServer
+	doctorRepo   repo.DoctorRepo
+	hospitalRepo repo.HospitalRepo
+}
+
+func (s *searchServer) Search(ctx context.Context, req *pb.SearchRequest) (*pb.SearchResponse, error) {
+	query := strings.TrimSpace(req.Query)
+	if query == "" {
+		return nil, status.Error(codes.InvalidArgument, "Search query is required")
+	}
+	limit := int(req.Limit)
+	if limit < 1 {
+		limit = 3
+	}
+	if limit > maxSearchLimit {
+		limit = maxSearchLimit
+	}
+
+	doctors, _, err1 := s.doctorRepo.List(repo.DoctorFilter{Search: query, Status: repo.VerificationVerified}, 0, limit)
+	hospitals, _, err2 := s.hospitalRepo.List(repo.HospitalFilter{Search: query}, 0, limit)
+	if err := errors.Join(err1, err2); err != nil {
+		return nil, internalError("Failed to fetch search results", err)
+	}
+
+	resp := &pb.SearchResponse{Doctors: doctorsToProto(doctors)}
+	for _, h := range hospitals {
+		resp.Hospitals = append(resp.Hospitals, hospitalToProto(h))
+	}
+	return resp, nil
+}
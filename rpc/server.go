@@ -0,0 +1,87 @@
+// Package rpc serves the hospital, doctor and affiliation CRUD and the search
+// of the HTTP API over gRPC, for internal services that prefer it.
+package rpc
+
+//go:generate protoc -I ../proto --go_out=.. --go_opt=module=medidhaka --go-grpc_out=.. --go-grpc_opt=module=medidhaka ../proto/medidhaka/v1/hospital.proto ../proto/medidhaka/v1/doctor.proto ../proto/medidhaka/v1/hospital_doctor.proto ../proto/medidhaka/v1/search.proto
+
+import (
+	"fmt"
+	"log"
+	"medidhaka/config"
+	"medidhaka/infra/ratelimit"
+	"medidhaka/infra/storage"
+	"medidhaka/repo"
+	pb "medidhaka/rpc/medidhakav1"
+	"net"
+	"os"
+	"strconv"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/reflection"
+	"google.golang.org/grpc/status"
+)
+
+// Deps are the repositories and services the gRPC API is built on, shared
+// with the HTTP server.
+type Deps struct {
+	HospitalRepo       repo.HospitalRepo
+	DoctorRepo         repo.DoctorRepo
+	HospitalDoctorRepo repo.HospitalDoctorRepo
+	WebhookRepo        repo.WebhookRepo
+	Store              storage.Storage
+}
+
+// Start serves the gRPC API on conf.GrpcPort. Calls that change data need
+// conf.AdminApiKey in the x-admin-key metadata, and every client is rate
+// limited.
+func Start(conf config.Config, deps Deps) {
+	limiter := ratelimit.New(ratelimit.Rate, ratelimit.Burst)
+	server := grpc.NewServer(grpc.ChainUnaryInterceptor(logger, recoverer, rateLimit(limiter), requireAdmin(conf.AdminApiKey)))
+
+	pb.RegisterHospitalServiceServer(server, &hospitalServer{repo: deps.HospitalRepo, store: deps.Store, webhooks: deps.WebhookRepo})
+	pb.RegisterDoctorServiceServer(server, &doctorServer{repo: deps.DoctorRepo, store: deps.Store, webhooks: deps.WebhookRepo})
+	pb.RegisterHospitalDoctorServiceServer(server, &hospitalDoctorServer{repo: deps.HospitalDoctorRepo, webhooks: deps.WebhookRepo})
+	pb.RegisterSearchServiceServer(server, &searchServer{doctorRepo: deps.DoctorRepo, hospitalRepo: deps.HospitalRepo})
+	reflection.Register(server)
+
+	port := ":" + strconv.Itoa(conf.GrpcPort)
+
+	lis, err := net.Listen("tcp", port)
+	if err != nil {
+		fmt.Println("Error starting the gRPC server:", err)
+		os.Exit(1)
+	}
+
+	fmt.Println("gRPC server running on port:", port)
+
+	if err := server.Serve(lis); err != nil {
+		fmt.Println("Error starting the gRPC server:", err)
+		os.Exit(1)
+	}
+}
+
+// maxLimit caps the page size of the list calls.
+const maxLimit = 100
+
+// pagination applies the defaults of the HTTP listings to a requested page
+// and limit and returns them with the offset.
+func pagination(page, limit int32) (int, int, int) {
+	p, l := int(page), int(limit)
+	if p < 1 {
+		p = 1
+	}
+	if l < 1 {
+		l = 10
+	}
+	if l > maxLimit {
+		l = maxLimit
+	}
+	return p, l, (p - 1) * l
+}
+
+// internalError logs err and hides it from the client behind msg.
+func internalError(msg string, err error) error {
+	log.Printf("%s: %v", msg, err)
+	return status.Error(codes.Internal, msg)
+}
@@ -0,0 +1,38 @@
+package util
+
+import "context"
+
+type contextKey string
+
+const (
+	patientIDKey contextKey = "patient_id"
+	staffKeyKey  contextKey = "staff_key"
+)
+
+// StaffIdentity is the hospital staff key a request was made with.
+type StaffIdentity struct {
+	KeyID      int
+	HospitalID int
+}
+
+// WithPatientID stores the authenticated patient's ID in the context.
+func WithPatientID(ctx context.Context, id int) context.Context {
+	return context.WithValue(ctx, patientIDKey, id)
+}
+
+// PatientID returns the authenticated patient's ID, if any.
+func PatientID(ctx context.Context) (int, bool) {
+	id, ok := ctx.Value(patientIDKey).(int)
+	return id, ok
+}
+
+// WithStaff stores the authenticated staff key in the context.
+func WithStaff(ctx context.Context, staff StaffIdentity) context.Context {
+	return context.WithValue(ctx, staffKeyKey, staff)
+}
+
+// Staff returns the authenticated staff key, if any.
+func Staff(ctx context.Context) (StaffIdentity, bool) {
+	staff, ok := ctx.Value(staffKeyKey).(StaffIdentity)
+	return staff, ok
+}
@@ -0,0 +1,80 @@
+package util
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"io"
+)
+
+// Export formats. Excel is CSV with a UTF-8 byte order mark and CRLF line
+// endings, so that Excel shows Bangla text correctly when the file is opened.
+const (
+	ExportCSV    = "csv"
+	ExportExcel  = "excel"
+	ExportNDJSON = "ndjson"
+)
+
+var ExportFormats = []string{ExportCSV, ExportExcel, ExportNDJSON}
+
+var ErrUnknownExportFormat = errors.New("unknown export format")
+
+// ExportContentType and ExportExtension describe a file in the format.
+func ExportContentType(format string) string {
+	if format == ExportNDJSON {
+		return "application/x-ndjson"
+	}
+	return "text/csv; charset=utf-8"
+}
+
+func ExportExtension(format string) string {
+	if format == ExportNDJSON {
+		return ".ndjson"
+	}
+	return ".csv"
+}
+
+// RecordWriter writes export rows one at a time: CSV formats write the flat
+// record, NDJSON writes the value as a JSON line.
+type RecordWriter struct {
+	csv  *csv.Writer
+	json *json.Encoder
+}
+
+// NewRecordWriter starts an export in the format, writing the CSV header
+// straight away.
+func NewRecordWriter(w io.Writer, format string, header []string) (*RecordWriter, error) {
+	switch format {
+	case ExportNDJSON:
+		return &RecordWriter{json: json.NewEncoder(w)}, nil
+	case ExportCSV, ExportExcel:
+		if format == ExportExcel {
+			if _, err := io.WriteString(w, "\xef\xbb\xbf"); err != nil {
+				return nil, err
+			}
+		}
+		cw := csv.NewWriter(w)
+		cw.UseCRLF = format == ExportExcel
+		if err := cw.Write(header); err != nil {
+			return nil, err
+		}
+		return &RecordWriter{csv: cw}, nil
+	}
+	return nil, ErrUnknownExportFormat
+}
+
+func (rw *RecordWriter) Write(v interface{}, record []string) error {
+	if rw.json != nil {
+		return rw.json.Encode(v)
+	}
+	return rw.csv.Write(record)
+}
+
+// Flush writes out buffered CSV rows.
+func (rw *RecordWriter) Flush() error {
+	if rw.csv == nil {
+		return nil
+	}
+	rw.csv.Flush()
+	return rw.csv.Error()
+}
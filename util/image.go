@@ -0,0 +1,105 @@
+package util
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"image"
+	"image/color"
+	"image/jpeg"
+	"image/png"
+	"path"
+	"strings"
+
+	"golang.org/x/image/draw"
+	_ "golang.org/x/image/webp" // register the WebP decoder
+)
+
+// ImageVariant is a downscaled copy of an uploaded photo that fits in a
+// MaxSide square.
+type ImageVariant struct {
+	Name    string
+	MaxSide int
+}
+
+var (
+	ThumbnailVariant = ImageVariant{Name: "thumb", MaxSide: 160}
+	MediumVariant    = ImageVariant{Name: "medium", MaxSide: 640}
+	ImageVariants    = []ImageVariant{ThumbnailVariant, MediumVariant}
+)
+
+// MaxImagePixels bounds the decoded size of an upload so a small, highly
+// compressed file can't exhaust memory when it is resized.
+const MaxImagePixels = 40_000_000
+
+var ErrImageTooLarge = errors.New("image dimensions are too large")
+
+// VariantExt is the extension the variants of an image stored with ext are
+// encoded as. PNG stays PNG to keep transparency; JPEG and WebP become JPEG
+// as there is no WebP encoder.
+func VariantExt(ext string) string {
+	if ext == ".png" {
+		return ".png"
+	}
+	return ".jpg"
+}
+
+// VariantKey is where a variant of the photo at key is stored, e.g.
+// hospitals/1/thumb/abc.jpg for hospitals/1/abc.webp.
+func VariantKey(key string, v ImageVariant) string {
+	dir, file := path.Split(key)
+	ext := path.Ext(file)
+	return dir + v.Name + "/" + strings.TrimSuffix(file, ext) + VariantExt(ext)
+}
+
+// CheckImage decodes only the image header and rejects undecodable or
+// oversized images.
+func CheckImage(data []byte) error {
+	cfg, _, err := image.DecodeConfig(bytes.NewReader(data))
+	if err != nil {
+		return fmt.Errorf("error decoding image: %w", err)
+	}
+	if cfg.Width*cfg.Height > MaxImagePixels {
+		return ErrImageTooLarge
+	}
+	return nil
+}
+
+// ResizeImage scales an image down to fit in a maxSide square, never
+// enlarging it, and encodes it for ext (".png" or ".jpg").
+func ResizeImage(data []byte, maxSide int, ext string) ([]byte, error) {
+	if err := CheckImage(data); err != nil {
+		return nil, err
+	}
+	src, _, err := image.Decode(bytes.NewReader(data))
+	if err != nil {
+		return nil, fmt.Errorf("error decoding image: %w", err)
+	}
+
+	bounds := src.Bounds()
+	w, h := bounds.Dx(), bounds.Dy()
+	if w > maxSide || h > maxSide {
+		if w >= h {
+			w, h = maxSide, max(1, h*maxSide/w)
+		} else {
+			w, h = max(1, w*maxSide/h), maxSide
+		}
+	}
+	dst := image.NewRGBA(image.Rect(0, 0, w, h))
+	if ext != ".png" {
+		// JPEG has no alpha channel; flatten transparent areas onto white.
+		draw.Draw(dst, dst.Bounds(), image.NewUniform(color.White), image.Point{}, draw.Src)
+	}
+	draw.CatmullRom.Scale(dst, dst.Bounds(), src, bounds, draw.Over, nil)
+
+	var buf bytes.Buffer
+	if ext == ".png" {
+		err = png.Encode(&buf, dst)
+	} else {
+		err = jpeg.Encode(&buf, dst, &jpeg.Options{Quality: 82})
+	}
+	if err != nil {
+		return nil, fmt.Errorf("error encoding image: %w", err)
+	}
+	return buf.Bytes(), nil
+}
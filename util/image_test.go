@@ -0,0 +1,133 @@
+package util
+
+import (
+	"bytes"
+	"encoding/binary"
+	"errors"
+	"hash/crc32"
+	"image"
+	"image/color"
+	"image/jpeg"
+	"image/png"
+	"testing"
+)
+
+func TestVariantKey(t *testing.T) {
+	tests := []struct {
+		key     string
+		variant ImageVariant
+		want    string
+	}{
+		{"hospitals/1/abc.webp", ThumbnailVariant, "hospitals/1/thumb/abc.jpg"},
+		{"hospitals/1/abc.jpg", MediumVariant, "hospitals/1/medium/abc.jpg"},
+		{"doctors/7/abc.png", ThumbnailVariant, "doctors/7/thumb/abc.png"},
+		{"abc.jpg", MediumVariant, "medium/abc.jpg"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.key+" "+tt.variant.Name, func(t *testing.T) {
+			if got := VariantKey(tt.key, tt.variant); got != tt.want {
+				t.Errorf("VariantKey() = %s, want %s", got, tt.want)
+			}
+		})
+	}
+}
+
+func encodeTestImage(t *testing.T, width, height int, format string) []byte {
+	t.Helper()
+	img := image.NewNRGBA(image.Rect(0, 0, width, height))
+	for y := 0; y < height; y++ {
+		for x := 0; x < width; x++ {
+			img.Set(x, y, color.NRGBA{R: uint8(x), G: uint8(y), B: 128, A: 255})
+		}
+	}
+	var buf bytes.Buffer
+	var err error
+	if format == "png" {
+		err = png.Encode(&buf, img)
+	} else {
+		err = jpeg.Encode(&buf, img, nil)
+	}
+	if err != nil {
+		t.Fatalf("error encoding test image: %v", err)
+	}
+	return buf.Bytes()
+}
+
+func TestResizeImage(t *testing.T) {
+	tests := []struct {
+		name       string
+		width      int
+		height     int
+		format     string
+		maxSide    int
+		ext        string
+		wantWidth  int
+		wantHeight int
+		wantFormat string
+	}{
+		{name: "landscape is scaled to fit", width: 320, height: 200, format: "png", maxSide: 160, ext: ".png", wantWidth: 160, wantHeight: 100, wantFormat: "png"},
+		{name: "portrait is scaled to fit", width: 200, height: 320, format: "jpeg", maxSide: 160, ext: ".jpg", wantWidth: 100, wantHeight: 160, wantFormat: "jpeg"},
+		{name: "small image is not enlarged", width: 100, height: 50, format: "png", maxSide: 640, ext: ".png", wantWidth: 100, wantHeight: 50, wantFormat: "png"},
+		{name: "thin image keeps a pixel", width: 1000, height: 2, format: "png", maxSide: 160, ext: ".png", wantWidth: 160, wantHeight: 1, wantFormat: "png"},
+		{name: "png becomes jpeg for .jpg", width: 320, height: 320, format: "png", maxSide: 160, ext: ".jpg", wantWidth: 160, wantHeight: 160, wantFormat: "jpeg"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			out, err := ResizeImage(encodeTestImage(t, tt.width, tt.height, tt.format), tt.maxSide, tt.ext)
+			if err != nil {
+				t.Fatalf("ResizeImage() error = %v", err)
+			}
+			cfg, format, err := image.DecodeConfig(bytes.NewReader(out))
+			if err != nil {
+				t.Fatalf("error decoding resized image: %v", err)
+			}
+			if cfg.Width != tt.wantWidth || cfg.Height != tt.wantHeight || format != tt.wantFormat {
+				t.Errorf("ResizeImage() = %dx%d %s, want %dx%d %s", cfg.Width, cfg.Height, format, tt.wantWidth, tt.wantHeight, tt.wantFormat)
+			}
+		})
+	}
+}
+
+// pngHeader returns the start of a PNG claiming the given dimensions, which
+// is all CheckImage reads.
+func pngHeader(width, height uint32) []byte {
+	ihdr := make([]byte, 17)
+	copy(ihdr, "IHDR")
+	binary.BigEndian.PutUint32(ihdr[4:], width)
+	binary.BigEndian.PutUint32(ihdr[8:], height)
+	ihdr[12] = 8 // bit depth
+	ihdr[13] = 2 // truecolor
+
+	var buf bytes.Buffer
+	buf.WriteString("\x89PNG\r\n\x1a\n")
+	binary.Write(&buf, binary.BigEndian, uint32(13))
+	buf.Write(ihdr)
+	binary.Write(&buf, binary.BigEndian, crc32.ChecksumIEEE(ihdr))
+	return buf.Bytes()
+}
+
+func TestResizeImageRejects(t *testing.T) {
+	tests := []struct {
+		name    string
+		data    []byte
+		wantErr error
+	}{
+		{name: "too many pixels", data: pngHeader(10000, 5000), wantErr: ErrImageTooLarge},
+		{name: "not an image", data: []byte("GIF89a, or rather not")},
+		{name: "empty", data: nil},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := ResizeImage(tt.data, 160, ".jpg")
+			if err == nil {
+				t.Fatal("ResizeImage() error = nil, want an error")
+			}
+			if tt.wantErr != nil && !errors.Is(err, tt.wantErr) {
+				t.Errorf("ResizeImage() error = %v, want %v", err, tt.wantErr)
+			}
+		})
+	}
+}
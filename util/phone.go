@@ -0,0 +1,20 @@
+package util
+
+import (
+	"regexp"
+	"strings"
+)
+
+var bdMobile = regexp.MustCompile(`^01[3-9][0-9]{8}$`)
+
+// NormalizePhone converts a Bangladeshi mobile number written as 01XXXXXXXXX,
+// 8801XXXXXXXXX or +8801XXXXXXXXX to the +8801XXXXXXXXX form.
+func NormalizePhone(phone string) (string, bool) {
+	p := strings.NewReplacer(" ", "", "-", "").Replace(strings.TrimSpace(phone))
+	p = strings.TrimPrefix(p, "+")
+	p = strings.TrimPrefix(p, "88")
+	if !bdMobile.MatchString(p) {
+		return "", false
+	}
+	return "+88" + p, true
+}
@@ -0,0 +1,30 @@
+package util
+
+import "time"
+
+// Dhaka is the Asia/Dhaka location. Bangladesh has no daylight saving, so a
+// fixed +06:00 zone is used when the tz database is unavailable.
+var Dhaka = loadDhaka()
+
+func loadDhaka() *time.Location {
+	loc, err := time.LoadLocation("Asia/Dhaka")
+	if err != nil {
+		return time.FixedZone("BST", 6*60*60)
+	}
+	return loc
+}
+
+// TodayInDhaka returns the current date in Asia/Dhaka formatted as YYYY-MM-DD.
+func TodayInDhaka() string {
+	return time.Now().In(Dhaka).Format("2006-01-02")
+}
+
+// EndOfDayInDhaka returns midnight in Asia/Dhaka at the end of date, a
+// YYYY-MM-DD day.
+func EndOfDayInDhaka(date string) (time.Time, error) {
+	start, err := time.ParseInLocation("2006-01-02", date, Dhaka)
+	if err != nil {
+		return time.Time{}, err
+	}
+	return start.AddDate(0, 0, 1), nil
+}
@@ -0,0 +1,61 @@
+package util
+
+import (
+	"encoding/base64"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestParseAccessToken(t *testing.T) {
+	const secret = "test-secret"
+	sign := func(secret, role string, subject int, ttl time.Duration) string {
+		t.Helper()
+		token, err := SignAccessToken(secret, role, subject, ttl)
+		if err != nil {
+			t.Fatalf("SignAccessToken() error = %v", err)
+		}
+		return token
+	}
+	valid := sign(secret, "patient", 42, time.Hour)
+	parts := strings.Split(valid, ".")
+	forged := parts[0] + "." + base64.RawURLEncoding.EncodeToString([]byte(`{"sub":"1","role":"patient","iat":0,"exp":9999999999}`)) + "." + parts[2]
+
+	tests := []struct {
+		name    string
+		secret  string
+		role    string
+		token   string
+		want    int
+		wantErr error
+	}{
+		{name: "valid", secret: secret, role: "patient", token: valid, want: 42},
+		{name: "other secret", secret: "other-secret", role: "patient", token: valid, wantErr: ErrInvalidToken},
+		{name: "other role", secret: secret, role: "staff", token: valid, wantErr: ErrInvalidToken},
+		{name: "expired", secret: secret, role: "patient", token: sign(secret, "patient", 42, -time.Minute), wantErr: ErrInvalidToken},
+		{name: "forged payload", secret: secret, role: "patient", token: forged, wantErr: ErrInvalidToken},
+		{name: "missing signature", secret: secret, role: "patient", token: parts[0] + "." + parts[1], wantErr: ErrInvalidToken},
+		{name: "other header", secret: secret, role: "patient", token: "e30." + parts[1] + "." + parts[2], wantErr: ErrInvalidToken},
+		{name: "empty token", secret: secret, role: "patient", token: "", wantErr: ErrInvalidToken},
+		{name: "no secret", secret: "", role: "patient", token: valid, wantErr: ErrNoSecret},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := ParseAccessToken(tt.secret, tt.role, tt.token)
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("ParseAccessToken() error = %v, want %v", err, tt.wantErr)
+			}
+			if got != tt.want {
+				t.Errorf("ParseAccessToken() = %d, want %d", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestSignAccessTokenWithoutSecret(t *testing.T) {
+	if _, err := SignAccessToken("", "patient", 1, time.Hour); !errors.Is(err, ErrNoSecret) {
+		t.Errorf("SignAccessToken() error = %v, want %v", err, ErrNoSecret)
+	}
+}